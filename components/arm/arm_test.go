@@ -3,6 +3,7 @@ package arm_test
 import (
 	"context"
 	"errors"
+	"math"
 	"strings"
 	"testing"
 
@@ -586,3 +587,103 @@ func locationCheckTestHelper(geomList []spatialmath.Geometry, checkMap map[strin
 	}
 	return true
 }
+
+func TestCollisionChecking(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	// a link which swings a box around the z axis, 500mm from it
+	model, err := referenceframe.UnmarshalModelJSON([]byte(`{
+		"name": "swing_arm",
+		"joints": [{"id": "joint", "type": "revolute", "parent": "world", "axis": {"z": 1}, "max": 360, "min": -360}],
+		"links": [{
+			"id": "link",
+			"parent": "joint",
+			"translation": {"x": 500},
+			"geometry": {"type": "box", "x": 100, "y": 100, "z": 100, "translation": {"x": 500}}
+		}]
+	}`), "")
+	test.That(t, err, test.ShouldBeNil)
+	injectArm := inject.NewArm(testArmName)
+	injectArm.ModelFrameFunc = func() referenceframe.Model { return model }
+	injectArm.JointPositionsFunc = func(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+		return &pb.JointPositions{Values: []float64{0}}, nil
+	}
+	injectArm.CurrentInputsFunc = func(ctx context.Context) ([]referenceframe.Input, error) {
+		return []referenceframe.Input{{Value: 0}}, nil
+	}
+	var moved *pb.JointPositions
+	injectArm.MoveToJointPositionsFunc = func(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
+		moved = joints
+		return nil
+	}
+	var wentTo [][]referenceframe.Input
+	injectArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+		wentTo = inputSteps
+		return nil
+	}
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 500}), r3.Vector{X: 100, Y: 100, Z: 100}, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	obstacles := func(ctx context.Context, armName string) ([]spatialmath.Geometry, error) {
+		test.That(t, armName, test.ShouldEqual, testArmName)
+		return []spatialmath.Geometry{obstacle}, nil
+	}
+	towards := &pb.JointPositions{Values: []float64{90}}
+	away := &pb.JointPositions{Values: []float64{-90}}
+
+	checker := &arm.CollisionChecker{}
+	checkedArm := arm.WithCollisionChecking(injectArm, checker, logger)
+	test.That(t, checkedArm.MoveToJointPositions(ctx, towards, nil), test.ShouldBeNil)
+	test.That(t, moved, test.ShouldEqual, towards)
+
+	// without enforcing, only moves which ask for it are checked
+	checker.SetChecking(&arm.CollisionChecking{Obstacles: obstacles})
+	moved = nil
+	test.That(t, checkedArm.MoveToJointPositions(ctx, towards, nil), test.ShouldBeNil)
+	test.That(t, moved, test.ShouldEqual, towards)
+	moved = nil
+	err = checkedArm.MoveToJointPositions(ctx, towards, map[string]interface{}{arm.CollisionCheckKey: true})
+	var collision *arm.CollisionError
+	test.That(t, errors.As(err, &collision), test.ShouldBeTrue)
+	test.That(t, collision.With, test.ShouldEqual, "an obstacle")
+	test.That(t, collision.Clamped, test.ShouldBeFalse)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "would collide with an obstacle")
+	test.That(t, moved, test.ShouldBeNil)
+
+	// a clamped move stops short of the collision and says so
+	checker.SetChecking(&arm.CollisionChecking{Enforce: true, Clamp: true, Obstacles: obstacles})
+	err = checkedArm.MoveToJointPositions(ctx, towards, nil)
+	test.That(t, errors.As(err, &collision), test.ShouldBeTrue)
+	test.That(t, collision.Clamped, test.ShouldBeTrue)
+	test.That(t, moved.Values[0], test.ShouldBeBetween, 45, 90)
+	test.That(t, collision.Positions[0], test.ShouldBeGreaterThan, moved.Values[0])
+	test.That(t, checkedArm.MoveToJointPositions(ctx, away, nil), test.ShouldBeNil)
+	test.That(t, moved.Values[0], test.ShouldAlmostEqual, -90)
+
+	err = checkedArm.GoToInputs(ctx, model.InputFromProtobuf(towards))
+	test.That(t, errors.As(err, &collision), test.ShouldBeTrue)
+	test.That(t, wentTo, test.ShouldHaveLength, 1)
+	test.That(t, wentTo[0][0].Value, test.ShouldBeBetween, math.Pi/4, math.Pi/2)
+
+	// a move to a pose whose planned path is clear is made by the driver, with its extra parameters
+	var movedTo spatialmath.Pose
+	var movedExtra map[string]interface{}
+	injectArm.MoveToPositionFunc = func(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+		movedTo, movedExtra = pose, extra
+		return nil
+	}
+	wentTo = nil
+	clearPose := spatialmath.NewPose(r3.Vector{X: 500}, &spatialmath.OrientationVectorDegrees{OZ: 1})
+	test.That(t, checkedArm.MoveToPosition(ctx, clearPose, map[string]interface{}{"speed": 10}), test.ShouldBeNil)
+	test.That(t, movedTo, test.ShouldEqual, clearPose)
+	test.That(t, movedExtra, test.ShouldResemble, map[string]interface{}{"speed": 10})
+	test.That(t, wentTo, test.ShouldBeNil)
+
+	// but a clamped move follows the planned path, stopping short of the collision
+	movedTo = nil
+	blockedPose := spatialmath.NewPose(r3.Vector{Y: 500}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	err = checkedArm.MoveToPosition(ctx, blockedPose, nil)
+	test.That(t, errors.As(err, &collision), test.ShouldBeTrue)
+	test.That(t, collision.Clamped, test.ShouldBeTrue)
+	test.That(t, movedTo, test.ShouldBeNil)
+	test.That(t, wentTo, test.ShouldNotBeNil)
+}
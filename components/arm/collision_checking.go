@@ -0,0 +1,250 @@
+package arm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// CollisionCheckKey is the extra parameter which asks for a move to be checked for collisions, when the robot
+// does not already check every move.
+const CollisionCheckKey = "collision_check"
+
+// collisionCheckResolution is the largest change of any joint between the joint positions checked for
+// collisions along a move, in radians or mm.
+const collisionCheckResolution = 0.02
+
+// CollisionChecking is how moves which arms are asked to make directly, outside of the motion service,
+// are checked for collisions of the arm with itself and with the static obstacles around it.
+type CollisionChecking struct {
+	// Enforce checks every move, rather than only those which ask for it with CollisionCheckKey.
+	Enforce bool
+	// Clamp stops the arm at the last joint positions before a collision, instead of rejecting the move.
+	Clamp    bool
+	BufferMM float64
+	// Obstacles returns the geometries which the named arm must not collide with, in the frame of its base.
+	Obstacles func(ctx context.Context, armName string) ([]spatialmath.Geometry, error)
+}
+
+// CollisionError is returned by a move which would make an arm collide. If the move was clamped, the arm was
+// moved as far as it could go before the collision instead of not being moved.
+type CollisionError struct {
+	Arm string
+	// With is what the arm would collide with: "itself" or "an obstacle".
+	With string
+	// Positions are the joint positions at which the arm would collide.
+	Positions []float64
+	Clamped   bool
+}
+
+func (e *CollisionError) Error() string {
+	if e.Clamped {
+		return fmt.Sprintf("arm %s stopped short of its goal since it would collide with %s at joint positions %v",
+			e.Arm, e.With, e.Positions)
+	}
+	return fmt.Sprintf("arm %s would collide with %s at joint positions %v", e.Arm, e.With, e.Positions)
+}
+
+// CollisionChecker checks the moves which the arms of a robot are asked to make for collisions, as the robot
+// is configured to. The zero value only checks the moves which ask for it, for collisions of the arm with itself.
+type CollisionChecker struct {
+	mu       sync.Mutex
+	checking *CollisionChecking
+}
+
+// SetChecking sets how moves are checked for collisions. Nil only checks the moves which ask for it, for
+// collisions of the arm with itself.
+func (c *CollisionChecker) SetChecking(checking *CollisionChecking) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checking = checking
+}
+
+// current returns how a move with the extra parameters is checked for collisions, or nil if it is not.
+func (c *CollisionChecker) current(extra map[string]interface{}) *CollisionChecking {
+	c.mu.Lock()
+	checking := c.checking
+	c.mu.Unlock()
+	if checking != nil && checking.Enforce {
+		return checking
+	}
+	if asked, _ := extra[CollisionCheckKey].(bool); !asked {
+		return nil
+	}
+	if checking == nil {
+		return &CollisionChecking{}
+	}
+	return checking
+}
+
+// WithCollisionChecking returns the arm with the moves it is asked to make checked for collisions by the checker.
+// A robot serves its arms this way, so that the moves of remote and modular arms are checked as well. Modules call
+// the robot's arms through the same API, so their moves are checked too, including those of modular motion
+// services; only the builtin motion service, which uses the arms in-process, bypasses the check.
+func WithCollisionChecking(a Arm, checker *CollisionChecker, logger logging.Logger) Arm {
+	return &collisionCheckedArm{Arm: a, checker: checker, logger: logger}
+}
+
+type collisionCheckedArm struct {
+	Arm
+	checker *CollisionChecker
+	logger  logging.Logger
+}
+
+func (a *collisionCheckedArm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	checking := a.checker.current(extra)
+	if checking == nil {
+		return a.Arm.MoveToPosition(ctx, pose, extra)
+	}
+	current, err := a.Arm.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	solution, err := Plan(ctx, a.logger, a.Arm, pose)
+	if err != nil {
+		return err
+	}
+	waypoints, clamped, err := checkWaypointCollisions(ctx, a.Arm, checking, append([][]referenceframe.Input{current}, solution...))
+	if err != nil {
+		return err
+	}
+	if clamped == nil {
+		// the planned path is clear, so the driver makes the move itself, with its extra parameters
+		return a.Arm.MoveToPosition(ctx, pose, extra)
+	}
+	// the driver's own path can't be stopped short, so a clamped move follows the planned one
+	if err := GoToWaypoints(ctx, a.Arm, waypoints[1:]); err != nil {
+		return err
+	}
+	return clamped
+}
+
+func (a *collisionCheckedArm) MoveToJointPositions(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
+	checking := a.checker.current(extra)
+	if checking == nil {
+		return a.Arm.MoveToJointPositions(ctx, joints, extra)
+	}
+	current, err := a.Arm.JointPositions(ctx, nil)
+	if err != nil {
+		return err
+	}
+	model := a.ModelFrame()
+	waypoints, clamped, err := checkWaypointCollisions(ctx, a.Arm, checking,
+		[][]referenceframe.Input{model.InputFromProtobuf(current), model.InputFromProtobuf(joints)})
+	if err != nil {
+		return err
+	}
+	if err := a.Arm.MoveToJointPositions(ctx, model.ProtobufFromInput(waypoints[len(waypoints)-1]), extra); err != nil {
+		return err
+	}
+	if clamped != nil {
+		return clamped
+	}
+	return nil
+}
+
+func (a *collisionCheckedArm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	checking := a.checker.current(nil)
+	if checking == nil || len(inputSteps) == 0 {
+		return a.Arm.GoToInputs(ctx, inputSteps...)
+	}
+	current, err := a.Arm.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	waypoints, clamped, err := checkWaypointCollisions(ctx, a.Arm, checking, append([][]referenceframe.Input{current}, inputSteps...))
+	if err != nil {
+		return err
+	}
+	if err := a.Arm.GoToInputs(ctx, waypoints[1:]...); err != nil {
+		return err
+	}
+	if clamped != nil {
+		return clamped
+	}
+	return nil
+}
+
+// checkWaypointCollisions checks moving the arm through the waypoints, which start at its current joint positions,
+// for collisions. It returns the waypoints to visit. When clamping a move with a collision, they end at the last
+// joint positions before it, and the collision is returned as well.
+func checkWaypointCollisions(
+	ctx context.Context,
+	a Arm,
+	checking *CollisionChecking,
+	waypoints [][]referenceframe.Input,
+) ([][]referenceframe.Input, *CollisionError, error) {
+	model := a.ModelFrame()
+	var obstacles []spatialmath.Geometry
+	if checking.Obstacles != nil {
+		var err error
+		if obstacles, err = checking.Obstacles(ctx, a.Name().ShortName()); err != nil {
+			return nil, nil, errors.Wrap(err, "cannot find the obstacles to check the arm move against")
+		}
+	}
+	startGeometries, err := model.Geometries(waypoints[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	// Collisions at the start of the move, such as those between adjacent links, are ignored.
+	selfCollision, err := motionplan.NewCollisionConstraint(startGeometries.Geometries(), nil, nil, false, checking.BufferMM)
+	if err != nil {
+		return nil, nil, err
+	}
+	obstacleCollision := func(*ik.State) bool { return true }
+	if len(obstacles) > 0 {
+		obstacleCollision, err = motionplan.NewCollisionConstraint(
+			startGeometries.Geometries(), obstacles, nil, false, checking.BufferMM)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1], waypoints[i]
+		var maxDelta float64
+		for j := range from {
+			maxDelta = math.Max(maxDelta, math.Abs(to[j].Value-from[j].Value))
+		}
+		steps := int(math.Ceil(maxDelta / collisionCheckResolution))
+		if steps < 1 {
+			steps = 1
+		}
+		lastSafe := from
+		for step := 1; step <= steps; step++ {
+			inputs := referenceframe.InterpolateInputs(from, to, float64(step)/float64(steps))
+			state := &ik.State{Configuration: inputs, Frame: model}
+			var with string
+			switch {
+			case !selfCollision(state):
+				with = "itself"
+			case !obstacleCollision(state):
+				with = "an obstacle"
+			default:
+				lastSafe = inputs
+				continue
+			}
+			collision := &CollisionError{
+				Arm:       a.Name().ShortName(),
+				With:      with,
+				Positions: model.ProtobufFromInput(inputs).Values,
+				Clamped:   checking.Clamp,
+			}
+			if !checking.Clamp {
+				return nil, nil, collision
+			}
+			return append(waypoints[:i:i], lastSafe), collision, nil
+		}
+	}
+	return waypoints, nil, nil
+}
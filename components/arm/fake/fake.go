@@ -3,8 +3,10 @@ package fake
 
 import (
 	"context"
+	"math"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/arm/v1"
@@ -13,8 +15,10 @@ import (
 	"go.viam.com/rdk/components/arm/eva"
 	ur "go.viam.com/rdk/components/arm/universalrobots"
 	"go.viam.com/rdk/components/arm/xarm"
+	"go.viam.com/rdk/internal/simulation"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
@@ -27,10 +31,18 @@ var errAttrCfgPopulation = errors.New("can only populate either ArmModel or Mode
 // Model is the name used to refer to the fake arm model.
 var Model = resource.DefaultModelFamily.WithModel("fake")
 
+const defaultJointSpeedDegsPerSec = 60
+
 // Config is used for converting config attributes.
 type Config struct {
 	ArmModel      string `json:"arm-model,omitempty"`
 	ModelFilePath string `json:"model-path,omitempty"`
+	// Simulate makes moves take as long as they would at the joint speed, reporting the joint positions
+	// along the way, instead of completing instantly.
+	Simulate bool `json:"simulate,omitempty"`
+	// JointSpeedDegsPerSec is the speed of every joint when simulating, in degrees per second for
+	// revolute joints and millimeters per second for prismatic joints.
+	JointSpeedDegsPerSec float64 `json:"joint_speed_degs_per_sec,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -44,6 +56,9 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	case conf.ArmModel == "" && conf.ModelFilePath != "":
 		_, err = modelFromPath(conf.ModelFilePath, "")
 	}
+	if err == nil && conf.JointSpeedDegsPerSec < 0 {
+		err = errors.New("joint_speed_degs_per_sec cannot be negative")
+	}
 	return nil, err
 }
 
@@ -58,6 +73,7 @@ func NewArm(ctx context.Context, deps resource.Dependencies, conf resource.Confi
 	a := &Arm{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
+		opMgr:  operation.NewSingleOperationManager(),
 	}
 	if err := a.Reconfigure(ctx, deps, conf); err != nil {
 		return nil, err
@@ -94,9 +110,12 @@ type Arm struct {
 	CloseCount int
 	logger     logging.Logger
 
-	mu     sync.RWMutex
-	joints *pb.JointPositions
-	model  referenceframe.Model
+	mu                   sync.RWMutex
+	joints               *pb.JointPositions
+	model                referenceframe.Model
+	simulate             bool
+	jointSpeedDegsPerSec float64
+	opMgr                *operation.SingleOperationManager
 }
 
 // Reconfigure atomically reconfigures this arm in place based on the new config.
@@ -115,6 +134,11 @@ func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf
 	defer a.mu.Unlock()
 	a.joints = &pb.JointPositions{Values: make([]float64, len(model.DoF()))}
 	a.model = model
+	a.simulate = newConf.Simulate
+	a.jointSpeedDegsPerSec = newConf.JointSpeedDegsPerSec
+	if a.jointSpeedDegsPerSec == 0 {
+		a.jointSpeedDegsPerSec = defaultJointSpeedDegsPerSec
+	}
 
 	return nil
 }
@@ -142,37 +166,65 @@ func (a *Arm) MoveToPosition(ctx context.Context, pos spatialmath.Pose, extra ma
 	return arm.Move(ctx, a.logger, a, pos)
 }
 
-// MoveToJointPositions sets the joints.
+// MoveToJointPositions sets the joints. When simulating, the joints move there at the joint speed.
 func (a *Arm) MoveToJointPositions(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
 	inputs := a.model.InputFromProtobuf(joints)
 	if err := arm.CheckDesiredJointPositions(ctx, a, inputs); err != nil {
 		return err
 	}
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-	pos, err := a.model.Transform(inputs)
-	if err != nil {
+	if _, err := a.model.Transform(inputs); err != nil {
 		return err
 	}
-	_ = pos
-	copy(a.joints.Values, joints.Values)
-	return nil
+	ctx, done := a.opMgr.New(ctx)
+	defer done()
+
+	a.mu.RLock()
+	simulate := a.simulate
+	from := append([]float64(nil), a.joints.Values...)
+	speed := a.jointSpeedDegsPerSec
+	a.mu.RUnlock()
+	if !simulate || len(from) != len(joints.Values) {
+		a.setJoints(joints.Values)
+		return nil
+	}
+
+	var maxDelta float64
+	for i, to := range joints.Values {
+		maxDelta = math.Max(maxDelta, math.Abs(to-from[i]))
+	}
+	duration := time.Duration(maxDelta / speed * float64(time.Second))
+	values := make([]float64, len(from))
+	return simulation.Move(ctx, duration, func(fraction float64) {
+		for i, to := range joints.Values {
+			values[i] = from[i] + (to-from[i])*fraction
+		}
+		a.setJoints(values)
+	})
+}
+
+func (a *Arm) setJoints(values []float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.joints = &pb.JointPositions{Values: append([]float64(nil), values...)}
 }
 
 // JointPositions returns joints.
 func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	retJoint := &pb.JointPositions{Values: a.joints.Values}
 	return retJoint, nil
 }
 
-// Stop doesn't do anything for a fake arm.
+// Stop stops a simulated move where it is, and the move returns an error.
 func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.opMgr.CancelRunning(ctx)
 	return nil
 }
 
-// IsMoving is always false for a fake arm.
+// IsMoving returns whether the arm is in the middle of a move.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	return a.opMgr.OpRunning(), nil
 }
 
 // CurrentInputs TODO.
@@ -184,16 +236,21 @@ func (a *Arm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error)
 	return a.model.InputFromProtobuf(res), nil
 }
 
-// GoToInputs TODO.
+// GoToInputs moves through each of the input steps.
 func (a *Arm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	opCtx, done := a.opMgr.New(ctx)
+	defer done()
 	for _, goal := range inputSteps {
+		if err := opCtx.Err(); err != nil {
+			return err
+		}
 		a.mu.RLock()
 		positionDegs := a.model.ProtobufFromInput(goal)
 		a.mu.RUnlock()
-		if err := arm.CheckDesiredJointPositions(ctx, a, goal); err != nil {
+		if err := arm.CheckDesiredJointPositions(opCtx, a, goal); err != nil {
 			return err
 		}
-		err := a.MoveToJointPositions(ctx, positionDegs, nil)
+		err := a.MoveToJointPositions(opCtx, positionDegs, nil)
 		if err != nil {
 			return err
 		}
@@ -240,9 +297,11 @@ func modelFromPath(modelPath, name string) (referenceframe.Model, error) {
 	switch {
 	case strings.HasSuffix(modelPath, ".urdf"):
 		return urdf.ParseModelXMLFile(modelPath, name)
+	case strings.HasSuffix(modelPath, "."+urdf.SDFExtension):
+		return urdf.ParseModelSDFFile(modelPath, name)
 	case strings.HasSuffix(modelPath, ".json"):
 		return referenceframe.ParseModelJSONFile(modelPath, name)
 	default:
-		return nil, errors.New("only files with .json and .urdf file extensions are supported")
+		return nil, errors.New("only files with .json, .urdf and .sdf file extensions are supported")
 	}
 }
@@ -3,9 +3,12 @@ package fake
 import (
 	"context"
 	"testing"
+	"time"
 
 	pb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
+	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
@@ -88,3 +91,53 @@ func TestReconfigure(t *testing.T) {
 	test.That(t, fakeArm.joints.Values, test.ShouldResemble, modelJoints)
 	test.That(t, fakeArm.model, test.ShouldResemble, model)
 }
+
+func TestSimulatedMove(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			ArmModel:             "ur5e",
+			Simulate:             true,
+			JointSpeedDegsPerSec: 100,
+		},
+	}
+	a, err := NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// moving the furthest joint by 20 degrees takes 200ms
+	goal := &pb.JointPositions{Values: []float64{20, 10, 0, 0, 0, 0}}
+	start := time.Now()
+	test.That(t, a.MoveToJointPositions(ctx, goal, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 200*time.Millisecond)
+	joints, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values, test.ShouldResemble, goal.Values)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	// intermediate positions are reported while moving, and stopping leaves the arm where it is
+	done := make(chan error, 1)
+	utils.PanicCapturingGo(func() {
+		done <- a.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{120, 10, 0, 0, 0, 0}}, nil)
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		joints, err := a.JointPositions(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, joints.Values[0], test.ShouldBeBetween, 20, 120)
+		moving, err := a.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-done, test.ShouldBeError, context.Canceled)
+	joints, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values[0], test.ShouldBeBetween, 20, 120)
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
@@ -210,9 +210,11 @@ func modelFromPath(modelPath, name string) (referenceframe.Model, error) {
 	switch {
 	case strings.HasSuffix(modelPath, ".urdf"):
 		return urdf.ParseModelXMLFile(modelPath, name)
+	case strings.HasSuffix(modelPath, "."+urdf.SDFExtension):
+		return urdf.ParseModelSDFFile(modelPath, name)
 	case strings.HasSuffix(modelPath, ".json"):
 		return referenceframe.ParseModelJSONFile(modelPath, name)
 	default:
-		return nil, errors.New("only files with .json and .urdf file extensions are supported")
+		return nil, errors.New("only files with .json, .urdf and .sdf file extensions are supported")
 	}
 }
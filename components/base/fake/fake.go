@@ -3,20 +3,27 @@ package fake
 
 import (
 	"context"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/internal/simulation"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
 )
 
 func init() {
 	resource.RegisterComponent(
 		base.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[base.Base, resource.NoNativeConfig]{Constructor: NewBase},
+		resource.Registration[base.Base, *Config]{Constructor: NewBase},
 	)
 }
 
@@ -24,8 +31,39 @@ const (
 	defaultWidthMm               = 600
 	defaultMinimumTurningRadiusM = 0
 	defaultWheelCircumferenceM   = 3
+	defaultMaxLinearMmPerSec     = 300
+	defaultMaxAngularDegsPerSec  = 90
 )
 
+// The DoCommand which returns the pose of a simulated base, and the keys of the pose.
+const (
+	// GetPoseCommand returns the pose of a simulated base relative to where it started.
+	GetPoseCommand = "get_pose"
+	// XKey and YKey are the position of the base in millimeters, with Y being forward at the start.
+	XKey = "x_mm"
+	YKey = "y_mm"
+	// ThetaKey is the heading of the base in degrees, counterclockwise from where it started.
+	ThetaKey = "theta_deg"
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	// Simulate makes moves take as long as they would at the requested speeds, tracking the pose of the
+	// base along the way, instead of completing instantly.
+	Simulate bool `json:"simulate,omitempty"`
+	// MaxLinearMmPerSec and MaxAngularDegsPerSec are the velocities at full power when simulating.
+	MaxLinearMmPerSec    float64 `json:"max_linear_mm_per_sec,omitempty"`
+	MaxAngularDegsPerSec float64 `json:"max_angular_degs_per_sec,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.MaxLinearMmPerSec < 0 || conf.MaxAngularDegsPerSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("max velocities cannot be negative"))
+	}
+	return nil, nil
+}
+
 // Base is a fake base that returns what it was provided in each method.
 type Base struct {
 	resource.Named
@@ -35,15 +73,34 @@ type Base struct {
 	TurningRadius            float64
 	WheelCircumferenceMeters float64
 	Geometry                 []spatialmath.Geometry
-	logger                   logging.Logger
+	// Simulate makes the base track its pose over time as it moves.
+	Simulate             bool
+	MaxLinearMmPerSec    float64
+	MaxAngularDegsPerSec float64
+	logger               logging.Logger
+
+	mu    sync.Mutex
+	opMgr *operation.SingleOperationManager
+	// pose is where the base was at poseTime, moving at the linear and angular velocities since.
+	pose       pose
+	poseTime   time.Time
+	linearMm   float64
+	angularDeg float64
+}
+
+// pose is the position and heading of a simulated base on the plane.
+type pose struct {
+	xMm, yMm, thetaDeg float64
 }
 
 // NewBase instantiates a new base of the fake model type.
 func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
 	b := &Base{
-		Named:    conf.ResourceName().AsNamed(),
-		Geometry: []spatialmath.Geometry{},
-		logger:   logger,
+		Named:                conf.ResourceName().AsNamed(),
+		Geometry:             []spatialmath.Geometry{},
+		MaxLinearMmPerSec:    defaultMaxLinearMmPerSec,
+		MaxAngularDegsPerSec: defaultMaxAngularDegsPerSec,
+		logger:               logger,
 	}
 	if conf.Frame != nil && conf.Frame.Geometry != nil {
 		geometry, err := conf.Frame.Geometry.ParseConfig()
@@ -52,39 +109,213 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, l
 		}
 		b.Geometry = []spatialmath.Geometry{geometry}
 	}
+	if conf.ConvertedAttributes != nil {
+		newConf, err := resource.NativeConfig[*Config](conf)
+		if err != nil {
+			return nil, err
+		}
+		b.Simulate = newConf.Simulate
+		if newConf.MaxLinearMmPerSec != 0 {
+			b.MaxLinearMmPerSec = newConf.MaxLinearMmPerSec
+		}
+		if newConf.MaxAngularDegsPerSec != 0 {
+			b.MaxAngularDegsPerSec = newConf.MaxAngularDegsPerSec
+		}
+	}
 	b.WidthMeters = defaultWidthMm * 0.001
 	b.TurningRadius = defaultMinimumTurningRadiusM
 	return b, nil
 }
 
-// MoveStraight does nothing.
+func (b *Base) operations() *operation.SingleOperationManager {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.opMgr == nil {
+		b.opMgr = operation.NewSingleOperationManager()
+	}
+	return b.opMgr
+}
+
+// advance integrates the velocities of the base up to now. It must be called with the lock held.
+func (b *Base) advance() {
+	now := time.Now()
+	if b.poseTime.IsZero() {
+		b.poseTime = now
+	}
+	dt := now.Sub(b.poseTime).Seconds()
+	b.poseTime = now
+	b.pose = b.pose.moved(b.linearMm*dt, b.angularDeg*dt)
+}
+
+// moved returns the pose after driving the given distance while turning by the given angle at a
+// constant rate, which is an arc, or a straight line when not turning.
+func (p pose) moved(distanceMm, angleDeg float64) pose {
+	theta := rdkutils.DegToRad(p.thetaDeg)
+	if math.Abs(angleDeg) < 1e-9 {
+		p.xMm -= distanceMm * math.Sin(theta)
+		p.yMm += distanceMm * math.Cos(theta)
+		return p
+	}
+	// heading 0 is forward along Y, so the velocity is distance*(-sin, cos) of the heading
+	radius := distanceMm / rdkutils.DegToRad(angleDeg)
+	end := theta + rdkutils.DegToRad(angleDeg)
+	p.xMm += radius * (math.Cos(end) - math.Cos(theta))
+	p.yMm += radius * (math.Sin(end) - math.Sin(theta))
+	p.thetaDeg += angleDeg
+	return p
+}
+
+func (b *Base) setVelocity(linearMm, angularDeg float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	b.linearMm = linearMm
+	b.angularDeg = angularDeg
+}
+
+// spatialPose returns the pose in the frame the base started in, with its heading as a rotation about Z. A heading of
+// 0 is forward along Y, as in the frame of a base.
+func (p pose) spatialPose() spatialmath.Pose {
+	return spatialmath.NewPose(r3.Vector{X: p.xMm, Y: p.yMm}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: p.thetaDeg})
+}
+
+// simulate simulates a move over the duration, in which the base is at the pose returned by at for the fraction of
+// the move which is done, given the pose it started at.
+func (b *Base) simulate(ctx context.Context, duration time.Duration, at func(start pose, fraction float64) pose) error {
+	ctx, done := b.operations().New(ctx)
+	defer done()
+
+	b.setVelocity(0, 0)
+	b.mu.Lock()
+	start := b.pose
+	b.mu.Unlock()
+	return simulation.Move(ctx, duration, func(fraction float64) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.pose = at(start, fraction)
+		b.poseTime = time.Now()
+	})
+}
+
+// move simulates driving the given distance while turning by the given angle over the duration.
+func (b *Base) move(ctx context.Context, distanceMm, angleDeg float64, duration time.Duration) error {
+	return b.simulate(ctx, duration, func(start pose, fraction float64) pose {
+		return start.moved(distanceMm*fraction, angleDeg*fraction)
+	})
+}
+
+// Pose returns the pose of a simulated base in the frame it started in, with its heading as a rotation about Z. A
+// heading of 0 is forward along Y, as in the frame of a base.
+func (b *Base) Pose() spatialmath.Pose {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.pose.spatialPose()
+}
+
+// DriveTo simulates a base going from where it is to the given position and heading, in the frame it started in, at
+// the given velocities, moving and turning the shorter way at once as a kinematic base following a plan does. The move
+// takes as long as the slower of the two needs.
+func (b *Base) DriveTo(ctx context.Context, xMm, yMm, thetaDeg, mmPerSec, degsPerSec float64) error {
+	if mmPerSec <= 0 || degsPerSec <= 0 {
+		return errors.New("cannot drive at a velocity of 0 or less")
+	}
+	b.mu.Lock()
+	b.advance()
+	from := b.pose
+	b.mu.Unlock()
+	to := pose{xMm: xMm, yMm: yMm, thetaDeg: from.thetaDeg + math.Remainder(thetaDeg-from.thetaDeg, 360)}
+	seconds := math.Max(math.Hypot(to.xMm-from.xMm, to.yMm-from.yMm)/mmPerSec, math.Abs(to.thetaDeg-from.thetaDeg)/degsPerSec)
+	return b.simulate(ctx, time.Duration(seconds*float64(time.Second)), func(start pose, fraction float64) pose {
+		return pose{
+			xMm:      start.xMm + (to.xMm-start.xMm)*fraction,
+			yMm:      start.yMm + (to.yMm-start.yMm)*fraction,
+			thetaDeg: start.thetaDeg + (to.thetaDeg-start.thetaDeg)*fraction,
+		}
+	})
+}
+
+// MoveStraight does nothing, unless simulating.
 func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
-	return nil
+	if !b.Simulate {
+		return nil
+	}
+	if mmPerSec == 0 {
+		return errors.New("cannot move straight at 0 mm per second")
+	}
+	distance := math.Abs(float64(distanceMm))
+	if (distanceMm < 0) != (mmPerSec < 0) {
+		distance = -distance
+	}
+	return b.move(ctx, distance, 0, time.Duration(math.Abs(distance/mmPerSec)*float64(time.Second)))
 }
 
-// Spin does nothing.
+// Spin does nothing, unless simulating.
 func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
-	return nil
+	if !b.Simulate {
+		return nil
+	}
+	if degsPerSec == 0 {
+		return errors.New("cannot spin at 0 degrees per second")
+	}
+	angle := math.Abs(angleDeg)
+	if (angleDeg < 0) != (degsPerSec < 0) {
+		angle = -angle
+	}
+	return b.move(ctx, 0, angle, time.Duration(math.Abs(angle/degsPerSec)*float64(time.Second)))
 }
 
-// SetPower does nothing.
+// SetPower does nothing, unless simulating.
 func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
-	return nil
+	return b.SetVelocity(ctx, linear.Mul(b.MaxLinearMmPerSec), angular.Mul(b.MaxAngularDegsPerSec), extra)
 }
 
-// SetVelocity does nothing.
+// SetVelocity does nothing, unless simulating.
 func (b *Base) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if !b.Simulate {
+		return nil
+	}
+	b.operations().CancelRunning(ctx)
+	b.setVelocity(linear.Y, angular.Z)
 	return nil
 }
 
-// Stop does nothing.
+// Stop does nothing, unless simulating, in which case a move in progress stops where it is and returns an error.
 func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
+	if !b.Simulate {
+		return nil
+	}
+	b.operations().CancelRunning(ctx)
+	b.setVelocity(0, 0)
 	return nil
 }
 
-// IsMoving always returns false.
+// IsMoving always returns false, unless simulating.
 func (b *Base) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	if !b.Simulate {
+		return false, nil
+	}
+	if b.operations().OpRunning() {
+		return true, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.linearMm != 0 || b.angularDeg != 0, nil
+}
+
+// DoCommand returns the pose of a simulated base.
+func (b *Base) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[GetPoseCommand]; !ok || !b.Simulate {
+		return nil, resource.ErrDoUnimplemented
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return map[string]interface{}{
+		XKey:     b.pose.xMm,
+		YKey:     b.pose.yMm,
+		ThetaKey: b.pose.thetaDeg,
+	}, nil
 }
 
 // Close does nothing.
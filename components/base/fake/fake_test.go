@@ -0,0 +1,133 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func newSimulatedBase(t *testing.T) base.Base {
+	t.Helper()
+	b, err := NewBase(context.Background(), nil, resource.Config{
+		Name:                "base",
+		ConvertedAttributes: &Config{Simulate: true, MaxLinearMmPerSec: 1000},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	return b
+}
+
+func getPose(tb testing.TB, b base.Base) (float64, float64, float64) {
+	tb.Helper()
+	resp, err := b.DoCommand(context.Background(), map[string]interface{}{GetPoseCommand: true})
+	test.That(tb, err, test.ShouldBeNil)
+	return resp[XKey].(float64), resp[YKey].(float64), resp[ThetaKey].(float64)
+}
+
+func TestSimulatedMoves(t *testing.T) {
+	ctx := context.Background()
+	b := newSimulatedBase(t)
+
+	start := time.Now()
+	test.That(t, b.MoveStraight(ctx, 100, 500, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 200*time.Millisecond)
+	x, y, theta := getPose(t, b)
+	test.That(t, x, test.ShouldAlmostEqual, 0)
+	test.That(t, y, test.ShouldAlmostEqual, 100)
+	test.That(t, theta, test.ShouldAlmostEqual, 0)
+
+	test.That(t, b.Spin(ctx, 90, 900, nil), test.ShouldBeNil)
+	test.That(t, b.MoveStraight(ctx, -50, 500, nil), test.ShouldBeNil)
+	x, y, theta = getPose(t, b)
+	test.That(t, x, test.ShouldAlmostEqual, 50)
+	test.That(t, y, test.ShouldAlmostEqual, 100)
+	test.That(t, theta, test.ShouldAlmostEqual, 90)
+
+	// intermediate poses are reported while moving, and stopping leaves the base where it is
+	done := make(chan error, 1)
+	utils.PanicCapturingGo(func() {
+		done <- b.MoveStraight(ctx, 1000, 500, nil)
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		x, _, _ := getPose(tb, b)
+		test.That(tb, x, test.ShouldBeLessThan, 0)
+		moving, err := b.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-done, test.ShouldBeError, context.Canceled)
+	x, _, _ = getPose(t, b)
+	test.That(t, x, test.ShouldBeBetween, -950, 0)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestSimulatedVelocity(t *testing.T) {
+	ctx := context.Background()
+	b := newSimulatedBase(t)
+
+	// half power forward is 500mm/s
+	test.That(t, b.SetPower(ctx, r3.Vector{Y: 0.5}, r3.Vector{}, nil), test.ShouldBeNil)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+	time.Sleep(100 * time.Millisecond)
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	x, y, _ := getPose(t, b)
+	test.That(t, x, test.ShouldAlmostEqual, 0)
+	test.That(t, y, test.ShouldBeBetween, 40, 100)
+
+	// a quarter circle to the left, with a radius of 100mm
+	x0, y0, _ := getPose(t, b)
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100 * 3.14159265 / 2}, r3.Vector{Z: 90}, nil), test.ShouldBeNil)
+	time.Sleep(time.Second)
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	x, y, theta := getPose(t, b)
+	test.That(t, theta, test.ShouldBeBetween, 85, 100)
+	test.That(t, x-x0, test.ShouldBeBetween, -110, -90)
+	test.That(t, y-y0, test.ShouldBeBetween, 90, 110)
+}
+
+func TestSimulatedDriveTo(t *testing.T) {
+	ctx := context.Background()
+	b := newSimulatedBase(t).(*Base)
+
+	// turning to a heading of 270 degrees turns right, the shorter way
+	test.That(t, b.DriveTo(ctx, 10, 20, 270, 1000, 900), test.ShouldBeNil)
+	x, y, theta := getPose(t, b)
+	test.That(t, x, test.ShouldAlmostEqual, 10)
+	test.That(t, y, test.ShouldAlmostEqual, 20)
+	test.That(t, theta, test.ShouldAlmostEqual, -90)
+
+	pose := b.Pose()
+	test.That(t, spatialmath.R3VectorAlmostEqual(pose.Point(), r3.Vector{X: 10, Y: 20}, 1e-6), test.ShouldBeTrue)
+	expected := &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90}
+	test.That(t, spatialmath.OrientationAlmostEqual(pose.Orientation(), expected), test.ShouldBeTrue)
+
+	test.That(t, b.DriveTo(ctx, 0, 0, 0, 0, 900), test.ShouldNotBeNil)
+}
+
+func TestNotSimulated(t *testing.T) {
+	ctx := context.Background()
+	b, err := NewBase(ctx, nil, resource.Config{Name: "base"}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	start := time.Now()
+	test.That(t, b.MoveStraight(ctx, 1000, 1, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, time.Second)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	_, err = b.DoCommand(ctx, map[string]interface{}{GetPoseCommand: true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/geo/r3"
+
 	"go.viam.com/rdk/components/base/fake"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
@@ -26,6 +28,9 @@ type fakeDiffDriveKinematics struct {
 	options                       Options
 	sensorNoise                   spatialmath.Pose
 	lock                          sync.RWMutex
+	// poseOffset is where the frame a simulated base started in is in the parent frame. The inputs of a simulated base
+	// are its pose, so that the frame system and the base agree on where it is.
+	poseOffset r3.Vector
 }
 
 // WrapWithFakeDiffDriveKinematics creates a DiffDrive KinematicBase from the fake Base so that it satisfies the ModelFramer and
@@ -52,6 +57,10 @@ func WrapWithFakeDiffDriveKinematics(
 		inputs:      referenceframe.FloatsToInputs([]float64{pt.X, pt.Y}),
 		sensorNoise: sensorNoise,
 	}
+	if b.Simulate {
+		start := b.Pose().Point()
+		fk.poseOffset = r3.Vector{X: pt.X - start.X, Y: pt.Y - start.Y}
+	}
 	var geometry spatialmath.Geometry
 	if len(fk.Base.Geometry) != 0 {
 		geometry = fk.Base.Geometry[0]
@@ -80,6 +89,14 @@ func (fk *fakeDiffDriveKinematics) Kinematics() referenceframe.Frame {
 }
 
 func (fk *fakeDiffDriveKinematics) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	if fk.Base.Simulate {
+		pose := fk.Base.Pose()
+		inputs := []referenceframe.Input{{Value: pose.Point().X + fk.poseOffset.X}, {Value: pose.Point().Y + fk.poseOffset.Y}}
+		if len(fk.planningFrame.DoF()) > 2 {
+			inputs = append(inputs, referenceframe.Input{Value: pose.Orientation().OrientationVectorRadians().Theta})
+		}
+		return inputs, nil
+	}
 	fk.lock.RLock()
 	defer fk.lock.RUnlock()
 	return fk.inputs, nil
@@ -91,6 +108,12 @@ func (fk *fakeDiffDriveKinematics) GoToInputs(ctx context.Context, inputSteps ..
 		if err != nil {
 			return err
 		}
+		if fk.Base.Simulate {
+			if err := fk.simulateMove(ctx, inputs); err != nil {
+				return err
+			}
+			continue
+		}
 		fk.lock.Lock()
 		fk.inputs = inputs
 		fk.lock.Unlock()
@@ -101,14 +124,33 @@ func (fk *fakeDiffDriveKinematics) GoToInputs(ctx context.Context, inputSteps ..
 	return nil
 }
 
+// simulateMove drives a simulated base to the inputs at the kinematic base's velocities, keeping its heading if the
+// inputs have none.
+func (fk *fakeDiffDriveKinematics) simulateMove(ctx context.Context, inputs []referenceframe.Input) error {
+	linear := fk.options.LinearVelocityMMPerSec
+	if linear == 0 {
+		linear = defaultLinearVelocityMMPerSec
+	}
+	angular := fk.options.AngularVelocityDegsPerSec
+	if angular == 0 {
+		angular = defaultAngularVelocityDegsPerSec
+	}
+	heading := fk.Base.Pose().Orientation().OrientationVectorDegrees().Theta
+	if len(inputs) > 2 {
+		heading = rdkutils.RadToDeg(inputs[2].Value)
+	}
+	return fk.Base.DriveTo(ctx, inputs[0].Value-fk.poseOffset.X, inputs[1].Value-fk.poseOffset.Y, heading, linear, angular)
+}
+
 func (fk *fakeDiffDriveKinematics) ErrorState(ctx context.Context, plan motionplan.Plan, currentNode int) (spatialmath.Pose, error) {
 	return fk.sensorNoise, nil
 }
 
 func (fk *fakeDiffDriveKinematics) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
-	fk.lock.RLock()
-	inputs := fk.inputs
-	fk.lock.RUnlock()
+	inputs, err := fk.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
 	currentPose, err := fk.planningFrame.Transform(inputs)
 	if err != nil {
 		return nil, err
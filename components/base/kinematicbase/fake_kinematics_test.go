@@ -2,6 +2,7 @@ package kinematicbase
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -61,6 +62,53 @@ func TestNewFakeDiffDriveKinematics(t *testing.T) {
 	test.That(t, spatialmath.PoseAlmostCoincident(pose.Pose(), spatialmath.NewPoseFromPoint(r3.Vector{11, 11, 0})), test.ShouldBeTrue)
 }
 
+func TestFakeDiffDriveKinematicsSimulated(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	b, err := fakebase.NewBase(ctx, resource.Dependencies{}, resource.Config{
+		Name:                "test",
+		ConvertedAttributes: &fakebase.Config{Simulate: true, MaxLinearMmPerSec: 1000},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	fb := b.(*fakebase.Base)
+	start := referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100, Y: 200}))
+	limits := []referenceframe.Limit{{Min: -1000, Max: 1000}, {Min: -1000, Max: 1000}, {Min: -2 * math.Pi, Max: 2 * math.Pi}}
+	options := NewKinematicBaseOptions()
+	options.PositionOnlyMode = false
+	options.AngularVelocityDegsPerSec = 900
+	kb, err := WrapWithFakeDiffDriveKinematics(ctx, fb, staticLocalizer{start}, limits, options, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the kinematic base moves the simulated base, and its inputs are where the base is
+	test.That(t, kb.GoToInputs(ctx, referenceframe.FloatsToInputs([]float64{110, 220, math.Pi / 2})), test.ShouldBeNil)
+	resp, err := fb.DoCommand(ctx, map[string]interface{}{fakebase.GetPoseCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp[fakebase.XKey], test.ShouldAlmostEqual, 10)
+	test.That(t, resp[fakebase.YKey], test.ShouldAlmostEqual, 20)
+	test.That(t, resp[fakebase.ThetaKey], test.ShouldAlmostEqual, 90)
+
+	// and moving the base directly moves it in the frame system, facing -X after turning left
+	test.That(t, fb.MoveStraight(ctx, 50, 1000, nil), test.ShouldBeNil)
+	inputs, err := kb.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs, test.ShouldHaveLength, 3)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, 60)
+	test.That(t, inputs[1].Value, test.ShouldAlmostEqual, 220)
+	test.That(t, inputs[2].Value, test.ShouldAlmostEqual, math.Pi/2)
+	pose, err := kb.CurrentPosition(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pose.Pose().Point(), r3.Vector{X: 60, Y: 220}, 1e-6), test.ShouldBeTrue)
+}
+
+// staticLocalizer is a localizer which is always at the same pose.
+type staticLocalizer struct {
+	pose *referenceframe.PoseInFrame
+}
+
+func (l staticLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.PoseInFrame, error) {
+	return l.pose, nil
+}
+
 func TestNewFakePTGKinematics(t *testing.T) {
 	conf := resource.Config{
 		Name: "test",
@@ -0,0 +1,168 @@
+package wheeled
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/resource"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// The drives a wheeled base can have. A differential drive has its motors on the left and right, while
+// the holonomic drives have a motor at each corner and can also move sideways.
+const (
+	driveDifferential = "differential"
+	driveMecanum      = "mecanum"
+	driveOmni         = "omni"
+)
+
+// kinematics converts a 2D twist of a holonomic base into the motions of its wheels, which are ordered
+// front left, front right, back left, back right. The twist is to the right (x) and forward (y), and
+// turning counterclockwise.
+type kinematics interface {
+	// wheelSpeeds returns the speed of each wheel along the ground, in mm/sec, that moves the base at
+	// vx and vy mm/sec while it turns at omega rad/sec.
+	wheelSpeeds(vx, vy, omega float64) []float64
+	// wheelPowers mixes powers between -1 and 1 for each direction into the power of each wheel.
+	wheelPowers(x, y, turn float64) []float64
+}
+
+// holonomicDrives are the kinematics of the holonomic drives, by the name they are configured with.
+var holonomicDrives = map[string]func(widthMm, lengthMm float64) kinematics{
+	driveMecanum: func(widthMm, lengthMm float64) kinematics {
+		return &mecanumKinematics{halfWidthMm: widthMm / 2, halfLengthMm: lengthMm / 2}
+	},
+	driveOmni: func(widthMm, lengthMm float64) kinematics {
+		return &omniKinematics{halfWidthMm: widthMm / 2, halfLengthMm: lengthMm / 2}
+	},
+}
+
+// mecanumKinematics are for four mecanum wheels whose rollers form an X when seen from above.
+type mecanumKinematics struct {
+	halfWidthMm  float64
+	halfLengthMm float64
+}
+
+func (mk *mecanumKinematics) wheelSpeeds(vx, vy, omega float64) []float64 {
+	turn := (mk.halfWidthMm + mk.halfLengthMm) * omega
+	return []float64{vy + vx - turn, vy - vx + turn, vy - vx - turn, vy + vx + turn}
+}
+
+func (mk *mecanumKinematics) wheelPowers(x, y, turn float64) []float64 {
+	return mixCorners(x, y, turn)
+}
+
+// omniKinematics are for four omni wheels at the corners of the base, each rolling at 45 degrees
+// to the front of the base, also known as an X drive.
+type omniKinematics struct {
+	halfWidthMm  float64
+	halfLengthMm float64
+}
+
+func (omk *omniKinematics) wheelSpeeds(vx, vy, omega float64) []float64 {
+	turn := math.Hypot(omk.halfWidthMm, omk.halfLengthMm) * omega
+	vx /= math.Sqrt2
+	vy /= math.Sqrt2
+	return []float64{vy + vx - turn, vy - vx + turn, vy - vx - turn, vy + vx + turn}
+}
+
+func (omk *omniKinematics) wheelPowers(x, y, turn float64) []float64 {
+	return mixCorners(x, y, turn)
+}
+
+// mixCorners mixes powers for wheels at the corners of a base that roll diagonally, scaling them down
+// so that none is more than 1.
+func mixCorners(x, y, turn float64) []float64 {
+	powers := []float64{y + x - turn, y - x + turn, y - x - turn, y + x + turn}
+	maxPower := 1.0
+	for _, p := range powers {
+		maxPower = math.Max(maxPower, math.Abs(p))
+	}
+	for i := range powers {
+		powers[i] /= maxPower
+	}
+	return powers
+}
+
+// spinHolonomic spins a holonomic base in place.
+func (wb *wheeledBase) spinHolonomic(ctx context.Context, kin kinematics, angleDeg, degsPerSec float64) error {
+	omega := rdkutils.DegToRad(math.Abs(degsPerSec))
+	if angleDeg*degsPerSec < 0 {
+		omega = -omega
+	}
+	speeds := kin.wheelSpeeds(0, 0, omega)
+	for i := range speeds {
+		speeds[i] *= wb.spinSlipFactor
+	}
+	return wb.runWheels(ctx, speeds, math.Abs(angleDeg/degsPerSec))
+}
+
+// moveStraightHolonomic drives a holonomic base forward or backward.
+func (wb *wheeledBase) moveStraightHolonomic(ctx context.Context, kin kinematics, distanceMm int, mmPerSec float64) error {
+	vy := math.Abs(mmPerSec)
+	if float64(distanceMm)*mmPerSec < 0 {
+		vy = -vy
+	}
+	return wb.runWheels(ctx, kin.wheelSpeeds(0, vy, 0), math.Abs(float64(distanceMm)/mmPerSec))
+}
+
+// runWheels runs each wheel of a holonomic base at its speed in mm/sec for the given number of seconds,
+// or until interrupted when it is zero, and stops the base if an error occurs. Wheels which do not need
+// to move are stopped. Like runAllGoFor, callers must register an operation via `wb.opMgr.New`.
+func (wb *wheeledBase) runWheels(ctx context.Context, speeds []float64, durationSec float64) error {
+	goForFuncs := func() []rdkutils.SimpleFunc {
+		ret := []rdkutils.SimpleFunc{}
+
+		wb.mu.Lock()
+		defer wb.mu.Unlock()
+		for i, m := range wb.wheels {
+			motor := m
+			rotationsPerSec := speeds[i] / float64(wb.wheelCircumferenceMm)
+			rpm := 60 * rotationsPerSec
+			if math.Abs(rpm) < 0.1 {
+				ret = append(ret, func(ctx context.Context) error { return motor.Stop(ctx, nil) })
+				continue
+			}
+			revolutions := math.Abs(rotationsPerSec) * durationSec
+			ret = append(ret, func(ctx context.Context) error { return motor.GoFor(ctx, rpm, revolutions, nil) })
+		}
+		return ret
+	}()
+
+	if _, err := rdkutils.RunInParallel(ctx, goForFuncs); err != nil {
+		err := multierr.Combine(err, wb.Stop(ctx, nil))
+		// Ignore the context canceled error - this occurs when the base is stopped by the user.
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+	return nil
+}
+
+// wheelMotors returns the motors of a holonomic base, ordered front left, front right, back left,
+// back right.
+func wheelMotors(ctx context.Context, deps resource.Dependencies, conf *Config, name string) ([]motor.Motor, error) {
+	wheels := make([]motor.Motor, 0, 4)
+	for _, wheel := range []struct{ field, motorName string }{
+		{"front_left", conf.FrontLeft},
+		{"front_right", conf.FrontRight},
+		{"back_left", conf.BackLeft},
+		{"back_right", conf.BackRight},
+	} {
+		select {
+		case <-ctx.Done():
+			return nil, rdkutils.NewBuildTimeoutError(name)
+		default:
+		}
+		m, err := motor.FromDependencies(deps, wheel.motorName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "no %s motor named (%s)", wheel.field, wheel.motorName)
+		}
+		wheels = append(wheels, m)
+	}
+	return wheels, nil
+}
@@ -0,0 +1,118 @@
+package wheeled
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestHolonomicKinematics(t *testing.T) {
+	mecanum := holonomicDrives[driveMecanum](400, 200)
+	test.That(t, mecanum.wheelSpeeds(0, 100, 0), test.ShouldResemble, []float64{100, 100, 100, 100})
+	test.That(t, mecanum.wheelSpeeds(100, 0, 0), test.ShouldResemble, []float64{100, -100, -100, 100})
+	test.That(t, mecanum.wheelSpeeds(0, 0, 1), test.ShouldResemble, []float64{-300, 300, -300, 300})
+
+	omni := holonomicDrives[driveOmni](300, 400)
+	speeds := omni.wheelSpeeds(0, 100, 0)
+	for _, speed := range speeds {
+		test.That(t, speed, test.ShouldAlmostEqual, 100/math.Sqrt2)
+	}
+	speeds = omni.wheelSpeeds(0, 0, -1)
+	test.That(t, speeds, test.ShouldResemble, []float64{250, -250, 250, -250})
+
+	test.That(t, mecanum.wheelPowers(1, 0, 0), test.ShouldResemble, []float64{1, -1, -1, 1})
+	test.That(t, omni.wheelPowers(1, 1, 0), test.ShouldResemble, []float64{1, 0, 0, 1})
+	test.That(t, omni.wheelPowers(0.5, 0.5, 1), test.ShouldResemble, []float64{0, 0.5, -0.5, 1})
+}
+
+func TestValidateHolonomic(t *testing.T) {
+	cfg := &Config{WidthMM: 100, WheelCircumferenceMM: 1000, Drive: "tank"}
+	_, err := cfg.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown drive "tank"`)
+
+	cfg.Drive = driveMecanum
+	_, err = cfg.Validate("path")
+	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "length_mm")
+
+	cfg.LengthMM = 100
+	cfg.FrontLeft, cfg.FrontRight, cfg.BackLeft = "fl-m", "fr-m", "bl-m"
+	_, err = cfg.Validate("path")
+	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "back_right")
+
+	cfg.BackRight = "br-m"
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"fl-m", "fr-m", "bl-m", "br-m"})
+}
+
+func TestHolonomicBase(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	testCfg := resource.Config{
+		Name:  "test",
+		API:   base.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			Drive:                driveMecanum,
+			WidthMM:              400,
+			LengthMM:             200,
+			WheelCircumferenceMM: 1000,
+			FrontLeft:            "fl-m",
+			FrontRight:           "fr-m",
+			BackLeft:             "bl-m",
+			BackRight:            "br-m",
+		},
+	}
+	deps, err := testCfg.Validate("path", resource.APITypeComponentName)
+	test.That(t, err, test.ShouldBeNil)
+	newBase, err := createWheeledBase(ctx, fakeMotorDependencies(t, deps), testCfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	wb := newBase.(*wheeledBase)
+	defer wb.Close(ctx)
+	test.That(t, wb.left, test.ShouldResemble, []motor.Motor{wb.wheels[0], wb.wheels[2]})
+
+	powers := func() []float64 {
+		var ret []float64
+		for _, m := range wb.wheels {
+			_, power, err := m.IsPowered(ctx, nil)
+			test.That(t, err, test.ShouldBeNil)
+			ret = append(ret, power)
+		}
+		return ret
+	}
+
+	t.Run("set power sideways", func(t *testing.T) {
+		test.That(t, wb.SetPower(ctx, r3.Vector{X: 0.5}, r3.Vector{}, nil), test.ShouldBeNil)
+		test.That(t, powers(), test.ShouldResemble, []float64{0.5, -0.5, -0.5, 0.5})
+		moving, err := wb.IsMoving(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, moving, test.ShouldBeTrue)
+
+		test.That(t, wb.Stop(ctx, nil), test.ShouldBeNil)
+		test.That(t, powers(), test.ShouldResemble, []float64{0, 0, 0, 0})
+	})
+
+	t.Run("set velocity diagonally", func(t *testing.T) {
+		// 500 mm/sec is 30 rpm, or half of the max rpm of the fake motors
+		test.That(t, wb.SetVelocity(ctx, r3.Vector{X: 250, Y: 250}, r3.Vector{}, nil), test.ShouldBeNil)
+		test.That(t, powers(), test.ShouldResemble, []float64{0.5, 0, 0, 0.5})
+		test.That(t, wb.Stop(ctx, nil), test.ShouldBeNil)
+	})
+
+	t.Run("reconfigure to differential", func(t *testing.T) {
+		diffCfg := newTestCfg()
+		diffDeps, err := diffCfg.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, wb.Reconfigure(ctx, fakeMotorDependencies(t, diffDeps), diffCfg), test.ShouldBeNil)
+		test.That(t, wb.holonomicKinematics(), test.ShouldBeNil)
+		test.That(t, wb.wheels, test.ShouldBeNil)
+	})
+}
@@ -29,6 +29,21 @@ package wheeled
      },
      "depends_on": ["left1", "left2", "right1", "right2", "local"],
    },
+
+   Setting the drive to "mecanum" or "omni" configures a holonomic base instead, with a motor at each corner
+   driving mecanum wheels or omni wheels at 45 degrees (an X drive). Such a base can also move sideways, so
+   SetVelocity and SetPower use the linear X component (to the right) as well. It needs the length of the base,
+   between the front and back wheels, in place of the left and right motors:
+   {
+     "drive": "mecanum",
+     "front_left": "fl",
+     "front_right": "fr",
+     "back_left": "bl",
+     "back_right": "br",
+     "wheel_circumference_mm": 300,
+     "width_mm": 400,
+     "length_mm": 350,
+   }
 */
 
 import (
@@ -60,6 +75,14 @@ type Config struct {
 	SpinSlipFactor       float64  `json:"spin_slip_factor,omitempty"`
 	Left                 []string `json:"left"`
 	Right                []string `json:"right"`
+
+	// Drive is differential by default, or mecanum or omni for a holonomic base with the motors below.
+	Drive      string `json:"drive,omitempty"`
+	LengthMM   int    `json:"length_mm,omitempty"`
+	FrontLeft  string `json:"front_left,omitempty"`
+	FrontRight string `json:"front_right,omitempty"`
+	BackLeft   string `json:"back_left,omitempty"`
+	BackRight  string `json:"back_right,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -74,6 +97,25 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 		return nil, resource.NewConfigValidationFieldRequiredError(path, "wheel_circumference_mm")
 	}
 
+	switch cfg.Drive {
+	case "", driveDifferential:
+	case driveMecanum, driveOmni:
+		if cfg.LengthMM == 0 {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "length_mm")
+		}
+		for field, name := range map[string]string{
+			"front_left": cfg.FrontLeft, "front_right": cfg.FrontRight, "back_left": cfg.BackLeft, "back_right": cfg.BackRight,
+		} {
+			if name == "" {
+				return nil, resource.NewConfigValidationFieldRequiredError(path, field)
+			}
+		}
+		return []string{cfg.FrontLeft, cfg.FrontRight, cfg.BackLeft, cfg.BackRight}, nil
+	default:
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("unknown drive %q, must be one of %q, %q or %q", cfg.Drive, driveDifferential, driveMecanum, driveOmni))
+	}
+
 	if len(cfg.Left) == 0 {
 		return nil, resource.NewConfigValidationFieldRequiredError(path, "left")
 	}
@@ -108,6 +150,10 @@ type wheeledBase struct {
 	right     []motor.Motor
 	allMotors []motor.Motor
 
+	// kinematics and wheels are only set for a holonomic base, whose wheels are also on the left and right.
+	kinematics kinematics
+	wheels     []motor.Motor
+
 	opMgr  *operation.SingleOperationManager
 	logger logging.Logger
 
@@ -139,6 +185,23 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 	} else {
 		wb.spinSlipFactor = newConf.SpinSlipFactor
 	}
+	wb.widthMm = newConf.WidthMM
+	wb.wheelCircumferenceMm = newConf.WheelCircumferenceMM
+
+	if newKinematics, ok := holonomicDrives[newConf.Drive]; ok {
+		wheels, err := wheelMotors(ctx, deps, newConf, wb.Name().String())
+		if err != nil {
+			return err
+		}
+		wb.kinematics = newKinematics(float64(newConf.WidthMM), float64(newConf.LengthMM))
+		wb.wheels = wheels
+		wb.left = []motor.Motor{wheels[0], wheels[2]}
+		wb.right = []motor.Motor{wheels[1], wheels[3]}
+		wb.allMotors = wheels
+		return nil
+	}
+	wb.kinematics = nil
+	wb.wheels = nil
 
 	updateMotors := func(curr []motor.Motor, fromConfig []string, whichMotor string) ([]motor.Motor, error) {
 		newMotors := make([]motor.Motor, 0)
@@ -192,14 +255,6 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 	wb.allMotors = append(wb.allMotors, wb.left...)
 	wb.allMotors = append(wb.allMotors, wb.right...)
 
-	if wb.widthMm != newConf.WidthMM {
-		wb.widthMm = newConf.WidthMM
-	}
-
-	if wb.wheelCircumferenceMm != newConf.WheelCircumferenceMM {
-		wb.wheelCircumferenceMm = newConf.WheelCircumferenceMM
-	}
-
 	return nil
 }
 
@@ -247,6 +302,10 @@ func (wb *wheeledBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, e
 		return err
 	}
 
+	if kin := wb.holonomicKinematics(); kin != nil {
+		return wb.spinHolonomic(ctx, kin, angleDeg, degsPerSec)
+	}
+
 	// Spin math
 	rpm, revolutions := wb.spinMath(angleDeg, degsPerSec)
 
@@ -266,6 +325,12 @@ func (wb *wheeledBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSe
 		return err
 	}
 
+	if kin := wb.holonomicKinematics(); kin != nil {
+		ctx, done := wb.opMgr.New(ctx)
+		defer done()
+		return wb.moveStraightHolonomic(ctx, kin, distanceMm, mmPerSec)
+	}
+
 	// Straight math
 	rpm, rotations := wb.straightDistanceToMotorInputs(distanceMm, mmPerSec)
 
@@ -361,6 +426,13 @@ func (wb *wheeledBase) SetVelocity(ctx context.Context, linear, angular r3.Vecto
 		return wb.Stop(ctx, nil)
 	}
 
+	if kin := wb.holonomicKinematics(); kin != nil {
+		speeds := kin.wheelSpeeds(linear.X, linear.Y, rdkutils.DegToRad(angular.Z))
+		ctx, done := wb.opMgr.New(ctx)
+		defer done()
+		return wb.runWheels(ctx, speeds, 0)
+	}
+
 	leftRPM, rightRPM := wb.velocityMath(linear.Y, angular.Z)
 	// Passing zero revolutions to `motor.GoFor` will have the motor run until
 	// interrupted. Moreover, `motor.GoFor` will return immediately when given zero revolutions.
@@ -389,6 +461,7 @@ func (wb *wheeledBase) SetPower(ctx context.Context, linear, angular r3.Vector,
 	}
 
 	lPower, rPower := wb.differentialDrive(linear.Y, angular.Z)
+	kin := wb.holonomicKinematics()
 
 	// Send motor commands
 	setPowerFuncs := func() []rdkutils.SimpleFunc {
@@ -396,6 +469,14 @@ func (wb *wheeledBase) SetPower(ctx context.Context, linear, angular r3.Vector,
 
 		wb.mu.Lock()
 		defer wb.mu.Unlock()
+		if kin != nil {
+			powers := kin.wheelPowers(linear.X, linear.Y, angular.Z)
+			for i, m := range wb.wheels {
+				motor, power := m, powers[i]
+				ret = append(ret, func(ctx context.Context) error { return motor.SetPower(ctx, power, extra) })
+			}
+			return ret
+		}
 		for _, m := range wb.left {
 			motor := m
 			ret = append(ret, func(ctx context.Context) error { return motor.SetPower(ctx, lPower, extra) })
@@ -414,6 +495,13 @@ func (wb *wheeledBase) SetPower(ctx context.Context, linear, angular r3.Vector,
 	return nil
 }
 
+// holonomicKinematics returns the kinematics of a holonomic base, or nil for a differential one.
+func (wb *wheeledBase) holonomicKinematics() kinematics {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	return wb.kinematics
+}
+
 // returns rpm, revolutions for a spin motion.
 func (wb *wheeledBase) spinMath(angleDeg, degsPerSec float64) (float64, float64) {
 	wheelTravel := wb.spinSlipFactor * float64(wb.widthMm) * math.Pi * (angleDeg / 360.0)
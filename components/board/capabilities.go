@@ -0,0 +1,92 @@
+package board
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// GetCapabilitiesCommand is the DoCommand which returns the capabilities of a board, so that they can
+// be queried over the network. The board server answers it for any board which is a
+// CapabilityReporter.
+const GetCapabilitiesCommand = "get_capabilities"
+
+// Capabilities describes what a board offers, so that configs can be checked against the actual
+// hardware and users can be offered valid choices.
+type Capabilities struct {
+	// Model identifies the detected board, if known.
+	Model string `json:"model,omitempty"`
+	// GPIOPins are the names of the pins which can be used as GPIO pins and digital interrupts.
+	GPIOPins []string `json:"gpio_pins"`
+	// PWMPins are the GPIO pins with hardware PWM. A board may also support software PWM on its other
+	// GPIO pins.
+	PWMPins []string `json:"pwm_pins"`
+	// AnalogReaders are the names of the configured analog readers.
+	AnalogReaders []string `json:"analog_readers"`
+	// AnalogChannels are the inputs of the analog to digital converters found on the board, whether or
+	// not an analog reader is configured on them, in a form specific to the board.
+	AnalogChannels []string `json:"analog_channels"`
+	// DigitalInterrupts are the names of the configured digital interrupts.
+	DigitalInterrupts []string `json:"digital_interrupts"`
+	// I2CBuses and SPIBuses are the numbers of the buses found on the board.
+	I2CBuses []string `json:"i2c_buses"`
+	SPIBuses []string `json:"spi_buses"`
+}
+
+// A CapabilityReporter is a board which can report its capabilities.
+type CapabilityReporter interface {
+	Capabilities(ctx context.Context) (Capabilities, error)
+}
+
+// GetCapabilities returns the capabilities of a board. Boards which are not CapabilityReporters only
+// report their analog readers and digital interrupts.
+func GetCapabilities(ctx context.Context, b Board) (Capabilities, error) {
+	if reporter, ok := b.(CapabilityReporter); ok {
+		return reporter.Capabilities(ctx)
+	}
+	caps := Capabilities{
+		AnalogReaders:     b.AnalogReaderNames(),
+		DigitalInterrupts: b.DigitalInterruptNames(),
+	}
+	caps.Normalize()
+	return caps, nil
+}
+
+// Normalize sorts every list of the capabilities and replaces missing ones with empty lists.
+func (c *Capabilities) Normalize() {
+	for _, names := range []*[]string{
+		&c.GPIOPins, &c.PWMPins, &c.AnalogReaders, &c.AnalogChannels, &c.DigitalInterrupts, &c.I2CBuses, &c.SPIBuses,
+	} {
+		if *names == nil {
+			*names = []string{}
+		}
+		sort.Strings(*names)
+	}
+}
+
+func capabilitiesToMap(caps Capabilities) (map[string]interface{}, error) {
+	capsJSON, err := json.Marshal(caps)
+	if err != nil {
+		return nil, err
+	}
+	var capsMap map[string]interface{}
+	if err := json.Unmarshal(capsJSON, &capsMap); err != nil {
+		return nil, err
+	}
+	return capsMap, nil
+}
+
+func capabilitiesFromMap(capsMap map[string]interface{}) (Capabilities, error) {
+	var caps Capabilities
+	capsJSON, err := json.Marshal(capsMap)
+	if err != nil {
+		return caps, err
+	}
+	if err := json.Unmarshal(capsJSON, &caps); err != nil {
+		return caps, errors.Wrap(err, "invalid board capabilities")
+	}
+	caps.Normalize()
+	return caps, nil
+}
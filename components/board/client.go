@@ -181,6 +181,15 @@ func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map
 	return rprotoutils.DoFromResourceClient(ctx, c.client, c.info.name, cmd)
 }
 
+// Capabilities queries the capabilities of the board with DoCommand.
+func (c *client) Capabilities(ctx context.Context) (Capabilities, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetCapabilitiesCommand: true})
+	if err != nil {
+		return Capabilities{}, err
+	}
+	return capabilitiesFromMap(resp)
+}
+
 // WriteAnalog writes the analog value to the specified pin.
 func (c *client) WriteAnalog(ctx context.Context, pin string, value int32, extra map[string]interface{}) error {
 	ext, err := protoutils.StructToStructPb(extra)
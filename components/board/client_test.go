@@ -83,6 +83,29 @@ func TestWorkingClient(t *testing.T) {
 		test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
+		// Capabilities
+		injectBoard.CapabilitiesFunc = func(ctx context.Context) (board.Capabilities, error) {
+			return board.Capabilities{
+				Model:          "Raspberry Pi 4 Model B Rev 1.4",
+				GPIOPins:       []string{"37", "11"},
+				PWMPins:        []string{"12"},
+				AnalogChannels: []string{"0:24:1", "0:24:0"},
+				I2CBuses:       []string{"1"},
+			}, nil
+		}
+		caps, err := board.GetCapabilities(context.Background(), client)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, caps, test.ShouldResemble, board.Capabilities{
+			Model:             "Raspberry Pi 4 Model B Rev 1.4",
+			GPIOPins:          []string{"11", "37"},
+			PWMPins:           []string{"12"},
+			AnalogReaders:     []string{},
+			AnalogChannels:    []string{"0:24:0", "0:24:1"},
+			DigitalInterrupts: []string{},
+			I2CBuses:          []string{"1"},
+			SPIBuses:          []string{},
+		})
+
 		// Status
 		injectStatus := &commonpb.BoardStatus{}
 		injectBoard.StatusFunc = func(ctx context.Context, extra map[string]interface{}) (*commonpb.BoardStatus, error) {
@@ -207,6 +207,24 @@ func (b *Board) DigitalInterruptNames() []string {
 	return names
 }
 
+// Capabilities returns the configured analog readers and digital interrupts, and the GPIO pins used so
+// far, since a fake board has any pin it is asked for.
+func (b *Board) Capabilities(ctx context.Context) (board.Capabilities, error) {
+	caps := board.Capabilities{
+		Model:             model.Name,
+		AnalogReaders:     b.AnalogReaderNames(),
+		DigitalInterrupts: b.DigitalInterruptNames(),
+	}
+	b.mu.RLock()
+	for name := range b.GPIOPins {
+		caps.GPIOPins = append(caps.GPIOPins, name)
+	}
+	b.mu.RUnlock()
+	caps.PWMPins = append([]string(nil), caps.GPIOPins...)
+	caps.Normalize()
+	return caps, nil
+}
+
 // Status returns the current status of the board.
 func (b *Board) Status(ctx context.Context, extra map[string]interface{}) (*commonpb.BoardStatus, error) {
 	return board.CreateStatus(ctx, b, extra)
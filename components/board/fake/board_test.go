@@ -51,6 +51,31 @@ func TestFakeBoard(t *testing.T) {
 	test.That(t, int(status.DigitalInterrupts["b"].Value), test.ShouldEqual, 0)
 }
 
+func TestCapabilities(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{Name: "board1", ConvertedAttributes: &Config{
+		AnalogReaders:     []board.AnalogReaderConfig{{Name: "blue", Pin: "0"}},
+		DigitalInterrupts: []board.DigitalInterruptConfig{{Name: "i1", Pin: "35"}},
+	}}
+	b, err := NewBoard(context.Background(), cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = b.GPIOPinByName("12")
+	test.That(t, err, test.ShouldBeNil)
+
+	caps, err := board.GetCapabilities(context.Background(), b)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, caps, test.ShouldResemble, board.Capabilities{
+		Model:             "fake",
+		GPIOPins:          []string{"12"},
+		PWMPins:           []string{"12"},
+		AnalogReaders:     []string{"blue"},
+		AnalogChannels:    []string{},
+		DigitalInterrupts: []string{"i1"},
+		I2CBuses:          []string{},
+		SPIBuses:          []string{},
+	})
+}
+
 func TestConfigValidate(t *testing.T) {
 	validConfig := Config{}
 
@@ -55,6 +55,7 @@ func NewBoard(
 	b := &Board{
 		Named:         conf.ResourceName().AsNamed(),
 		convertConfig: convertConfig,
+		model:         conf.Model.Name,
 
 		logger:     logger,
 		cancelCtx:  cancelCtx,
@@ -198,11 +199,13 @@ func (b *Board) reconfigureGpios(newConf *LinuxBoardConfig) error {
 
 func (b *Board) reconfigureAnalogReaders(ctx context.Context, newConf *LinuxBoardConfig) error {
 	stillExists := map[string]struct{}{}
+	b.analogADCs = map[adc]struct{}{}
 	for _, c := range newConf.AnalogReaders {
 		channel, err := strconv.Atoi(c.Pin)
 		if err != nil {
 			return errors.Errorf("bad analog pin (%s)", c.Pin)
 		}
+		b.analogADCs[adc{spiBus: c.SPIBus, chipSelect: c.ChipSelect}] = struct{}{}
 
 		bus := buses.NewSpiBus(c.SPIBus)
 
@@ -379,10 +382,13 @@ type Board struct {
 	resource.Named
 	mu            sync.RWMutex
 	convertConfig ConfigConverter
+	model         string
 
 	gpioMappings  map[string]GPIOBoardMapping
 	analogReaders map[string]*wrappedAnalogReader
 	logger        logging.Logger
+	// analogADCs are the ADCs which the analog readers are on.
+	analogADCs map[adc]struct{}
 
 	gpios      map[string]*gpioPin
 	interrupts map[string]*digitalInterrupt
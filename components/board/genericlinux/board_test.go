@@ -8,6 +8,8 @@ package genericlinux
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"go.viam.com/test"
@@ -144,3 +146,52 @@ func TestNewBoard(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, gn2, test.ShouldNotBeNil)
 }
+
+func TestCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	oldDevDir, oldModelPath := devDir, deviceTreeModelPath
+	defer func() {
+		devDir, deviceTreeModelPath = oldDevDir, oldModelPath
+	}()
+	devDir = dir
+	deviceTreeModelPath = filepath.Join(dir, "model")
+	for _, name := range []string{"i2c-1", "i2c-10", "spidev0.0", "spidev0.1", "spidev1.0", "null"} {
+		test.That(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600), test.ShouldBeNil)
+	}
+
+	b := &Board{
+		model: "jetson",
+		gpioMappings: map[string]GPIOBoardMapping{
+			"11": {GPIOChipDev: "gpiochip0", GPIO: 17, PWMID: -1},
+			"32": {GPIOChipDev: "gpiochip0", GPIO: 12, PWMSysFsDir: "/sys/class/pwm/pwmchip0", PWMID: 0, HWPWMSupported: true},
+			// the PWM chip of this pin was not found
+			"33": {GPIOChipDev: "gpiochip0", GPIO: 13, PWMID: 1, HWPWMSupported: true},
+		},
+		analogReaders: map[string]*wrappedAnalogReader{"an": {}, "an2": {}},
+		interrupts:    map[string]*digitalInterrupt{},
+		logger:        logging.NewTestLogger(t),
+		// every channel of each ADC is reported, though only some are read
+		analogADCs: map[adc]struct{}{{spiBus: "0", chipSelect: "24"}: {}, {spiBus: "1", chipSelect: "26"}: {}},
+	}
+
+	caps, err := b.Capabilities(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, caps, test.ShouldResemble, board.Capabilities{
+		Model:             "jetson",
+		GPIOPins:          []string{"11", "32", "33"},
+		PWMPins:           []string{"32"},
+		AnalogReaders:     []string{"an", "an2"},
+		DigitalInterrupts: []string{},
+		I2CBuses:          []string{"1", "10"},
+		SPIBuses:          []string{"0", "1"},
+		AnalogChannels: []string{
+			"0:24:0", "0:24:1", "0:24:2", "0:24:3", "0:24:4", "0:24:5", "0:24:6", "0:24:7",
+			"1:26:0", "1:26:1", "1:26:2", "1:26:3", "1:26:4", "1:26:5", "1:26:6", "1:26:7",
+		},
+	})
+
+	test.That(t, os.WriteFile(deviceTreeModelPath, []byte("NVIDIA Jetson Nano Developer Kit\x00"), 0o600), test.ShouldBeNil)
+	caps, err = b.Capabilities(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, caps.Model, test.ShouldEqual, "NVIDIA Jetson Nano Developer Kit")
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package genericlinux
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/mcp3008helper"
+)
+
+// These are variables so that tests can point them at fake files.
+var (
+	deviceTreeModelPath = "/proc/device-tree/model"
+	devDir              = "/dev"
+)
+
+// adc is an MCP3008 ADC, which is identified by the SPI bus it is on and its chip select.
+type adc struct {
+	spiBus, chipSelect string
+}
+
+// Capabilities returns the pins of the board, the configured analog readers and digital interrupts,
+// and the I2C and SPI buses found in /dev. The analog channels are every input of the ADCs the analog
+// readers are on, named "<spi bus>:<chip select>:<channel>", since an MCP3008 can't be detected on
+// its SPI bus and so ADCs without readers are unknown.
+func (b *Board) Capabilities(ctx context.Context) (board.Capabilities, error) {
+	b.mu.RLock()
+	caps := board.Capabilities{
+		Model:             b.model,
+		AnalogReaders:     b.AnalogReaderNames(),
+		DigitalInterrupts: b.DigitalInterruptNames(),
+	}
+	for a := range b.analogADCs {
+		for channel := 0; channel < mcp3008helper.Channels; channel++ {
+			caps.AnalogChannels = append(caps.AnalogChannels, fmt.Sprintf("%s:%s:%d", a.spiBus, a.chipSelect, channel))
+		}
+	}
+	for name, mapping := range b.gpioMappings {
+		caps.GPIOPins = append(caps.GPIOPins, name)
+		if mapping.HWPWMSupported && mapping.PWMSysFsDir != "" {
+			caps.PWMPins = append(caps.PWMPins, name)
+		}
+	}
+	b.mu.RUnlock()
+
+	// the device tree names the actual hardware, such as "Raspberry Pi 4 Model B Rev 1.4"
+	//nolint:gosec
+	if model, err := os.ReadFile(deviceTreeModelPath); err == nil {
+		caps.Model = strings.TrimRight(string(model), "\x00\n")
+	}
+	caps.I2CBuses = busNumbers("i2c-*", "i2c-")
+	caps.SPIBuses = busNumbers("spidev*", "spidev")
+	caps.Normalize()
+	return caps, nil
+}
+
+// busNumbers returns the numbers of the buses whose devices match the pattern. SPI devices are named
+// spidevB.C, with a device for each chip select C of bus B.
+func busNumbers(pattern, prefix string) []string {
+	devices, err := filepath.Glob(filepath.Join(devDir, pattern))
+	if err != nil {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	var buses []string
+	for _, device := range devices {
+		bus, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(device), prefix), ".")
+		if _, ok := seen[bus]; ok || bus == "" {
+			continue
+		}
+		seen[bus] = struct{}{}
+		buses = append(buses, bus)
+	}
+	return buses
+}
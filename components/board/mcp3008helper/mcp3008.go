@@ -11,6 +11,9 @@ import (
 	"go.viam.com/rdk/resource"
 )
 
+// Channels is the number of single-ended inputs of an MCP3008 ADC.
+const Channels = 8
+
 // MCP3008AnalogReader implements a board.AnalogReader using an MCP3008 ADC via SPI.
 type MCP3008AnalogReader struct {
 	Channel int
@@ -8,6 +8,7 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/board/v1"
 	"go.viam.com/utils"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -257,9 +258,30 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if reporter, ok := b.(CapabilityReporter); ok {
+		if _, ok := req.GetCommand().AsMap()[GetCapabilitiesCommand]; ok {
+			return capabilitiesResponse(ctx, reporter)
+		}
+	}
 	return protoutils.DoFromResourceServer(ctx, b, req)
 }
 
+func capabilitiesResponse(ctx context.Context, reporter CapabilityReporter) (*commonpb.DoCommandResponse, error) {
+	caps, err := reporter.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capsMap, err := capabilitiesToMap(caps)
+	if err != nil {
+		return nil, err
+	}
+	result, err := structpb.NewStruct(capsMap)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: result}, nil
+}
+
 func (s *serviceServer) SetPowerMode(ctx context.Context,
 	req *pb.SetPowerModeRequest,
 ) (*pb.SetPowerModeResponse, error) {
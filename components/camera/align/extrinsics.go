@@ -218,6 +218,18 @@ func (cde *colorDepthExtrinsics) NextPointCloud(ctx context.Context) (pointcloud
 	return cde.projector.RGBDToPointCloud(alignedColor, alignedDepth)
 }
 
+// SourcedImages returns simultaneous color and depth images, both aligned to the frame of the color camera,
+// with the intrinsics which project them.
+func (cde *colorDepthExtrinsics) SourcedImages(ctx context.Context) ([]camera.SourcedImage, resource.ResponseMetadata, error) {
+	ctx, span := trace.StartSpan(ctx, "align::colorDepthExtrinsics::SourcedImages")
+	defer span.End()
+	col, dm, err := nextColorDepth(ctx, cde.color, cde.depth, cde.colorName, cde.depthName, cde.aligner)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return colorDepthImages(col, dm, cde.colorName, cde.colorName, cde.projector)
+}
+
 func (cde *colorDepthExtrinsics) Close(ctx context.Context) error {
 	return multierr.Combine(cde.color.Close(ctx), cde.depth.Close(ctx))
 }
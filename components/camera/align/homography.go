@@ -185,6 +185,18 @@ func (acd *colorDepthHomography) NextPointCloud(ctx context.Context) (pointcloud
 	return acd.projector.RGBDToPointCloud(alignedColor, alignedDepth)
 }
 
+// SourcedImages returns simultaneous color and depth images, both aligned to the frame of the color camera,
+// with the intrinsics which project them.
+func (acd *colorDepthHomography) SourcedImages(ctx context.Context) ([]camera.SourcedImage, resource.ResponseMetadata, error) {
+	ctx, span := trace.StartSpan(ctx, "align::colorDepthHomography::SourcedImages")
+	defer span.End()
+	col, dm, err := nextColorDepth(ctx, acd.color, acd.depth, acd.colorName, acd.depthName, acd.aligner)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return colorDepthImages(col, dm, acd.colorName, acd.colorName, acd.projector)
+}
+
 func (acd *colorDepthHomography) Close(ctx context.Context) error {
 	return multierr.Combine(acd.color.Close(ctx), acd.depth.Close(ctx))
 }
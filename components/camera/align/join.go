@@ -192,6 +192,34 @@ func (jcd *joinColorDepth) nextPointCloudFromImages(ctx context.Context) (pointc
 	return jcd.projector.RGBDToPointCloud(col, dm)
 }
 
+// SourcedImages returns simultaneous color and depth images, each in the frame of the camera it is from, with
+// the intrinsics which project them.
+func (jcd *joinColorDepth) SourcedImages(ctx context.Context) ([]camera.SourcedImage, resource.ResponseMetadata, error) {
+	ctx, span := trace.StartSpan(ctx, "align::joinColorDepth::SourcedImages")
+	defer span.End()
+	if jcd.colorName == jcd.depthName {
+		imgs, meta, err := camera.SourcedImages(ctx, jcd.underlyingCamera)
+		if err != nil {
+			return nil, resource.ResponseMetadata{}, errors.Wrapf(err, "could not call Images on underlying camera %q", jcd.colorName)
+		}
+		intrinsics, _ := jcd.projector.(*transform.PinholeCameraIntrinsics)
+		for i := range imgs {
+			if imgs[i].FrameName == "" {
+				imgs[i].FrameName = jcd.colorName
+			}
+			if imgs[i].Intrinsics == nil {
+				imgs[i].Intrinsics = intrinsics
+			}
+		}
+		return imgs, meta, nil
+	}
+	col, dm, err := nextColorDepth(ctx, jcd.color, jcd.depth, jcd.colorName, jcd.depthName, nil)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return colorDepthImages(col, dm, jcd.colorName, jcd.depthName, jcd.projector)
+}
+
 func (jcd *joinColorDepth) Close(ctx context.Context) error {
 	return multierr.Combine(jcd.color.Close(ctx), jcd.depth.Close(ctx))
 }
@@ -61,6 +61,13 @@ func TestJoinWithImages(t *testing.T) {
 	defer os.Remove(tempPCD.Name())
 	err = pointcloud.ToPCD(alignedPointCloud, tempPCD, pointcloud.PCDBinary)
 	test.That(t, err, test.ShouldBeNil)
+	imgs, _, err := camera.SourcedImages(context.Background(), joinCam)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, imgs, test.ShouldHaveLength, 2)
+	for _, img := range imgs {
+		test.That(t, img.FrameName, test.ShouldEqual, "intel")
+		test.That(t, img.Intrinsics, test.ShouldResemble, params)
+	}
 
 	test.That(t, joinCam.Close(context.Background()), test.ShouldBeNil)
 }
@@ -100,6 +107,14 @@ func TestJoin(t *testing.T) {
 	outDepth, ok := outImage.(*rimage.DepthMap)
 	test.That(t, ok, test.ShouldBeTrue)
 	test.That(t, outDepth, test.ShouldNotBeNil)
+	imgs, _, err := camera.SourcedImages(context.Background(), is)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, imgs, test.ShouldHaveLength, 2)
+	test.That(t, imgs[0].SourceName, test.ShouldEqual, "color")
+	test.That(t, imgs[0].FrameName, test.ShouldEqual, "color")
+	test.That(t, imgs[1].SourceName, test.ShouldEqual, "depth")
+	test.That(t, imgs[1].FrameName, test.ShouldEqual, "depth")
+	test.That(t, imgs[1].Intrinsics, test.ShouldResemble, joinConf.CameraParameters)
 
 	test.That(t, colorVideoSrc.Close(context.Background()), test.ShouldBeNil)
 	test.That(t, depthVideoSrc.Close(context.Background()), test.ShouldBeNil)
@@ -0,0 +1,55 @@
+package align
+
+import (
+	"context"
+	"image"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+)
+
+// nextColorDepth returns the next images from the color and the depth sources, aligned to the frame of the
+// color camera by the aligner if there is one.
+func nextColorDepth(
+	ctx context.Context,
+	color, depth gostream.VideoStream,
+	colorName, depthName string,
+	aligner transform.Aligner,
+) (image.Image, *rimage.DepthMap, error) {
+	col, dm := camera.SimultaneousColorDepthNext(ctx, color, depth)
+	if col == nil {
+		return nil, nil, errors.Errorf("could not get color image from source camera %q", colorName)
+	}
+	if dm == nil {
+		return nil, nil, errors.Errorf("could not get depth image from source camera %q", depthName)
+	}
+	if aligner == nil {
+		return col, dm, nil
+	}
+	alignedColor, alignedDepth, err := aligner.AlignColorAndDepthImage(rimage.ConvertImage(col), dm)
+	if err != nil {
+		return nil, nil, err
+	}
+	return alignedColor, alignedDepth, nil
+}
+
+// colorDepthImages returns the color and depth images as the images of an align camera, in the frames of
+// the cameras they are from and with the intrinsics which project them.
+func colorDepthImages(
+	col image.Image,
+	dm *rimage.DepthMap,
+	colorFrame, depthFrame string,
+	projector transform.Projector,
+) ([]camera.SourcedImage, resource.ResponseMetadata, error) {
+	intrinsics, _ := projector.(*transform.PinholeCameraIntrinsics)
+	return []camera.SourcedImage{
+		{NamedImage: camera.NamedImage{Image: col, SourceName: "color"}, FrameName: colorFrame, Intrinsics: intrinsics},
+		{NamedImage: camera.NamedImage{Image: dm, SourceName: "depth"}, FrameName: depthFrame, Intrinsics: intrinsics},
+	}, resource.ResponseMetadata{CapturedAt: time.Now()}, nil
+}
@@ -45,6 +45,10 @@ func init() {
 		API:        API,
 		MethodName: getImages.String(),
 	}, newGetImagesCollector)
+	data.RegisterCollector(data.MethodMetadata{
+		API:        API,
+		MethodName: streamImage.String(),
+	}, newStreamImageCollector)
 }
 
 // SubtypeName is a constant that identifies the camera resource subtype string.
@@ -139,6 +143,11 @@ type sourceBasedCamera struct {
 	logging.Logger
 }
 
+// SourcedImages returns the images of the video source along with where their sources are, if it can tell.
+func (cam *sourceBasedCamera) SourcedImages(ctx context.Context) ([]SourcedImage, resource.ResponseMetadata, error) {
+	return SourcedImages(ctx, cam.VideoSource)
+}
+
 // NewVideoSourceFromReader creates a VideoSource either with or without a projector. The stream type
 // argument is for detecting whether or not the resulting camera supports return
 // of pointcloud data in the absence of an implemented NextPointCloud function.
@@ -262,6 +271,13 @@ func (vs *videoSource) Images(ctx context.Context) ([]NamedImage, resource.Respo
 	if c, ok := vs.actualSource.(ImagesSource); ok {
 		return c.Images(ctx)
 	}
+	if c, ok := vs.actualSource.(SourcedImagesSource); ok {
+		imgs, meta, err := c.SourcedImages(ctx)
+		if err != nil {
+			return nil, resource.ResponseMetadata{}, err
+		}
+		return NamedImages(imgs), meta, nil
+	}
 	img, release, err := ReadImage(ctx, vs.videoSource)
 	if err != nil {
 		return nil, resource.ResponseMetadata{}, errors.Wrap(err, "videoSource: call to get Images failed")
@@ -275,6 +291,23 @@ func (vs *videoSource) Images(ctx context.Context) ([]NamedImage, resource.Respo
 	return []NamedImage{{img, ""}}, resource.ResponseMetadata{CapturedAt: ts}, nil
 }
 
+// SourcedImages returns the images of the underlying source along with where their sources are, if the
+// source can tell. Otherwise, the images of a camera with known intrinsics are returned with them.
+func (vs *videoSource) SourcedImages(ctx context.Context) ([]SourcedImage, resource.ResponseMetadata, error) {
+	if c, ok := vs.actualSource.(SourcedImagesSource); ok {
+		return c.SourcedImages(ctx)
+	}
+	imgs, meta, err := vs.Images(ctx)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	sourced := withoutSources(imgs)
+	if len(sourced) == 1 && vs.system != nil {
+		sourced[0].Intrinsics = vs.system.PinholeCameraIntrinsics
+	}
+	return sourced, meta, nil
+}
+
 // NextPointCloud returns the next PointCloud from the camera, or will error if not supported.
 func (vs *videoSource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
 	ctx, span := trace.StartSpan(ctx, "camera::videoSource::NextPointCloud")
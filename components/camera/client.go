@@ -13,6 +13,8 @@ import (
 	goutils "go.viam.com/utils"
 	goprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -193,14 +195,25 @@ func (c *client) Images(ctx context.Context) ([]NamedImage, resource.ResponseMet
 	ctx, span := trace.StartSpan(ctx, "camera::client::Images")
 	defer span.End()
 
+	images, meta, err := c.SourcedImages(ctx)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return NamedImages(images), meta, nil
+}
+
+// SourcedImages returns the images of the camera along with the frame names and intrinsics of their
+// sources, if the camera reports them.
+func (c *client) SourcedImages(ctx context.Context) ([]SourcedImage, resource.ResponseMetadata, error) {
+	var header metadata.MD
 	resp, err := c.client.GetImages(ctx, &pb.GetImagesRequest{
 		Name: c.name,
-	})
+	}, grpc.Header(&header))
 	if err != nil {
 		return nil, resource.ResponseMetadata{}, errors.Wrap(err, "camera client: could not gets images from the camera")
 	}
 
-	images := make([]NamedImage, 0, len(resp.Images))
+	images := make([]SourcedImage, 0, len(resp.Images))
 	// keep everything lazy encoded by default, if type is unknown, attempt to decode it
 	for _, img := range resp.Images {
 		var rdkImage image.Image
@@ -219,7 +232,10 @@ func (c *client) Images(ctx context.Context) ([]NamedImage, resource.ResponseMet
 				return nil, resource.ResponseMetadata{}, err
 			}
 		}
-		images = append(images, NamedImage{rdkImage, img.SourceName})
+		images = append(images, SourcedImage{NamedImage: NamedImage{rdkImage, img.SourceName}})
+	}
+	if err := applyImageSources(images, header); err != nil {
+		return nil, resource.ResponseMetadata{}, err
 	}
 	return images, resource.ResponseMetadataFromProto(resp.ResponseMetadata), nil
 }
@@ -490,6 +490,87 @@ func TestClientLazyImage(t *testing.T) {
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
 
+// sourcedImagesCamera is a camera which reports where the sources of its images are.
+type sourcedImagesCamera struct {
+	*inject.Camera
+	imgs []camera.SourcedImage
+}
+
+func (cam *sourcedImagesCamera) SourcedImages(ctx context.Context) ([]camera.SourcedImage, resource.ResponseMetadata, error) {
+	return cam.imgs, resource.ResponseMetadata{CapturedAt: time.UnixMilli(12345)}, nil
+}
+
+func TestClientSourcedImages(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener1, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger.AsZap(), rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 40, Height: 50, Fx: 200, Fy: 200, Ppx: 20, Ppy: 25}
+	sourcedCamera := &sourcedImagesCamera{
+		Camera: &inject.Camera{},
+		imgs: []camera.SourcedImage{
+			{NamedImage: camera.NamedImage{Image: rimage.NewImage(40, 50), SourceName: "color"}, FrameName: "rgb", Intrinsics: intrinsics},
+			{NamedImage: camera.NamedImage{Image: rimage.NewEmptyDepthMap(40, 50), SourceName: "depth"}, FrameName: "tof"},
+		},
+	}
+	plainCamera := &inject.Camera{}
+	plainCamera.ImagesFunc = func(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+		return []camera.NamedImage{{rimage.NewImage(40, 50), "color"}}, resource.ResponseMetadata{}, nil
+	}
+
+	resources := map[resource.Name]camera.Camera{
+		camera.Named(testCameraName):  sourcedCamera,
+		camera.Named(depthCameraName): plainCamera,
+	}
+	cameraSvc, err := resource.NewAPIResourceCollection(camera.API, resources)
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[camera.Camera](camera.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, cameraSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener1)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+
+	t.Run("sources are sent", func(t *testing.T) {
+		client, err := camera.NewClientFromConn(context.Background(), conn, "", camera.Named(testCameraName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		imgs, meta, err := camera.SourcedImages(context.Background(), client)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, meta.CapturedAt, test.ShouldEqual, time.UnixMilli(12345))
+		test.That(t, imgs, test.ShouldHaveLength, 2)
+		test.That(t, imgs[0].SourceName, test.ShouldEqual, "color")
+		test.That(t, imgs[0].FrameName, test.ShouldEqual, "rgb")
+		test.That(t, imgs[0].Intrinsics, test.ShouldResemble, intrinsics)
+		test.That(t, imgs[1].SourceName, test.ShouldEqual, "depth")
+		test.That(t, imgs[1].FrameName, test.ShouldEqual, "tof")
+		test.That(t, imgs[1].Intrinsics, test.ShouldBeNil)
+
+		named, _, err := client.Images(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, named, test.ShouldHaveLength, 2)
+		test.That(t, named[1].SourceName, test.ShouldEqual, "depth")
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		client, err := camera.NewClientFromConn(context.Background(), conn, "", camera.Named(depthCameraName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		imgs, _, err := camera.SourcedImages(context.Background(), client)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, imgs, test.ShouldHaveLength, 1)
+		test.That(t, imgs[0].FrameName, test.ShouldEqual, "")
+		test.That(t, imgs[0].Intrinsics, test.ShouldBeNil)
+	})
+}
+
 func TestClientWithInterceptor(t *testing.T) {
 	// Set up gRPC server
 	logger := logging.NewTestLogger(t)
@@ -3,10 +3,12 @@ package camera
 import (
 	"bytes"
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	pb "go.viam.com/api/component/camera/v1"
+	goutils "go.viam.com/utils"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
@@ -22,6 +24,7 @@ const (
 	nextPointCloud method = iota
 	readImage
 	getImages
+	streamImage
 )
 
 func (m method) String() string {
@@ -32,6 +35,8 @@ func (m method) String() string {
 		return "ReadImage"
 	case getImages:
 		return "GetImages"
+	case streamImage:
+		return "StreamImage"
 	}
 	return "Unknown"
 }
@@ -126,6 +131,59 @@ func newReadImageCollector(resource interface{}, params data.CollectorParams) (d
 	return data.NewCollector(cFunc, params)
 }
 
+// newStreamImageCollector captures the frames of the camera's stream as they arrive, instead of reading an image at
+// every interval, so that each frame is captured at most once. Frames don't carry the time they were captured, so
+// each is stamped with the time it arrived on the collector's clock.
+func newStreamImageCollector(resource interface{}, params data.CollectorParams) (data.Collector, error) {
+	camera, err := assertCamera(resource)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := utils.MimeTypeRawRGBA
+	if mimeTypeParam := params.MethodParams["mime_type"]; mimeTypeParam != nil {
+		mimeStr := new(wrapperspb.StringValue)
+		if err := mimeTypeParam.UnmarshalTo(mimeStr); err != nil {
+			return nil, err
+		}
+		mimeType = mimeStr.Value
+	}
+
+	sFunc := data.StreamCaptureFunc(func(
+		ctx context.Context,
+		_ map[string]*anypb.Any,
+		push func(timeCaptured time.Time, reading func() (interface{}, error)),
+	) error {
+		ctx, span := trace.StartSpan(ctx, "camera::data::collector::StreamCaptureFunc::StreamImage")
+		defer span.End()
+
+		ctx = context.WithValue(ctx, data.FromDMContextKey{}, true)
+
+		stream, err := camera.Stream(ctx)
+		if err != nil {
+			return data.FailedToReadErr(params.ComponentName, streamImage.String(), err)
+		}
+		defer func() {
+			goutils.UncheckedError(stream.Close(context.Background()))
+		}()
+		for {
+			img, release, err := stream.Next(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return data.FailedToReadErr(params.ComponentName, streamImage.String(), err)
+			}
+			push(time.Time{}, func() (interface{}, error) {
+				return rimage.EncodeImage(ctx, img, mimeType)
+			})
+			if release != nil {
+				release()
+			}
+		}
+	})
+	return data.NewStreamCollector(sFunc, params)
+}
+
 func newGetImagesCollector(resource interface{}, params data.CollectorParams) (data.Collector, error) {
 	camera, err := assertCamera(resource)
 	if err != nil {
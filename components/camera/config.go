@@ -12,6 +12,7 @@ const (
 	UnspecifiedStream = ImageType("")
 	ColorStream       = ImageType("color")
 	DepthStream       = ImageType("depth")
+	ThermalStream     = ImageType("thermal")
 )
 
 // NewUnsupportedImageTypeError is when the stream type is unknown.
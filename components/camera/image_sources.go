@@ -0,0 +1,113 @@
+package camera
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+)
+
+// ImageSourcesMetadataKey is the gRPC response header of GetImages which carries the frame name and
+// intrinsics of every image, in the order of the images, since the image messages cannot.
+const ImageSourcesMetadataKey = "viam-image-sources"
+
+// SourcedImage is an image along with where its source is, for cameras whose imagers are in different
+// places.
+type SourcedImage struct {
+	NamedImage
+	// FrameName is the name of the reference frame of the source. It is empty if the source is in the
+	// frame of the camera itself.
+	FrameName string
+	// Intrinsics are the intrinsic parameters of the source, if known, so that its image can be
+	// projected without getting the properties of the camera.
+	Intrinsics *transform.PinholeCameraIntrinsics
+}
+
+// A SourcedImagesSource is a source which can tell where each of the simultaneous images it returns
+// comes from.
+type SourcedImagesSource interface {
+	SourcedImages(ctx context.Context) ([]SourcedImage, resource.ResponseMetadata, error)
+}
+
+// SourcedImages returns simultaneous images from the camera, as Images does, along with the frame names
+// and intrinsics of their sources if the camera reports them.
+func SourcedImages(ctx context.Context, cam VideoSource) ([]SourcedImage, resource.ResponseMetadata, error) {
+	if src, ok := cam.(SourcedImagesSource); ok {
+		return src.SourcedImages(ctx)
+	}
+	imgs, meta, err := cam.Images(ctx)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return withoutSources(imgs), meta, nil
+}
+
+// withoutSources returns the images as sourced images whose sources are in the frame of the camera.
+func withoutSources(imgs []NamedImage) []SourcedImage {
+	sourced := make([]SourcedImage, 0, len(imgs))
+	for _, img := range imgs {
+		sourced = append(sourced, SourcedImage{NamedImage: img})
+	}
+	return sourced
+}
+
+// NamedImages returns the images without their sources.
+func NamedImages(imgs []SourcedImage) []NamedImage {
+	named := make([]NamedImage, 0, len(imgs))
+	for _, img := range imgs {
+		named = append(named, img.NamedImage)
+	}
+	return named
+}
+
+// imageSource is what is sent about the source of an image alongside it.
+type imageSource struct {
+	FrameName  string                             `json:"frame_name,omitempty"`
+	Intrinsics *transform.PinholeCameraIntrinsics `json:"intrinsics,omitempty"`
+}
+
+// imageSourcesMetadata returns the header describing the sources of the images, or nil if none of
+// them have a frame name or intrinsics, so that nothing is sent for cameras with a single imager.
+func imageSourcesMetadata(imgs []SourcedImage) (metadata.MD, error) {
+	sources := make([]string, 0, len(imgs))
+	described := false
+	for _, img := range imgs {
+		if img.FrameName != "" || img.Intrinsics != nil {
+			described = true
+		}
+		source, err := json.Marshal(imageSource{FrameName: img.FrameName, Intrinsics: img.Intrinsics})
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, string(source))
+	}
+	if !described {
+		return nil, nil
+	}
+	return metadata.MD{ImageSourcesMetadataKey: sources}, nil
+}
+
+// applyImageSources sets the frame names and intrinsics of the images from the header describing
+// their sources, if there is one.
+func applyImageSources(imgs []SourcedImage, md metadata.MD) error {
+	sources := md.Get(ImageSourcesMetadataKey)
+	if len(sources) == 0 {
+		return nil
+	}
+	if len(sources) != len(imgs) {
+		return errors.Errorf("got sources of %d images for %d images", len(sources), len(imgs))
+	}
+	for i, source := range sources {
+		var src imageSource
+		if err := json.Unmarshal([]byte(source), &src); err != nil {
+			return errors.Wrapf(err, "invalid source of image %q", imgs[i].SourceName)
+		}
+		imgs[i].FrameName = src.FrameName
+		imgs[i].Intrinsics = src.Intrinsics
+	}
+	return nil
+}
@@ -4,5 +4,6 @@ package register
 import (
 	// for cameras.
 	_ "go.viam.com/rdk/components/camera/fake"
+	_ "go.viam.com/rdk/components/camera/gstreamer"
 	_ "go.viam.com/rdk/components/camera/transformpipeline"
 )
@@ -10,6 +10,7 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/camera/v1"
 	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
 
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
@@ -65,6 +66,8 @@ func (s *serviceServer) GetImage(
 			req.MimeType = utils.MimeTypeJPEG
 		case DepthStream:
 			req.MimeType = utils.MimeTypeRawDepth
+		case ThermalStream:
+			req.MimeType = utils.MimeTypeRawThermal
 		default:
 			req.MimeType = utils.MimeTypeJPEG
 		}
@@ -109,7 +112,7 @@ func (s *serviceServer) GetImages(
 	}
 	// request the images, and then check to see what the underlying type is to determine
 	// what to encode as. If it's color, just encode as JPEG.
-	imgs, metadata, err := cam.Images(ctx)
+	imgs, metadata, err := SourcedImages(ctx, cam)
 	if err != nil {
 		return nil, errors.Wrap(err, "camera server GetImages could not call Images on the camera")
 	}
@@ -126,6 +129,9 @@ func (s *serviceServer) GetImages(
 		}
 		imagesMessage = append(imagesMessage, imgMes)
 	}
+	if err := setImageSourcesHeader(ctx, imgs); err != nil {
+		return nil, errors.Wrap(err, "camera server GetImages could not send the sources of the images")
+	}
 	// right now the only metadata is timestamp
 	resp := &pb.GetImagesResponse{
 		Images:           imagesMessage,
@@ -135,6 +141,19 @@ func (s *serviceServer) GetImages(
 	return resp, nil
 }
 
+// setImageSourcesHeader sends the frame names and intrinsics of the images in the response header, if
+// there is one.
+func setImageSourcesHeader(ctx context.Context, imgs []SourcedImage) error {
+	if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+		md, err := imageSourcesMetadata(imgs)
+		if err != nil || md == nil {
+			return err
+		}
+		return grpc.SetHeader(ctx, md)
+	}
+	return nil
+}
+
 func encodeImageFromUnderlyingType(ctx context.Context, img image.Image) (pb.Format, []byte, error) {
 	switch v := img.(type) {
 	case *rimage.LazyEncodedImage:
@@ -158,6 +177,14 @@ func encodeImageFromUnderlyingType(ctx context.Context, img image.Image) (pb.For
 			return pb.Format_FORMAT_UNSPECIFIED, nil, err
 		}
 		return format, outBytes, nil
+	case *rimage.ThermalImage:
+		// there is no format for thermal images, so they are sent with their header for the client to
+		// recognize them
+		outBytes, err := rimage.EncodeImage(ctx, v, utils.MimeTypeRawThermal)
+		if err != nil {
+			return pb.Format_FORMAT_UNSPECIFIED, nil, err
+		}
+		return pb.Format_FORMAT_UNSPECIFIED, outBytes, nil
 	case *image.Gray16:
 		format := pb.Format_FORMAT_PNG
 		outBytes, err := rimage.EncodeImage(ctx, v, utils.MimeTypePNG)
@@ -101,6 +101,8 @@ func newTransformPipeline(
 		streamType = camera.DepthStream
 	} else if _, ok := img.(*image.Gray16); ok {
 		streamType = camera.DepthStream
+	} else if _, ok := img.(*rimage.ThermalImage); ok {
+		streamType = camera.ThermalStream
 	} else {
 		streamType = camera.ColorStream
 	}
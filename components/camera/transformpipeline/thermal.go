@@ -0,0 +1,100 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/utils"
+)
+
+// thermalPrettyConfig are the attributes for a thermal_to_pretty transform.
+type thermalPrettyConfig struct {
+	Colormap   string   `json:"colormap,omitempty"`
+	MinCelsius *float64 `json:"min_celsius,omitempty"`
+	MaxCelsius *float64 `json:"max_celsius,omitempty"`
+}
+
+// thermalToPretty takes a thermal image and renders it with a colormap, so that it can be streamed.
+// Without a fixed range of temperatures, the colormap spans the temperatures of every image.
+type thermalToPretty struct {
+	originalStream gostream.VideoStream
+	colormap       rimage.Colormap
+	minCelsius     *float64
+	maxCelsius     *float64
+}
+
+func newThermalToPrettyTransform(
+	ctx context.Context,
+	source gostream.VideoSource,
+	stream camera.ImageType,
+	am utils.AttributeMap,
+) (gostream.VideoSource, camera.ImageType, error) {
+	if stream != camera.ThermalStream {
+		return nil, camera.UnspecifiedStream,
+			errors.Errorf("source has stream type %s, thermal_to_pretty only supports thermal stream inputs", stream)
+	}
+	conf, err := resource.TransformAttributeMap[*thermalPrettyConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	colormap, err := rimage.ColormapByName(conf.Colormap)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	if (conf.MinCelsius == nil) != (conf.MaxCelsius == nil) {
+		return nil, camera.UnspecifiedStream, errors.New("thermal_to_pretty needs both min_celsius and max_celsius, or neither")
+	}
+	if conf.MinCelsius != nil && *conf.MinCelsius >= *conf.MaxCelsius {
+		return nil, camera.UnspecifiedStream, errors.New("thermal_to_pretty min_celsius must be less than max_celsius")
+	}
+	props, err := propsFromVideoSource(ctx, source)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	var cameraModel transform.PinholeCameraModel
+	cameraModel.PinholeCameraIntrinsics = props.IntrinsicParams
+
+	if props.DistortionParams != nil {
+		cameraModel.Distortion = props.DistortionParams
+	}
+	reader := &thermalToPretty{
+		originalStream: gostream.NewEmbeddedVideoStream(source),
+		colormap:       colormap,
+		minCelsius:     conf.MinCelsius,
+		maxCelsius:     conf.MaxCelsius,
+	}
+	src, err := camera.NewVideoSourceFromReader(ctx, reader, &cameraModel, camera.ColorStream)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	return src, camera.ColorStream, err
+}
+
+func (ttp *thermalToPretty) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::thermalToPretty::Read")
+	defer span.End()
+	i, release, err := ttp.originalStream.Next(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ti, err := rimage.ConvertImageToThermalImage(ctx, i)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "source camera does not make thermal images")
+	}
+	if ttp.minCelsius != nil {
+		return ti.ColorizeRange(ttp.colormap, *ttp.minCelsius, *ttp.maxCelsius), release, nil
+	}
+	return ti.Colorize(ttp.colormap), release, nil
+}
+
+func (ttp *thermalToPretty) Close(ctx context.Context) error {
+	return ttp.originalStream.Close(ctx)
+}
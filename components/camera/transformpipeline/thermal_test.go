@@ -0,0 +1,61 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+func TestThermalToPretty(t *testing.T) {
+	ti := rimage.NewEmptyThermalImage(4, 2, rimage.CentikelvinScale)
+	ti.Set(0, 0, rimage.CentikelvinScale.Reading(20))
+	ti.Set(3, 1, rimage.CentikelvinScale.Reading(40))
+	for _, p := range []image.Point{{1, 0}, {2, 0}, {3, 0}, {0, 1}, {1, 1}, {2, 1}} {
+		ti.Set(p.X, p.Y, rimage.CentikelvinScale.Reading(30))
+	}
+	reader := gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		return ti, func() {}, nil
+	})
+	source, err := camera.NewVideoSourceFromReader(context.Background(), reader, nil, camera.ThermalStream)
+	test.That(t, err, test.ShouldBeNil)
+	defer source.Close(context.Background())
+
+	_, _, err = newThermalToPrettyTransform(context.Background(), source, camera.DepthStream, utils.AttributeMap{})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "only supports thermal stream inputs")
+	_, _, err = newThermalToPrettyTransform(context.Background(), source, camera.ThermalStream,
+		utils.AttributeMap{"colormap": "plasma"})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown colormap")
+	_, _, err = newThermalToPrettyTransform(context.Background(), source, camera.ThermalStream,
+		utils.AttributeMap{"min_celsius": 10.0})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "both min_celsius and max_celsius")
+
+	pretty, stream, err := newThermalToPrettyTransform(context.Background(), source, camera.ThermalStream,
+		utils.AttributeMap{"colormap": "gray"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.ColorStream)
+	img, _, err := camera.ReadImage(context.Background(), pretty)
+	test.That(t, err, test.ShouldBeNil)
+	colored := rimage.ConvertImage(img)
+	test.That(t, colored.GetXY(0, 0), test.ShouldEqual, rimage.NewColor(0, 0, 0))
+	test.That(t, colored.GetXY(1, 0), test.ShouldEqual, rimage.NewColor(128, 128, 128))
+	test.That(t, colored.GetXY(3, 1), test.ShouldEqual, rimage.NewColor(255, 255, 255))
+	test.That(t, pretty.Close(context.Background()), test.ShouldBeNil)
+
+	pretty, _, err = newThermalToPrettyTransform(context.Background(), source, camera.ThermalStream,
+		utils.AttributeMap{"colormap": "gray", "min_celsius": 30.0, "max_celsius": 35.0})
+	test.That(t, err, test.ShouldBeNil)
+	defer pretty.Close(context.Background())
+	img, _, err = camera.ReadImage(context.Background(), pretty)
+	test.That(t, err, test.ShouldBeNil)
+	colored = rimage.ConvertImage(img)
+	test.That(t, colored.GetXY(0, 0), test.ShouldEqual, rimage.NewColor(0, 0, 0))
+	test.That(t, colored.GetXY(1, 0), test.ShouldEqual, rimage.NewColor(0, 0, 0))
+	test.That(t, colored.GetXY(3, 1), test.ShouldEqual, rimage.NewColor(255, 255, 255))
+}
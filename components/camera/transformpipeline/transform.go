@@ -23,6 +23,7 @@ const (
 	transformTypeResize          = transformType("resize")
 	transformTypeCrop            = transformType("crop")
 	transformTypeDepthPretty     = transformType("depth_to_pretty")
+	transformTypeThermalPretty   = transformType("thermal_to_pretty")
 	transformTypeOverlay         = transformType("overlay")
 	transformTypeUndistort       = transformType("undistort")
 	transformTypeDetections      = transformType("detections")
@@ -75,6 +76,11 @@ var registeredTransformConfigs = map[transformType]*transformRegistration{
 		&depthPrettyConfig{},
 		"Turns a depth image source into a colorful image, with blue indicating distant points and red indicating nearby points.",
 	},
+	transformTypeThermalPretty: {
+		string(transformTypeThermalPretty),
+		&thermalPrettyConfig{},
+		"Turns a thermal image source into a colorful image with a colormap, over a fixed or each image's range of temperatures.",
+	},
 	transformTypeOverlay: {
 		string(transformTypeOverlay),
 		&overlayConfig{},
@@ -153,6 +159,8 @@ func buildTransform(
 		return newCropTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeDepthPretty:
 		return newDepthToPrettyTransform(ctx, source, stream)
+	case transformTypeThermalPretty:
+		return newThermalToPrettyTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeOverlay:
 		return newOverlayTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeUndistort:
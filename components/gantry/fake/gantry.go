@@ -4,44 +4,72 @@ package fake
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
+	"time"
 
 	"github.com/golang/geo/r3"
 
 	"go.viam.com/rdk/components/gantry"
+	"go.viam.com/rdk/internal/simulation"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils"
 )
 
+const defaultSpeedMmPerSec = 120
+
 func init() {
 	resource.RegisterComponent(
 		gantry.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[gantry.Gantry, resource.NoNativeConfig]{
+		resource.Registration[gantry.Gantry, *Config]{
 			Constructor: func(
 				ctx context.Context,
 				_ resource.Dependencies,
 				conf resource.Config,
 				logger logging.Logger,
 			) (gantry.Gantry, error) {
-				return NewGantry(conf.ResourceName(), logger), nil
+				newConf, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				g := newGantry(conf.ResourceName(), logger)
+				g.simulate = newConf.Simulate
+				return g, nil
 			},
 		})
 }
 
+// Config is used for converting config attributes.
+type Config struct {
+	// Simulate makes moves take as long as they would at the requested speeds, reporting the
+	// positions along the way, instead of completing instantly.
+	Simulate bool `json:"simulate,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	return nil, nil
+}
+
 // NewGantry returns a new fake gantry.
 func NewGantry(name resource.Name, logger logging.Logger) gantry.Gantry {
+	return newGantry(name, logger)
+}
+
+func newGantry(name resource.Name, logger logging.Logger) *Gantry {
 	return &Gantry{
-		testutils.NewUnimplementedResource(name),
-		resource.TriviallyReconfigurable{},
-		resource.TriviallyCloseable{},
-		[]float64{1.2},
-		[]float64{120},
-		[]float64{5},
-		2,
-		r3.Vector{X: 1, Y: 0, Z: 0},
-		logger,
+		Named:          testutils.NewUnimplementedResource(name),
+		positionsMm:    []float64{1.2},
+		speedsMmPerSec: []float64{defaultSpeedMmPerSec},
+		lengths:        []float64{5},
+		lengthMeters:   2,
+		frame:          r3.Vector{X: 1, Y: 0, Z: 0},
+		opMgr:          operation.NewSingleOperationManager(),
+		logger:         logger,
 	}
 }
 
@@ -50,16 +78,21 @@ type Gantry struct {
 	resource.Named
 	resource.TriviallyReconfigurable
 	resource.TriviallyCloseable
+	mu             sync.Mutex
 	positionsMm    []float64
 	speedsMmPerSec []float64
 	lengths        []float64
 	lengthMeters   float64
 	frame          r3.Vector
+	simulate       bool
+	opMgr          *operation.SingleOperationManager
 	logger         logging.Logger
 }
 
 // Position returns the position in meters.
 func (g *Gantry) Position(ctx context.Context, extra map[string]interface{}) ([]float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	return g.positionsMm, nil
 }
 
@@ -74,21 +107,50 @@ func (g *Gantry) Home(ctx context.Context, extra map[string]interface{}) (bool,
 	return true, nil
 }
 
-// MoveToPosition is in meters.
+// MoveToPosition is in meters. When simulating, every axis moves at its speed and they all arrive at
+// once, so the move takes as long as the slowest axis needs.
 func (g *Gantry) MoveToPosition(ctx context.Context, positionsMm, speedsMmPerSec []float64, extra map[string]interface{}) error {
-	g.positionsMm = positionsMm
+	ctx, done := g.opMgr.New(ctx)
+	defer done()
+
+	g.mu.Lock()
+	from := g.positionsMm
 	g.speedsMmPerSec = speedsMmPerSec
-	return nil
+	if !g.simulate || len(from) != len(positionsMm) {
+		g.positionsMm = positionsMm
+		g.mu.Unlock()
+		return nil
+	}
+	g.mu.Unlock()
+
+	var duration time.Duration
+	for i, to := range positionsMm {
+		speed := float64(defaultSpeedMmPerSec)
+		if i < len(speedsMmPerSec) && speedsMmPerSec[i] > 0 {
+			speed = speedsMmPerSec[i]
+		}
+		duration = time.Duration(math.Max(float64(duration), math.Abs(to-from[i])/speed*float64(time.Second)))
+	}
+	return simulation.Move(ctx, duration, func(fraction float64) {
+		positions := make([]float64, len(from))
+		for i, to := range positionsMm {
+			positions[i] = from[i] + (to-from[i])*fraction
+		}
+		g.mu.Lock()
+		g.positionsMm = positions
+		g.mu.Unlock()
+	})
 }
 
-// Stop doesn't do anything for a fake gantry.
+// Stop stops a simulated move where it is, and the move returns an error.
 func (g *Gantry) Stop(ctx context.Context, extra map[string]interface{}) error {
+	g.opMgr.CancelRunning(ctx)
 	return nil
 }
 
-// IsMoving is always false for a fake gantry.
+// IsMoving returns whether the gantry is in the middle of a move.
 func (g *Gantry) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	return g.opMgr.OpRunning(), nil
 }
 
 // ModelFrame returns a Gantry frame.
@@ -113,8 +175,16 @@ func (g *Gantry) CurrentInputs(ctx context.Context) ([]referenceframe.Input, err
 
 // GoToInputs moves using the Gantry frames..
 func (g *Gantry) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	opCtx, done := g.opMgr.New(ctx)
+	defer done()
 	for _, goal := range inputSteps {
-		err := g.MoveToPosition(ctx, referenceframe.InputsToFloats(goal), g.speedsMmPerSec, nil)
+		if err := opCtx.Err(); err != nil {
+			return err
+		}
+		g.mu.Lock()
+		speeds := g.speedsMmPerSec
+		g.mu.Unlock()
+		err := g.MoveToPosition(opCtx, referenceframe.InputsToFloats(goal), speeds, nil)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,51 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/gantry"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestSimulatedMove(t *testing.T) {
+	ctx := context.Background()
+	g := newGantry(gantry.Named("gantry"), logging.NewTestLogger(t))
+	g.simulate = true
+
+	// moving 100mm at 500mm/s takes 200ms
+	start := time.Now()
+	test.That(t, g.MoveToPosition(ctx, []float64{101.2}, []float64{500}, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 200*time.Millisecond)
+	pos, err := g.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldResemble, []float64{101.2})
+
+	// intermediate positions are reported while moving, and stopping leaves the gantry where it is
+	done := make(chan error, 1)
+	utils.PanicCapturingGo(func() {
+		done <- g.GoToInputs(ctx, referenceframe.FloatsToInputs([]float64{1000}), referenceframe.FloatsToInputs([]float64{0}))
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		pos, err := g.Position(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, pos[0], test.ShouldBeBetween, 101.2, 1000)
+		moving, err := g.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, g.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-done, test.ShouldBeError, context.Canceled)
+	pos, err = g.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos[0], test.ShouldBeBetween, 101.2, 1000)
+	moving, err := g.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
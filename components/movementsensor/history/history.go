@@ -0,0 +1,379 @@
+// Package history implements a movement sensor which records the readings of another movement sensor
+// at a high rate, keeping the most recent ones so that they can be fetched on demand, for example to
+// find out what happened in the seconds before a collision even when data capture runs at a low rate.
+package history
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var model = resource.DefaultModelFamily.WithModel("history")
+
+const (
+	defaultRateHz    = 50
+	defaultBufferSec = 10
+	maxRateHz        = 1000
+	// maxBufferedReadings bounds rate_hz * buffer_sec, which is how many readings are kept in memory.
+	maxBufferedReadings = 100000
+)
+
+// The DoCommand which returns the recorded readings, and its keys.
+const (
+	// GetHistoryCommand returns the recorded readings, oldest first. Without a start or an end, it
+	// returns all of them.
+	GetHistoryCommand = "get_history"
+	// StartKey and EndKey are the times, in RFC 3339 format, between which to return the readings.
+	StartKey = "start"
+	EndKey   = "end"
+	// LastSecKey returns the readings of the given number of seconds up to now instead.
+	LastSecKey = "last_sec"
+	// HistoryKey is the list of readings in the result, each with its time and readings.
+	HistoryKey  = "history"
+	TimeKey     = "time"
+	ReadingsKey = "readings"
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	MovementSensor string `json:"movement_sensor"`
+	// RateHz is how often the readings are recorded, and BufferSec is how long they are kept.
+	RateHz    float64 `json:"rate_hz,omitempty"`
+	BufferSec float64 `json:"buffer_sec,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.MovementSensor == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "movement_sensor")
+	}
+	if conf.RateHz < 0 || conf.BufferSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("rate_hz and buffer_sec cannot be negative"))
+	}
+	rateHz, bufferSec := conf.rateHzAndBufferSec()
+	if rateHz > maxRateHz {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("rate_hz must be at most %d", maxRateHz))
+	}
+	if rateHz*bufferSec > maxBufferedReadings {
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("rate_hz * buffer_sec must be at most %d readings", maxBufferedReadings))
+	}
+	return []string{conf.MovementSensor}, nil
+}
+
+// rateHzAndBufferSec returns rate_hz and buffer_sec, or their defaults if unset.
+func (conf *Config) rateHzAndBufferSec() (float64, float64) {
+	rateHz, bufferSec := conf.RateHz, conf.BufferSec
+	if rateHz == 0 {
+		rateHz = defaultRateHz
+	}
+	if bufferSec == 0 {
+		bufferSec = defaultBufferSec
+	}
+	return rateHz, bufferSec
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API,
+		model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{Constructor: newHistory})
+}
+
+// Reading is the readings of a movement sensor at a point in time.
+type Reading struct {
+	Time     time.Time
+	Readings map[string]interface{}
+}
+
+type history struct {
+	resource.Named
+	logger logging.Logger
+
+	mu       sync.Mutex
+	ms       movementsensor.MovementSensor
+	interval time.Duration
+	buffer   *ringBuffer
+
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newHistory(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	movementsensor.MovementSensor, error,
+) {
+	h := &history{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+		cancel: func() {},
+	}
+	if err := h.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Reconfigure records the readings of the new movement sensor at the new rate, keeping the most recent
+// readings which fit in the new buffer so that a rebuild doesn't lose what happened before it.
+func (h *history) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	ms, err := movementsensor.FromDependencies(deps, newConf.MovementSensor)
+	if err != nil {
+		return err
+	}
+	rateHz, bufferSec := newConf.rateHzAndBufferSec()
+	size := int(rateHz * bufferSec)
+	if size < 1 {
+		size = 1
+	}
+
+	h.cancel()
+	h.activeBackgroundWorkers.Wait()
+
+	h.mu.Lock()
+	h.ms = ms
+	h.interval = time.Duration(float64(time.Second) / rateHz)
+	if h.buffer == nil {
+		h.buffer = newRingBuffer(size)
+	} else {
+		h.buffer = h.buffer.resized(size)
+	}
+	interval := h.interval
+	h.mu.Unlock()
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer h.activeBackgroundWorkers.Done()
+		h.record(cancelCtx, ms, interval)
+	})
+	return nil
+}
+
+// record adds the readings of the movement sensor to the buffer at every interval.
+func (h *history) record(ctx context.Context, ms movementsensor.MovementSensor, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		readings, err := ms.Readings(ctx, nil)
+		if err != nil {
+			if ctx.Err() == nil {
+				h.logger.CDebugw(ctx, "failed to record readings", "error", err)
+			}
+			continue
+		}
+		h.mu.Lock()
+		h.buffer.add(Reading{Time: time.Now(), Readings: readings})
+		h.mu.Unlock()
+	}
+}
+
+// History returns the readings recorded between start and end, oldest first. A zero start or end
+// leaves that side of the range open.
+func (h *history) History(start, end time.Time) []Reading {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.buffer.between(start, end)
+}
+
+func (h *history) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[GetHistoryCommand]; !ok {
+		return h.sensor().DoCommand(ctx, cmd)
+	}
+	var start, end time.Time
+	if lastSec, ok := cmd[LastSecKey].(float64); ok {
+		start = time.Now().Add(-time.Duration(lastSec * float64(time.Second)))
+	}
+	for key, t := range map[string]*time.Time{StartKey: &start, EndKey: &end} {
+		value, ok := cmd[key].(string)
+		if !ok {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", key)
+		}
+		*t = parsed
+	}
+
+	readings := h.History(start, end)
+	entries := make([]interface{}, 0, len(readings))
+	for _, r := range readings {
+		protoReadings, err := protoutils.ReadingGoToProto(r.Readings)
+		if err != nil {
+			return nil, err
+		}
+		entryReadings := make(map[string]interface{}, len(protoReadings))
+		for name, value := range protoReadings {
+			entryReadings[name] = value.AsInterface()
+		}
+		entries = append(entries, map[string]interface{}{
+			TimeKey:     r.Time.Format(time.RFC3339Nano),
+			ReadingsKey: entryReadings,
+		})
+	}
+	return map[string]interface{}{HistoryKey: entries}, nil
+}
+
+// GetHistory returns the readings recorded by a history movement sensor between start and end, over
+// the network if need be. A zero start or end leaves that side of the range open.
+func GetHistory(ctx context.Context, ms movementsensor.MovementSensor, start, end time.Time) ([]Reading, error) {
+	cmd := map[string]interface{}{GetHistoryCommand: true}
+	if !start.IsZero() {
+		cmd[StartKey] = start.Format(time.RFC3339Nano)
+	}
+	if !end.IsZero() {
+		cmd[EndKey] = end.Format(time.RFC3339Nano)
+	}
+	resp, err := ms.DoCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	entries, ok := resp[HistoryKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%s is not a history movement sensor", ms.Name())
+	}
+	readings := make([]Reading, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid history entry")
+		}
+		timeStr, _ := entry[TimeKey].(string)
+		t, err := time.Parse(time.RFC3339Nano, timeStr)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid history entry time")
+		}
+		values, _ := entry[ReadingsKey].(map[string]interface{})
+		protoReadings := make(map[string]*structpb.Value, len(values))
+		for name, value := range values {
+			protoValue, err := structpb.NewValue(value)
+			if err != nil {
+				return nil, err
+			}
+			protoReadings[name] = protoValue
+		}
+		goReadings, err := protoutils.ReadingProtoToGo(protoReadings)
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, Reading{Time: t, Readings: goReadings})
+	}
+	return readings, nil
+}
+
+// sensor returns the movement sensor whose readings are recorded.
+func (h *history) sensor() movementsensor.MovementSensor {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.ms
+}
+
+func (h *history) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	return h.sensor().Position(ctx, extra)
+}
+
+func (h *history) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return h.sensor().LinearVelocity(ctx, extra)
+}
+
+func (h *history) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	return h.sensor().AngularVelocity(ctx, extra)
+}
+
+func (h *history) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return h.sensor().LinearAcceleration(ctx, extra)
+}
+
+func (h *history) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	return h.sensor().CompassHeading(ctx, extra)
+}
+
+func (h *history) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	return h.sensor().Orientation(ctx, extra)
+}
+
+func (h *history) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return h.sensor().Properties(ctx, extra)
+}
+
+func (h *history) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	return h.sensor().Accuracy(ctx, extra)
+}
+
+func (h *history) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return h.sensor().Readings(ctx, extra)
+}
+
+func (h *history) Close(ctx context.Context) error {
+	h.cancel()
+	h.activeBackgroundWorkers.Wait()
+	return nil
+}
+
+// ringBuffer keeps the most recent readings, overwriting the oldest once it is full.
+type ringBuffer struct {
+	entries []Reading
+	next    int
+	full    bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]Reading, size)}
+}
+
+// resized returns a buffer of the given size with the most recent readings of this one.
+func (rb *ringBuffer) resized(size int) *ringBuffer {
+	resized := newRingBuffer(size)
+	for _, r := range rb.between(time.Time{}, time.Time{}) {
+		resized.add(r)
+	}
+	return resized
+}
+
+func (rb *ringBuffer) add(r Reading) {
+	rb.entries[rb.next] = r
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.next == 0 {
+		rb.full = true
+	}
+}
+
+// between returns the readings between start and end, oldest first. A zero start or end leaves that
+// side of the range open.
+func (rb *ringBuffer) between(start, end time.Time) []Reading {
+	ordered := rb.entries[:rb.next]
+	if rb.full {
+		ordered = append(append([]Reading{}, rb.entries[rb.next:]...), rb.entries[:rb.next]...)
+	}
+	var readings []Reading
+	for _, r := range ordered {
+		if (!start.IsZero() && r.Time.Before(start)) || (!end.IsZero() && r.Time.After(end)) {
+			continue
+		}
+		readings = append(readings, r)
+	}
+	return readings
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/movementsensor/fake"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestValidate(t *testing.T) {
+	conf := &Config{}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeError, resource.NewConfigValidationFieldRequiredError("path", "movement_sensor"))
+
+	conf = &Config{MovementSensor: "imu", RateHz: -1}
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be negative")
+
+	// a rate this high would make the recording interval zero
+	conf = &Config{MovementSensor: "imu", RateHz: 1e12}
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "rate_hz must be at most")
+
+	conf = &Config{MovementSensor: "imu", RateHz: maxRateHz, BufferSec: 1e9}
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "rate_hz * buffer_sec")
+	// the default rate counts toward the bound
+	conf = &Config{MovementSensor: "imu", BufferSec: maxBufferedReadings}
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "rate_hz * buffer_sec")
+
+	conf = &Config{MovementSensor: "imu"}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"imu"})
+}
+
+func TestRingBuffer(t *testing.T) {
+	start := time.Now()
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Second) }
+	rb := newRingBuffer(3)
+	test.That(t, rb.between(time.Time{}, time.Time{}), test.ShouldBeEmpty)
+	for i := 0; i < 5; i++ {
+		rb.add(Reading{Time: at(i)})
+	}
+	times := func(readings []Reading) []time.Time {
+		var ts []time.Time
+		for _, r := range readings {
+			ts = append(ts, r.Time)
+		}
+		return ts
+	}
+	test.That(t, times(rb.between(time.Time{}, time.Time{})), test.ShouldResemble, []time.Time{at(2), at(3), at(4)})
+	test.That(t, times(rb.between(at(3), time.Time{})), test.ShouldResemble, []time.Time{at(3), at(4)})
+	test.That(t, times(rb.between(time.Time{}, at(3))), test.ShouldResemble, []time.Time{at(2), at(3)})
+
+	// resizing keeps the most recent readings which fit
+	test.That(t, times(rb.resized(2).between(time.Time{}, time.Time{})), test.ShouldResemble, []time.Time{at(3), at(4)})
+	grown := rb.resized(5)
+	grown.add(Reading{Time: at(5)})
+	test.That(t, times(grown.between(time.Time{}, time.Time{})), test.ShouldResemble, []time.Time{at(2), at(3), at(4), at(5)})
+}
+
+func TestHistory(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	imu, err := fake.NewMovementSensor(ctx, nil, resource.Config{Name: "imu"}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	deps := resource.Dependencies{movementsensor.Named("imu"): imu}
+	conf := resource.Config{
+		Name:                "recorded",
+		ConvertedAttributes: &Config{MovementSensor: "imu", RateHz: 100, BufferSec: 0.2},
+	}
+	ms, err := newHistory(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer ms.Close(ctx)
+
+	vel, err := ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, vel, test.ShouldResemble, r3.Vector{Y: 5.4})
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, len(ms.(*history).History(time.Time{}, time.Time{})), test.ShouldEqual, 20)
+	})
+
+	readings, err := GetHistory(ctx, ms, time.Time{}, time.Time{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(readings), test.ShouldBeBetweenOrEqual, 20, 21)
+	for i := 1; i < len(readings); i++ {
+		test.That(t, readings[i].Time, test.ShouldHappenAfter, readings[i-1].Time)
+	}
+	test.That(t, readings[0].Readings["linear_velocity"], test.ShouldResemble, r3.Vector{Y: 5.4})
+	test.That(t, readings[0].Readings["compass"], test.ShouldEqual, 25)
+
+	recent, err := GetHistory(ctx, ms, time.Now().Add(-50*time.Millisecond), time.Time{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(recent), test.ShouldBeBetweenOrEqual, 1, 10)
+
+	resp, err := ms.DoCommand(ctx, map[string]interface{}{GetHistoryCommand: true, LastSecKey: 0.05})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(resp[HistoryKey].([]interface{})), test.ShouldBeBetweenOrEqual, 1, 10)
+
+	_, err = ms.DoCommand(ctx, map[string]interface{}{GetHistoryCommand: true, StartKey: "yesterday"})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid start")
+
+	// other commands go to the recorded movement sensor
+	resp, err = ms.DoCommand(ctx, map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldBeEmpty)
+	_, err = GetHistory(ctx, imu, time.Time{}, time.Time{})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a history movement sensor")
+}
+
+func TestReconfigure(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	imu, err := fake.NewMovementSensor(ctx, nil, resource.Config{Name: "imu"}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	deps := resource.Dependencies{movementsensor.Named("imu"): imu}
+	conf := resource.Config{
+		Name:                "recorded",
+		ConvertedAttributes: &Config{MovementSensor: "imu", RateHz: 100, BufferSec: 0.2},
+	}
+	ms, err := newHistory(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer ms.Close(ctx)
+	h := ms.(*history)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, len(h.History(time.Time{}, time.Time{})), test.ShouldEqual, 20)
+	})
+
+	// the readings recorded before reconfiguring are kept, as many as fit in the new buffer
+	conf.ConvertedAttributes = &Config{MovementSensor: "imu", RateHz: 10, BufferSec: 1}
+	test.That(t, h.Reconfigure(ctx, deps, conf), test.ShouldBeNil)
+	test.That(t, len(h.History(time.Time{}, time.Time{})), test.ShouldEqual, 10)
+
+	conf.ConvertedAttributes = &Config{MovementSensor: "gps"}
+	test.That(t, h.Reconfigure(ctx, deps, conf), test.ShouldNotBeNil)
+}
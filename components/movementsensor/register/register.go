@@ -9,6 +9,7 @@ import (
 	_ "go.viam.com/rdk/components/movementsensor/gpsnmea"
 	_ "go.viam.com/rdk/components/movementsensor/gpsrtkpmtk"
 	_ "go.viam.com/rdk/components/movementsensor/gpsrtkserial"
+	_ "go.viam.com/rdk/components/movementsensor/history"
 	_ "go.viam.com/rdk/components/movementsensor/imuvectornav"
 	_ "go.viam.com/rdk/components/movementsensor/imuwit"
 	_ "go.viam.com/rdk/components/movementsensor/merged"
@@ -0,0 +1,131 @@
+package posetracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "go.viam.com/api/common/v1"
+	pb "go.viam.com/api/component/posetracker/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/referenceframe"
+)
+
+type method int64
+
+const (
+	poses method = iota
+	streamPoses
+)
+
+func (m method) String() string {
+	switch m {
+	case poses:
+		return "Poses"
+	case streamPoses:
+		return "StreamPoses"
+	}
+	return "Unknown"
+}
+
+func assertPoseTracker(resource interface{}) (PoseTracker, error) {
+	pt, ok := resource.(PoseTracker)
+	if !ok {
+		return nil, data.InvalidInterfaceErr(API)
+	}
+	return pt, nil
+}
+
+// newPosesCollector returns a collector of the poses of the bodies a pose tracker observes. Poses are only captured
+// when they changed since the previous capture, so that a pose tracker which observes nothing new doesn't fill the
+// capture with duplicates.
+func newPosesCollector(resource interface{}, params data.CollectorParams) (data.Collector, error) {
+	pt, err := assertPoseTracker(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// the capture func is never called concurrently, so the previous poses need no lock.
+	var last map[string]*v1.PoseInFrame
+	cFunc := data.CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		bodyToPose, err := pt.Poses(ctx, []string{}, data.FromDMExtraMap)
+		if err != nil {
+			// A modular filter component can be created to filter the readings from a component. The error ErrNoCaptureToStore
+			// is used in the datamanager to exclude readings from being captured and stored.
+			if errors.Is(err, data.ErrNoCaptureToStore) {
+				return nil, err
+			}
+			return nil, data.FailedToReadErr(params.ComponentName, poses.String(), err)
+		}
+		bodyPoses := posesToProtobuf(bodyToPose)
+		if samePoses(last, bodyPoses) {
+			return nil, data.ErrNoCaptureToStore
+		}
+		last = bodyPoses
+		return pb.GetPosesResponse{BodyPoses: bodyPoses}, nil
+	})
+	return data.NewCollector(cFunc, params)
+}
+
+// newStreamPosesCollector returns a collector of the poses of the bodies a PoseStreamer observes, captured as they
+// are observed and stamped with the time they were observed rather than polled at an interval. As with
+// newPosesCollector, poses are only captured when they changed. Pose trackers which don't stream their poses, which
+// include all remote and modular pose trackers, are captured with Poses instead.
+func newStreamPosesCollector(resource interface{}, params data.CollectorParams) (data.Collector, error) {
+	pt, err := assertPoseTracker(resource)
+	if err != nil {
+		return nil, err
+	}
+	streamer, ok := pt.(PoseStreamer)
+	if !ok {
+		return nil, fmt.Errorf("pose tracker %s does not stream its poses; capture Poses instead", params.ComponentName)
+	}
+
+	sFunc := data.StreamCaptureFunc(func(
+		ctx context.Context,
+		_ map[string]*anypb.Any,
+		push func(timeCaptured time.Time, reading func() (interface{}, error)),
+	) error {
+		var last map[string]*v1.PoseInFrame
+		err := streamer.StreamPoses(ctx, func(observed time.Time, bodyToPose BodyToPoseInFrame) error {
+			bodyPoses := posesToProtobuf(bodyToPose)
+			if samePoses(last, bodyPoses) {
+				return nil
+			}
+			last = bodyPoses
+			push(observed, func() (interface{}, error) {
+				return pb.GetPosesResponse{BodyPoses: bodyPoses}, nil
+			})
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			return data.FailedToReadErr(params.ComponentName, streamPoses.String(), err)
+		}
+		return err
+	})
+	return data.NewStreamCollector(sFunc, params)
+}
+
+func posesToProtobuf(bodyToPose BodyToPoseInFrame) map[string]*v1.PoseInFrame {
+	bodyPoses := make(map[string]*v1.PoseInFrame, len(bodyToPose))
+	for body, pif := range bodyToPose {
+		bodyPoses[body] = referenceframe.PoseInFrameToProtobuf(pif)
+	}
+	return bodyPoses
+}
+
+func samePoses(a, b map[string]*v1.PoseInFrame) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for body, pose := range a {
+		if !proto.Equal(pose, b[body]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package posetracker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	v1 "go.viam.com/api/common/v1"
+	pb "go.viam.com/api/component/posetracker/v1"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	tu "go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+const captureInterval = time.Second
+
+func TestPosesCollector(t *testing.T) {
+	mockClock := clk.NewMock()
+	buf := tu.MockBuffer{}
+	params := data.CollectorParams{
+		ComponentName: "pose_tracker",
+		Interval:      captureInterval,
+		Logger:        logging.NewTestLogger(t),
+		Target:        &buf,
+		Clock:         mockClock,
+	}
+
+	// the body only moves on the third call, so the second call's poses are not captured again
+	calls := make(chan int)
+	var n int
+	pt := inject.NewPoseTracker("pose_tracker")
+	pt.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (posetracker.BodyToPoseInFrame, error) {
+		n++
+		x := 1.0
+		if n > 2 {
+			x = 2
+		}
+		calls <- n
+		return posetracker.BodyToPoseInFrame{
+			"body": referenceframe.NewPoseInFrame("world", spatialmath.NewPoseFromPoint(r3.Vector{X: x})),
+		}, nil
+	}
+	col, err := posetracker.NewPosesCollector(pt, params)
+	test.That(t, err, test.ShouldBeNil)
+
+	defer col.Close()
+	col.Collect()
+	for i := 1; i <= 3; i++ {
+		mockClock.Add(captureInterval)
+		test.That(t, <-calls, test.ShouldEqual, i)
+	}
+
+	tu.Retry(func() bool {
+		return buf.Length() == 2
+	}, 5)
+	test.That(t, buf.Length(), test.ShouldEqual, 2)
+	for i, x := range []float64{1, 2} {
+		pose := referenceframe.NewPoseInFrame("world", spatialmath.NewPoseFromPoint(r3.Vector{X: x}))
+		test.That(t, buf.Writes[i].GetStruct().AsMap(), test.ShouldResemble,
+			tu.ToProtoMapIgnoreOmitEmpty(pb.GetPosesResponse{
+				BodyPoses: map[string]*v1.PoseInFrame{"body": referenceframe.PoseInFrameToProtobuf(pose)},
+			}))
+	}
+}
+
+// streamingPoseTracker streams the poses it is given, as a pose tracker which pushes its observations would.
+type streamingPoseTracker struct {
+	*inject.PoseTracker
+	observations []observation
+}
+
+type observation struct {
+	observed time.Time
+	x        float64
+}
+
+func (pt *streamingPoseTracker) StreamPoses(
+	ctx context.Context, fn func(observed time.Time, poses posetracker.BodyToPoseInFrame) error,
+) error {
+	for _, obs := range pt.observations {
+		if err := fn(obs.observed, posetracker.BodyToPoseInFrame{
+			"body": referenceframe.NewPoseInFrame("world", spatialmath.NewPoseFromPoint(r3.Vector{X: obs.x})),
+		}); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStreamPosesCollector(t *testing.T) {
+	params := data.CollectorParams{
+		ComponentName: "pose_tracker",
+		Interval:      10 * time.Millisecond,
+		Logger:        logging.NewTestLogger(t),
+		Target:        &tu.MockBuffer{},
+		Clock:         clk.NewMock(),
+	}
+
+	// pose trackers which can only be polled are captured with Poses
+	_, err := posetracker.NewStreamPosesCollector(inject.NewPoseTracker("pose_tracker"), params)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "capture Poses instead")
+
+	// poses are captured as they are observed, stamped with when they were, and only if they changed
+	buf := tu.MockBuffer{}
+	params.Target = &buf
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pt := &streamingPoseTracker{
+		PoseTracker: inject.NewPoseTracker("pose_tracker"),
+		observations: []observation{
+			{observed: start, x: 1},
+			{observed: start.Add(time.Second), x: 1},
+			{observed: start.Add(2 * time.Second), x: 2},
+		},
+	}
+	col, err := posetracker.NewStreamPosesCollector(pt, params)
+	test.That(t, err, test.ShouldBeNil)
+	defer col.Close()
+	col.Collect()
+
+	tu.Retry(func() bool {
+		return buf.Length() == 2
+	}, 5)
+	test.That(t, buf.Length(), test.ShouldEqual, 2)
+	for i, obs := range []observation{pt.observations[0], pt.observations[2]} {
+		pose := referenceframe.NewPoseInFrame("world", spatialmath.NewPoseFromPoint(r3.Vector{X: obs.x}))
+		test.That(t, buf.Writes[i].GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual, obs.observed)
+		test.That(t, buf.Writes[i].GetStruct().AsMap(), test.ShouldResemble,
+			tu.ToProtoMapIgnoreOmitEmpty(pb.GetPosesResponse{
+				BodyPoses: map[string]*v1.PoseInFrame{"body": referenceframe.PoseInFrameToProtobuf(pose)},
+			}))
+	}
+}
@@ -0,0 +1,8 @@
+// export_collectors_test.go adds functionality to the package that we only want to use and expose during testing.
+package posetracker
+
+// Exported variables for testing collectors, see unexported collectors for implementation details.
+var (
+	NewPosesCollector       = newPosesCollector
+	NewStreamPosesCollector = newStreamPosesCollector
+)
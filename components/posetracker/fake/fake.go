@@ -0,0 +1,106 @@
+// Package fake implements a fake pose tracker, which observes bodies circling its origin and streams their poses.
+package fake
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// observationInterval is how often the bodies are observed, and so how often their poses are streamed.
+	observationInterval = 100 * time.Millisecond
+	// orbitPeriod is how long a body takes to circle the pose tracker, and orbitRadiusMM how far from it it is.
+	orbitPeriod   = 10 * time.Second
+	orbitRadiusMM = 1000
+)
+
+// bodies are the names of the bodies the pose tracker observes. They are evenly spaced around the circle.
+var bodies = []string{"body1", "body2"}
+
+func init() {
+	resource.RegisterComponent(
+		posetracker.API,
+		resource.DefaultModelFamily.WithModel("fake"),
+		resource.Registration[posetracker.PoseTracker, resource.NoNativeConfig]{Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (posetracker.PoseTracker, error) {
+			return NewPoseTracker(conf.ResourceName(), logger), nil
+		}})
+}
+
+// NewPoseTracker returns a fake pose tracker.
+func NewPoseTracker(name resource.Name, logger logging.Logger) *PoseTracker {
+	return &PoseTracker{Named: name.AsNamed(), logger: logger, start: time.Now()}
+}
+
+// PoseTracker is a fake pose tracker, whose bodies circle its origin in its XY plane.
+type PoseTracker struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	logger logging.Logger
+	start  time.Time
+}
+
+// Poses returns the poses of the named bodies, or of every body if none are named, in the pose tracker's frame.
+func (pt *PoseTracker) Poses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (posetracker.BodyToPoseInFrame, error) {
+	return pt.posesAt(time.Now(), bodyNames), nil
+}
+
+// StreamPoses calls fn with the poses of every body each time they are observed.
+func (pt *PoseTracker) StreamPoses(
+	ctx context.Context, fn func(observed time.Time, poses posetracker.BodyToPoseInFrame) error,
+) error {
+	ticker := time.NewTicker(observationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case observed := <-ticker.C:
+			if err := fn(observed, pt.posesAt(observed, nil)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (pt *PoseTracker) posesAt(t time.Time, bodyNames []string) posetracker.BodyToPoseInFrame {
+	if len(bodyNames) == 0 {
+		bodyNames = bodies
+	}
+	elapsed := t.Sub(pt.start).Seconds() / orbitPeriod.Seconds()
+	poses := posetracker.BodyToPoseInFrame{}
+	for i, body := range bodies {
+		if !slices.Contains(bodyNames, body) {
+			continue
+		}
+		angle := 2 * math.Pi * (elapsed + float64(i)/float64(len(bodies)))
+		pose := spatialmath.NewPose(
+			r3.Vector{X: orbitRadiusMM * math.Cos(angle), Y: orbitRadiusMM * math.Sin(angle)},
+			&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: angle * 180 / math.Pi},
+		)
+		poses[body] = referenceframe.NewPoseInFrame(pt.Name().ShortName(), pose)
+	}
+	return poses
+}
+
+// Readings returns the poses of every body.
+func (pt *PoseTracker) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return posetracker.Readings(ctx, pt)
+}
@@ -0,0 +1,51 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/logging"
+)
+
+func TestPoseTracker(t *testing.T) {
+	ctx := context.Background()
+	pt := NewPoseTracker(posetracker.Named("tracker"), logging.NewTestLogger(t))
+
+	poses, err := pt.Poses(ctx, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 2)
+	test.That(t, poses["body1"].Parent(), test.ShouldEqual, "tracker")
+	// the bodies are on opposite sides of the circle
+	test.That(t, poses["body1"].Pose().Point().Add(poses["body2"].Pose().Point()).Norm(), test.ShouldBeLessThan, 1e-6)
+	test.That(t, poses["body1"].Pose().Point().Norm(), test.ShouldAlmostEqual, orbitRadiusMM)
+
+	poses, err = pt.Poses(ctx, []string{"body2", "missing"}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 1)
+	test.That(t, poses, test.ShouldContainKey, "body2")
+
+	// the bodies move between observations
+	streamCtx, cancel := context.WithCancel(ctx)
+	var observed []time.Time
+	var firstX, lastX float64
+	err = pt.StreamPoses(streamCtx, func(at time.Time, poses posetracker.BodyToPoseInFrame) error {
+		test.That(t, poses, test.ShouldHaveLength, 2)
+		if len(observed) == 0 {
+			firstX = poses["body1"].Pose().Point().X
+		}
+		lastX = poses["body1"].Pose().Point().X
+		observed = append(observed, at)
+		if len(observed) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+	test.That(t, observed, test.ShouldHaveLength, 3)
+	test.That(t, observed[2].After(observed[0]), test.ShouldBeTrue)
+	test.That(t, lastX, test.ShouldNotEqual, firstX)
+}
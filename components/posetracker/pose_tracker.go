@@ -4,13 +4,16 @@ package posetracker
 
 import (
 	"context"
+	"time"
 
 	pb "go.viam.com/api/component/posetracker/v1"
 
 	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
 )
 
 func init() {
@@ -20,6 +23,15 @@ func init() {
 		RPCServiceDesc:              &pb.PoseTrackerService_ServiceDesc,
 		RPCClient:                   NewClientFromConn,
 	})
+
+	data.RegisterCollector(data.MethodMetadata{
+		API:        API,
+		MethodName: poses.String(),
+	}, newPosesCollector)
+	data.RegisterCollector(data.MethodMetadata{
+		API:        API,
+		MethodName: streamPoses.String(),
+	}, newStreamPosesCollector)
 }
 
 // SubtypeName is a constant that identifies the component resource API string "posetracker".
@@ -44,6 +56,14 @@ type PoseTracker interface {
 	Poses(ctx context.Context, bodyNames []string, extra map[string]interface{}) (BodyToPoseInFrame, error)
 }
 
+// A PoseStreamer is a pose tracker which pushes the poses of the bodies it observes as it observes them, rather than
+// only being polled with Poses. StreamPoses calls fn with the poses and the time they were observed until the context
+// is done, the stream fails or fn returns an error, which is returned. The pose tracker API has no streaming method,
+// so only pose trackers running in the same process as the caller, such as the fake one, can stream their poses.
+type PoseStreamer interface {
+	StreamPoses(ctx context.Context, fn func(observed time.Time, poses BodyToPoseInFrame) error) error
+}
+
 // FromRobot is a helper for getting the named force matrix sensor from the given Robot.
 func FromRobot(r robot.Robot, name string) (PoseTracker, error) {
 	return robot.ResourceFromRobot[PoseTracker](r, Named(name))
@@ -61,3 +81,39 @@ func Readings(ctx context.Context, poseTracker PoseTracker) (map[string]interfac
 	}
 	return result, nil
 }
+
+// DynamicFrames returns a source of the frames of the bodies seen by the pose tracker, for use with
+// framesystem.StreamTransforms. Each body's frame is named after the pose tracker and the body,
+// separated by a colon, e.g. "tracker:body".
+func DynamicFrames(poseTracker PoseTracker) framesystem.DynamicFrameSource {
+	return func(ctx context.Context) ([]*referenceframe.PoseInFrame, error) {
+		poseLookup, err := poseTracker.Poses(ctx, []string{}, map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+		poses := make([]*referenceframe.PoseInFrame, 0, len(poseLookup))
+		for bodyName, poseInFrame := range poseLookup {
+			pif := referenceframe.NewPoseInFrame(poseInFrame.Parent(), poseInFrame.Pose())
+			pif.SetName(poseTracker.Name().ShortName() + ":" + bodyName)
+			poses = append(poses, pif)
+		}
+		return poses, nil
+	}
+}
+
+// RobotDynamicFrames returns sources of the frames of the bodies seen by each of the robot's pose trackers, as
+// DynamicFrames does.
+func RobotDynamicFrames(r robot.Robot) []framesystem.DynamicFrameSource {
+	var sources []framesystem.DynamicFrameSource
+	for _, name := range r.ResourceNames() {
+		if name.API != API {
+			continue
+		}
+		poseTracker, err := robot.ResourceFromRobot[PoseTracker](r, name)
+		if err != nil {
+			continue
+		}
+		sources = append(sources, DynamicFrames(poseTracker))
+	}
+	return sources
+}
@@ -0,0 +1,40 @@
+package posetracker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDynamicFrames(t *testing.T) {
+	pt := inject.NewPoseTracker("tracker")
+	pt.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (
+		posetracker.BodyToPoseInFrame, error,
+	) {
+		return posetracker.BodyToPoseInFrame{
+			bodyName: referenceframe.NewPoseInFrame(bodyFrame, spatialmath.NewPoseFromPoint(r3.Vector{X: 1})),
+		}, nil
+	}
+
+	poses, err := posetracker.DynamicFrames(pt)(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, poses, test.ShouldHaveLength, 1)
+	test.That(t, poses[0].Name(), test.ShouldEqual, "tracker:"+bodyName)
+	test.That(t, poses[0].Parent(), test.ShouldEqual, bodyFrame)
+	test.That(t, poses[0].Pose().Point(), test.ShouldResemble, r3.Vector{X: 1})
+
+	pt.PosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (
+		posetracker.BodyToPoseInFrame, error,
+	) {
+		return nil, errPoseFailed
+	}
+	_, err = posetracker.DynamicFrames(pt)(context.Background())
+	test.That(t, err, test.ShouldBeError, errPoseFailed)
+}
@@ -0,0 +1,165 @@
+package recording
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// inProcessServer calls the unary methods of a gRPC service server directly.
+type inProcessServer struct {
+	desc   *grpc.ServiceDesc
+	server interface{}
+}
+
+func newInProcessServer(desc *grpc.ServiceDesc, server interface{}) *inProcessServer {
+	return &inProcessServer{desc: desc, server: server}
+}
+
+// invoke calls the fully qualified method, e.g. "/viam.component.sensor.v1.SensorService/GetReadings".
+func (s *inProcessServer) invoke(ctx context.Context, method string, req proto.Message) (interface{}, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok || service != s.desc.ServiceName {
+		return nil, status.Errorf(codes.Unimplemented, "unknown service for method %s", method)
+	}
+	for _, m := range s.desc.Methods {
+		if m.MethodName != name {
+			continue
+		}
+		dec := func(v interface{}) error {
+			msg, ok := v.(proto.Message)
+			if !ok {
+				return errors.Errorf("expected a proto message, got %T", v)
+			}
+			proto.Merge(msg, req)
+			return nil
+		}
+		return m.Handler(s.server, ctx, dec, nil)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method %s is not supported by recordings", method)
+}
+
+// recordingConn is a client connection which calls an in process server and records every call.
+type recordingConn struct {
+	server *inProcessServer
+	path   string
+}
+
+func (c *recordingConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	req, ok := args.(proto.Message)
+	if !ok {
+		return errors.Errorf("expected a proto message, got %T", args)
+	}
+	out, ok := reply.(proto.Message)
+	if !ok {
+		return errors.Errorf("expected a proto message, got %T", reply)
+	}
+
+	call := recordedCall{Time: time.Now(), Method: method}
+	var err error
+	if call.Request, err = protojson.Marshal(req); err != nil {
+		return err
+	}
+	resp, callErr := c.server.invoke(ctx, method, req)
+	if callErr != nil {
+		st := status.Convert(callErr)
+		call.Code = uint32(st.Code())
+		call.Error = st.Message()
+	} else {
+		respMsg, ok := resp.(proto.Message)
+		if !ok {
+			return errors.Errorf("expected a proto message, got %T", resp)
+		}
+		if call.Response, err = protojson.Marshal(respMsg); err != nil {
+			return err
+		}
+		proto.Reset(out)
+		proto.Merge(out, respMsg)
+	}
+	if err := appendRecording(c.path, call); err != nil {
+		return errors.Wrap(err, "failed to record call")
+	}
+	return callErr
+}
+
+func (c *recordingConn) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming method %s is not supported by recordings", method)
+}
+
+func (c *recordingConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+// replayConn is a client connection which answers calls with recorded results.
+type replayConn struct {
+	mu      sync.Mutex
+	entries map[string][]recordedCall
+	next    map[string]int
+	loop    bool
+}
+
+func (c *replayConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	out, ok := reply.(proto.Message)
+	if !ok {
+		return errors.Errorf("expected a proto message, got %T", reply)
+	}
+
+	c.mu.Lock()
+	calls := c.entries[method]
+	if len(calls) == 0 {
+		c.mu.Unlock()
+		return status.Errorf(codes.Unimplemented, "no calls to %s were recorded", method)
+	}
+	idx := c.next[method]
+	if idx >= len(calls) {
+		if !c.loop {
+			c.mu.Unlock()
+			return ErrEndOfRecording
+		}
+		idx = 0
+	}
+	c.next[method] = idx + 1
+	c.mu.Unlock()
+
+	call := calls[idx]
+	if codes.Code(call.Code) != codes.OK {
+		return status.Error(codes.Code(call.Code), call.Error)
+	}
+	proto.Reset(out)
+	return protojson.Unmarshal(call.Response, out)
+}
+
+func (c *replayConn) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streaming method %s is not supported by recordings", method)
+}
+
+func (c *replayConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+func (c *replayConn) Close() error {
+	return nil
+}
@@ -0,0 +1,245 @@
+// Package recording records the results of the API calls made to components to a file and replays
+// them, so that robot logic can be tested against the recorded behavior of real hardware without it.
+//
+// Recording and replaying work at the level of a component's gRPC API, and so work for any API:
+// a recording component serves calls through the API's gRPC server backed by the recorded
+// component, and a replaying component answers them from the recording file.
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var (
+	// RecordModel is the model of components which record the calls made to another component.
+	RecordModel = resource.DefaultModelFamily.WithModel("record")
+
+	// ReplayModel is the model of components which replay the calls recorded by a RecordModel component.
+	ReplayModel = resource.DefaultModelFamily.WithModel("replay_recording")
+
+	// ErrEndOfRecording is returned by a replaying component once it has replayed every recorded
+	// call to a method, unless it is configured to loop.
+	ErrEndOfRecording = errors.New("reached end of recording")
+)
+
+// RecordConfig describes how to configure a recording component.
+type RecordConfig struct {
+	// Component is the name of the component to record, which must have the same API.
+	Component string `json:"component"`
+	// Path is the file the calls are recorded to. Calls are appended to an existing file. Every
+	// recording component should record to its own file.
+	Path string `json:"path"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *RecordConfig) Validate(path string) ([]string, error) {
+	if cfg.Component == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "component")
+	}
+	if cfg.Path == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "path")
+	}
+	return []string{cfg.Component}, nil
+}
+
+// ReplayConfig describes how to configure a replaying component.
+type ReplayConfig struct {
+	// Path is the file written by a recording component.
+	Path string `json:"path"`
+	// Loop, if set, starts replaying the calls to a method from the beginning of the recording
+	// after the last one, instead of returning ErrEndOfRecording.
+	Loop bool `json:"loop,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *ReplayConfig) Validate(path string) ([]string, error) {
+	if cfg.Path == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "path")
+	}
+	return nil, nil
+}
+
+// Register registers the record and replay models for the API. Since the components are created
+// from the API's client, which cannot be reconfigured, wrap must return a component which is
+// rebuilt whenever it is reconfigured, e.g. by returning resource.NewMustRebuildError from Reconfigure.
+func Register[T resource.Resource](api resource.API, wrap func(T) T) {
+	resource.RegisterComponent(api, RecordModel, resource.Registration[T, *RecordConfig]{
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (T, error) {
+			var zero T
+			cfg, err := resource.NativeConfig[*RecordConfig](conf)
+			if err != nil {
+				return zero, err
+			}
+			target, err := resource.FromDependencies[T](deps, resource.NewName(api, cfg.Component))
+			if err != nil {
+				return zero, err
+			}
+			res, err := NewRecorder(ctx, conf.ResourceName(), target, cfg.Path, logger)
+			if err != nil {
+				return zero, err
+			}
+			return wrap(res), nil
+		},
+	})
+	resource.RegisterComponent(api, ReplayModel, resource.Registration[T, *ReplayConfig]{
+		Constructor: func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (T, error) {
+			var zero T
+			cfg, err := resource.NativeConfig[*ReplayConfig](conf)
+			if err != nil {
+				return zero, err
+			}
+			res, err := NewReplay[T](ctx, conf.ResourceName(), cfg.Path, cfg.Loop, logger)
+			if err != nil {
+				return zero, err
+			}
+			return wrap(res), nil
+		},
+	})
+}
+
+// NewRecorder returns a component named name which forwards calls to the target component and
+// appends their requests and results to the file at path.
+func NewRecorder[T resource.Resource](
+	ctx context.Context,
+	name resource.Name,
+	target T,
+	path string,
+	logger logging.Logger,
+) (T, error) {
+	var zero T
+	reg, err := lookupAPI[T](name.API)
+	if err != nil {
+		return zero, err
+	}
+	if reg.RPCServiceServerConstructor == nil {
+		return zero, errors.Errorf("API %s has no gRPC server to record calls with", name.API)
+	}
+	coll, err := resource.NewAPIResourceCollection(name.API, map[resource.Name]T{name: target})
+	if err != nil {
+		return zero, err
+	}
+	// check the recording can be written before any calls are made
+	//nolint:gosec // the path is configured by the user
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return zero, err
+	}
+	if err := f.Close(); err != nil {
+		return zero, err
+	}
+
+	conn := &recordingConn{
+		server: newInProcessServer(reg.RPCServiceDesc, reg.RPCServiceServerConstructor(coll)),
+		path:   path,
+	}
+	return reg.RPCClient(ctx, conn, "", name, logger)
+}
+
+// NewReplay returns a component named name which answers calls with the results recorded to the
+// file at path. The calls to each method are answered with the results of the recorded calls to
+// it in the order they were recorded, regardless of their requests, so that replaying is
+// deterministic.
+func NewReplay[T resource.Resource](
+	ctx context.Context,
+	name resource.Name,
+	path string,
+	loop bool,
+	logger logging.Logger,
+) (T, error) {
+	var zero T
+	reg, err := lookupAPI[T](name.API)
+	if err != nil {
+		return zero, err
+	}
+	entries, err := readRecording(path)
+	if err != nil {
+		return zero, err
+	}
+	conn := &replayConn{
+		entries: map[string][]recordedCall{},
+		next:    map[string]int{},
+		loop:    loop,
+	}
+	for _, entry := range entries {
+		conn.entries[entry.Method] = append(conn.entries[entry.Method], entry)
+	}
+	return reg.RPCClient(ctx, conn, "", name, logger)
+}
+
+func lookupAPI[T resource.Resource](api resource.API) (resource.APIRegistration[T], error) {
+	reg, ok, err := resource.LookupAPIRegistration[T](api)
+	if err != nil {
+		return reg, err
+	}
+	if !ok || reg.RPCClient == nil {
+		return reg, errors.Errorf("API %s has no gRPC client registered", api)
+	}
+	return reg, nil
+}
+
+// recordedCall is a call recorded to a recording file, which has one JSON encoded call per line.
+// The request and response are JSON encoded protobuf messages.
+type recordedCall struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Code     uint32          `json:"code,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// recordingMu serializes writes to recording files.
+var recordingMu sync.Mutex
+
+func appendRecording(path string, call recordedCall) error {
+	line, err := json.Marshal(call)
+	if err != nil {
+		return err
+	}
+	recordingMu.Lock()
+	defer recordingMu.Unlock()
+	//nolint:gosec // the path is configured by the user
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return multierr.Combine(err, f.Close())
+}
+
+func readRecording(path string) ([]recordedCall, error) {
+	//nolint:gosec // the path is configured by the user
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer goutils.UncheckedErrorFunc(f.Close)
+
+	var calls []recordedCall
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var call recordedCall
+		if err := dec.Decode(&call); err != nil {
+			return nil, errors.Wrapf(err, "invalid recording %q", path)
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+var (
+	_ rpc.ClientConn = (*recordingConn)(nil)
+	_ rpc.ClientConn = (*replayConn)(nil)
+)
@@ -0,0 +1,147 @@
+package recording_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/components/recording"
+	_ "go.viam.com/rdk/components/recording/register"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// countingSensor returns an increasing count, and fails every third call.
+type countingSensor struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	count int
+}
+
+func (s *countingSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	s.count++
+	if s.count%3 == 0 {
+		return nil, errors.New("sensor unplugged")
+	}
+	return map[string]interface{}{"count": s.count}, nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	path := filepath.Join(t.TempDir(), "sensor.jsonl")
+
+	target := &countingSensor{Named: sensor.Named("real").AsNamed()}
+	recorder, err := recording.NewRecorder[sensor.Sensor](ctx, sensor.Named("recorder"), target, path, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, recorder.Name(), test.ShouldResemble, sensor.Named("recorder"))
+
+	var recorded []map[string]interface{}
+	for i := 0; i < 4; i++ {
+		readings, err := recorder.Readings(ctx, nil)
+		if i == 2 {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, "sensor unplugged")
+		} else {
+			test.That(t, err, test.ShouldBeNil)
+		}
+		recorded = append(recorded, readings)
+	}
+	test.That(t, recorded[0], test.ShouldResemble, map[string]interface{}{"count": 1.0})
+
+	t.Run("replay", func(t *testing.T) {
+		replay, err := recording.NewReplay[sensor.Sensor](ctx, sensor.Named("replay"), path, false, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		for i := 0; i < 4; i++ {
+			readings, err := replay.Readings(ctx, nil)
+			if i == 2 {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, "sensor unplugged")
+			} else {
+				test.That(t, err, test.ShouldBeNil)
+			}
+			test.That(t, readings, test.ShouldResemble, recorded[i])
+		}
+		_, err = replay.Readings(ctx, nil)
+		test.That(t, err, test.ShouldBeError, recording.ErrEndOfRecording)
+
+		_, err = replay.DoCommand(ctx, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no calls to")
+	})
+
+	t.Run("replay loop", func(t *testing.T) {
+		replay, err := recording.NewReplay[sensor.Sensor](ctx, sensor.Named("replay"), path, true, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		for i := 0; i < 8; i++ {
+			readings, _ := replay.Readings(ctx, nil)
+			test.That(t, readings, test.ShouldResemble, recorded[i%4])
+		}
+	})
+
+	t.Run("wrong API", func(t *testing.T) {
+		_, err := recording.NewReplay[sensor.Sensor](ctx, encoder.Named("replay"), path, false, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("missing recording", func(t *testing.T) {
+		_, err := recording.NewReplay[sensor.Sensor](ctx, sensor.Named("replay"), filepath.Join(t.TempDir(), "missing"), false, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestRegisteredModels(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	path := filepath.Join(t.TempDir(), "sensor.jsonl")
+
+	reg, ok := resource.LookupRegistration(sensor.API, recording.RecordModel)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	target := &countingSensor{Named: sensor.Named("real").AsNamed()}
+	conf := resource.Config{
+		Name:                "recorder",
+		API:                 sensor.API,
+		Model:               recording.RecordModel,
+		ConvertedAttributes: &recording.RecordConfig{Component: "real", Path: path},
+	}
+	deps, err := conf.ConvertedAttributes.(*recording.RecordConfig).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"real"})
+
+	res, err := reg.Constructor(ctx, resource.Dependencies{target.Name(): target}, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	recorder, ok := res.(sensor.Sensor)
+	test.That(t, ok, test.ShouldBeTrue)
+	readings, err := recorder.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 1.0})
+
+	// the recorded component must be rebuilt to pick up changes
+	test.That(t, resource.IsMustRebuildError(recorder.Reconfigure(ctx, nil, conf)), test.ShouldBeTrue)
+
+	reg, ok = resource.LookupRegistration(sensor.API, recording.ReplayModel)
+	test.That(t, ok, test.ShouldBeTrue)
+	conf = resource.Config{
+		Name:                "replay",
+		API:                 sensor.API,
+		Model:               recording.ReplayModel,
+		ConvertedAttributes: &recording.ReplayConfig{Path: path},
+	}
+	res, err = reg.Constructor(ctx, nil, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	replay, ok := res.(sensor.Sensor)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, replay.Name(), test.ShouldResemble, sensor.Named("replay"))
+	readings, err = replay.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 1.0})
+}
@@ -0,0 +1,46 @@
+// Package register registers the record and replay models for the APIs whose state is worth replaying.
+package register
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/powersensor"
+	"go.viam.com/rdk/components/recording"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	recording.Register(sensor.API, func(s sensor.Sensor) sensor.Sensor { return rebuiltSensor{s} })
+	recording.Register(movementsensor.API, func(ms movementsensor.MovementSensor) movementsensor.MovementSensor {
+		return rebuiltMovementSensor{ms}
+	})
+	recording.Register(powersensor.API, func(ps powersensor.PowerSensor) powersensor.PowerSensor { return rebuiltPowerSensor{ps} })
+	recording.Register(encoder.API, func(e encoder.Encoder) encoder.Encoder { return rebuiltEncoder{e} })
+}
+
+type rebuiltSensor struct{ sensor.Sensor }
+
+func (s rebuiltSensor) Reconfigure(context.Context, resource.Dependencies, resource.Config) error {
+	return resource.NewMustRebuildError(s.Name())
+}
+
+type rebuiltMovementSensor struct{ movementsensor.MovementSensor }
+
+func (ms rebuiltMovementSensor) Reconfigure(context.Context, resource.Dependencies, resource.Config) error {
+	return resource.NewMustRebuildError(ms.Name())
+}
+
+type rebuiltPowerSensor struct{ powersensor.PowerSensor }
+
+func (ps rebuiltPowerSensor) Reconfigure(context.Context, resource.Dependencies, resource.Config) error {
+	return resource.NewMustRebuildError(ps.Name())
+}
+
+type rebuiltEncoder struct{ encoder.Encoder }
+
+func (e rebuiltEncoder) Reconfigure(context.Context, resource.Dependencies, resource.Config) error {
+	return resource.NewMustRebuildError(e.Name())
+}
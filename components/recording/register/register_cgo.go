@@ -0,0 +1,21 @@
+//go:build !no_cgo
+
+package register
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/recording"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	recording.Register(arm.API, func(a arm.Arm) arm.Arm { return rebuiltArm{a} })
+}
+
+type rebuiltArm struct{ arm.Arm }
+
+func (a rebuiltArm) Reconfigure(context.Context, resource.Dependencies, resource.Config) error {
+	return resource.NewMustRebuildError(a.Name())
+}
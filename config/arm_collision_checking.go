@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// ArmCollisionCheckingConfig describes how the moves which arms are asked to make through the robot's API, rather
+// than by the builtin motion service, are checked for collisions of each arm with itself, with the geometries of the
+// rest of the robot and with static obstacles. This includes the moves of remote and modular arms. Modules reach
+// arms through the robot's API too, so the moves of a modular motion service are checked as well, and with enforce
+// and clamp set, one of its paths may be cut short. Moves ask to be checked with the "collision_check" extra
+// parameter, unless every move is.
+type ArmCollisionCheckingConfig struct {
+	// Enforce checks every arm move of the robot.
+	Enforce bool `json:"enforce,omitempty"`
+	// Clamp stops an arm at its last position before a collision, instead of rejecting the move.
+	Clamp    bool    `json:"clamp,omitempty"`
+	BufferMM float64 `json:"buffer_mm,omitempty"`
+	// Obstacles are placed relative to their parent frame, which defaults to the world frame.
+	Obstacles []*referenceframe.LinkConfig `json:"obstacles,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (acc *ArmCollisionCheckingConfig) Validate(path string) error {
+	if acc.BufferMM < 0 {
+		return resource.NewConfigValidationError(path, errors.New("buffer_mm cannot be negative"))
+	}
+	for idx, obstacle := range acc.Obstacles {
+		obstaclePath := fmt.Sprintf("%s.obstacles.%d", path, idx)
+		if obstacle == nil || obstacle.Geometry == nil {
+			return resource.NewConfigValidationFieldRequiredError(obstaclePath, "geometry")
+		}
+		if _, err := obstacle.ParseConfig(); err != nil {
+			return resource.NewConfigValidationError(obstaclePath, err)
+		}
+	}
+	return nil
+}
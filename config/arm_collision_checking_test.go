@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestArmCollisionCheckingConfigValidate(t *testing.T) {
+	acc := config.ArmCollisionCheckingConfig{Enforce: true}
+	test.That(t, acc.Validate("arm_collision_checking"), test.ShouldBeNil)
+
+	acc.BufferMM = -1
+	err := acc.Validate("arm_collision_checking")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "buffer_mm cannot be negative")
+
+	acc.BufferMM = 10
+	acc.Obstacles = []*referenceframe.LinkConfig{{ID: "table", Translation: r3.Vector{Z: -50}}}
+	err = acc.Validate("arm_collision_checking")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "arm_collision_checking.obstacles.0")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"geometry" is required`)
+
+	acc.Obstacles[0].Geometry = &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: 1000, Y: 1000, Z: 100}
+	test.That(t, acc.Validate("arm_collision_checking"), test.ShouldBeNil)
+}
@@ -38,6 +38,9 @@ type Config struct {
 	Auth            AuthConfig
 	Debug           bool
 	GlobalLogConfig []GlobalLogConfig
+	Telemetry       *TelemetryConfig
+
+	ArmCollisionChecking *ArmCollisionCheckingConfig
 
 	ConfigFilePath string
 
@@ -62,6 +65,10 @@ type Config struct {
 
 	// PackagePath sets the directory used to store packages locally. Defaults to ~/.viam/packages
 	PackagePath string
+
+	// CachedAt is when this cloud config was last read from the cloud, if the cloud could not be reached
+	// and the robot came up from the cached config instead. It is zero otherwise.
+	CachedAt time.Time
 }
 
 // NOTE: This data must be maintained with what is in Config.
@@ -78,6 +85,9 @@ type configData struct {
 	Debug               bool                  `json:"debug,omitempty"`
 	DisablePartialStart bool                  `json:"disable_partial_start"`
 	GlobalLogConfig     []GlobalLogConfig     `json:"global_log_configuration"`
+	Telemetry           *TelemetryConfig      `json:"telemetry,omitempty"`
+
+	ArmCollisionChecking *ArmCollisionCheckingConfig `json:"arm_collision_checking,omitempty"`
 }
 
 // AppValidationStatus refers to the.
@@ -119,6 +129,18 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		return err
 	}
 
+	if c.Telemetry != nil {
+		if err := c.Telemetry.Validate("telemetry"); err != nil {
+			return err
+		}
+	}
+
+	if c.ArmCollisionChecking != nil {
+		if err := c.ArmCollisionChecking.Validate("arm_collision_checking"); err != nil {
+			return err
+		}
+	}
+
 	for idx := 0; idx < len(c.Modules); idx++ {
 		if err := c.Modules[idx].Validate(fmt.Sprintf("%s.%d", "modules", idx)); err != nil {
 			if c.DisablePartialStart {
@@ -265,6 +287,8 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.Debug = conf.Debug
 	c.DisablePartialStart = conf.DisablePartialStart
 	c.GlobalLogConfig = conf.GlobalLogConfig
+	c.Telemetry = conf.Telemetry
+	c.ArmCollisionChecking = conf.ArmCollisionChecking
 
 	return nil
 }
@@ -294,6 +318,9 @@ func (c Config) MarshalJSON() ([]byte, error) {
 		Debug:               c.Debug,
 		DisablePartialStart: c.DisablePartialStart,
 		GlobalLogConfig:     c.GlobalLogConfig,
+		Telemetry:           c.Telemetry,
+
+		ArmCollisionChecking: c.ArmCollisionChecking,
 	})
 }
 
@@ -666,6 +693,17 @@ type NetworkConfigData struct {
 
 	// Sessions configures session management.
 	Sessions SessionsConfig `json:"sessions"`
+
+	// RTSPBindAddress, if set, is the address of an RTSP server which serves the video of every
+	// camera at rtsp://<address>/<camera name>. If the robot has auth handlers, clients
+	// authenticate with basic authentication, using an API key ID and key, or the robot's
+	// address and location secret. Basic authentication is not encrypted, so the RTSP server
+	// should only be reachable from trusted networks.
+	RTSPBindAddress string `json:"rtsp_bind_address,omitempty"`
+
+	// RTSPMaxClients is the number of clients the RTSP server serves at once. If zero, it
+	// serves 16.
+	RTSPMaxClients int `json:"rtsp_max_clients,omitempty"`
 }
 
 // MarshalJSON marshals out this config.
@@ -696,6 +734,14 @@ func (nc *NetworkConfig) Validate(path string) error {
 	if (nc.TLSCertFile == "") != (nc.TLSKeyFile == "") {
 		return resource.NewConfigValidationError(path, errors.New("must provide both tls_cert_file and tls_key_file"))
 	}
+	if nc.RTSPBindAddress != "" {
+		if _, _, err := net.SplitHostPort(nc.RTSPBindAddress); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "error validating rtsp_bind_address"))
+		}
+	}
+	if nc.RTSPMaxClients < 0 {
+		return resource.NewConfigValidationError(path, errors.New("rtsp_max_clients cannot be negative"))
+	}
 
 	return nc.Sessions.Validate(path + ".sessions")
 }
@@ -587,6 +587,20 @@ func TestConfigEnsurePartialStart(t *testing.T) {
 	invalidNetwork.Network.TLSKeyFile = ""
 	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidNetwork.Network.RTSPBindAddress = "woop"
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `rtsp_bind_address`)
+
+	invalidNetwork.Network.RTSPBindAddress = "localhost:8554"
+	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
+
+	invalidNetwork.Network.RTSPMaxClients = -1
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `rtsp_max_clients`)
+	invalidNetwork.Network.RTSPMaxClients = 0
+
 	invalidNetwork.Network.BindAddress = "woop"
 	err = invalidNetwork.Ensure(false, logger)
 	test.That(t, err, test.ShouldNotBeNil)
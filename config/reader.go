@@ -132,6 +132,53 @@ func clearCache(id string) {
 	})
 }
 
+// CacheInfo describes the cached copy of the last config a robot part read from the cloud, which the
+// robot comes up from when it cannot reach the cloud on boot.
+type CacheInfo struct {
+	// Path is where the config is cached.
+	Path string
+	// LastUpdated is when the config was last read from the cloud and cached.
+	LastUpdated time.Time
+	// Packages are the packages used by the cached config, and MissingPackages are the ones among them
+	// which have not been downloaded. The robot can only come up fully from the cache if none are missing.
+	Packages        []PackageConfig
+	MissingPackages []PackageConfig
+}
+
+// Age returns how long ago the cached config was read from the cloud.
+func (info CacheInfo) Age() time.Duration {
+	return time.Since(info.LastUpdated)
+}
+
+// CachedConfigInfo returns how fresh the cached cloud config of the robot part with the given ID is,
+// and which of its packages are in the packages directory, which defaults to ~/.viam/packages if empty.
+// The error satisfies os.IsNotExist if nothing is cached.
+func CachedConfigInfo(id, packagesDir string) (CacheInfo, error) {
+	path := getCloudCacheFilePath(id)
+	fInfo, err := os.Stat(path)
+	if err != nil {
+		return CacheInfo{}, err
+	}
+	cachedCfg, err := readFromCache(id)
+	if err != nil {
+		return CacheInfo{}, err
+	}
+	if packagesDir == "" {
+		packagesDir = viamPackagesDir
+	}
+	info := CacheInfo{
+		Path:        path,
+		LastUpdated: fInfo.ModTime(),
+		Packages:    cachedCfg.Packages,
+	}
+	for _, pkg := range cachedCfg.Packages {
+		if _, err := os.Stat(pkg.LocalDataDirectory(packagesDir)); err != nil {
+			info.MissingPackages = append(info.MissingPackages, pkg)
+		}
+	}
+	return info, nil
+}
+
 func readCertificateDataFromCloudGRPC(ctx context.Context,
 	signalingInsecure bool,
 	cloudConfigFromDisk *Cloud,
@@ -245,6 +292,7 @@ func readFromCloud(
 	if cfg.Cloud == nil {
 		return nil, errors.New("expected config to have cloud section")
 	}
+	cfg.CachedAt = unprocessedConfig.CachedAt
 
 	tls := tlsConfig{
 		// both fields are empty if not cached, since its a separate request, which we
@@ -259,6 +307,7 @@ func readFromCloud(
 			return nil, err
 		}
 	}
+	cachedTLS := tls
 
 	if prevCfg != nil && shouldCheckForCert(prevCfg.Cloud, cfg.Cloud) {
 		checkForNewCert = true
@@ -311,11 +360,17 @@ func readFromCloud(
 	}
 
 	mergeCloudConfig(cfg)
+	// The cloud config has no telemetry section yet, so the one in the local config applies.
+	cfg.Telemetry = originalCfg.Telemetry
 	unprocessedConfig.Cloud.TLSCertificate = tls.certificate
 	unprocessedConfig.Cloud.TLSPrivateKey = tls.privateKey
 
-	if err := storeToCache(cloudCfg.ID, unprocessedConfig); err != nil {
-		logger.Errorw("failed to cache config", "error", err)
+	// A config read from the cache is only stored again if its certificate was refreshed, so that the
+	// cache keeps recording when the config was last read from the cloud.
+	if !cached || tls != cachedTLS {
+		if err := storeToCache(cloudCfg.ID, unprocessedConfig); err != nil {
+			logger.Errorw("failed to cache config", "error", err)
+		}
 	}
 
 	return cfg, nil
@@ -621,9 +676,14 @@ func getFromCloudOrCache(ctx context.Context, cloudCfg *Cloud, shouldReadFromCac
 			}
 
 			lastUpdated := "unknown"
-			if fInfo, err := os.Stat(getCloudCacheFilePath(cloudCfg.ID)); err == nil {
+			if info, infoErr := CachedConfigInfo(cloudCfg.ID, cachedConfig.PackagePath); infoErr == nil {
 				// Use logging.DefaultTimeFormatStr since this time will be logged.
-				lastUpdated = fInfo.ModTime().Format(logging.DefaultTimeFormatStr)
+				lastUpdated = info.LastUpdated.Format(logging.DefaultTimeFormatStr)
+				cachedConfig.CachedAt = info.LastUpdated
+				if len(info.MissingPackages) > 0 {
+					logger.Warnw("cached config uses packages which have not been downloaded; they will be once the cloud is reachable",
+						"missing packages", len(info.MissingPackages))
+				}
 			}
 			logger.Warnw("unable to get cloud config; using cached version", "config last updated", lastUpdated, "error", err)
 			cached = true
@@ -116,6 +116,10 @@ func TestFromReader(t *testing.T) {
 		expectedCloud.TLSPrivateKey = "key"
 		expectedCloud.RefreshInterval = time.Duration(10000000000)
 		test.That(t, gotCfg.Cloud, test.ShouldResemble, &expectedCloud)
+
+		info, err := CachedConfigInfo(robotPartID, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotCfg.CachedAt, test.ShouldEqual, info.LastUpdated)
 	})
 }
 
@@ -148,6 +152,8 @@ func TestStoreToCache(t *testing.T) {
 	// read config from cloud, confirm consistency
 	cloudCfg, err := readFromCloud(ctx, cfg, nil, true, false, logger)
 	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloudCfg.CachedAt.IsZero(), test.ShouldBeFalse)
+	cloudCfg.CachedAt = time.Time{}
 	test.That(t, cloudCfg, test.ShouldResemble, cfg)
 
 	// Modify our config
@@ -168,9 +174,34 @@ func TestStoreToCache(t *testing.T) {
 	// read updated cloud config, confirm that it now matches our updated cfg
 	cloudCfg3, err := readFromCloud(ctx, cfg, nil, true, false, logger)
 	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloudCfg3.CachedAt.IsZero(), test.ShouldBeFalse)
+	cloudCfg3.CachedAt = time.Time{}
 	test.That(t, cloudCfg3, test.ShouldResemble, cfg)
 }
 
+func TestCachedConfigInfo(t *testing.T) {
+	id := uuid.New().String()
+	_, err := CachedConfigInfo(id, "")
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+	packagesDir := t.TempDir()
+	downloaded := PackageConfig{Name: "downloaded", Package: "org/downloaded", Version: "1", Type: PackageTypeModule}
+	missing := PackageConfig{Name: "missing", Package: "org/missing", Version: "1", Type: PackageTypeModule}
+	test.That(t, os.MkdirAll(downloaded.LocalDataDirectory(packagesDir), 0o700), test.ShouldBeNil)
+
+	before := time.Now().Add(-time.Second)
+	test.That(t, storeToCache(id, &Config{Packages: []PackageConfig{downloaded, missing}}), test.ShouldBeNil)
+	defer clearCache(id)
+
+	info, err := CachedConfigInfo(id, packagesDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Path, test.ShouldEqual, getCloudCacheFilePath(id))
+	test.That(t, info.LastUpdated, test.ShouldHappenAfter, before)
+	test.That(t, info.Age(), test.ShouldBeLessThan, time.Minute)
+	test.That(t, info.Packages, test.ShouldResemble, []PackageConfig{downloaded, missing})
+	test.That(t, info.MissingPackages, test.ShouldResemble, []PackageConfig{missing})
+}
+
 func TestCacheInvalidation(t *testing.T) {
 	id := uuid.New().String()
 	// store invalid config in cache
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.viam.com/rdk/utils"
+)
+
+// secretKeyParts are the parts of attribute and environment variable names which mark their values as secret.
+var secretKeyParts = []string{"secret", "password", "passwd", "token", "apikey", "api_key", "private_key", "credential"}
+
+// isSecretKey returns whether the value of an attribute or environment variable is likely to be secret, going
+// by its name.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactSecrets removes the secrets from a config which has been copied with CopyOnlyPublicFields, so that it can
+// be handed out: its cloud credentials, the config of its auth handlers, the credentials of its remotes, URL
+// passwords and the values of attributes and environment variables whose names look secret. It returns the
+// path of each value it removed, which must be configured again where the config is used.
+func RedactSecrets(cfg *Config) []string {
+	var redacted []string
+	redact := func(path string) {
+		redacted = append(redacted, path)
+	}
+
+	if cfg.Cloud != nil {
+		cfg.Cloud = nil
+		redact("cloud")
+	}
+	for i := range cfg.Auth.Handlers {
+		if len(cfg.Auth.Handlers[i].Config) != 0 {
+			cfg.Auth.Handlers[i].Config = nil
+			redact(fmt.Sprintf("auth.handlers.%s", cfg.Auth.Handlers[i].Type))
+		}
+	}
+	for i := range cfg.Remotes {
+		rem := &cfg.Remotes[i]
+		if rem.Secret != "" {
+			rem.Secret = ""
+			redact(fmt.Sprintf("remotes.%s.secret", rem.Name))
+		}
+		if rem.Auth.Credentials != nil {
+			rem.Auth.Credentials = nil
+			redact(fmt.Sprintf("remotes.%s.auth.credentials", rem.Name))
+		}
+		if address := redactURL(rem.Address); address != rem.Address {
+			rem.Address = address
+			redact(fmt.Sprintf("remotes.%s.address", rem.Name))
+		}
+	}
+	for i := range cfg.Modules {
+		for key := range cfg.Modules[i].Environment {
+			if isSecretKey(key) {
+				delete(cfg.Modules[i].Environment, key)
+				redact(fmt.Sprintf("modules.%s.env.%s", cfg.Modules[i].Name, key))
+			}
+		}
+	}
+	for i := range cfg.Processes {
+		for key := range cfg.Processes[i].Environment {
+			if isSecretKey(key) {
+				delete(cfg.Processes[i].Environment, key)
+				redact(fmt.Sprintf("processes.%s.env.%s", cfg.Processes[i].ID, key))
+			}
+		}
+	}
+	for i := range cfg.Components {
+		redactAttributes(cfg.Components[i].Attributes, "components."+cfg.Components[i].Name+".attributes", redact)
+	}
+	for i := range cfg.Services {
+		redactAttributes(cfg.Services[i].Attributes, "services."+cfg.Services[i].Name+".attributes", redact)
+	}
+	return redacted
+}
+
+// redactAttributes removes the secrets from attributes, including those of nested attributes.
+func redactAttributes(attrs utils.AttributeMap, path string, redact func(path string)) {
+	for key, value := range attrs {
+		keyPath := path + "." + key
+		if isSecretKey(key) {
+			delete(attrs, key)
+			redact(keyPath)
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if redactedURL := redactURL(v); redactedURL != v {
+				attrs[key] = redactedURL
+				redact(keyPath)
+			}
+		case map[string]interface{}:
+			redactAttributes(v, keyPath, redact)
+		case []interface{}:
+			for i, elem := range v {
+				if nested, ok := elem.(map[string]interface{}); ok {
+					redactAttributes(nested, fmt.Sprintf("%s.%d", keyPath, i), redact)
+				}
+			}
+		}
+	}
+}
+
+// redactURL removes the password from a URL, and returns any other string unchanged.
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return s
+	}
+	u.User = url.User(u.User.Username())
+	return u.String()
+}
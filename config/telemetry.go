@@ -0,0 +1,67 @@
+package config
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// defaultTraceSampleRate is the fraction of traces exported when no sample rate is configured.
+const defaultTraceSampleRate = 0.01
+
+// TelemetryConfig describes where and how a robot ships its traces and logs for observability.
+type TelemetryConfig struct {
+	OTLP *OTLPConfig `json:"otlp,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (tc *TelemetryConfig) Validate(path string) error {
+	if tc.OTLP == nil {
+		return nil
+	}
+	return tc.OTLP.Validate(path + ".otlp")
+}
+
+// OTLPConfig configures exporting traces and logs to an OpenTelemetry collector over OTLP/gRPC.
+type OTLPConfig struct {
+	// Endpoint is the host:port of the collector.
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool `json:"insecure,omitempty"`
+	// Headers are sent as gRPC metadata on every export, typically for authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+	// ServiceName identifies this robot to the collector. Defaults to the robot's cloud ID or "viam-server".
+	ServiceName string `json:"service_name,omitempty"`
+
+	// Traces enables exporting trace spans.
+	Traces bool `json:"traces,omitempty"`
+	// TraceSampleRate is the fraction of traces to sample, between 0 and 1. Defaults to 0.01.
+	TraceSampleRate float64 `json:"trace_sample_rate,omitempty"`
+
+	// Logs enables exporting structured logs.
+	Logs bool `json:"logs,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (oc *OTLPConfig) Validate(path string) error {
+	if oc.Endpoint == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "endpoint")
+	}
+	if _, _, err := net.SplitHostPort(oc.Endpoint); err != nil {
+		return resource.NewConfigValidationError(path, errors.Wrap(err, "error validating endpoint"))
+	}
+	if oc.TraceSampleRate < 0 || oc.TraceSampleRate > 1 {
+		return resource.NewConfigValidationError(path, errors.New("trace_sample_rate must be between 0 and 1"))
+	}
+	return nil
+}
+
+// SampleRate returns the fraction of traces to sample, which defaults to 0.01 when none is configured.
+func (oc *OTLPConfig) SampleRate() float64 {
+	if oc.TraceSampleRate == 0 {
+		return defaultTraceSampleRate
+	}
+	return oc.TraceSampleRate
+}
@@ -0,0 +1,40 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestTelemetryConfigValidate(t *testing.T) {
+	tc := config.TelemetryConfig{}
+	test.That(t, tc.Validate("telemetry"), test.ShouldBeNil)
+
+	tc.OTLP = &config.OTLPConfig{}
+	err := tc.Validate("telemetry")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `telemetry.otlp`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"endpoint" is required`)
+
+	tc.OTLP.Endpoint = "collector"
+	err = tc.Validate("telemetry")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `missing port`)
+
+	tc.OTLP.Endpoint = "collector:4317"
+	tc.OTLP.TraceSampleRate = 1.5
+	err = tc.Validate("telemetry")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `trace_sample_rate`)
+
+	tc.OTLP.TraceSampleRate = 0
+	test.That(t, tc.Validate("telemetry"), test.ShouldBeNil)
+	test.That(t, tc.OTLP.TraceSampleRate, test.ShouldEqual, 0)
+	test.That(t, tc.OTLP.SampleRate(), test.ShouldEqual, 0.01)
+
+	tc.OTLP.TraceSampleRate = 1
+	test.That(t, tc.Validate("telemetry"), test.ShouldBeNil)
+	test.That(t, tc.OTLP.SampleRate(), test.ShouldEqual, 1)
+}
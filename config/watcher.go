@@ -57,6 +57,8 @@ func newCloudWatcher(ctx context.Context, config *Config, logger logging.Logger)
 	var prevCfg *Config
 	utils.ManagedGo(func() {
 		firstRead := true
+		// offline is whether the last config came from the cache because the cloud could not be reached.
+		offline := !config.CachedAt.IsZero()
 		for {
 			// have first read with the watcher happen much faster in case the request timed out on the initial read on server startup
 			interval := config.Cloud.RefreshInterval
@@ -76,6 +78,10 @@ func newCloudWatcher(ctx context.Context, config *Config, logger logging.Logger)
 				logger.Errorw("error reading cloud config", "error", err)
 				continue
 			}
+			if offline {
+				logger.Info("reconnected to the cloud; no longer using the cached config")
+				offline = false
+			}
 			if cp, err := newConfig.CopyOnlyPublicFields(); err == nil {
 				prevCfg = cp
 			}
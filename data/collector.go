@@ -22,6 +22,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.viam.com/rdk/internal/metrics"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/datamanager/datacapture"
@@ -30,9 +31,23 @@ import (
 // The cutoff at which if interval < cutoff, a sleep based capture func is used instead of a ticker.
 var sleepCaptureCutoff = 2 * time.Millisecond
 
+// The delay before subscribing to a streaming source again after its stream fails.
+var streamResubscribeDelay = time.Second
+
 // CaptureFunc allows the creation of simple Capturers with anonymous functions.
 type CaptureFunc func(ctx context.Context, params map[string]*anypb.Any) (interface{}, error)
 
+// StreamCaptureFunc subscribes to a streaming source, such as a camera stream, and calls push for every reading as it
+// arrives, until the context is done or the stream fails. push takes the time the reading was captured if the source
+// reports it, or the zero time to stamp the reading with the time it arrived on the collector's clock, and a function
+// returning the reading in the form to capture, which is only called for the readings which are kept so that costly
+// conversions like image encoding are skipped for the others. push must not be called concurrently.
+type StreamCaptureFunc func(
+	ctx context.Context,
+	params map[string]*anypb.Any,
+	push func(timeCaptured time.Time, reading func() (interface{}, error)),
+) error
+
 // FromDMContextKey is used to check whether the context is from data management.
 // Deprecated: use a camera.Extra with camera.NewContext instead.
 type FromDMContextKey struct{}
@@ -69,9 +84,12 @@ type collector struct {
 	cancelCtx        context.Context
 	cancel           context.CancelFunc
 	captureFunc      CaptureFunc
+	streamFunc       StreamCaptureFunc
 	closed           bool
 	target           datacapture.BufferedWriter
 	lastLoggedErrors map[string]int64
+	componentName    string
+	methodName       string
 }
 
 // Close closes the channels backing the Collector. It should always be called before disposing of a Collector to avoid
@@ -94,6 +112,14 @@ func (c *collector) Close() {
 	close(c.captureErrors)
 	c.logRoutine.Wait()
 	c.closed = true
+	// stop exporting the depth of a queue which no longer exists
+	metrics.CaptureQueueDepth.DeleteLabelValues(c.componentName, c.methodName)
+}
+
+// setQueueDepth reports the number of readings waiting to be written. The gauge is looked up each time,
+// rather than kept, so that a collector replacing a closed one with the same labels is still exported.
+func (c *collector) setQueueDepth() {
+	metrics.CaptureQueueDepth.WithLabelValues(c.componentName, c.methodName).Set(float64(len(c.captureResults)))
 }
 
 func (c *collector) Flush() {
@@ -136,9 +162,12 @@ func (c *collector) Collect() {
 // avoid wasting CPU on a thread that's idling for the vast majority of the time.
 // [0]: https://www.mail-archive.com/golang-nuts@googlegroups.com/msg46002.html
 func (c *collector) capture(started chan struct{}) {
-	if c.interval < sleepCaptureCutoff {
+	switch {
+	case c.streamFunc != nil:
+		c.streamBasedCapture(started)
+	case c.interval < sleepCaptureCutoff:
 		c.sleepBasedCapture(started)
-	} else {
+	default:
 		c.tickerBasedCapture(started)
 	}
 }
@@ -203,6 +232,47 @@ func (c *collector) tickerBasedCapture(started chan struct{}) {
 	}
 }
 
+// streamBasedCapture subscribes to a streaming source and writes its readings as they arrive, stamped with the time
+// they were captured, rather than polling it. Readings captured sooner than the interval after the previous one are
+// dropped, so that the interval caps the capture rate. If the stream fails, it is subscribed to again.
+func (c *collector) streamBasedCapture(started chan struct{}) {
+	var lastCaptured time.Time
+	push := func(timeCaptured time.Time, getReading func() (interface{}, error)) {
+		if timeCaptured.IsZero() {
+			timeCaptured = c.clock.Now()
+		}
+		if !lastCaptured.IsZero() && timeCaptured.Sub(lastCaptured) < c.interval {
+			return
+		}
+		lastCaptured = timeCaptured
+		reading, err := getReading()
+		if err != nil {
+			if errors.Is(err, ErrNoCaptureToStore) {
+				c.logger.Debug("capture filtered out by modular resource")
+				return
+			}
+			c.captureErrors <- errors.Wrap(err, "error while capturing data")
+			return
+		}
+		captured := timestamppb.New(timeCaptured.UTC())
+		c.pushReading(reading, captured, captured)
+	}
+
+	close(started)
+	for {
+		err := c.streamFunc(c.cancelCtx, c.params, push)
+		if err != nil && c.cancelCtx.Err() == nil {
+			c.captureErrors <- errors.Wrap(err, "error while streaming data")
+		}
+		select {
+		case <-c.cancelCtx.Done():
+			close(c.captureResults)
+			return
+		case <-c.clock.After(streamResubscribeDelay):
+		}
+	}
+}
+
 func (c *collector) getAndPushNextReading() {
 	timeRequested := timestamppb.New(c.clock.Now().UTC())
 	reading, err := c.captureFunc(c.cancelCtx, c.params)
@@ -215,7 +285,11 @@ func (c *collector) getAndPushNextReading() {
 		c.captureErrors <- errors.Wrap(err, "error while capturing data")
 		return
 	}
+	c.pushReading(reading, timeRequested, timeReceived)
+}
 
+// pushReading converts a reading to SensorData and queues it to be written to the target.
+func (c *collector) pushReading(reading interface{}, timeRequested, timeReceived *timestamppb.Timestamp) {
 	var msg v1.SensorData
 	switch v := reading.(type) {
 	case []byte:
@@ -266,6 +340,7 @@ func (c *collector) getAndPushNextReading() {
 	// still work when this happens.
 	case <-c.cancelCtx.Done():
 	case c.captureResults <- &msg:
+		c.setQueueDepth()
 	}
 }
 
@@ -275,7 +350,20 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 	if err := params.Validate(); err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to construct collector for %s", params.ComponentName))
 	}
+	return newCollector(captureFunc, nil, params), nil
+}
+
+// NewStreamCollector returns a new Collector which subscribes to a streaming source with streamFunc and appends each
+// reading to target as it arrives, preserving the time it was captured instead of polling at the Interval. At most
+// one reading is kept per Interval.
+func NewStreamCollector(streamFunc StreamCaptureFunc, params CollectorParams) (Collector, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to construct collector for %s", params.ComponentName))
+	}
+	return newCollector(nil, streamFunc, params), nil
+}
 
+func newCollector(captureFunc CaptureFunc, streamFunc StreamCaptureFunc, params CollectorParams) *collector {
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 	var c clock.Clock
 	if params.Clock == nil {
@@ -292,15 +380,19 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		cancelCtx:        cancelCtx,
 		cancel:           cancelFunc,
 		captureFunc:      captureFunc,
+		streamFunc:       streamFunc,
 		target:           params.Target,
 		clock:            c,
 		closed:           false,
 		lastLoggedErrors: make(map[string]int64, 0),
-	}, nil
+		componentName:    params.ComponentName,
+		methodName:       params.MethodName,
+	}
 }
 
 func (c *collector) writeCaptureResults() error {
 	for msg := range c.captureResults {
+		c.setQueueDepth()
 		if err := c.target.Write(msg); err != nil {
 			return err
 		}
@@ -11,14 +11,17 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap/zapcore"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/internal/metrics"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/services/datamanager/datacapture"
 )
@@ -233,8 +236,11 @@ func TestClose(t *testing.T) {
 	case <-wrote:
 	}
 
-	// Close and validate no additional writes occur even after an additional interval.
+	// Close and validate no additional writes occur even after an additional interval, and that the
+	// depth of its queue is no longer exported.
+	exported := testutil.CollectAndCount(metrics.CaptureQueueDepth)
 	c.Close()
+	test.That(t, testutil.CollectAndCount(metrics.CaptureQueueDepth), test.ShouldEqual, exported-1)
 	mockClock.Add(interval)
 	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*10)
 	defer cancel()
@@ -322,6 +328,81 @@ func TestLogErrorsOnlyOnce(t *testing.T) {
 	c.Close()
 }
 
+func TestStreamCollector(t *testing.T) {
+	mockClock := clock.NewMock()
+	target := &recordingBuffer{}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockClock.Set(start.Add(time.Hour))
+	subscribed := make(chan int, 2)
+	arrived := make(chan [2]time.Time, 1)
+	var subscriptions, converted int
+	streamFunc := StreamCaptureFunc(func(
+		ctx context.Context,
+		_ map[string]*anypb.Any,
+		push func(timeCaptured time.Time, reading func() (interface{}, error)),
+	) error {
+		subscriptions++
+		if subscriptions > 1 {
+			// readings without a time of their own are stamped with the time they arrived on the collector's clock
+			before := mockClock.Now()
+			push(time.Time{}, func() (interface{}, error) {
+				return dummyBytesReading, nil
+			})
+			arrived <- [2]time.Time{before, mockClock.Now()}
+			subscribed <- subscriptions
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		subscribed <- subscriptions
+		// readings closer together than the interval are dropped without being converted
+		for _, offset := range []time.Duration{0, time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+			push(start.Add(offset), func() (interface{}, error) {
+				converted++
+				return dummyBytesReading, nil
+			})
+		}
+		return errors.New("stream ended")
+	})
+	c, err := NewStreamCollector(streamFunc, CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      10 * time.Millisecond,
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logging.NewTestLogger(t),
+		Clock:         mockClock,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	test.That(t, <-subscribed, test.ShouldEqual, 1)
+
+	// the stream is subscribed to again after it fails
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(streamResubscribeDelay)
+		select {
+		case n := <-subscribed:
+			test.That(tb, n, test.ShouldEqual, 2)
+		default:
+			tb.Fatal("not subscribed again")
+		}
+	})
+	c.Close()
+
+	written := target.written()
+	test.That(t, len(written), test.ShouldEqual, 3)
+	test.That(t, converted, test.ShouldEqual, 2)
+	for i, offset := range []time.Duration{0, 20 * time.Millisecond} {
+		test.That(t, written[i].GetBinary(), test.ShouldResemble, dummyBytesReading)
+		test.That(t, written[i].GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual, start.Add(offset))
+		test.That(t, written[i].GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual, start.Add(offset))
+	}
+	window := <-arrived
+	stamped := written[2].GetMetadata().GetTimeReceived().AsTime()
+	test.That(t, stamped.Before(window[0]), test.ShouldBeFalse)
+	test.That(t, stamped.After(window[1]), test.ShouldBeFalse)
+}
+
 func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	t.Helper()
 	for i := 0; i < n; i++ {
@@ -368,3 +449,29 @@ func (b *signalingBuffer) Flush() error {
 func (b *signalingBuffer) Path() string {
 	return b.bw.Path()
 }
+
+type recordingBuffer struct {
+	mu   sync.Mutex
+	data []*v1.SensorData
+}
+
+func (b *recordingBuffer) Write(data *v1.SensorData) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, data)
+	return nil
+}
+
+func (b *recordingBuffer) written() []*v1.SensorData {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.data
+}
+
+func (b *recordingBuffer) Flush() error {
+	return nil
+}
+
+func (b *recordingBuffer) Path() string {
+	return "recording"
+}
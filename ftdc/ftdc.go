@@ -0,0 +1,191 @@
+// Package ftdc records full-time diagnostic data capture (FTDC): periodic samples of a machine's numeric stats,
+// appended to rotating files on disk so that they can be inspected after the fact, e.g. when debugging a crash.
+package ftdc
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// DefaultMaxFileSize is the size in bytes a file is allowed to reach before a new one is started.
+	DefaultMaxFileSize = 1 << 20
+	// DefaultMaxFiles is how many files are kept. When another file is started, the oldest is removed.
+	DefaultMaxFiles = 10
+
+	filePrefix = "ftdc-"
+	fileSuffix = ".jsonl"
+	// fileTimeFormat names files by when they were started, such that their names sort in that order.
+	fileTimeFormat = "20060102T150405.000000000Z"
+)
+
+// Datum is a sample of stats, keyed by the name of what reported them and then by stat.
+type Datum struct {
+	Time  time.Time                     `json:"time"`
+	Stats map[string]map[string]float64 `json:"stats"`
+}
+
+// Recorder appends datums to the files of a directory, one JSON datum per line. It starts a new file once the
+// current one reaches its maximum size, and removes the oldest files so that at most its maximum number of files
+// are kept. A Recorder is safe for concurrent use.
+type Recorder struct {
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+	logger      logging.Logger
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRecorder returns a Recorder which writes to dir, creating it if needed, with the default limits.
+func NewRecorder(dir string, logger logging.Logger) (*Recorder, error) {
+	return NewRecorderWithLimits(dir, DefaultMaxFileSize, DefaultMaxFiles, logger)
+}
+
+// NewRecorderWithLimits returns a Recorder which writes to dir, creating it if needed, starting a new file once
+// the current one reaches maxFileSize bytes and keeping at most maxFiles files.
+func NewRecorderWithLimits(dir string, maxFileSize int64, maxFiles int, logger logging.Logger) (*Recorder, error) {
+	if maxFileSize <= 0 || maxFiles <= 0 {
+		return nil, errors.New("the maximum file size and number of files must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrap(err, "failed to create FTDC directory")
+	}
+	return &Recorder{dir: dir, maxFileSize: maxFileSize, maxFiles: maxFiles, logger: logger}, nil
+}
+
+// Record appends a datum to the current file.
+func (r *Recorder) Record(datum Datum) error {
+	data, err := json.Marshal(datum)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil || (r.size > 0 && r.size+int64(len(data)) > r.maxFileSize) {
+		if err := r.rotate(datum.Time); err != nil {
+			return err
+		}
+	}
+	n, err := r.file.Write(data)
+	r.size += int64(n)
+	return err
+}
+
+// rotate closes the current file, if any, starts a new one and removes the oldest files beyond the maximum.
+func (r *Recorder) rotate(now time.Time) error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			r.logger.Warnw("failed to close FTDC file", "file", r.file.Name(), "error", err)
+		}
+		r.file = nil
+	}
+	name := filepath.Join(r.dir, filePrefix+now.UTC().Format(fileTimeFormat)+fileSuffix)
+	//nolint:gosec
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create FTDC file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		//nolint:errcheck
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+
+	files, err := files(r.dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > r.maxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			r.logger.Warnw("failed to remove old FTDC file", "file", files[0], "error", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Close closes the current file. Recording again starts a new one.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Read returns the datums recorded to dir, oldest first.
+func Read(dir string) ([]Datum, error) {
+	files, err := files(dir)
+	if err != nil {
+		return nil, err
+	}
+	var datums []Datum
+	for _, name := range files {
+		fileDatums, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		datums = append(datums, fileDatums...)
+	}
+	return datums, nil
+}
+
+func readFile(name string) ([]Datum, error) {
+	//nolint:gosec
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//nolint:errcheck
+		file.Close()
+	}()
+	var datums []Datum
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, DefaultMaxFileSize)
+	for scanner.Scan() {
+		var datum Datum
+		if err := json.Unmarshal(scanner.Bytes(), &datum); err != nil {
+			return nil, errors.Wrapf(err, "invalid datum in %s", name)
+		}
+		datums = append(datums, datum)
+	}
+	return datums, scanner.Err()
+}
+
+// files returns the FTDC files in dir, oldest first.
+func files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), filePrefix) && strings.HasSuffix(entry.Name(), fileSuffix) {
+			names = append(names, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
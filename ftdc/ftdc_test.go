@@ -0,0 +1,65 @@
+package ftdc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	logger := logging.NewTestLogger(t)
+
+	_, err := NewRecorderWithLimits(dir, 0, 1, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	datum := func(i int) Datum {
+		return Datum{
+			Time:  start.Add(time.Duration(i) * time.Second),
+			Stats: map[string]map[string]float64{"rdk:component:arm/arm1": {"queue_depth": float64(i)}},
+		}
+	}
+
+	// each datum is larger than the maximum file size, so each is written to a file of its own
+	r, err := NewRecorderWithLimits(dir, 10, 3, logger)
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 5; i++ {
+		test.That(t, r.Record(datum(i)), test.ShouldBeNil)
+	}
+	test.That(t, r.Close(), test.ShouldBeNil)
+
+	// only the most recent files are kept
+	names, err := files(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldHaveLength, 3)
+	datums, err := Read(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldResemble, []Datum{datum(2), datum(3), datum(4)})
+
+	// datums are appended to the current file until it is full
+	dir = t.TempDir()
+	r, err = NewRecorder(dir, logger)
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 5; i++ {
+		test.That(t, r.Record(datum(i)), test.ShouldBeNil)
+	}
+	test.That(t, r.Close(), test.ShouldBeNil)
+	names, err = files(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldHaveLength, 1)
+	datums, err = Read(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldResemble, []Datum{datum(0), datum(1), datum(2), datum(3), datum(4)})
+
+	// other files in the directory are left alone
+	test.That(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o600), test.ShouldBeNil)
+	names, err = files(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldHaveLength, 1)
+}
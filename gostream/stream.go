@@ -299,6 +299,9 @@ func (bs *basicStream) processInputFrames() {
 				case <-bs.shutdownCtx.Done():
 					return
 				case bs.outputVideoChan <- encodedFrame:
+					if bs.config.OnVideoFrameSent != nil {
+						bs.config.OnVideoFrameSent()
+					}
 				}
 			}
 		}()
@@ -15,5 +15,9 @@ type StreamConfig struct {
 	// TargetFrameRate will hint to the stream to try to maintain this frame rate.
 	TargetFrameRate int
 
+	// OnVideoFrameSent, if set, is called every time an encoded video frame is handed
+	// off to the video track.
+	OnVideoFrameSent func()
+
 	Logger golog.Logger
 }
@@ -0,0 +1,122 @@
+// Package metrics defines the Prometheus metrics exported by viam-server and the
+// helpers used to record them.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "viam"
+
+var (
+	// ResourceRequests counts inbound gRPC requests by resource and method.
+	ResourceRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "resource_requests_total",
+		Help:      "Number of gRPC requests handled, partitioned by resource, method, and status code.",
+	}, []string{"resource", "method", "code"})
+
+	// ResourceRequestDuration observes the latency of inbound gRPC requests by resource and method.
+	ResourceRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "resource_request_duration_seconds",
+		Help:      "Latency of gRPC requests handled, partitioned by resource and method.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"resource", "method"})
+
+	// StreamFrames counts video frames encoded and sent per stream. Its rate is the stream's FPS.
+	StreamFrames = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stream_frames_total",
+		Help:      "Number of video frames encoded for WebRTC, partitioned by stream name.",
+	}, []string{"stream"})
+
+	// CaptureQueueDepth reports the number of captured readings waiting to be written to disk.
+	CaptureQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "capture_queue_depth",
+		Help:      "Number of captured readings queued for writing, partitioned by component and method.",
+	}, []string{"component", "method"})
+
+	// ModuleRestarts counts attempts to restart crashed modules.
+	ModuleRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "module_restarts_total",
+		Help:      "Number of restarts of crashed modules, partitioned by module and result.",
+	}, []string{"module", "result"})
+)
+
+// Registry holds every metric above. It is separate from the Prometheus default
+// registry so that importing the RDK as a library never pollutes an application's
+// own metrics.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		ResourceRequests,
+		ResourceRequestDuration,
+		StreamFrames,
+		CaptureQueueDepth,
+		ModuleRestarts,
+	)
+}
+
+// resourceNameFromRequest returns the resource name a request targets, following
+// the convention that resource requests carry a "name" field.
+func resourceNameFromRequest(req interface{}) string {
+	if named, ok := req.(interface{ GetName() string }); ok {
+		return named.GetName()
+	}
+	return ""
+}
+
+// UnaryServerInterceptor records request counts and latencies for unary calls.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(resourceNameFromRequest(req), info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamServerInterceptor records request counts and durations for streaming calls. The
+// resource name is taken from the first message received on the stream.
+func StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	wrapped := &namedServerStream{ServerStream: ss}
+	err := handler(srv, wrapped)
+	observe(wrapped.name, info.FullMethod, start, err)
+	return err
+}
+
+func observe(resourceName, method string, start time.Time, err error) {
+	ResourceRequests.WithLabelValues(resourceName, method, status.Code(err).String()).Inc()
+	ResourceRequestDuration.WithLabelValues(resourceName, method).Observe(time.Since(start).Seconds())
+}
+
+type namedServerStream struct {
+	grpc.ServerStream
+	name string
+}
+
+func (s *namedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.name == "" {
+		s.name = resourceNameFromRequest(m)
+	}
+	return err
+}
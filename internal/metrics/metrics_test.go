@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/GetEndPosition"}
+	okHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.GetEndPositionResponse{}, nil
+	}
+	errHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("whoops")
+	}
+
+	okCounter := ResourceRequests.WithLabelValues("arm1", info.FullMethod, "OK")
+	errCounter := ResourceRequests.WithLabelValues("arm1", info.FullMethod, "Unknown")
+	okBefore := testutil.ToFloat64(okCounter)
+	errBefore := testutil.ToFloat64(errCounter)
+
+	req := &pb.GetEndPositionRequest{Name: "arm1"}
+	_, err := UnaryServerInterceptor(context.Background(), req, info, okHandler)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = UnaryServerInterceptor(context.Background(), req, info, errHandler)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, testutil.ToFloat64(okCounter), test.ShouldEqual, okBefore+1)
+	test.That(t, testutil.ToFloat64(errCounter), test.ShouldEqual, errBefore+1)
+}
+
+func TestResourceNameFromRequest(t *testing.T) {
+	test.That(t, resourceNameFromRequest(&pb.StopRequest{Name: "arm2"}), test.ShouldEqual, "arm2")
+	test.That(t, resourceNameFromRequest(struct{}{}), test.ShouldEqual, "")
+}
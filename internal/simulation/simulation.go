@@ -0,0 +1,44 @@
+// Package simulation helps fake components simulate moves which take as long as they would on
+// hardware, instead of completing instantly.
+package simulation
+
+import (
+	"context"
+	"time"
+)
+
+// Step is how often a simulated move updates its position.
+const Step = 20 * time.Millisecond
+
+// Move simulates a move which takes the given duration, calling update with the fraction of the move
+// which is done, between 0 and 1, at every step. If the context is cancelled before the move finishes,
+// the last update is where the move stopped and the context's error is returned.
+func Move(ctx context.Context, duration time.Duration, update func(fraction float64)) error {
+	if duration <= 0 {
+		update(1)
+		return nil
+	}
+	start := time.Now()
+	ticker := time.NewTicker(Step)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			update(fraction(time.Since(start), duration))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		elapsed := time.Since(start)
+		update(fraction(elapsed, duration))
+		if elapsed >= duration {
+			return nil
+		}
+	}
+}
+
+func fraction(elapsed, duration time.Duration) float64 {
+	if elapsed >= duration {
+		return 1
+	}
+	return float64(elapsed) / float64(duration)
+}
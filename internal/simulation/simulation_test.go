@@ -0,0 +1,37 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestMove(t *testing.T) {
+	var fractions []float64
+	start := time.Now()
+	test.That(t, Move(context.Background(), 100*time.Millisecond, func(fraction float64) {
+		fractions = append(fractions, fraction)
+	}), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+	test.That(t, len(fractions), test.ShouldBeGreaterThan, 2)
+	test.That(t, fractions[len(fractions)-1], test.ShouldEqual, 1)
+	for i := 1; i < len(fractions); i++ {
+		test.That(t, fractions[i], test.ShouldBeGreaterThan, fractions[i-1])
+	}
+
+	fractions = nil
+	test.That(t, Move(context.Background(), 0, func(fraction float64) {
+		fractions = append(fractions, fraction)
+	}), test.ShouldBeNil)
+	test.That(t, fractions, test.ShouldResemble, []float64{1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var last float64
+	test.That(t, Move(ctx, time.Second, func(fraction float64) {
+		last = fraction
+	}), test.ShouldBeError, context.DeadlineExceeded)
+	test.That(t, last, test.ShouldBeBetween, 0, 0.5)
+}
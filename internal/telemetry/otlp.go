@@ -0,0 +1,344 @@
+// Package telemetry ships traces and logs from viam-server to external observability backends.
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+	goutils "go.viam.com/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	instrumentationScope = "go.viam.com/rdk"
+	defaultMaxQueueSize  = 10000
+	exportBatchSize      = 512
+	exportTimeout        = 10 * time.Second
+)
+
+// OTLPExporter exports opencensus trace spans and log entries to an OpenTelemetry collector
+// over OTLP/gRPC. It implements both trace.Exporter and logging.Appender, and is hooked up to
+// the process's tracing and logging by OTLP. Exports are batched and sent by a background
+// worker; OTLPExporters ought to be `Close`d prior to shutdown to flush remaining data.
+type OTLPExporter struct {
+	cfg      config.OTLPConfig
+	conn     *grpc.ClientConn
+	traces   coltracepb.TraceServiceClient
+	logs     collogspb.LogsServiceClient
+	resource *resourcepb.Resource
+
+	// queueMu guards spans, records and dropped.
+	queueMu      sync.Mutex
+	spans        []*tracepb.Span
+	records      []*logspb.LogRecord
+	dropped      int
+	maxQueueSize int
+
+	cancelCtx               context.Context
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+
+	// logs about exporting must not go through this exporter, which would cause a recursive loop.
+	loggerWithoutOTLP logging.Logger
+}
+
+// NewOTLPExporter connects to the collector described by cfg and starts the background
+// exporter. The connection is established lazily, so an unreachable collector does not
+// prevent startup.
+func NewOTLPExporter(cfg config.OTLPConfig, serviceName string) (*OTLPExporter, error) {
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to OTLP collector at %q", cfg.Endpoint)
+	}
+
+	if cfg.ServiceName != "" {
+		serviceName = cfg.ServiceName
+	}
+	attrs := []*commonpb.KeyValue{stringAttribute("service.name", serviceName)}
+	if config.Version != "" {
+		attrs = append(attrs, stringAttribute("service.version", config.Version))
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, stringAttribute("host.name", hostname))
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	exp := &OTLPExporter{
+		cfg:               cfg,
+		conn:              conn,
+		traces:            coltracepb.NewTraceServiceClient(conn),
+		logs:              collogspb.NewLogsServiceClient(conn),
+		resource:          &resourcepb.Resource{Attributes: attrs},
+		maxQueueSize:      defaultMaxQueueSize,
+		cancelCtx:         cancelCtx,
+		cancel:            cancel,
+		loggerWithoutOTLP: logging.NewLogger("otlp"),
+	}
+	exp.activeBackgroundWorkers.Add(1)
+	goutils.ManagedGo(exp.backgroundWorker, exp.activeBackgroundWorkers.Done)
+	return exp, nil
+}
+
+// Close stops exporting and makes a best effort at sending everything queued before returning.
+func (exp *OTLPExporter) Close() {
+	exp.cancel()
+	exp.activeBackgroundWorkers.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	if err := exp.flush(ctx); err != nil {
+		exp.loggerWithoutOTLP.Infow("error flushing OTLP exporter on close", "error", err)
+	}
+	goutils.UncheckedError(exp.conn.Close())
+}
+
+// ExportSpan queues a finished span for export.
+func (exp *OTLPExporter) ExportSpan(sd *trace.SpanData) {
+	span := spanFromSpanData(sd)
+	exp.queueMu.Lock()
+	defer exp.queueMu.Unlock()
+	if len(exp.spans) >= exp.maxQueueSize {
+		exp.spans = exp.spans[1:]
+		exp.dropped++
+	}
+	exp.spans = append(exp.spans, span)
+}
+
+// Write queues a log entry for export.
+func (exp *OTLPExporter) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := logRecordFromEntry(entry, fields)
+	exp.queueMu.Lock()
+	defer exp.queueMu.Unlock()
+	if len(exp.records) >= exp.maxQueueSize {
+		exp.records = exp.records[1:]
+		exp.dropped++
+	}
+	exp.records = append(exp.records, record)
+	return nil
+}
+
+// Sync is a no-op; queued logs are sent by the background worker.
+func (exp *OTLPExporter) Sync() error {
+	return nil
+}
+
+func (exp *OTLPExporter) backgroundWorker() {
+	normalInterval := time.Second
+	abnormalInterval := 10 * time.Second
+	interval := normalInterval
+	for goutils.SelectContextOrWait(exp.cancelCtx, interval) {
+		if err := exp.flush(exp.cancelCtx); err != nil && !errors.Is(err, context.Canceled) {
+			interval = abnormalInterval
+			exp.loggerWithoutOTLP.Infow("error exporting to OTLP collector", "endpoint", exp.cfg.Endpoint, "error", err)
+		} else {
+			interval = normalInterval
+		}
+	}
+}
+
+// flush exports everything currently queued in batches. Batches that fail to send are dropped
+// rather than retried so that a down collector cannot grow memory without bound.
+func (exp *OTLPExporter) flush(ctx context.Context) error {
+	exp.queueMu.Lock()
+	spans, records, dropped := exp.spans, exp.records, exp.dropped
+	exp.spans, exp.records, exp.dropped = nil, nil, 0
+	exp.queueMu.Unlock()
+
+	if dropped > 0 {
+		exp.loggerWithoutOTLP.Warnw("OTLP export queue overflowed; dropped oldest entries", "dropped", dropped)
+	}
+
+	for k, v := range exp.cfg.Headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+	}
+
+	var errs error
+	for len(spans) > 0 {
+		n := min(len(spans), exportBatchSize)
+		errs = multierr.Combine(errs, exp.exportSpans(ctx, spans[:n]))
+		spans = spans[n:]
+	}
+	for len(records) > 0 {
+		n := min(len(records), exportBatchSize)
+		errs = multierr.Combine(errs, exp.exportLogs(ctx, records[:n]))
+		records = records[n:]
+	}
+	return errs
+}
+
+func (exp *OTLPExporter) exportSpans(ctx context.Context, spans []*tracepb.Span) error {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	_, err := exp.traces.Export(ctx, &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: exp.resource,
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Scope: &commonpb.InstrumentationScope{Name: instrumentationScope, Version: config.Version},
+				Spans: spans,
+			}},
+		}},
+	})
+	return errors.Wrap(err, "failed to export spans")
+}
+
+func (exp *OTLPExporter) exportLogs(ctx context.Context, records []*logspb.LogRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	_, err := exp.logs.Export(ctx, &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: exp.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: instrumentationScope, Version: config.Version},
+				LogRecords: records,
+			}},
+		}},
+	})
+	return errors.Wrap(err, "failed to export logs")
+}
+
+func spanFromSpanData(sd *trace.SpanData) *tracepb.Span {
+	span := &tracepb.Span{
+		TraceId:           sd.TraceID[:],
+		SpanId:            sd.SpanID[:],
+		Name:              sd.Name,
+		Kind:              spanKind(sd.SpanKind),
+		StartTimeUnixNano: uint64(sd.StartTime.UnixNano()),
+		EndTimeUnixNano:   uint64(sd.EndTime.UnixNano()),
+		Attributes:        attributes(sd.Attributes),
+		Status:            &tracepb.Status{Message: sd.Status.Message},
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanId = sd.ParentSpanID[:]
+	}
+	if sd.Status.Code != trace.StatusCodeOK {
+		span.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+	}
+	for _, annotation := range sd.Annotations {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			TimeUnixNano: uint64(annotation.Time.UnixNano()),
+			Name:         annotation.Message,
+			Attributes:   attributes(annotation.Attributes),
+		})
+	}
+	for _, link := range sd.Links {
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:    link.TraceID[:],
+			SpanId:     link.SpanID[:],
+			Attributes: attributes(link.Attributes),
+		})
+	}
+	return span
+}
+
+func spanKind(kind int) tracepb.Span_SpanKind {
+	switch kind {
+	case trace.SpanKindServer:
+		return tracepb.Span_SPAN_KIND_SERVER
+	case trace.SpanKindClient:
+		return tracepb.Span_SPAN_KIND_CLIENT
+	default:
+		return tracepb.Span_SPAN_KIND_INTERNAL
+	}
+}
+
+func logRecordFromEntry(entry zapcore.Entry, fields []zapcore.Field) *logspb.LogRecord {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	attrs := attributes(enc.Fields)
+	attrs = append(attrs, stringAttribute("logger.name", entry.LoggerName))
+	if entry.Caller.Defined {
+		attrs = append(attrs, stringAttribute("code.filepath", entry.Caller.File), intAttribute("code.lineno", int64(entry.Caller.Line)))
+	}
+	if entry.Stack != "" {
+		attrs = append(attrs, stringAttribute("exception.stacktrace", entry.Stack))
+	}
+	return &logspb.LogRecord{
+		TimeUnixNano:         uint64(entry.Time.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		SeverityNumber:       severity(entry.Level),
+		SeverityText:         entry.Level.CapitalString(),
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: entry.Message}},
+		Attributes:           attrs,
+	}
+}
+
+func severity(level zapcore.Level) logspb.SeverityNumber {
+	switch level {
+	case zapcore.DebugLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case zapcore.InfoLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case zapcore.WarnLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case zapcore.ErrorLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel, zapcore.InvalidLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
+
+func attributes(values map[string]interface{}) []*commonpb.KeyValue {
+	attrs := make([]*commonpb.KeyValue, 0, len(values))
+	for k, v := range values {
+		attrs = append(attrs, &commonpb.KeyValue{Key: k, Value: anyValue(v)})
+	}
+	return attrs
+}
+
+func anyValue(v interface{}) *commonpb.AnyValue {
+	switch val := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: val}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: val}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(val)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(val)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: val}}
+	case uint32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(val)}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(val)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: val}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(val)}}
+	}
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: anyValue(value)}
+}
+
+func intAttribute(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: anyValue(value)}
+}
@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"reflect"
+	"sync"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap/zapcore"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultServiceName = "viam-server"
+	// defaultSampleRate is the sample rate of opencensus when none is configured, which is restored when
+	// traces stop being exported.
+	defaultSampleRate = 1e-4
+)
+
+// OTLP exports the process's traces and logs to the OpenTelemetry collector in a robot's config, and is
+// reconfigured whenever the config changes so that exporting can be changed without a restart.
+type OTLP struct {
+	// keepSampler leaves the process's trace sampler as it is instead of applying the configured sample rate.
+	keepSampler bool
+
+	mu          sync.Mutex
+	cfg         *config.OTLPConfig
+	serviceName string
+	exporter    *OTLPExporter
+}
+
+// NewOTLP returns an OTLP which exports the logs of the logger once configured to. If keepSampler is true,
+// traces are sampled as another exporter, such as the one of -output-telemetry, has already set up, rather
+// than at the configured sample rate.
+func NewOTLP(logger logging.Logger, keepSampler bool) *OTLP {
+	otlp := &OTLP{keepSampler: keepSampler}
+	logger.AddAppender(otlp)
+	return otlp
+}
+
+// Reconfigure starts, restarts or stops exporting as the telemetry section of the config says. Nothing is
+// done if that section is unchanged.
+func (otlp *OTLP) Reconfigure(cfg *config.Config) error {
+	var otlpCfg *config.OTLPConfig
+	if cfg.Telemetry != nil {
+		otlpCfg = cfg.Telemetry.OTLP
+	}
+	serviceName := defaultServiceName
+	if cfg.Cloud != nil && cfg.Cloud.ID != "" {
+		serviceName = cfg.Cloud.ID
+	}
+
+	otlp.mu.Lock()
+	if reflect.DeepEqual(otlpCfg, otlp.cfg) && serviceName == otlp.serviceName {
+		otlp.mu.Unlock()
+		return nil
+	}
+	prevCfg, prevExporter := otlp.cfg, otlp.exporter
+	otlp.cfg, otlp.serviceName, otlp.exporter = nil, "", nil
+	otlp.mu.Unlock()
+	// the previous exporter is flushed without holding the lock, which every log written takes.
+	otlp.stop(prevCfg, prevExporter)
+	if otlpCfg == nil {
+		return nil
+	}
+
+	exporter, err := NewOTLPExporter(*otlpCfg, serviceName)
+	if err != nil {
+		return err
+	}
+	if otlpCfg.Traces {
+		trace.RegisterExporter(exporter)
+		if !otlp.keepSampler {
+			trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(otlpCfg.SampleRate())})
+		}
+	}
+	otlp.mu.Lock()
+	otlp.cfg, otlp.serviceName, otlp.exporter = otlpCfg, serviceName, exporter
+	otlp.mu.Unlock()
+	return nil
+}
+
+// Close stops exporting and makes a best effort at sending everything queued before returning.
+func (otlp *OTLP) Close() {
+	otlp.mu.Lock()
+	cfg, exporter := otlp.cfg, otlp.exporter
+	otlp.cfg, otlp.serviceName, otlp.exporter = nil, "", nil
+	otlp.mu.Unlock()
+	otlp.stop(cfg, exporter)
+}
+
+func (otlp *OTLP) stop(cfg *config.OTLPConfig, exporter *OTLPExporter) {
+	if exporter == nil {
+		return
+	}
+	if cfg.Traces {
+		trace.UnregisterExporter(exporter)
+		if !otlp.keepSampler {
+			trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(defaultSampleRate)})
+		}
+	}
+	exporter.Close()
+}
+
+// Write queues a log entry for export, if logs are exported.
+func (otlp *OTLP) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	otlp.mu.Lock()
+	exporter := otlp.exporter
+	exportLogs := otlp.cfg != nil && otlp.cfg.Logs
+	otlp.mu.Unlock()
+	if exporter == nil || !exportLogs {
+		return nil
+	}
+	return exporter.Write(entry, fields)
+}
+
+// Sync is a no-op; queued logs are sent by the exporter's background worker.
+func (otlp *OTLP) Sync() error {
+	return nil
+}
@@ -0,0 +1,215 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opencensus.io/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+type fakeCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	collogspb.UnimplementedLogsServiceServer
+
+	mu      sync.Mutex
+	spans   []*tracepb.Span
+	records []*logspb.LogRecord
+	headers metadata.MD
+}
+
+func (c *fakeCollector) Export(
+	ctx context.Context,
+	req *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers, _ = metadata.FromIncomingContext(ctx)
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+type fakeLogsCollector struct {
+	*fakeCollector
+}
+
+func (c fakeLogsCollector) Export(
+	ctx context.Context,
+	req *collogspb.ExportLogsServiceRequest,
+) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			c.records = append(c.records, sl.LogRecords...)
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func TestOTLPExporter(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server := grpc.NewServer()
+	collector := &fakeCollector{}
+	coltracepb.RegisterTraceServiceServer(server, collector)
+	collogspb.RegisterLogsServiceServer(server, fakeLogsCollector{collector})
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Error(err)
+		}
+	}()
+	defer server.Stop()
+
+	exp, err := NewOTLPExporter(config.OTLPConfig{
+		Endpoint: listener.Addr().String(),
+		Insecure: true,
+		Headers:  map[string]string{"authorization": "secret"},
+	}, "test-robot")
+	test.That(t, err, test.ShouldBeNil)
+
+	exp.ExportSpan(&trace.SpanData{
+		SpanContext: trace.SpanContext{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}},
+		Name:        "arm.MoveToPosition",
+		StartTime:   time.Now().Add(-time.Second),
+		EndTime:     time.Now(),
+		Status:      trace.Status{Code: trace.StatusCodeUnknown, Message: "oops"},
+	})
+	test.That(t, exp.Write(zapcore.Entry{Level: zapcore.WarnLevel, Message: "hello"}, nil), test.ShouldBeNil)
+	exp.Close()
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	test.That(t, collector.spans, test.ShouldHaveLength, 1)
+	test.That(t, collector.spans[0].Name, test.ShouldEqual, "arm.MoveToPosition")
+	test.That(t, collector.spans[0].Status.Code, test.ShouldEqual, tracepb.Status_STATUS_CODE_ERROR)
+	test.That(t, collector.headers.Get("authorization"), test.ShouldResemble, []string{"secret"})
+	test.That(t, collector.records, test.ShouldHaveLength, 1)
+	test.That(t, collector.records[0].Body.GetStringValue(), test.ShouldEqual, "hello")
+	test.That(t, collector.records[0].SeverityNumber, test.ShouldEqual, logspb.SeverityNumber_SEVERITY_NUMBER_WARN)
+}
+
+func TestOTLPReconfigure(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server := grpc.NewServer()
+	collector := &fakeCollector{}
+	coltracepb.RegisterTraceServiceServer(server, collector)
+	collogspb.RegisterLogsServiceServer(server, fakeLogsCollector{collector})
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Error(err)
+		}
+	}()
+	defer server.Stop()
+
+	otlp := NewOTLP(logging.NewTestLogger(t), true)
+	defer otlp.Close()
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "hello"}
+
+	// nothing is exported until the config says to
+	test.That(t, otlp.Reconfigure(&config.Config{}), test.ShouldBeNil)
+	test.That(t, otlp.Write(entry, nil), test.ShouldBeNil)
+
+	cfg := &config.Config{Telemetry: &config.TelemetryConfig{OTLP: &config.OTLPConfig{
+		Endpoint: listener.Addr().String(),
+		Insecure: true,
+		Logs:     true,
+	}}}
+	test.That(t, otlp.Reconfigure(cfg), test.ShouldBeNil)
+	exporter := otlp.exporter
+	test.That(t, exporter, test.ShouldNotBeNil)
+	test.That(t, otlp.Write(entry, nil), test.ShouldBeNil)
+
+	// an unchanged config keeps the exporter
+	test.That(t, otlp.Reconfigure(cfg), test.ShouldBeNil)
+	test.That(t, otlp.exporter, test.ShouldEqual, exporter)
+
+	// removing the config flushes and stops the exporter
+	test.That(t, otlp.Reconfigure(&config.Config{}), test.ShouldBeNil)
+	test.That(t, otlp.exporter, test.ShouldBeNil)
+	test.That(t, otlp.Write(entry, nil), test.ShouldBeNil)
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	test.That(t, collector.records, test.ShouldHaveLength, 1)
+	test.That(t, collector.records[0].Body.GetStringValue(), test.ShouldEqual, "hello")
+}
+
+func TestSpanFromSpanData(t *testing.T) {
+	sd := &trace.SpanData{
+		SpanContext:  trace.SpanContext{TraceID: trace.TraceID{1, 2, 3}, SpanID: trace.SpanID{4, 5}},
+		ParentSpanID: trace.SpanID{6},
+		SpanKind:     trace.SpanKindServer,
+		Name:         "op",
+		StartTime:    time.Unix(1, 0),
+		EndTime:      time.Unix(2, 0),
+		Attributes:   map[string]interface{}{"resource": "arm1", "count": int64(3)},
+		Annotations:  []trace.Annotation{{Time: time.Unix(1, 500), Message: "halfway"}},
+	}
+	span := spanFromSpanData(sd)
+	test.That(t, span.TraceId, test.ShouldResemble, sd.TraceID[:])
+	test.That(t, span.SpanId, test.ShouldResemble, sd.SpanID[:])
+	test.That(t, span.ParentSpanId, test.ShouldResemble, sd.ParentSpanID[:])
+	test.That(t, span.Kind, test.ShouldEqual, tracepb.Span_SPAN_KIND_SERVER)
+	test.That(t, span.StartTimeUnixNano, test.ShouldEqual, uint64(1e9))
+	test.That(t, span.EndTimeUnixNano, test.ShouldEqual, uint64(2e9))
+	test.That(t, span.Status.Code, test.ShouldEqual, tracepb.Status_STATUS_CODE_UNSET)
+	test.That(t, span.Attributes, test.ShouldHaveLength, 2)
+	test.That(t, span.Events, test.ShouldHaveLength, 1)
+	test.That(t, span.Events[0].Name, test.ShouldEqual, "halfway")
+
+	sd.ParentSpanID = trace.SpanID{}
+	test.That(t, spanFromSpanData(sd).ParentSpanId, test.ShouldBeNil)
+}
+
+func TestLogRecordFromEntry(t *testing.T) {
+	entry := zapcore.Entry{
+		Level:      zapcore.ErrorLevel,
+		Time:       time.Unix(3, 0),
+		LoggerName: "robot_server.arm1",
+		Message:    "failed to move",
+		Caller:     zapcore.EntryCaller{Defined: true, File: "arm.go", Line: 42},
+	}
+	record := logRecordFromEntry(entry, []zapcore.Field{zap.String("error", "bad"), zap.Int("attempt", 2)})
+	test.That(t, record.TimeUnixNano, test.ShouldEqual, uint64(3e9))
+	test.That(t, record.SeverityNumber, test.ShouldEqual, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR)
+	test.That(t, record.SeverityText, test.ShouldEqual, "ERROR")
+	test.That(t, record.Body.GetStringValue(), test.ShouldEqual, "failed to move")
+
+	attrs := map[string]interface{}{}
+	for _, kv := range record.Attributes {
+		switch {
+		case kv.Value.GetStringValue() != "":
+			attrs[kv.Key] = kv.Value.GetStringValue()
+		default:
+			attrs[kv.Key] = kv.Value.GetIntValue()
+		}
+	}
+	test.That(t, attrs, test.ShouldResemble, map[string]interface{}{
+		"error":         "bad",
+		"attempt":       int64(2),
+		"logger.name":   "robot_server.arm1",
+		"code.filepath": "arm.go",
+		"code.lineno":   int64(42),
+	})
+}
@@ -149,6 +149,9 @@ func (imp *impl) shouldLog(logLevel Level) bool {
 		return true
 	}
 
+	if level, ok := overriddenLevel(imp.name); ok {
+		return logLevel >= level
+	}
 	return logLevel >= imp.level.Get()
 }
 
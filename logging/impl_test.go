@@ -298,3 +298,33 @@ func TestSublogger(t *testing.T) {
 	assertLogMatches(t, notStdout,
 		`2023-10-30T09:12:09.459Z	INFO	impl.sub	logging/impl_test.go:67	info log`)
 }
+
+func TestLevelOverrides(t *testing.T) {
+	notStdout := &bytes.Buffer{}
+	logger := &impl{
+		name:       "impl",
+		level:      NewAtomicLevelAt(WARN),
+		appenders:  []Appender{NewWriterAppender(notStdout)},
+		testHelper: func() {},
+	}
+	subLogger := logger.Sublogger("sub")
+	siblingLogger := logger.Sublogger("sub2")
+
+	subLogger.Info("info log")
+	test.That(t, notStdout.Len(), test.ShouldEqual, 0)
+
+	SetLevelOverride("impl.sub", DEBUG)
+	defer ClearLevelOverride("impl.sub")
+	test.That(t, LevelOverrides(), test.ShouldResemble, map[string]Level{"impl.sub": DEBUG})
+
+	subLogger.Sublogger("child").Debug("debug log")
+	assertLogMatches(t, notStdout,
+		`2023-10-30T09:12:09.459Z	DEBUG	impl.sub.child	logging/impl_test.go:67	debug log`)
+	siblingLogger.Info("info log")
+	logger.Info("info log")
+	test.That(t, notStdout.Len(), test.ShouldEqual, 0)
+
+	ClearLevelOverride("impl.sub")
+	subLogger.Info("info log")
+	test.That(t, notStdout.Len(), test.ShouldEqual, 0)
+}
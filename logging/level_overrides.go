@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// levelOverrides are log levels set at runtime by logger name. They take precedence over the level a logger
+// was created or configured with, for the named logger and its subloggers.
+var (
+	levelOverridesMu  sync.RWMutex
+	levelOverrides    = map[string]Level{}
+	hasLevelOverrides atomic.Bool
+)
+
+// SetLevelOverride makes the logger with the given name, and all of its subloggers, log at the given level
+// until ClearLevelOverride is called with the same name. Loggers that do not exist yet are included.
+func SetLevelOverride(name string, level Level) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	levelOverrides[name] = level
+	hasLevelOverrides.Store(true)
+}
+
+// ClearLevelOverride removes the level set with SetLevelOverride for the logger with the given name.
+func ClearLevelOverride(name string) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	delete(levelOverrides, name)
+	hasLevelOverrides.Store(len(levelOverrides) > 0)
+}
+
+// LevelOverrides returns the levels set with SetLevelOverride, by logger name.
+func LevelOverrides() map[string]Level {
+	levelOverridesMu.RLock()
+	defer levelOverridesMu.RUnlock()
+	overrides := make(map[string]Level, len(levelOverrides))
+	for name, level := range levelOverrides {
+		overrides[name] = level
+	}
+	return overrides
+}
+
+// LevelOverrideNames returns the names of the loggers with levels set with SetLevelOverride, sorted.
+func LevelOverrideNames() []string {
+	overrides := LevelOverrides()
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// overriddenLevel returns the level set for the logger with the given name, or for the closest of its parents
+// that has one.
+func overriddenLevel(name string) (Level, bool) {
+	if !hasLevelOverrides.Load() {
+		return 0, false
+	}
+	levelOverridesMu.RLock()
+	defer levelOverridesMu.RUnlock()
+	for {
+		if level, ok := levelOverrides[name]; ok {
+			return level, true
+		}
+		idx := strings.LastIndexByte(name, '.')
+		if idx < 0 {
+			return 0, false
+		}
+		name = name[:idx]
+	}
+}
@@ -0,0 +1,296 @@
+package logging
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultLogBufferSize is the number of entries a LogBuffer keeps for each logger by default.
+const DefaultLogBufferSize = 1000
+
+// maxLogBufferLoggers is how many loggers a LogBuffer keeps entries for. When another logger writes,
+// the entries of the logger which wrote least recently are dropped.
+const maxLogBufferLoggers = 256
+
+// logBufferTotalSizeFactor bounds the entries a LogBuffer keeps for all of its loggers to this many
+// times the entries it keeps for each, so that memory stays bounded when many loggers are chatty. When
+// the total is exceeded, the entries of the logger which wrote least recently are dropped.
+const logBufferTotalSizeFactor = 16
+
+// logBufferSubscriberQueueSize is how many entries may be waiting for a subscriber before new
+// entries are dropped for it.
+const logBufferSubscriberQueueSize = 256
+
+// BufferedLogEntry is a log entry retained by a LogBuffer.
+type BufferedLogEntry struct {
+	// Seq orders the entries of a LogBuffer by when they were written to it.
+	Seq        uint64                 `json:"seq"`
+	Time       time.Time              `json:"time"`
+	Level      string                 `json:"level"`
+	LoggerName string                 `json:"logger_name"`
+	Caller     string                 `json:"caller,omitempty"`
+	Message    string                 `json:"message"`
+	Stack      string                 `json:"stack,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+
+	level zapcore.Level
+}
+
+// LogBufferFilter selects entries from a LogBuffer. The zero value matches every entry at INFO
+// and above.
+type LogBufferFilter struct {
+	// MinLevel excludes entries below this level. Set it to DEBUG to include everything.
+	MinLevel Level
+	// Logger, if set, only matches entries of the logger with this name and of its subloggers, e.g.
+	// "rdk.resource_manager" matches "rdk.resource_manager" and "rdk.resource_manager.rdk:component:arm/arm1".
+	Logger string
+	// Since, if set, excludes entries logged before it.
+	Since time.Time
+	// Limit, if positive, returns at most this many of the most recent entries.
+	Limit int
+}
+
+func (f LogBufferFilter) matches(entry *BufferedLogEntry) bool {
+	if entry.level < f.MinLevel.AsZap() {
+		return false
+	}
+	if f.Logger != "" && entry.LoggerName != f.Logger &&
+		!(strings.HasPrefix(entry.LoggerName, f.Logger) && entry.LoggerName[len(f.Logger)] == '.') {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// LogBuffer is an Appender that keeps the most recent entries of each logger in memory so that
+// recent history is available even when logs are not being shipped anywhere else.
+type LogBuffer struct {
+	size       int
+	maxEntries int
+
+	mu          sync.Mutex
+	seq         uint64
+	entries     int
+	rings       map[string]*logRing
+	subscribers map[*logBufferSubscriber]struct{}
+}
+
+// NewLogBuffer creates a LogBuffer that keeps up to `size` entries for each of the most recently written
+// loggers, and up to 16 times that for all of them.
+func NewLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		size = DefaultLogBufferSize
+	}
+	return &LogBuffer{
+		size:        size,
+		maxEntries:  size * logBufferTotalSizeFactor,
+		rings:       map[string]*logRing{},
+		subscribers: map[*logBufferSubscriber]struct{}{},
+	}
+}
+
+// Write records the entry, evicting the oldest entry of the same logger if its buffer is full, and
+// hands it to any matching subscribers.
+func (buf *LogBuffer) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	buffered := &BufferedLogEntry{
+		Time:       entry.Time,
+		Level:      entry.Level.CapitalString(),
+		LoggerName: entry.LoggerName,
+		Message:    entry.Message,
+		Stack:      entry.Stack,
+		Fields:     enc.Fields,
+		level:      entry.Level,
+	}
+	if entry.Caller.Defined {
+		buffered.Caller = callerToString(&entry.Caller)
+	}
+	if len(buffered.Fields) == 0 {
+		buffered.Fields = nil
+	}
+
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	buf.seq++
+	buffered.Seq = buf.seq
+	ring, ok := buf.rings[entry.LoggerName]
+	if !ok {
+		if len(buf.rings) >= maxLogBufferLoggers {
+			buf.evictLeastRecentRing()
+		}
+		ring = &logRing{size: buf.size}
+		buf.rings[entry.LoggerName] = ring
+	}
+	if ring.push(buffered) {
+		buf.entries++
+	}
+	// a single ring never exceeds the total, so this stops before evicting the one just written.
+	for buf.entries > buf.maxEntries {
+		buf.evictLeastRecentRing()
+	}
+
+	for sub := range buf.subscribers {
+		if !sub.filter.matches(buffered) {
+			continue
+		}
+		select {
+		case sub.ch <- *buffered:
+		default:
+			// the subscriber is not keeping up; drop rather than block logging.
+		}
+	}
+	return nil
+}
+
+// evictLeastRecentRing drops the entries of the logger which wrote least recently.
+func (buf *LogBuffer) evictLeastRecentRing() {
+	var evict string
+	var evictSeq uint64
+	for name, ring := range buf.rings {
+		if seq := ring.lastSeq(); evict == "" || seq < evictSeq {
+			evict, evictSeq = name, seq
+		}
+	}
+	buf.entries -= len(buf.rings[evict].entries)
+	delete(buf.rings, evict)
+}
+
+// Sync is a no-op.
+func (buf *LogBuffer) Sync() error {
+	return nil
+}
+
+// Entries returns the buffered entries that match the filter, in the order they were written.
+func (buf *LogBuffer) Entries(filter LogBufferFilter) []BufferedLogEntry {
+	buf.mu.Lock()
+	var matched []BufferedLogEntry
+	for _, ring := range buf.rings {
+		ring.each(func(entry *BufferedLogEntry) {
+			if filter.matches(entry) {
+				matched = append(matched, *entry)
+			}
+		})
+	}
+	buf.mu.Unlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Seq < matched[j].Seq
+	})
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[len(matched)-filter.Limit:]
+	}
+	return matched
+}
+
+// Subscribe returns a channel that receives every subsequently written entry matching the filter.
+// Entries are dropped for a subscriber that does not keep up. The returned function must be
+// called to unsubscribe, after which the channel is closed.
+func (buf *LogBuffer) Subscribe(filter LogBufferFilter) (<-chan BufferedLogEntry, func()) {
+	sub := &logBufferSubscriber{
+		filter: filter,
+		ch:     make(chan BufferedLogEntry, logBufferSubscriberQueueSize),
+	}
+	buf.mu.Lock()
+	buf.subscribers[sub] = struct{}{}
+	buf.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			buf.mu.Lock()
+			delete(buf.subscribers, sub)
+			buf.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Follow calls fn with the buffered entries that match the filter, in the order they were written, and then
+// with every subsequently written entry that matches it, until ctx is done or fn returns an error, which is
+// returned. As with Subscribe, new entries are dropped if fn does not keep up.
+func (buf *LogBuffer) Follow(ctx context.Context, filter LogBufferFilter, fn func(BufferedLogEntry) error) error {
+	// subscribe before reading history so that nothing logged in between is missed.
+	live, unsubscribe := buf.Subscribe(LogBufferFilter{MinLevel: filter.MinLevel, Logger: filter.Logger})
+	defer unsubscribe()
+
+	var lastSent uint64
+	for _, entry := range buf.Entries(filter) {
+		if err := fn(entry); err != nil {
+			return err
+		}
+		lastSent = entry.Seq
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case entry := <-live:
+			// skip entries written before the history was read, which it included.
+			if entry.Seq <= lastSent {
+				continue
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type logBufferSubscriber struct {
+	filter LogBufferFilter
+	ch     chan BufferedLogEntry
+}
+
+// logRing is a circular buffer of up to size entries, which grows as entries are pushed.
+type logRing struct {
+	size    int
+	entries []*BufferedLogEntry
+	next    int
+	full    bool
+}
+
+// push adds an entry, replacing the oldest if the ring is full. It returns whether the ring grew.
+func (r *logRing) push(entry *BufferedLogEntry) bool {
+	grew := len(r.entries) < r.size
+	if grew {
+		r.entries = append(r.entries, entry)
+	} else {
+		r.entries[r.next] = entry
+	}
+	r.next = (r.next + 1) % r.size
+	if r.next == 0 {
+		r.full = true
+	}
+	return grew
+}
+
+// lastSeq returns the sequence number of the most recent entry.
+func (r *logRing) lastSeq() uint64 {
+	last := r.next - 1
+	if last < 0 {
+		last = len(r.entries) - 1
+	}
+	return r.entries[last].Seq
+}
+
+// each calls fn on every entry, oldest first.
+func (r *logRing) each(fn func(*BufferedLogEntry)) {
+	if r.full {
+		for _, entry := range r.entries[r.next:] {
+			fn(entry)
+		}
+	}
+	for _, entry := range r.entries[:r.next] {
+		fn(entry)
+	}
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestLogBuffer(t *testing.T) {
+	buf := NewLogBuffer(3)
+	logger := NewBlankLogger("robot")
+	logger.SetLevel(DEBUG)
+	logger.AddAppender(buf)
+	cam := logger.Sublogger("rdk:component:camera/cam1")
+	arm := logger.Sublogger("rdk:component:arm/arm1")
+
+	for i := 0; i < 5; i++ {
+		cam.Infow(fmt.Sprintf("cam %d", i), "i", i)
+	}
+	arm.Debug("arm debug")
+	arm.Error("arm error")
+
+	messages := func(entries []BufferedLogEntry) []string {
+		out := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			out = append(out, entry.Message)
+		}
+		return out
+	}
+
+	t.Run("ring evicts oldest per logger", func(t *testing.T) {
+		entries := buf.Entries(LogBufferFilter{Logger: "robot.rdk:component:camera/cam1"})
+		test.That(t, messages(entries), test.ShouldResemble, []string{"cam 2", "cam 3", "cam 4"})
+		test.That(t, entries[0].LoggerName, test.ShouldEqual, "robot.rdk:component:camera/cam1")
+		test.That(t, entries[0].Level, test.ShouldEqual, "INFO")
+		test.That(t, entries[0].Fields, test.ShouldResemble, map[string]interface{}{"i": int64(2)})
+		test.That(t, entries[1].Seq, test.ShouldEqual, entries[0].Seq+1)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		test.That(t, messages(buf.Entries(LogBufferFilter{})), test.ShouldHaveLength, 4)
+		test.That(t, messages(buf.Entries(LogBufferFilter{MinLevel: DEBUG})), test.ShouldHaveLength, 5)
+		test.That(t, messages(buf.Entries(LogBufferFilter{MinLevel: WARN})), test.ShouldResemble, []string{"arm error"})
+		armFilter := LogBufferFilter{Logger: "robot.rdk:component:arm/arm1", Limit: 1}
+		test.That(t, messages(buf.Entries(armFilter)), test.ShouldResemble, []string{"arm error"})
+		test.That(t, buf.Entries(LogBufferFilter{Since: time.Now().Add(time.Hour)}), test.ShouldBeEmpty)
+		// loggers match by their full name or as the parent of a sublogger
+		test.That(t, buf.Entries(LogBufferFilter{Logger: "robot"}), test.ShouldHaveLength, 4)
+		test.That(t, buf.Entries(LogBufferFilter{Logger: "rob"}), test.ShouldBeEmpty)
+		test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.rdk:component:arm"}), test.ShouldBeEmpty)
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		ch, unsubscribe := buf.Subscribe(LogBufferFilter{Logger: "robot.rdk:component:arm/arm1", MinLevel: INFO})
+		arm.Debug("filtered out")
+		cam.Info("other logger")
+		arm.Warn("streamed")
+		entry := <-ch
+		test.That(t, entry.Message, test.ShouldEqual, "streamed")
+		unsubscribe()
+		unsubscribe()
+		_, ok := <-ch
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
+
+func TestLogBufferMaxLoggers(t *testing.T) {
+	// large enough that the total of all entries is not what evicts loggers
+	buf := NewLogBuffer(100)
+	logger := NewBlankLogger("robot")
+	logger.AddAppender(buf)
+	for i := 0; i < maxLogBufferLoggers; i++ {
+		logger.Sublogger(fmt.Sprintf("logger%d", i)).Info("hello")
+	}
+	// logging again keeps a logger's entries
+	logger.Sublogger("logger0").Info("hello again")
+	logger.Sublogger("extra").Info("hello")
+
+	test.That(t, buf.rings, test.ShouldHaveLength, maxLogBufferLoggers)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger0"}), test.ShouldHaveLength, 2)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger1"}), test.ShouldBeEmpty)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger2"}), test.ShouldHaveLength, 1)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.extra"}), test.ShouldHaveLength, 1)
+}
+
+func TestLogBufferMaxEntries(t *testing.T) {
+	buf := NewLogBuffer(2)
+	logger := NewBlankLogger("robot")
+	logger.AddAppender(buf)
+	for i := 0; i < logBufferTotalSizeFactor; i++ {
+		sub := logger.Sublogger(fmt.Sprintf("logger%d", i))
+		sub.Info("one")
+		sub.Info("two")
+	}
+	test.That(t, buf.Entries(LogBufferFilter{}), test.ShouldHaveLength, 2*logBufferTotalSizeFactor)
+
+	// a full ring replacing its oldest entry doesn't grow the total
+	logger.Sublogger("logger0").Info("three")
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger0"}), test.ShouldHaveLength, 2)
+
+	// but a new entry does, which drops the logger which wrote least recently
+	logger.Sublogger("extra").Info("hello")
+	test.That(t, buf.entries, test.ShouldEqual, 2*logBufferTotalSizeFactor-1)
+	test.That(t, buf.Entries(LogBufferFilter{}), test.ShouldHaveLength, 2*logBufferTotalSizeFactor-1)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger1"}), test.ShouldBeEmpty)
+	test.That(t, buf.Entries(LogBufferFilter{Logger: "robot.logger0"}), test.ShouldHaveLength, 2)
+}
+
+func TestLogBufferFollow(t *testing.T) {
+	buf := NewLogBuffer(10)
+	logger := NewBlankLogger("robot")
+	logger.AddAppender(buf)
+	logger.Info("history 1")
+	logger.Debug("filtered out")
+	logger.Info("history 2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	followed := make(chan string, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- buf.Follow(ctx, LogBufferFilter{Limit: 1}, func(entry BufferedLogEntry) error {
+			followed <- entry.Message
+			return nil
+		})
+	}()
+	test.That(t, <-followed, test.ShouldEqual, "history 2")
+
+	logger.Debug("filtered out")
+	logger.Info("live")
+	test.That(t, <-followed, test.ShouldEqual, "live")
+
+	cancel()
+	test.That(t, <-errCh, test.ShouldBeError, context.Canceled)
+	test.That(t, followed, test.ShouldBeEmpty)
+}
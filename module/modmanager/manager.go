@@ -26,9 +26,12 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/config"
 	rdkgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/internal/metrics"
 	"go.viam.com/rdk/logging"
 	modlib "go.viam.com/rdk/module"
 	modmanageroptions "go.viam.com/rdk/module/modmanager/options"
@@ -590,6 +593,38 @@ func (mgr *Manager) IsModularResource(name resource.Name) bool {
 	return ok
 }
 
+// ResourceStats returns the stats of the modular resources which implement resource.Statser, by resource name.
+// Modules which cannot report stats, such as those built against an older SDK, are skipped.
+func (mgr *Manager) ResourceStats(ctx context.Context) map[resource.Name]map[string]float64 {
+	var mods []*module
+	mgr.mu.RLock()
+	mgr.modules.Range(func(_ string, mod *module) bool {
+		mods = append(mods, mod)
+		return true
+	})
+	mgr.mu.RUnlock()
+
+	stats := map[resource.Name]map[string]float64{}
+	for _, mod := range mods {
+		resp := &structpb.Struct{}
+		if err := mod.conn.Invoke(ctx, modlib.StatsMethod, &emptypb.Empty{}, resp); err != nil {
+			if status.Code(err) != codes.Unimplemented {
+				mgr.logger.CDebugw(ctx, "cannot get the stats of module resources", "module", mod.cfg.Name, "error", err)
+			}
+			continue
+		}
+		modStats, err := modlib.ParseStats(resp)
+		if err != nil {
+			mgr.logger.CDebugw(ctx, "cannot get the stats of module resources", "module", mod.cfg.Name, "error", err)
+			continue
+		}
+		for name, resStats := range modStats {
+			stats[name] = resStats
+		}
+	}
+	return stats
+}
+
 // RemoveResource requests the removal of a resource from a module.
 func (mgr *Manager) RemoveResource(ctx context.Context, name resource.Name) error {
 	mgr.mu.Lock()
@@ -916,6 +951,11 @@ func (mgr *Manager) attemptRestart(ctx context.Context, mod *module) []resource.
 
 	var success, processRestarted bool
 	defer func() {
+		result := "failure"
+		if success {
+			result = "success"
+		}
+		metrics.ModuleRestarts.WithLabelValues(mod.cfg.Name, result).Inc()
 		if !success {
 			if processRestarted {
 				if err := mod.stopProcess(); err != nil {
@@ -16,6 +16,7 @@ import (
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/components/base"
@@ -305,6 +306,16 @@ func TestModuleFunctions(t *testing.T) {
 		test.That(t, retCmd, test.ShouldResemble, testCmd)
 	})
 
+	t.Run("GetStats", func(t *testing.T) {
+		resp := &structpb.Struct{}
+		err := conn.Invoke(ctx, module.StatsMethod, &emptypb.Empty{}, resp)
+		test.That(t, err, test.ShouldBeNil)
+		stats, err := module.ParseStats(resp)
+		test.That(t, err, test.ShouldBeNil)
+		// the gizmo does not report any stats
+		test.That(t, stats, test.ShouldBeEmpty)
+	})
+
 	t.Run("RemoveResource", func(t *testing.T) {
 		_, err = m.RemoveResource(ctx, &pb.RemoveResourceRequest{Name: gizmoConf.Api + "/" + gizmoConf.Name})
 		test.That(t, err, test.ShouldBeNil)
@@ -0,0 +1,105 @@
+package module
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/resource"
+)
+
+// StatsServiceName is the name of the service through which a module reports the stats of its resources which
+// implement resource.Statser to its parent. Its messages are protobuf well-known types, so that it needs no
+// generated code.
+const StatsServiceName = "viam.rdk.module.v1.StatsService"
+
+// StatsMethod is the full name of the method which returns the stats of a module's resources.
+const StatsMethod = "/" + StatsServiceName + "/GetStats"
+
+// StatsServiceServer serves the stats of a module's resources.
+type StatsServiceServer interface {
+	// GetStats returns the stats of each resource, keyed by resource name and then by stat.
+	GetStats(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+}
+
+// StatsServiceDesc describes the stats service for registering with an rpc.Server.
+var StatsServiceDesc = grpc.ServiceDesc{
+	ServiceName: StatsServiceName,
+	HandlerType: (*StatsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStats",
+			Handler: func(
+				srv interface{},
+				ctx context.Context,
+				dec func(interface{}) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (interface{}, error) {
+				in := &emptypb.Empty{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(StatsServiceServer).GetStats(ctx, req.(*emptypb.Empty))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: StatsMethod}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+	},
+}
+
+// GetStats returns the stats of the module's resources which implement resource.Statser.
+func (m *Module) GetStats(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	m.mu.Lock()
+	statsers := map[resource.Name]resource.Statser{}
+	for res := range m.resLoggers {
+		name := res.Name()
+		coll, ok := m.collections[name.API]
+		if !ok {
+			continue
+		}
+		// resources which were rebuilt are replaced in their collection, and removed ones are gone from it.
+		current, err := coll.Resource(name.Name)
+		if err != nil {
+			continue
+		}
+		if statser, ok := current.(resource.Statser); ok {
+			statsers[name] = statser
+		}
+	}
+	m.mu.Unlock()
+
+	fields := make(map[string]interface{}, len(statsers))
+	for name, statser := range statsers {
+		stats := map[string]interface{}{}
+		for stat, value := range statser.Stats() {
+			stats[stat] = value
+		}
+		fields[name.String()] = stats
+	}
+	return structpb.NewStruct(fields)
+}
+
+// ParseStats returns the stats of resources returned by GetStats, by resource name.
+func ParseStats(resp *structpb.Struct) (map[resource.Name]map[string]float64, error) {
+	ret := make(map[resource.Name]map[string]float64, len(resp.GetFields()))
+	for nameStr, value := range resp.GetFields() {
+		name, err := resource.NewFromString(nameStr)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid resource name in stats")
+		}
+		stats := map[string]float64{}
+		for stat, statValue := range value.GetStructValue().GetFields() {
+			stats[stat] = statValue.GetNumberValue()
+		}
+		ret[name] = stats
+	}
+	return ret, nil
+}
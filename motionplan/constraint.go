@@ -357,6 +357,57 @@ func NewCollisionConstraint(
 	return constraint, nil
 }
 
+// NewSweptCollisionConstraint creates a SegmentConstraint which is violated if the volume swept out by the moving geometries between
+// any two consecutive states of a segment, interpolated at the given resolution, comes into collision with the static geometries.
+// Checking states alone misses motions which pass entirely through a thin obstacle between two checked states. Swept volumes are
+// conservatively approximated by capsules, see spatialmath.SweptCapsule. Collisions present between the moving and static geometries
+// as given, as well as those in collisionSpecifications, are ignored.
+func NewSweptCollisionConstraint(
+	moving, static []spatial.Geometry,
+	collisionSpecifications []*Collision,
+	collisionBufferMM, resolution float64,
+) (SegmentConstraint, error) {
+	zeroCG, err := setupZeroCG(moving, static, collisionSpecifications, collisionBufferMM)
+	if err != nil {
+		return nil, err
+	}
+
+	constraint := func(segment *ik.Segment) bool {
+		interpolatedConfigurations, err := interpolateSegment(segment, resolution)
+		if err != nil {
+			return false
+		}
+		var previous map[string]spatial.Geometry
+		for _, configuration := range interpolatedConfigurations {
+			internal, err := segment.Frame.Geometries(configuration)
+			if err != nil {
+				return false
+			}
+			current := map[string]spatial.Geometry{}
+			swept := make([]spatial.Geometry, 0, len(internal.Geometries()))
+			for _, geom := range internal.Geometries() {
+				current[geom.Label()] = geom
+				if start, ok := previous[geom.Label()]; ok {
+					sweptGeom, err := spatial.SweptCapsule(start, geom)
+					if err != nil {
+						return false
+					}
+					swept = append(swept, sweptGeom)
+				}
+			}
+			if len(swept) > 0 {
+				cg, err := newCollisionGraph(swept, static, zeroCG, false, collisionBufferMM)
+				if err != nil || len(cg.collisions(collisionBufferMM)) > 0 {
+					return false
+				}
+			}
+			previous = current
+		}
+		return true
+	}
+	return constraint, nil
+}
+
 // NewAbsoluteLinearInterpolatingConstraint provides a Constraint whose valid manifold allows a specified amount of deviation from the
 // shortest straight-line path between the start and the goal. linTol is the allowed linear deviation in mm, orientTol is the allowed
 // orientation deviation measured by norm of the R3AA orientation difference to the slerp path between start/goal orientations.
@@ -333,3 +333,39 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	}
 	bt = b1
 }
+
+func TestSweptCollisionConstraint(t *testing.T) {
+	ball, err := spatial.NewSphere(spatial.NewZeroPose(), 1, "ball")
+	test.That(t, err, test.ShouldBeNil)
+	slider, err := frame.NewTranslationalFrameWithGeometry("slider", r3.Vector{X: 1}, frame.Limit{Min: -200, Max: 200}, ball)
+	test.That(t, err, test.ShouldBeNil)
+	wall, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{0.5, 100, 100}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+
+	start := frame.FloatsToInputs([]float64{-100})
+	startGeoms, err := slider.Geometries(start)
+	test.That(t, err, test.ShouldBeNil)
+	obstacles := []spatial.Geometry{wall}
+
+	// with a coarse resolution, only the ends of the motion are checked, and neither is in collision with the wall.
+	const resolution = 500
+	stateConstraint, err := NewCollisionConstraint(startGeoms.Geometries(), obstacles, nil, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	sweptConstraint, err := NewSweptCollisionConstraint(startGeoms.Geometries(), obstacles, nil, defaultCollisionBufferMM, resolution)
+	test.That(t, err, test.ShouldBeNil)
+	handler := &ConstraintHandler{}
+	handler.AddStateConstraint(defaultObstacleConstraintDesc, stateConstraint)
+
+	through := &ik.Segment{StartConfiguration: start, EndConfiguration: frame.FloatsToInputs([]float64{100}), Frame: slider}
+	ok, _ := handler.CheckSegmentAndStateValidity(through, resolution)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	handler.AddSegmentConstraint(defaultSweptCollisionConstraintDesc, sweptConstraint)
+	ok, _ = handler.CheckSegmentAndStateValidity(through, resolution)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// a motion that stops short of the wall is still valid.
+	shortOf := &ik.Segment{StartConfiguration: start, EndConfiguration: frame.FloatsToInputs([]float64{-5}), Frame: slider}
+	ok, _ = handler.CheckSegmentAndStateValidity(shortOf, resolution)
+	test.That(t, ok, test.ShouldBeTrue)
+}
@@ -584,6 +584,25 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		return nil, err
 	}
 
+	if opt.SweptCollisionChecks && !pm.useTPspace {
+		obstacles := make([]spatialmath.Geometry, 0, len(worldGeometries.Geometries())+len(staticRobotGeometries))
+		obstacles = append(obstacles, worldGeometries.Geometries()...)
+		obstacles = append(obstacles, staticRobotGeometries...)
+		if len(obstacles) > 0 {
+			sweptConstraint, err := NewSweptCollisionConstraint(
+				movingRobotGeometries,
+				obstacles,
+				allowedCollisions,
+				collisionBufferMM,
+				opt.Resolution,
+			)
+			if err != nil {
+				return nil, err
+			}
+			opt.AddSegmentConstraint(defaultSweptCollisionConstraintDesc, sweptConstraint)
+		}
+	}
+
 	alg, ok := planningOpts["planning_alg"]
 	if ok {
 		planAlg, ok = alg.(string)
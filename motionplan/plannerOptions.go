@@ -55,6 +55,7 @@ const (
 	defaultObstacleConstraintDesc       = "Collision between the robot and an obstacle"
 	defaultSelfCollisionConstraintDesc  = "Collision between two robot components that are moving"
 	defaultRobotCollisionConstraintDesc = "Collision between a robot component that is moving and one that is stationary"
+	defaultSweptCollisionConstraintDesc = "Collision between the volume swept by a moving robot component and an obstacle"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultPathStepSize = 10
@@ -162,6 +163,11 @@ type plannerOptions struct {
 	// Number of seeds to pre-generate for bidirectional position-only solving.
 	PositionSeeds int `json:"position_seeds"`
 
+	// Also check the volume swept out by moving geometries between interpolated states against obstacles, so that motions cannot
+	// pass through thin obstacles between checked states. Swept volumes are conservative, so this may reject motions passing close
+	// to obstacles. Not applied when planning for TP-space frames.
+	SweptCollisionChecks bool `json:"swept_collision_checks"`
+
 	// This is how far cbirrt will try to extend the map towards a goal per-step. Determined from FrameStep
 	qstep []float64
 
@@ -0,0 +1,42 @@
+package operation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxArgumentSummaryLength bounds the encoded size of each summarized argument so that large
+// payloads such as images or point clouds don't bloat operation listings.
+const maxArgumentSummaryLength = 256
+
+// summarizeArguments returns the fields of a protobuf request as a JSON-compatible map, replacing
+// values whose encoding exceeds maxArgumentSummaryLength with a note of their size. It returns nil
+// if the request is not a protobuf message.
+func summarizeArguments(req interface{}) interface{} {
+	msg, ok := req.(proto.Message)
+	if !ok || msg == nil {
+		return nil
+	}
+	encoded, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil
+	}
+	for key, val := range fields {
+		fieldEncoded, err := json.Marshal(val)
+		if err != nil {
+			delete(fields, key)
+			continue
+		}
+		if len(fieldEncoded) > maxArgumentSummaryLength {
+			fields[key] = fmt.Sprintf("<%d bytes elided>", len(fieldEncoded))
+		}
+	}
+	return fields
+}
@@ -3,6 +3,7 @@ package operation
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -33,7 +34,51 @@ type Operation struct {
 
 	myManager *Manager
 	cancel    context.CancelFunc
+	labelsMu  sync.Mutex
 	labels    []string
+	// request is the RPC request that started this operation, if any.
+	request interface{}
+
+	progressMu sync.Mutex
+	progress   Progress
+}
+
+// Progress describes how far along a long-running operation is.
+type Progress struct {
+	// Percent is how complete the operation is, between 0 and 100.
+	Percent float64
+	// Phase describes what the operation is currently doing, e.g. "planning" or "executing".
+	Phase string
+	// Updated is when progress was last reported, or the zero time if it never has been.
+	Updated time.Time
+}
+
+// SetProgress reports how far along the operation is. Percent is clamped to [0, 100].
+func (o *Operation) SetProgress(percent float64, phase string) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	o.progressMu.Lock()
+	defer o.progressMu.Unlock()
+	o.progress = Progress{Percent: percent, Phase: phase, Updated: time.Now()}
+}
+
+// Progress returns the most recently reported progress of the operation.
+func (o *Operation) Progress() Progress {
+	o.progressMu.Lock()
+	defer o.progressMu.Unlock()
+	return o.progress
+}
+
+// ArgumentsSummary returns the arguments of the operation. For operations started by an RPC,
+// this is a summary of the request's fields with large values elided.
+func (o *Operation) ArgumentsSummary() interface{} {
+	if o.Arguments != nil {
+		return o.Arguments
+	}
+	return summarizeArguments(o.request)
 }
 
 // Cancel cancel the context associated with an operation.
@@ -43,12 +88,9 @@ func (o *Operation) Cancel() {
 
 // HasLabel returns true if this operation has a specific label.
 func (o *Operation) HasLabel(label string) bool {
-	for _, l := range o.labels {
-		if l == label {
-			return true
-		}
-	}
-	return false
+	o.labelsMu.Lock()
+	defer o.labelsMu.Unlock()
+	return slices.Contains(o.labels, label)
 }
 
 // CancelOtherWithLabel will cancel all operations besides this one with this label.
@@ -63,7 +105,11 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 		}
 	}
 
-	o.labels = append(o.labels, label)
+	o.labelsMu.Lock()
+	defer o.labelsMu.Unlock()
+	if !slices.Contains(o.labels, label) {
+		o.labels = append(o.labels, label)
+	}
 }
 
 func (o *Operation) cleanup() {
@@ -125,6 +171,15 @@ func (m *Manager) Create(ctx context.Context, method string, args interface{}) (
 }
 
 func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, func()) {
+	return m.createWithRequest(ctx, id, method, args, nil)
+}
+
+func (m *Manager) createWithRequest(
+	ctx context.Context,
+	id uuid.UUID,
+	method string,
+	args, request interface{},
+) (context.Context, func()) {
 	if ctx.Value(opidKey) != nil {
 		panic("operations cannot be nested")
 	}
@@ -161,6 +216,7 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 		Arguments: args,
 		Started:   time.Now(),
 		myManager: m,
+		request:   request,
 	}
 	if sess, ok := session.FromContext(ctx); ok {
 		op.SessionID = sess.ID()
@@ -172,6 +228,35 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 	return ctx, func() { op.cleanup() }
 }
 
+// CreateDetached puts a new operation on detachedCtx, in the same manager as the current Operation of ctx, for work
+// which carries on after the call in ctx returns, such as a navigation mission. The operation can be listed and
+// cancelled until the returned func is called. If ctx has no Operation, detachedCtx is returned as is.
+func CreateDetached(ctx, detachedCtx context.Context, method string, args interface{}) (context.Context, func()) {
+	o := Get(ctx)
+	if o == nil {
+		return detachedCtx, func() {}
+	}
+	return o.myManager.Create(detachedCtx, method, args)
+}
+
+// withoutOperation is a context which hides the Operation of the context it wraps.
+type withoutOperation struct {
+	context.Context
+}
+
+func (ctx withoutOperation) Value(key interface{}) interface{} {
+	if key == opidKey {
+		return nil
+	}
+	return ctx.Context.Value(key)
+}
+
+// WithoutOperation returns a context which is cancelled along with ctx but has no Operation, so that work done with
+// it, such as cancelling other operations with a label, doesn't apply to the Operation of ctx.
+func WithoutOperation(ctx context.Context) context.Context {
+	return withoutOperation{ctx}
+}
+
 // Get returns the current Operation. This can be nil.
 func Get(ctx context.Context) *Operation {
 	o := ctx.Value(opidKey)
@@ -181,6 +266,13 @@ func Get(ctx context.Context) *Operation {
 	return o.(*Operation)
 }
 
+// SetProgress reports the progress of the current Operation. If no Operation is set, it does nothing.
+func SetProgress(ctx context.Context, percent float64, phase string) {
+	if o := Get(ctx); o != nil {
+		o.SetProgress(percent, phase)
+	}
+}
+
 // CancelOtherWithLabel will cancel all operations besides this one with this label.
 // if no Operation is set, will do nothing.
 func CancelOtherWithLabel(ctx context.Context, label string) {
@@ -115,3 +115,77 @@ func TestCreateDuplicate(t *testing.T) {
 	cleanup()
 	test.That(t, op3Ctx.Err(), test.ShouldBeError, context.Canceled)
 }
+
+func TestProgress(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	h := NewManager(logger)
+
+	// no operation on the context is a no-op
+	SetProgress(context.Background(), 50, "ignored")
+
+	ctx, cleanup := h.Create(context.Background(), "move", nil)
+	defer cleanup()
+	op := Get(ctx)
+	test.That(t, op.Progress().Updated.IsZero(), test.ShouldBeTrue)
+
+	SetProgress(ctx, 25, "planning")
+	progress := op.Progress()
+	test.That(t, progress.Percent, test.ShouldEqual, 25)
+	test.That(t, progress.Phase, test.ShouldEqual, "planning")
+	test.That(t, progress.Updated.IsZero(), test.ShouldBeFalse)
+
+	op.SetProgress(150, "executing")
+	test.That(t, op.Progress().Percent, test.ShouldEqual, 100)
+	op.SetProgress(-1, "executing")
+	test.That(t, op.Progress().Percent, test.ShouldEqual, 0)
+}
+
+func TestCreateDetached(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	h := NewManager(logger)
+
+	// without an operation to detach from, there is nothing to create
+	detachedCtx, done := CreateDetached(context.Background(), context.Background(), "mission", nil)
+	test.That(t, Get(detachedCtx), test.ShouldBeNil)
+	done()
+
+	ctx, cleanup := h.Create(context.Background(), "SetMode", nil)
+	detachedCtx, done = CreateDetached(ctx, context.Background(), "mission", map[string]interface{}{"mode": "waypoint"})
+	// the detached operation outlives the one it was created from
+	cleanup()
+	ops := h.All()
+	test.That(t, ops, test.ShouldHaveLength, 1)
+	test.That(t, ops[0].Method, test.ShouldEqual, "mission")
+	test.That(t, Get(detachedCtx), test.ShouldEqual, ops[0])
+
+	ops[0].Cancel()
+	test.That(t, detachedCtx.Err(), test.ShouldBeError, context.Canceled)
+	done()
+	test.That(t, h.All(), test.ShouldBeEmpty)
+}
+
+func TestLabels(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	h := NewManager(logger)
+
+	ctx, cleanup := h.Create(context.Background(), "mission", nil)
+	defer cleanup()
+	for i := 0; i < 3; i++ {
+		CancelOtherWithLabel(ctx, "motion")
+	}
+	test.That(t, Get(ctx).labels, test.ShouldResemble, []string{"motion"})
+
+	// work done without the operation doesn't label it, so other operations can't cancel it by that label
+	CancelOtherWithLabel(WithoutOperation(ctx), "base")
+	test.That(t, Get(ctx).HasLabel("base"), test.ShouldBeFalse)
+	otherCtx, otherCleanup := h.Create(context.Background(), "Move", nil)
+	defer otherCleanup()
+	CancelOtherWithLabel(otherCtx, "base")
+	test.That(t, ctx.Err(), test.ShouldBeNil)
+
+	// but it is still cancelled along with the operation
+	noOpCtx := WithoutOperation(ctx)
+	test.That(t, Get(noOpCtx), test.ShouldBeNil)
+	Get(ctx).Cancel()
+	test.That(t, noOpCtx.Err(), test.ShouldBeError, context.Canceled)
+}
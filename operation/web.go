@@ -53,7 +53,7 @@ func (m *Manager) UnaryServerInterceptor(
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	ctx, done := m.CreateFromIncomingContext(ctx, info.FullMethod)
+	ctx, done := m.createFromIncomingContext(ctx, info.FullMethod, req)
 	defer done()
 	if op := Get(ctx); op != nil && op.ID.String() != "" {
 		utils.UncheckedError(grpc.SetHeader(ctx, metadata.MD{opidMetadataKey: []string{op.ID.String()}}))
@@ -80,17 +80,23 @@ func (m *Manager) StreamServerInterceptor(
 
 // CreateFromIncomingContext creates a new operation from an incoming context.
 func (m *Manager) CreateFromIncomingContext(ctx context.Context, method string) (context.Context, func()) {
+	return m.createFromIncomingContext(ctx, method, nil)
+}
+
+// createFromIncomingContext creates a new operation from an incoming context, keeping the request
+// so that its arguments can be listed.
+func (m *Manager) createFromIncomingContext(ctx context.Context, method string, req interface{}) (context.Context, func()) {
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		m.logger.CWarnw(ctx, "failed to pull metadata from context", "method", method)
-		return m.Create(ctx, method, nil)
+		return m.createWithRequest(ctx, uuid.New(), method, nil, req)
 	}
 	opid, err := GetOrCreateFromMetadata(meta)
 	if err != nil {
 		m.logger.CWarnw(ctx, "failed to create operation id from metadata", "error", err)
-		return m.Create(ctx, method, nil)
+		return m.createWithRequest(ctx, uuid.New(), method, nil, req)
 	}
-	return m.createWithID(ctx, opid, method, nil)
+	return m.createWithRequest(ctx, opid, method, nil, req)
 }
 
 // GetOrCreateFromMetadata returns an operation id from metadata, or generates a random
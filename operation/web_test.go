@@ -2,11 +2,15 @@ package operation
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/logging"
 )
@@ -37,3 +41,29 @@ func TestCreateFromIncomingContextWithOpid(t *testing.T) {
 	test.That(t, ops, test.ShouldHaveLength, 1)
 	test.That(t, ops[0].ID.String(), test.ShouldEqual, opid.String())
 }
+
+func TestUnaryServerInterceptorArguments(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m := NewManager(logger)
+
+	req := &commonpb.GetGeometriesRequest{
+		Name:  "arm1",
+		Extra: &structpb.Struct{Fields: map[string]*structpb.Value{"blob": structpb.NewStringValue(strings.Repeat("a", 1000))}},
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/GetGeometries"}
+	_, err := m.UnaryServerInterceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		ops := m.All()
+		test.That(t, ops, test.ShouldHaveLength, 1)
+		test.That(t, ops[0].Method, test.ShouldEqual, info.FullMethod)
+		test.That(t, ops[0].ArgumentsSummary(), test.ShouldResemble, map[string]interface{}{
+			"name":  "arm1",
+			"extra": "<1011 bytes elided>",
+		})
+		return nil, nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	ctx, done := m.Create(context.Background(), "explicit", map[string]interface{}{"a": 1})
+	defer done()
+	test.That(t, Get(ctx).ArgumentsSummary(), test.ShouldResemble, map[string]interface{}{"a": 1})
+}
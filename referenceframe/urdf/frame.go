@@ -14,6 +14,9 @@ type limit struct {
 	XMLName xml.Name `xml:"limit"`
 	Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
 	Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
+	// Effort and Velocity are required by the URDF specification but not used by RDK.
+	Effort   float64 `xml:"effort,attr"`
+	Velocity float64 `xml:"velocity,attr"`
 }
 
 type axis struct {
@@ -0,0 +1,210 @@
+package urdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// NewModelFromFrameSystem creates a urdf.Config struct which can be marshalled into xml and will be a valid .urdf file
+// representing the given frame system, e.g. for visualization in RViz or use in a simulator. Every frame becomes a link
+// attached to the link of its parent frame by a joint named after it with a "_joint" suffix. Models are expanded into
+// their individual links and joints, which are named after the model and separated by a colon, e.g. "arm:shoulder".
+// Revolute and prismatic frames become revolute (or continuous, if unlimited) and prismatic joints, static frames
+// become fixed joints, and frames which cannot be expressed as a single URDF joint become fixed joints at the pose
+// given by the inputs, or at their zero inputs if none are given. Capsules, which URDF cannot represent, are exported as
+// the cylinders enclosing them.
+func NewModelFromFrameSystem(fs referenceframe.FrameSystem, inputs map[string][]referenceframe.Input, name string) (*ModelConfig, error) {
+	if name == "" {
+		name = fs.Name()
+	}
+	e := &frameSystemExporter{inputs: inputs}
+	e.addLink(referenceframe.World)
+
+	// walk the frame system from the world outwards so that every parent link is exported before its children
+	children := map[string][]referenceframe.Frame{}
+	for _, frameName := range fs.FrameNames() {
+		f := fs.Frame(frameName)
+		parent, err := fs.Parent(f)
+		if err != nil {
+			return nil, err
+		}
+		children[parent.Name()] = append(children[parent.Name()], f)
+	}
+	queue := []string{referenceframe.World}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		frames := children[parent]
+		sort.Slice(frames, func(i, j int) bool { return frames[i].Name() < frames[j].Name() })
+		for _, f := range frames {
+			if err := e.addFrame(f, parent); err != nil {
+				return nil, errors.Wrapf(err, "cannot export frame %q", f.Name())
+			}
+			queue = append(queue, f.Name())
+		}
+	}
+	return &ModelConfig{
+		Name:   name,
+		Links:  e.links,
+		Joints: e.joints,
+	}, nil
+}
+
+// errNotURDFJoint is returned for frames whose motion cannot be represented by a single URDF joint.
+var errNotURDFJoint = errors.New("frame cannot be represented by a URDF joint")
+
+type frameSystemExporter struct {
+	inputs map[string][]referenceframe.Input
+	links  []link
+	joints []joint
+}
+
+// addLink adds a link and returns it. The link is only valid until the next link is added.
+func (e *frameSystemExporter) addLink(name string) *link {
+	e.links = append(e.links, link{Name: name})
+	return &e.links[len(e.links)-1]
+}
+
+// addFrame exports a frame of the frame system, expanding it if it is a model.
+func (e *frameSystemExporter) addFrame(f referenceframe.Frame, parent string) error {
+	model, ok := f.(*referenceframe.SimpleModel)
+	if !ok {
+		if err := e.addJoint(f, f.Name(), parent); !errors.Is(err, errNotURDFJoint) {
+			return err
+		}
+		inputs, ok := e.inputs[f.Name()]
+		if !ok {
+			inputs = make([]referenceframe.Input, len(f.DoF()))
+		}
+		return e.addFixed(f, inputs, f.Name(), parent)
+	}
+
+	// the model's transforms are ordered from its base to its end effector, which the model frame itself represents
+	for _, tf := range model.OrdTransforms {
+		linkName := model.Name() + ":" + tf.Name()
+		if err := e.addJoint(tf, linkName, parent); err != nil {
+			return err
+		}
+		parent = linkName
+	}
+	e.addLink(f.Name())
+	e.joints = append(e.joints, joint{
+		Name:   f.Name() + "_joint",
+		Type:   referenceframe.FixedJoint,
+		Parent: frame{parent},
+		Child:  frame{f.Name()},
+		Origin: newPose(spatialmath.NewZeroPose()),
+	})
+	return nil
+}
+
+// addJoint exports a frame with no more than one degree of freedom as a link and the joint attaching it to its parent.
+// errNotURDFJoint is returned, and nothing is added, for frames that move in any other way than rotating about or
+// translating along a single axis.
+func (e *frameSystemExporter) addJoint(f referenceframe.Frame, linkName, parent string) error {
+	switch len(f.DoF()) {
+	case 0:
+		return e.addFixed(f, []referenceframe.Input{}, linkName, parent)
+	case 1:
+	default:
+		return errors.Wrapf(errNotURDFJoint, "frame has %d degrees of freedom", len(f.DoF()))
+	}
+
+	zero := []referenceframe.Input{{Value: 0}}
+	home, err := f.Transform(zero)
+	if err != nil {
+		return err
+	}
+	// inputs outside of the frame's limits still produce a pose
+	moved, _ := f.Transform([]referenceframe.Input{{Value: 1}})
+	if moved == nil {
+		return errors.New("cannot determine how the frame moves")
+	}
+	if !spatialmath.PoseAlmostEqual(home, spatialmath.NewZeroPose()) {
+		return errors.Wrap(errNotURDFJoint, "frame is not at its parent's origin at its zero position")
+	}
+
+	j := joint{
+		Name:   linkName + "_joint",
+		Parent: frame{parent},
+		Child:  frame{linkName},
+		Origin: newPose(spatialmath.NewZeroPose()),
+	}
+	limits := f.DoF()[0]
+	rotation := moved.Orientation().AxisAngles()
+	switch {
+	case spatialmath.R3VectorAlmostEqual(moved.Point(), r3.Vector{}, 1e-8) && math.Abs(rotation.Theta-1) < 1e-6:
+		j.Axis = &axis{XYZ: vectorString(r3.Vector{X: rotation.RX, Y: rotation.RY, Z: rotation.RZ})}
+		if math.IsInf(limits.Min, -1) && math.IsInf(limits.Max, 1) {
+			j.Type = referenceframe.ContinuousJoint
+		} else {
+			j.Type = referenceframe.RevoluteJoint
+			j.Limit = &limit{Lower: limits.Min, Upper: limits.Max}
+		}
+	case spatialmath.OrientationAlmostEqual(moved.Orientation(), spatialmath.NewZeroOrientation()) &&
+		math.Abs(moved.Point().Norm()-1) < 1e-6:
+		j.Type = referenceframe.PrismaticJoint
+		j.Axis = &axis{XYZ: vectorString(moved.Point())}
+		j.Limit = &limit{Lower: utils.MMToMeters(limits.Min), Upper: utils.MMToMeters(limits.Max)}
+	default:
+		return errors.Wrap(errNotURDFJoint, "frame does not rotate about or translate along a single axis")
+	}
+
+	l := e.addLink(linkName)
+	if err := addCollisions(l, f, zero, home); err != nil {
+		return err
+	}
+	e.joints = append(e.joints, j)
+	return nil
+}
+
+// addFixed exports a frame at the given inputs as a link and the fixed joint attaching it to its parent.
+func (e *frameSystemExporter) addFixed(f referenceframe.Frame, inputs []referenceframe.Input, linkName, parent string) error {
+	tf, err := f.Transform(inputs)
+	if err != nil {
+		return err
+	}
+	l := e.addLink(linkName)
+	if err := addCollisions(l, f, inputs, tf); err != nil {
+		return err
+	}
+	e.joints = append(e.joints, joint{
+		Name:   linkName + "_joint",
+		Type:   referenceframe.FixedJoint,
+		Parent: frame{parent},
+		Child:  frame{linkName},
+		Origin: newPose(tf),
+	})
+	return nil
+}
+
+// addCollisions adds the frame's geometries at the given inputs to the link. Frames express their geometries relative to
+// their parent, so they are moved into the link by the inverse of the frame's transform at those inputs.
+func addCollisions(l *link, f referenceframe.Frame, inputs []referenceframe.Input, tf spatialmath.Pose) error {
+	gif, err := f.Geometries(inputs)
+	if err != nil {
+		return err
+	}
+	for _, g := range gif.Geometries() {
+		coll, err := newEnclosingCollision(g.Transform(spatialmath.PoseInverse(tf)))
+		if err != nil {
+			return err
+		}
+		coll.Name = g.Label()
+		l.Collision = append(l.Collision, *coll)
+	}
+	return nil
+}
+
+// vectorString formats a vector in the "x y z" format.
+func vectorString(v r3.Vector) string {
+	return fmt.Sprintf("%f %f %f", v.X, v.Y, v.Z)
+}
@@ -0,0 +1,106 @@
+package urdf
+
+import (
+	"encoding/xml"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNewModelFromFrameSystem(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), r3.Vector{X: 100, Y: 100, Z: 100}, "carriage")
+	test.That(t, err, test.ShouldBeNil)
+	gantry, err := referenceframe.NewTranslationalFrameWithGeometry("gantry", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000}, box)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+	arm, err := ParseModelSDFFile(utils.ResolveFile("referenceframe/urdf/testfiles/two_link.sdf"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(arm, gantry), test.ShouldBeNil)
+	sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 20, "")
+	test.That(t, err, test.ShouldBeNil)
+	gripper, err := referenceframe.NewStaticFrameWithGeometry(
+		"gripper",
+		spatialmath.NewPose(r3.Vector{X: 30}, &spatialmath.OrientationVectorDegrees{OY: 1, Theta: 10}),
+		sphere,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripper, arm), test.ShouldBeNil)
+
+	cfg, err := NewModelFromFrameSystem(fs, nil, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Name, test.ShouldEqual, "test")
+	linkNames := make([]string, 0, len(cfg.Links))
+	for _, l := range cfg.Links {
+		linkNames = append(linkNames, l.Name)
+	}
+	test.That(t, linkNames, test.ShouldResemble, []string{
+		referenceframe.World, "gantry", "arm:base", "arm:shoulder_offset", "arm:shoulder", "arm:upper",
+		"arm:elbow_offset", "arm:elbow", "arm:forearm", "arm", "gripper",
+	})
+	test.That(t, cfg.Joints[0].Type, test.ShouldEqual, referenceframe.PrismaticJoint)
+	test.That(t, cfg.Joints[0].Limit.Upper, test.ShouldAlmostEqual, 1)
+	test.That(t, cfg.Joints[3].Type, test.ShouldEqual, referenceframe.RevoluteJoint)
+	test.That(t, cfg.Joints[3].Limit.Upper, test.ShouldAlmostEqual, 3.14)
+	// the arm's capsule is exported as a cylinder
+	test.That(t, cfg.Links[5].Collision[0].Geometry.Cylinder, test.ShouldNotBeNil)
+
+	// exporting and importing again results in the same kinematics and geometries
+	data, err := xml.MarshalIndent(cfg, "", "  ")
+	test.That(t, err, test.ShouldBeNil)
+	mc, err := UnmarshalModelXML(data, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := mc.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(model.DoF()), test.ShouldEqual, 3)
+
+	for _, inputs := range [][]float64{{0, 0, 0}, {250, math.Pi / 3, -1}, {1000, -2, 1.5}} {
+		positions := referenceframe.StartPositions(fs)
+		positions["gantry"] = referenceframe.FloatsToInputs(inputs[:1])
+		positions["arm"] = referenceframe.FloatsToInputs(inputs[1:])
+		expected, err := fs.Transform(positions, referenceframe.NewPoseInFrame("gripper", spatialmath.NewZeroPose()), referenceframe.World)
+		test.That(t, err, test.ShouldBeNil)
+		pose, err := model.Transform(referenceframe.FloatsToInputs(inputs))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqualEps(pose, expected.(*referenceframe.PoseInFrame).Pose(), 1e-3), test.ShouldBeTrue)
+
+		expectedGeometries, err := referenceframe.FrameSystemGeometries(fs, positions)
+		test.That(t, err, test.ShouldBeNil)
+		gif, err := model.Geometries(referenceframe.FloatsToInputs(inputs))
+		test.That(t, err, test.ShouldBeNil)
+		geometries := gif.Geometries()
+		count := 0
+		for _, expectedGif := range expectedGeometries {
+			for _, expectedGeometry := range expectedGif.Geometries() {
+				count++
+				found := false
+				for _, g := range geometries {
+					found = found || spatialmath.PoseAlmostEqualEps(g.Pose(), expectedGeometry.Pose(), 1e-3)
+				}
+				test.That(t, found, test.ShouldBeTrue)
+			}
+		}
+		test.That(t, len(geometries), test.ShouldEqual, count)
+	}
+}
+
+func TestNewModelFromFrameSystemFallback(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	base, err := referenceframe.New2DMobileModelFrame("base", []referenceframe.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(referenceframe.NewNamedFrame(base, "rover"), fs.World()), test.ShouldBeNil)
+
+	// frames which cannot be represented by a URDF joint are fixed at the given inputs
+	cfg, err := NewModelFromFrameSystem(fs, map[string][]referenceframe.Input{"rover": {{Value: 10}, {Value: 20}}}, "robot")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Name, test.ShouldEqual, "robot")
+	test.That(t, cfg.Joints, test.ShouldHaveLength, 1)
+	test.That(t, cfg.Joints[0].Type, test.ShouldEqual, referenceframe.FixedJoint)
+	test.That(t, spatialmath.PoseAlmostEqual(cfg.Joints[0].Origin.Parse(), spatialmath.NewPoseFromPoint(r3.Vector{X: 10, Y: 20})), test.ShouldBeTrue)
+}
@@ -3,6 +3,8 @@ package urdf
 import (
 	"encoding/xml"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
@@ -16,11 +18,14 @@ var errGeometryTypeUnsupported = errors.New("unsupported Geometry type")
 // collision is a struct which details the XML used in a URDF collision geometry.
 type collision struct {
 	XMLName  xml.Name `xml:"collision"`
+	Name     string   `xml:"name,attr,omitempty"`
 	Origin   *pose    `xml:"origin"`
 	Geometry struct {
-		XMLName xml.Name `xml:"geometry"`
-		Box     *box     `xml:"box,omitempty"`
-		Sphere  *sphere  `xml:"sphere,omitempty"`
+		XMLName  xml.Name  `xml:"geometry"`
+		Box      *box      `xml:"box,omitempty"`
+		Sphere   *sphere   `xml:"sphere,omitempty"`
+		Cylinder *cylinder `xml:"cylinder,omitempty"`
+		Mesh     *mesh     `xml:"mesh,omitempty"`
 	} `xml:"geometry"`
 }
 
@@ -34,6 +39,21 @@ type sphere struct {
 	Radius  float64  `xml:"radius,attr"` // in meters
 }
 
+type cylinder struct {
+	XMLName xml.Name `xml:"cylinder"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+	Length  float64  `xml:"length,attr"` // in meters
+}
+
+type mesh struct {
+	XMLName  xml.Name `xml:"mesh"`
+	Filename string   `xml:"filename,attr"`
+	Scale    string   `xml:"scale,attr,omitempty"` // "x y z" format, 1 if omitted
+}
+
+// meshScale is the scale of a mesh whose file is in mm, which is how RDK reads mesh files.
+const meshScale = "0.001 0.001 0.001"
+
 func newCollision(g spatialmath.Geometry) (*collision, error) {
 	cfg, err := spatialmath.NewGeometryConfig(g)
 	if err != nil {
@@ -48,12 +68,30 @@ func newCollision(g spatialmath.Geometry) (*collision, error) {
 		urdf.Geometry.Box = &box{Size: fmt.Sprintf("%f %f %f", utils.MMToMeters(cfg.X), utils.MMToMeters(cfg.Y), utils.MMToMeters(cfg.Z))}
 	case spatialmath.SphereType:
 		urdf.Geometry.Sphere = &sphere{Radius: utils.MMToMeters(cfg.R)}
+	case spatialmath.MeshType:
+		urdf.Geometry.Mesh = &mesh{Filename: cfg.MeshFile, Scale: meshScale}
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", cfg.Type))
 	}
 	return urdf, nil
 }
 
+// newEnclosingCollision is like newCollision, but capsules, which URDF cannot represent, are replaced by the cylinder enclosing them.
+func newEnclosingCollision(g spatialmath.Geometry) (*collision, error) {
+	cfg, err := spatialmath.NewGeometryConfig(g)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Type != spatialmath.CapsuleType {
+		return newCollision(g)
+	}
+	urdf := &collision{
+		Origin: newPose(g.Pose()),
+	}
+	urdf.Geometry.Cylinder = &cylinder{Radius: utils.MMToMeters(cfg.R), Length: utils.MMToMeters(cfg.L)}
+	return urdf, nil
+}
+
 func (c *collision) toGeometry() (spatialmath.Geometry, error) {
 	switch {
 	case c.Geometry.Box != nil:
@@ -65,6 +103,16 @@ func (c *collision) toGeometry() (spatialmath.Geometry, error) {
 		)
 	case c.Geometry.Sphere != nil:
 		return spatialmath.NewSphere(c.Origin.Parse(), utils.MetersToMM(c.Geometry.Sphere.Radius), "")
+	case c.Geometry.Cylinder != nil:
+		// there is no cylinder geometry, so use the smallest capsule that contains the cylinder
+		radius := utils.MetersToMM(c.Geometry.Cylinder.Radius)
+		return spatialmath.NewCapsule(c.Origin.Parse(), radius, utils.MetersToMM(c.Geometry.Cylinder.Length)+2*radius, "")
+	case c.Geometry.Mesh != nil:
+		scale := spaceDelimitedStringToFloatSlice(c.Geometry.Mesh.Scale)
+		if len(scale) != 3 || math.Abs(scale[0]-0.001) > 1e-9 || scale[0] != scale[1] || scale[1] != scale[2] {
+			return nil, errors.Errorf("mesh %q must be scaled by %q, only mesh files in mm are supported", c.Geometry.Mesh.Filename, meshScale)
+		}
+		return spatialmath.NewMeshFromFile(c.Origin.Parse(), strings.TrimPrefix(c.Geometry.Mesh.Filename, "file://"), "")
 	default:
 		return nil, errors.New("couldn't parse xml: no geometry defined")
 	}
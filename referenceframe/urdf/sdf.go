@@ -0,0 +1,434 @@
+package urdf
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// SDFExtension is the file extension associated with SDFormat (SDF) files, which are used by Gazebo.
+const SDFExtension string = "sdf"
+
+// sdfModelFrame is the name SDF uses for the frame of the model itself.
+const sdfModelFrame = "__model__"
+
+// sdfUnlimited is the magnitude of the default SDF joint limits, at or above which a joint is considered unlimited.
+const sdfUnlimited = 1e16
+
+// sdfRoot represents the supported fields of an SDF file.
+type sdfRoot struct {
+	XMLName xml.Name   `xml:"sdf"`
+	Models  []sdfModel `xml:"model"`
+}
+
+type sdfModel struct {
+	Name   string     `xml:"name,attr"`
+	Links  []sdfLink  `xml:"link"`
+	Joints []sdfJoint `xml:"joint"`
+}
+
+type sdfPose struct {
+	RelativeTo string `xml:"relative_to,attr"`
+	Degrees    bool   `xml:"degrees,attr"`
+	Value      string `xml:",chardata"` // "x y z roll pitch yaw" format, in meters and radians
+}
+
+type sdfLink struct {
+	Name      string         `xml:"name,attr"`
+	Pose      *sdfPose       `xml:"pose"`
+	Collision []sdfCollision `xml:"collision"`
+}
+
+type sdfCollision struct {
+	Name     string   `xml:"name,attr"`
+	Pose     *sdfPose `xml:"pose"`
+	Geometry struct {
+		Box *struct {
+			Size string `xml:"size"` // "x y z" format, in meters
+		} `xml:"box"`
+		Sphere *struct {
+			Radius float64 `xml:"radius"` // in meters
+		} `xml:"sphere"`
+		Cylinder *struct {
+			Radius float64 `xml:"radius"` // in meters
+			Length float64 `xml:"length"` // in meters
+		} `xml:"cylinder"`
+	} `xml:"geometry"`
+}
+
+type sdfJoint struct {
+	Name   string   `xml:"name,attr"`
+	Type   string   `xml:"type,attr"`
+	Parent string   `xml:"parent"`
+	Child  string   `xml:"child"`
+	Pose   *sdfPose `xml:"pose"`
+	Axis   *struct {
+		XYZ struct {
+			ExpressedIn string `xml:"expressed_in,attr"`
+			Value       string `xml:",chardata"`
+		} `xml:"xyz"`
+		UseParentModelFrame bool `xml:"use_parent_model_frame"`
+		Limit               *struct {
+			Lower float64 `xml:"lower"` // translation limits are in meters, revolute limits are in radians
+			Upper float64 `xml:"upper"` // translation limits are in meters, revolute limits are in radians
+		} `xml:"limit"`
+	} `xml:"axis"`
+}
+
+// parse returns the pose in RDK units, and the name of the frame it is relative to.
+func (p *sdfPose) parse() (spatialmath.Pose, string, error) {
+	if p == nil {
+		return spatialmath.NewZeroPose(), "", nil
+	}
+	values := spaceDelimitedStringToFloatSlice(p.Value)
+	if len(values) != 6 {
+		return nil, "", errors.Errorf("pose %q must have 6 values", p.Value)
+	}
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return nil, "", errors.Errorf("pose %q is not numeric", p.Value)
+		}
+	}
+	rpy := values[3:]
+	if p.Degrees {
+		for i := range rpy {
+			rpy[i] = utils.DegToRad(rpy[i])
+		}
+	}
+	return spatialmath.NewPose(
+		r3.Vector{X: utils.MetersToMM(values[0]), Y: utils.MetersToMM(values[1]), Z: utils.MetersToMM(values[2])},
+		&spatialmath.EulerAngles{Roll: rpy[0], Pitch: rpy[1], Yaw: rpy[2]},
+	), p.RelativeTo, nil
+}
+
+func (c *sdfCollision) toGeometry() (spatialmath.Geometry, error) {
+	pose, relativeTo, err := c.Pose.parse()
+	if err != nil {
+		return nil, err
+	}
+	if relativeTo != "" {
+		return nil, errors.Errorf("collision %q: only collision poses relative to their link are supported", c.Name)
+	}
+	switch {
+	case c.Geometry.Box != nil:
+		dims := spaceDelimitedStringToFloatSlice(c.Geometry.Box.Size)
+		if len(dims) != 3 {
+			return nil, errors.Errorf("collision %q: box size %q must have 3 values", c.Name, c.Geometry.Box.Size)
+		}
+		return spatialmath.NewBox(pose, r3.Vector{X: utils.MetersToMM(dims[0]), Y: utils.MetersToMM(dims[1]), Z: utils.MetersToMM(dims[2])}, "")
+	case c.Geometry.Sphere != nil:
+		return spatialmath.NewSphere(pose, utils.MetersToMM(c.Geometry.Sphere.Radius), "")
+	case c.Geometry.Cylinder != nil:
+		// there is no cylinder geometry, so use the smallest capsule that contains the cylinder
+		radius := utils.MetersToMM(c.Geometry.Cylinder.Radius)
+		return spatialmath.NewCapsule(pose, radius, utils.MetersToMM(c.Geometry.Cylinder.Length)+2*radius, "")
+	default:
+		return nil, errors.Wrapf(errGeometryTypeUnsupported, "collision %q", c.Name)
+	}
+}
+
+// sdfPoseResolver computes the poses of the links and joints of an SDF model relative to the model's frame.
+type sdfPoseResolver struct {
+	model     string
+	links     map[string]*sdfLink
+	joints    map[string]*sdfJoint
+	poses     map[string]spatialmath.Pose
+	resolving map[string]bool
+}
+
+func (r *sdfPoseResolver) resolve(name string) (spatialmath.Pose, error) {
+	if name == "" || name == sdfModelFrame || name == r.model || name == referenceframe.World {
+		return spatialmath.NewZeroPose(), nil
+	}
+	if pose, ok := r.poses[name]; ok {
+		return pose, nil
+	}
+	if r.resolving[name] {
+		return nil, errors.Errorf("the pose of %q is defined relative to itself", name)
+	}
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+
+	var poseElem *sdfPose
+	var defaultRelativeTo string
+	if l, ok := r.links[name]; ok {
+		poseElem = l.Pose
+	} else if j, ok := r.joints[name]; ok {
+		// joint poses are relative to their child link by default
+		poseElem = j.Pose
+		defaultRelativeTo = j.Child
+	} else {
+		return nil, errors.Errorf("unknown frame %q", name)
+	}
+	pose, relativeTo, err := poseElem.parse()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pose of %q", name)
+	}
+	if relativeTo == "" {
+		relativeTo = defaultRelativeTo
+	}
+	base, err := r.resolve(relativeTo)
+	if err != nil {
+		return nil, err
+	}
+	pose = spatialmath.Compose(base, pose)
+	r.poses[name] = pose
+	return pose, nil
+}
+
+// newSDFLinkConfig creates a static link from the parent to the child, given both of their poses in the model's frame.
+func newSDFLinkConfig(id, parent string, parentPose, childPose spatialmath.Pose) (referenceframe.LinkConfig, error) {
+	pose := spatialmath.PoseBetween(parentPose, childPose)
+	orientation, err := spatialmath.NewOrientationConfig(pose.Orientation())
+	if err != nil {
+		return referenceframe.LinkConfig{}, err
+	}
+	return referenceframe.LinkConfig{ID: id, Parent: parent, Translation: pose.Point(), Orientation: orientation}, nil
+}
+
+// UnmarshalModelSDF will transfer the given SDF XML data into an equivalent ModelConfig. If the file contains more than
+// one model, the one named modelName is used. Link poses may be given relative to the model or to other links and joints.
+// A moving joint becomes a static link named after the joint with an "_offset" suffix, placing the joint relative to its
+// parent link, followed by the joint itself, whose child link is placed relative to it. The first collision of a link is
+// its geometry, and each further collision is the geometry of a static link named after the link with a "_collision_<i>"
+// suffix, which follows it without moving. Cylinders are replaced by the capsules enclosing them.
+func UnmarshalModelSDF(xmlData []byte, modelName string) (*referenceframe.ModelConfig, error) {
+	if len(xmlData) == 0 {
+		return nil, referenceframe.ErrNoModelInformation
+	}
+	root := &sdfRoot{}
+	if err := xml.Unmarshal(xmlData, root); err != nil {
+		return nil, errors.Wrap(err, "Failed to convert SDF data to equivalent sdfRoot struct")
+	}
+
+	var model *sdfModel
+	if len(root.Models) == 1 {
+		model = &root.Models[0]
+	} else {
+		for i := range root.Models {
+			if root.Models[i].Name == modelName {
+				model = &root.Models[i]
+			}
+		}
+	}
+	if model == nil {
+		return nil, errors.Errorf("SDF data must contain exactly one model or a model named %q", modelName)
+	}
+	if modelName == "" {
+		modelName = model.Name
+	}
+
+	resolver := &sdfPoseResolver{
+		model:     model.Name,
+		links:     map[string]*sdfLink{},
+		joints:    map[string]*sdfJoint{},
+		poses:     map[string]spatialmath.Pose{},
+		resolving: map[string]bool{},
+	}
+	for i := range model.Links {
+		resolver.links[model.Links[i].Name] = &model.Links[i]
+	}
+	for i := range model.Joints {
+		resolver.joints[model.Joints[i].Name] = &model.Joints[i]
+	}
+
+	mc := &referenceframe.ModelConfig{Name: modelName, KinParamType: "SVA"}
+	// links are placed relative to the frame of the joint whose child they are, or to the model if there is none
+	childLinks := map[string]referenceframe.LinkConfig{}
+	for _, j := range model.Joints {
+		if j.Name == referenceframe.World {
+			return nil, errors.New("Joints with the name 'world' are not supported by config parsers")
+		}
+		if _, ok := resolver.links[j.Child]; !ok {
+			return nil, errors.Errorf("joint %q has unknown child link %q", j.Name, j.Child)
+		}
+		if _, ok := childLinks[j.Child]; ok {
+			return nil, errors.Errorf("link %q is the child of more than one joint", j.Child)
+		}
+		parent := j.Parent
+		if parent == sdfModelFrame || parent == model.Name {
+			parent = referenceframe.World
+		}
+		parentPose, err := resolver.resolve(parent)
+		if err != nil {
+			return nil, err
+		}
+		childPose, err := resolver.resolve(j.Child)
+		if err != nil {
+			return nil, err
+		}
+
+		if j.Type == referenceframe.FixedJoint {
+			childLink, err := newSDFLinkConfig(j.Child, parent, parentPose, childPose)
+			if err != nil {
+				return nil, err
+			}
+			childLinks[j.Child] = childLink
+			continue
+		}
+
+		jointPose, err := resolver.resolve(j.Name)
+		if err != nil {
+			return nil, err
+		}
+		offset, err := newSDFLinkConfig(j.Name+"_offset", parent, parentPose, jointPose)
+		if err != nil {
+			return nil, err
+		}
+		mc.Links = append(mc.Links, offset)
+
+		thisJoint := referenceframe.JointConfig{ID: j.Name, Type: j.Type, Parent: offset.ID}
+		if j.Axis == nil {
+			return nil, errors.Errorf("joint %q has no axis", j.Name)
+		}
+		axisValues := spaceDelimitedStringToFloatSlice(j.Axis.XYZ.Value)
+		if len(axisValues) != 3 {
+			return nil, errors.Errorf("joint %q axis %q must have 3 values", j.Name, j.Axis.XYZ.Value)
+		}
+		jointAxis := r3.Vector{X: axisValues[0], Y: axisValues[1], Z: axisValues[2]}
+		if expressedIn := j.Axis.XYZ.ExpressedIn; expressedIn != "" || j.Axis.UseParentModelFrame {
+			if j.Axis.UseParentModelFrame {
+				expressedIn = sdfModelFrame
+			}
+			axisFramePose, err := resolver.resolve(expressedIn)
+			if err != nil {
+				return nil, err
+			}
+			// rotate the axis into the joint's frame
+			rotation := spatialmath.NewPoseFromOrientation(spatialmath.PoseBetween(jointPose, axisFramePose).Orientation())
+			jointAxis = spatialmath.Compose(rotation, spatialmath.NewPoseFromPoint(jointAxis)).Point()
+		}
+		thisJoint.Axis = spatialmath.AxisConfig{X: jointAxis.X, Y: jointAxis.Y, Z: jointAxis.Z}
+
+		lower, upper := math.Inf(-1), math.Inf(1)
+		if j.Axis.Limit != nil {
+			if j.Axis.Limit.Lower > -sdfUnlimited {
+				lower = j.Axis.Limit.Lower
+			}
+			if j.Axis.Limit.Upper < sdfUnlimited {
+				upper = j.Axis.Limit.Upper
+			}
+		}
+		switch j.Type {
+		case referenceframe.ContinuousJoint:
+			thisJoint.Type = referenceframe.RevoluteJoint
+			thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
+		case referenceframe.RevoluteJoint:
+			thisJoint.Min, thisJoint.Max = utils.RadToDeg(lower), utils.RadToDeg(upper)
+		case referenceframe.PrismaticJoint:
+			thisJoint.Min, thisJoint.Max = utils.MetersToMM(lower), utils.MetersToMM(upper)
+		default:
+			return nil, referenceframe.NewUnsupportedJointTypeError(j.Type)
+		}
+		mc.Joints = append(mc.Joints, thisJoint)
+
+		childLink, err := newSDFLinkConfig(j.Child, j.Name, jointPose, childPose)
+		if err != nil {
+			return nil, err
+		}
+		childLinks[j.Child] = childLink
+	}
+
+	// the links carrying the collisions after the first of a link, and the last of them by the link they follow
+	collisionLinks := map[string]bool{}
+	reparented := map[string]string{}
+	for _, l := range model.Links {
+		if l.Name == referenceframe.World {
+			continue
+		}
+		thisLink, ok := childLinks[l.Name]
+		if !ok {
+			pose, err := resolver.resolve(l.Name)
+			if err != nil {
+				return nil, err
+			}
+			thisLink, err = newSDFLinkConfig(l.Name, referenceframe.World, spatialmath.NewZeroPose(), pose)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(l.Collision) > 0 {
+			// geometries are attached to the start of a link's transform, so express the collision in its parent's frame
+			geometry, err := l.Collision[0].toGeometry()
+			if err != nil {
+				return nil, errors.Wrapf(err, "link %q", l.Name)
+			}
+			linkPose, err := thisLink.Pose()
+			if err != nil {
+				return nil, err
+			}
+			thisLink.Geometry, err = spatialmath.NewGeometryConfig(geometry.Transform(linkPose))
+			if err != nil {
+				return nil, err
+			}
+		}
+		mc.Links = append(mc.Links, thisLink)
+
+		// a link config has a single geometry, so each further collision is carried by a static link at the end of the
+		// link, which is inserted between the link and its child
+		last := l.Name
+		for i := 1; i < len(l.Collision); i++ {
+			geometry, err := l.Collision[i].toGeometry()
+			if err != nil {
+				return nil, errors.Wrapf(err, "link %q", l.Name)
+			}
+			collisionLink, err := newSDFLinkConfig(fmt.Sprintf("%s_collision_%d", l.Name, i), last, spatialmath.NewZeroPose(),
+				spatialmath.NewZeroPose())
+			if err != nil {
+				return nil, err
+			}
+			collisionLink.Geometry, err = spatialmath.NewGeometryConfig(geometry)
+			if err != nil {
+				return nil, err
+			}
+			mc.Links = append(mc.Links, collisionLink)
+			collisionLinks[collisionLink.ID] = true
+			last = collisionLink.ID
+		}
+		if last != l.Name {
+			reparented[l.Name] = last
+		}
+	}
+	for i := range mc.Links {
+		if parent, ok := reparented[mc.Links[i].Parent]; ok && !collisionLinks[mc.Links[i].ID] {
+			mc.Links[i].Parent = parent
+		}
+	}
+	for i := range mc.Joints {
+		if parent, ok := reparented[mc.Joints[i].Parent]; ok {
+			mc.Joints[i].Parent = parent
+		}
+	}
+
+	// there is no kinematics file format for SDF, so the equivalent JSON is kept as the original file. JSON cannot
+	// represent unlimited joints, in which case there is no original file.
+	if jsonData, err := json.Marshal(mc); err == nil {
+		mc.OriginalFile = &referenceframe.ModelFile{Bytes: jsonData, Extension: "json"}
+	}
+	return mc, nil
+}
+
+// ParseModelSDFFile will read a given file and parse the contained SDF data into an equivalent Model.
+func ParseModelSDFFile(filename, modelName string) (referenceframe.Model, error) {
+	//nolint:gosec
+	xmlData, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read SDF file")
+	}
+
+	mc, err := UnmarshalModelSDF(xmlData, modelName)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.ParseConfig(modelName)
+}
@@ -0,0 +1,121 @@
+package urdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestParseSDFFile(t *testing.T) {
+	model, err := ParseModelSDFFile(utils.ResolveFile("referenceframe/urdf/testfiles/two_link.sdf"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.Name(), test.ShouldEqual, "two_link")
+	test.That(t, len(model.DoF()), test.ShouldEqual, 2)
+	test.That(t, model.ModelConfig().OriginalFile.Extension, test.ShouldEqual, "json")
+
+	// the equivalent JSON model is kept so that it can be served by GetKinematics
+	fromJSON, err := referenceframe.UnmarshalModelJSON(model.ModelConfig().OriginalFile.Bytes, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(fromJSON.DoF()), test.ShouldEqual, 2)
+
+	for _, tc := range []struct {
+		inputs   []float64
+		expected spatialmath.Pose
+	}{
+		{[]float64{0, 0}, spatialmath.NewPose(r3.Vector{X: 100, Z: 400}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})},
+		{[]float64{math.Pi / 2, 0}, spatialmath.NewPose(r3.Vector{Y: 100, Z: 400}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 180})},
+		{[]float64{0, math.Pi / 2}, spatialmath.NewPose(r3.Vector{Z: 500}, &spatialmath.OrientationVectorDegrees{OX: -1, Theta: -90})},
+	} {
+		pose, err := model.Transform(referenceframe.FloatsToInputs(tc.inputs))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose, tc.expected, 1e-6), test.ShouldBeTrue)
+		test.That(t, spatialmath.OrientationAlmostEqual(pose.Orientation(), tc.expected.Orientation()), test.ShouldBeTrue)
+	}
+
+	gif, err := model.Geometries(make([]referenceframe.Input, 2))
+	test.That(t, err, test.ShouldBeNil)
+	geometries := map[string]spatialmath.Geometry{}
+	for _, g := range gif.Geometries() {
+		geometries[g.Label()] = g
+	}
+	test.That(t, geometries, test.ShouldHaveLength, 3)
+	test.That(t, spatialmath.R3VectorAlmostEqual(geometries["two_link:base"].Pose().Point(), r3.Vector{Z: 50}, 1e-6), test.ShouldBeTrue)
+	test.That(t, spatialmath.R3VectorAlmostEqual(geometries["two_link:upper"].Pose().Point(), r3.Vector{Z: 250}, 1e-6), test.ShouldBeTrue)
+	forearm := geometries["two_link:forearm"].Pose().Point()
+	test.That(t, spatialmath.R3VectorAlmostEqual(forearm, r3.Vector{X: 100, Z: 400}, 1e-6), test.ShouldBeTrue)
+	capsule, err := spatialmath.NewGeometryConfig(geometries["two_link:upper"])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capsule.Type, test.ShouldEqual, spatialmath.CapsuleType)
+	test.That(t, capsule.L, test.ShouldAlmostEqual, 400)
+}
+
+func TestUnmarshalModelSDFCollisions(t *testing.T) {
+	// every collision of a link is kept, without changing how the model moves
+	sdf := []byte(`<sdf version="1.7"><model name="a">
+		<link name="l1">
+			<collision name="body"><geometry><box><size>0.2 0.2 0.1</size></box></geometry></collision>
+			<collision name="mount"><pose>0.1 0 0 0 0 0</pose><geometry><sphere><radius>0.02</radius></sphere></geometry></collision>
+			<collision name="cable"><pose>0 0.1 0 0 0 0</pose><geometry><sphere><radius>0.01</radius></sphere></geometry></collision>
+		</link>
+		<link name="l2"><pose>0 0 0.2 0 0 0</pose>
+			<collision name="tip"><geometry><sphere><radius>0.05</radius></sphere></geometry></collision>
+		</link>
+		<joint name="j" type="revolute"><parent>l1</parent><child>l2</child>
+			<axis><xyz>0 0 1</xyz><limit><lower>-1</lower><upper>1</upper></limit></axis></joint>
+	</model></sdf>`)
+	mc, err := UnmarshalModelSDF(sdf, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := mc.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(model.DoF()), test.ShouldEqual, 1)
+
+	pose, err := model.Transform([]referenceframe.Input{{Value: 0}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pose.Point(), r3.Vector{Z: 200}, 1e-6), test.ShouldBeTrue)
+
+	gif, err := model.Geometries([]referenceframe.Input{{Value: 0}})
+	test.That(t, err, test.ShouldBeNil)
+	geometries := map[string]spatialmath.Geometry{}
+	for _, g := range gif.Geometries() {
+		geometries[g.Label()] = g
+	}
+	test.That(t, geometries, test.ShouldHaveLength, 4)
+	for label, expected := range map[string]r3.Vector{
+		"a:l1":             {},
+		"a:l1_collision_1": {X: 100},
+		"a:l1_collision_2": {Y: 100},
+		"a:l2":             {Z: 200},
+	} {
+		test.That(t, geometries[label], test.ShouldNotBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(geometries[label].Pose().Point(), expected, 1e-6), test.ShouldBeTrue)
+	}
+}
+
+func TestUnmarshalModelSDFErrors(t *testing.T) {
+	_, err := UnmarshalModelSDF(nil, "")
+	test.That(t, err, test.ShouldBeError, referenceframe.ErrNoModelInformation)
+
+	twoModels := []byte(`<sdf version="1.7"><model name="a"><link name="l"/></model><model name="b"><link name="l"/></model></sdf>`)
+	_, err = UnmarshalModelSDF(twoModels, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	mc, err := UnmarshalModelSDF(twoModels, "b")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mc.Name, test.ShouldEqual, "b")
+
+	ball := []byte(`<sdf version="1.7"><model name="a"><link name="l1"/><link name="l2"/>
+		<joint name="j" type="ball"><parent>l1</parent><child>l2</child><axis><xyz>0 0 1</xyz></axis></joint></model></sdf>`)
+	_, err = UnmarshalModelSDF(ball, "")
+	test.That(t, err, test.ShouldBeError, referenceframe.NewUnsupportedJointTypeError("ball"))
+
+	cycle := []byte(`<sdf version="1.7"><model name="a">
+		<link name="l1"><pose relative_to="l2">0 0 0 0 0 0</pose></link>
+		<link name="l2"><pose relative_to="l1">0 0 0 0 0 0</pose></link></model></sdf>`)
+	_, err = UnmarshalModelSDF(cycle, "")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "relative to itself")
+}
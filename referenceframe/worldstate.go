@@ -1,10 +1,12 @@
 package referenceframe
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 
 	"go.viam.com/rdk/spatialmath"
@@ -12,6 +14,17 @@ import (
 
 const unnamedWorldStateGeometryPrefix = "unnamedWorldStateGeometry_"
 
+// MeshObstaclesKey is the key of the extra of a request under which the mesh obstacles of its WorldState are sent. The WorldState
+// proto has no mesh geometry, so a mesh obstacle is sent in it as its bounding box, and the mesh itself is sent under this key as
+// a list of meshObstacles, which the receiver puts in place of the box with the same label.
+const MeshObstaclesKey = "mesh_obstacles"
+
+// meshObstacle is a mesh obstacle of a WorldState, as sent under MeshObstaclesKey.
+type meshObstacle struct {
+	Parent   string                     `json:"parent"`
+	Geometry spatialmath.GeometryConfig `json:"geometry"`
+}
+
 // WorldState is a struct to store the data representation of the robot's environment.
 type WorldState struct {
 	obstacleNames map[string]bool
@@ -104,6 +117,97 @@ func (ws *WorldState) ToProtobuf() (*commonpb.WorldState, error) {
 	}, nil
 }
 
+// MeshObstaclesToExtra returns extra with the mesh obstacles of the WorldState added under MeshObstaclesKey, so that they are
+// not reduced to their bounding boxes when the WorldState is sent as a proto. Extra is returned as is if there are none.
+func (ws *WorldState) MeshObstaclesToExtra(extra map[string]interface{}) (map[string]interface{}, error) {
+	if ws == nil {
+		return extra, nil
+	}
+	var meshes []meshObstacle
+	for _, gf := range ws.obstacles {
+		for _, geometry := range gf.geometries {
+			config, err := spatialmath.NewPortableGeometryConfig(geometry)
+			if err != nil {
+				return nil, err
+			}
+			if config.Type == spatialmath.MeshType {
+				meshes = append(meshes, meshObstacle{Parent: gf.frame, Geometry: *config})
+			}
+		}
+	}
+	if len(meshes) == 0 {
+		return extra, nil
+	}
+	// convert the meshes to the plain values that an extra is made of
+	data, err := json.Marshal(meshes)
+	if err != nil {
+		return nil, err
+	}
+	var value []interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	withMeshes := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withMeshes[k] = v
+	}
+	withMeshes[MeshObstaclesKey] = value
+	return withMeshes, nil
+}
+
+// MeshObstaclesFromExtra returns the WorldState with the mesh obstacles sent in extra under MeshObstaclesKey in place of the
+// obstacles with the same labels, or added if there are none, and extra without them. The WorldState and extra are returned as
+// they are if no mesh obstacles were sent.
+func (ws *WorldState) MeshObstaclesFromExtra(extra map[string]interface{}) (*WorldState, map[string]interface{}, error) {
+	value, ok := extra[MeshObstaclesKey]
+	if !ok {
+		return ws, extra, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	var meshes []meshObstacle
+	if err := json.Unmarshal(data, &meshes); err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid %s", MeshObstaclesKey)
+	}
+	replaced := make(map[string]bool, len(meshes))
+	meshObstacles := make([]*GeometriesInFrame, 0, len(meshes))
+	for i := range meshes {
+		geometry, err := meshes[i].Geometry.ParseConfig()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid %s", MeshObstaclesKey)
+		}
+		replaced[geometry.Label()] = true
+		meshObstacles = append(meshObstacles, NewGeometriesInFrame(meshes[i].Parent, []spatialmath.Geometry{geometry}))
+	}
+
+	var obstacles []*GeometriesInFrame
+	if ws != nil {
+		for _, gf := range ws.obstacles {
+			geometries := make([]spatialmath.Geometry, 0, len(gf.geometries))
+			for _, geometry := range gf.geometries {
+				if !replaced[geometry.Label()] {
+					geometries = append(geometries, geometry)
+				}
+			}
+			obstacles = append(obstacles, NewGeometriesInFrame(gf.frame, geometries))
+		}
+	}
+	withMeshes, err := NewWorldState(append(obstacles, meshObstacles...), ws.Transforms())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withoutMeshes := make(map[string]interface{}, len(extra)-1)
+	for k, v := range extra {
+		if k != MeshObstaclesKey {
+			withoutMeshes[k] = v
+		}
+	}
+	return withMeshes, withoutMeshes, nil
+}
+
 // String returns a string representation of the geometries in the WorldState.
 func (ws *WorldState) String() string {
 	if ws == nil {
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/geo/r3"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/spatialmath"
 )
@@ -78,3 +80,67 @@ func TestString(t *testing.T) {
 
 	test.That(t, ws.String(), test.ShouldEqual, testTable.Render())
 }
+
+func TestMeshObstaclesExtra(t *testing.T) {
+	// a tetrahedron, which the WorldState proto can only send as its bounding box
+	tetra, err := spatialmath.NewMesh(
+		spatialmath.NewPoseFromPoint(r3.Vector{X: 100}),
+		[]r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 10, Y: 0, Z: 0}, {X: 0, Y: 10, Z: 0}, {X: 0, Y: 0, Z: 10}},
+		[][3]int{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}},
+		"tetra",
+	)
+	test.That(t, err, test.ShouldBeNil)
+	sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "sphere")
+	test.That(t, err, test.ShouldBeNil)
+	ws, err := NewWorldState([]*GeometriesInFrame{
+		NewGeometriesInFrame("world", []spatialmath.Geometry{sphere}),
+		NewGeometriesInFrame("camera", []spatialmath.Geometry{tetra}),
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	extra, err := ws.MeshObstaclesToExtra(map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldContainKey, MeshObstaclesKey)
+
+	// send the WorldState and extra as a request would
+	wsProto, err := ws.ToProtobuf()
+	test.That(t, err, test.ShouldBeNil)
+	extraProto, err := structpb.NewStruct(extra)
+	test.That(t, err, test.ShouldBeNil)
+	received, err := WorldStateFromProtobuf(wsProto)
+	test.That(t, err, test.ShouldBeNil)
+	received, receivedExtra, err := received.MeshObstaclesFromExtra(extraProto.AsMap())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, receivedExtra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
+	test.That(t, received.ObstacleNames(), test.ShouldResemble, map[string]bool{"sphere": true, "tetra": true})
+	for _, gf := range received.obstacles {
+		for _, geometry := range gf.Geometries() {
+			switch geometry.Label() {
+			case "tetra":
+				test.That(t, gf.Parent(), test.ShouldEqual, "camera")
+				test.That(t, spatialmath.GeometriesAlmostEqual(geometry, tetra), test.ShouldBeTrue)
+			case "sphere":
+				test.That(t, gf.Parent(), test.ShouldEqual, "world")
+				test.That(t, spatialmath.GeometriesAlmostEqual(geometry, sphere), test.ShouldBeTrue)
+			}
+		}
+	}
+
+	// without mesh obstacles, neither is changed
+	ws, err = NewWorldState([]*GeometriesInFrame{NewGeometriesInFrame("world", []spatialmath.Geometry{sphere})}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	extra, err = ws.MeshObstaclesToExtra(map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+	unchanged, unchangedExtra, err := ws.MeshObstaclesFromExtra(extra)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, unchanged, test.ShouldEqual, ws)
+	test.That(t, unchangedExtra, test.ShouldResemble, extra)
+
+	// a mesh obstacle which does not describe a mesh is rejected
+	_, _, err = ws.MeshObstaclesFromExtra(map[string]interface{}{
+		MeshObstaclesKey: []interface{}{map[string]interface{}{"parent": "world", "geometry": map[string]interface{}{"type": "mesh"}}},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+}
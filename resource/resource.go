@@ -150,6 +150,24 @@ type Shaped interface {
 	Geometries(context.Context, map[string]interface{}) ([]spatialmath.Geometry, error)
 }
 
+// Statser is implemented by resources that report custom numeric statistics, such as driver queue
+// depths or device temperatures, to be included in the robot's diagnostics.
+type Statser interface {
+	// Stats returns the current value of each statistic, keyed by name. The robot, or the module
+	// serving the resource, samples it periodically, so it must be cheap and must not block.
+	Stats() map[string]float64
+}
+
+// Calibrated is implemented by resources which hold calibration data measured on the device, such as sensor
+// offsets or camera intrinsics, so that the data can be carried over to a replacement device in a machine
+// snapshot.
+type Calibrated interface {
+	// CalibrationData returns the resource's calibration data in a form it can restore from.
+	CalibrationData(ctx context.Context) ([]byte, error)
+	// RestoreCalibrationData replaces the resource's calibration data with data returned by CalibrationData.
+	RestoreCalibrationData(ctx context.Context, data []byte) error
+}
+
 // ErrDoUnimplemented is returned if the DoCommand methods is not implemented.
 var ErrDoUnimplemented = errors.New("DoCommand unimplemented")
 
@@ -0,0 +1,76 @@
+package rimage
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// A Colormap maps values between 0 and 1 to colors, by interpolating between evenly spaced colors
+// from the lowest to the highest value.
+type Colormap []Color
+
+// The available colormaps.
+var (
+	// GrayColormap goes from black to white.
+	GrayColormap = Colormap{NewColor(0, 0, 0), NewColor(255, 255, 255)}
+	// IronColormap goes from black through purple, red and yellow to white, like most thermal cameras.
+	IronColormap = Colormap{
+		NewColor(0, 0, 0),
+		NewColor(68, 0, 140),
+		NewColor(180, 0, 140),
+		NewColor(240, 80, 0),
+		NewColor(255, 190, 0),
+		NewColor(255, 255, 255),
+	}
+	// RainbowColormap goes from blue through green and yellow to red.
+	RainbowColormap = Colormap{
+		NewColor(0, 0, 255),
+		NewColor(0, 255, 255),
+		NewColor(0, 255, 0),
+		NewColor(255, 255, 0),
+		NewColor(255, 0, 0),
+	}
+)
+
+var colormaps = map[string]Colormap{
+	"gray":    GrayColormap,
+	"iron":    IronColormap,
+	"rainbow": RainbowColormap,
+}
+
+// ColormapByName returns the colormap with the given name, which is one of "gray", "iron" and
+// "rainbow". An empty name is the iron colormap.
+func ColormapByName(name string) (Colormap, error) {
+	if name == "" {
+		return IronColormap, nil
+	}
+	colormap, ok := colormaps[name]
+	if !ok {
+		return nil, errors.Errorf("unknown colormap %q", name)
+	}
+	return colormap, nil
+}
+
+// At returns the color of a value between 0 and 1. Values outside of the range get the colors at
+// its ends.
+func (cm Colormap) At(value float64) Color {
+	if len(cm) == 0 {
+		return NewColor(0, 0, 0)
+	}
+	if len(cm) == 1 || math.IsNaN(value) || value <= 0 {
+		return cm[0]
+	}
+	if value >= 1 {
+		return cm[len(cm)-1]
+	}
+	position := value * float64(len(cm)-1)
+	i := int(position)
+	fraction := position - float64(i)
+	r0, g0, b0 := cm[i].RGB255()
+	r1, g1, b1 := cm[i+1].RGB255()
+	lerp := func(from, to uint8) uint8 {
+		return uint8(math.Round(float64(from) + (float64(to)-float64(from))*fraction))
+	}
+	return NewColor(lerp(r0, r1), lerp(g0, g1), lerp(b0, b1))
+}
@@ -107,6 +107,29 @@ func init() {
 			}, nil
 		},
 	)
+
+	// Here we register our format for thermal images so that we can use
+	// image.Decode as long as we have the appropriate header
+	image.RegisterFormat("vnd.viam.thermal", string(ThermalImageMagicNumber),
+		func(r io.Reader) (image.Image, error) {
+			return ReadThermalImage(r)
+		},
+		func(r io.Reader) (image.Config, error) {
+			header := make([]byte, RawThermalHeaderLength)
+			if _, err := io.ReadFull(r, header); err != nil {
+				return image.Config{}, err
+			}
+			width, height, _, err := parseThermalHeader(header)
+			if err != nil {
+				return image.Config{}, err
+			}
+			return image.Config{
+				ColorModel: color.Gray16Model,
+				Width:      width,
+				Height:     height,
+			}, nil
+		},
+	)
 } // end of init
 
 // readImageFromFile extracts the RGB, Z16, or raw depth data from an image file.
@@ -291,6 +314,10 @@ func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte,
 		if _, err := WriteViamDepthMapTo(img, &buf); err != nil {
 			return nil, err
 		}
+	case ut.MimeTypeRawThermal:
+		if _, err := WriteThermalImageTo(img, &buf); err != nil {
+			return nil, err
+		}
 	case ut.MimeTypeRawRGBA:
 		// Here we create a custom header to prepend to Raw RGBA data. Credit to
 		// Ben Zotto for inventing this formulation
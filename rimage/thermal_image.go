@@ -0,0 +1,251 @@
+package rimage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ThermalImageMagicNumber represents the magic number for our custom header for raw thermal data.
+var ThermalImageMagicNumber = []byte("THERMAL1")
+
+// RawThermalHeaderLength is the length of our custom header for raw thermal data in bytes. The header
+// contains 8 bytes of magic number, followed by 8 bytes each for the width, the height, and the scale
+// and offset of the temperature scale as float64s.
+const RawThermalHeaderLength = 40
+
+// TemperatureScale converts the raw readings of a radiometric thermal camera into degrees Celsius,
+// as Scale*reading + OffsetCelsius.
+type TemperatureScale struct {
+	Scale         float64 `json:"scale"`
+	OffsetCelsius float64 `json:"offset_celsius"`
+}
+
+// CentikelvinScale is the temperature scale of cameras which report hundredths of a kelvin, such as
+// FLIR Lepton cameras in TLinear mode.
+var CentikelvinScale = TemperatureScale{Scale: 0.01, OffsetCelsius: -273.15}
+
+// Celsius returns the temperature of a raw reading in degrees Celsius.
+func (ts TemperatureScale) Celsius(reading uint16) float64 {
+	return ts.Scale*float64(reading) + ts.OffsetCelsius
+}
+
+// Reading returns the raw reading closest to a temperature in degrees Celsius.
+func (ts TemperatureScale) Reading(celsius float64) uint16 {
+	if ts.Scale == 0 {
+		return 0
+	}
+	reading := math.Round((celsius - ts.OffsetCelsius) / ts.Scale)
+	return uint16(math.Max(0, math.Min(math.MaxUint16, reading)))
+}
+
+// ThermalImage fulfills the image.Image interface and represents the raw 16-bit readings of a
+// radiometric thermal camera, along with the scale which turns them into temperatures.
+type ThermalImage struct {
+	width  int
+	height int
+	scale  TemperatureScale
+
+	data []uint16
+}
+
+// NewEmptyThermalImage returns an unset thermal image with the given dimensions and temperature scale.
+func NewEmptyThermalImage(width, height int, scale TemperatureScale) *ThermalImage {
+	return &ThermalImage{
+		width:  width,
+		height: height,
+		scale:  scale,
+		data:   make([]uint16, width*height),
+	}
+}
+
+// Clone makes a copy of the thermal image.
+func (ti *ThermalImage) Clone() *ThermalImage {
+	cloned := NewEmptyThermalImage(ti.width, ti.height, ti.scale)
+	copy(cloned.data, ti.data)
+	return cloned
+}
+
+func (ti *ThermalImage) kxy(x, y int) int {
+	return (y * ti.width) + x
+}
+
+// Width returns the width of the thermal image.
+func (ti *ThermalImage) Width() int {
+	return ti.width
+}
+
+// Height returns the height of the thermal image.
+func (ti *ThermalImage) Height() int {
+	return ti.height
+}
+
+// Scale returns the temperature scale of the raw readings.
+func (ti *ThermalImage) Scale() TemperatureScale {
+	return ti.scale
+}
+
+// Data returns the raw readings of the thermal image, row by row.
+func (ti *ThermalImage) Data() []uint16 {
+	return ti.data
+}
+
+// Bounds returns the rectangle dimensions of the image.
+func (ti *ThermalImage) Bounds() image.Rectangle {
+	return image.Rect(0, 0, ti.width, ti.height)
+}
+
+// GetReading returns the raw reading at a given (x,y) coordinate.
+func (ti *ThermalImage) GetReading(x, y int) uint16 {
+	return ti.data[ti.kxy(x, y)]
+}
+
+// Set sets the raw reading at a given (x,y) coordinate.
+func (ti *ThermalImage) Set(x, y int, reading uint16) {
+	ti.data[ti.kxy(x, y)] = reading
+}
+
+// Celsius returns the temperature at a given (x,y) coordinate in degrees Celsius.
+func (ti *ThermalImage) Celsius(x, y int) float64 {
+	return ti.scale.Celsius(ti.GetReading(x, y))
+}
+
+// At returns the raw reading as a color.Color so ThermalImage can implement image.Image.
+func (ti *ThermalImage) At(x, y int) color.Color {
+	return color.Gray16{ti.GetReading(x, y)}
+}
+
+// ColorModel for ThermalImage so that it implements image.Image.
+func (ti *ThermalImage) ColorModel() color.Model { return color.Gray16Model }
+
+// MinMaxCelsius returns the lowest and highest temperatures in the image in degrees Celsius.
+func (ti *ThermalImage) MinMaxCelsius() (float64, float64) {
+	if len(ti.data) == 0 {
+		return 0, 0
+	}
+	minReading, maxReading := ti.data[0], ti.data[0]
+	for _, reading := range ti.data {
+		if reading < minReading {
+			minReading = reading
+		}
+		if reading > maxReading {
+			maxReading = reading
+		}
+	}
+	low, high := ti.scale.Celsius(minReading), ti.scale.Celsius(maxReading)
+	if low > high {
+		// a negative scale flips the order of the readings
+		return high, low
+	}
+	return low, high
+}
+
+// Colorize renders the thermal image with the colormap, spread over the temperatures of the image.
+func (ti *ThermalImage) Colorize(colormap Colormap) *Image {
+	minCelsius, maxCelsius := ti.MinMaxCelsius()
+	return ti.ColorizeRange(colormap, minCelsius, maxCelsius)
+}
+
+// ColorizeRange renders the thermal image with the colormap, spread over the given temperatures in
+// degrees Celsius. Temperatures outside the range get the colors at its ends.
+func (ti *ThermalImage) ColorizeRange(colormap Colormap, minCelsius, maxCelsius float64) *Image {
+	img := NewImage(ti.width, ti.height)
+	span := maxCelsius - minCelsius
+	for y := 0; y < ti.height; y++ {
+		for x := 0; x < ti.width; x++ {
+			ratio := 0.0
+			if span > 0 {
+				ratio = (ti.Celsius(x, y) - minCelsius) / span
+			}
+			img.SetXY(x, y, colormap.At(ratio))
+		}
+	}
+	return img
+}
+
+// ConvertImageToThermalImage takes an image and figures out if it's already a ThermalImage or if it
+// can be decoded into one.
+func ConvertImageToThermalImage(ctx context.Context, img image.Image) (*ThermalImage, error) {
+	switch ii := img.(type) {
+	case *LazyEncodedImage:
+		decodedImg, err := DecodeImage(ctx, ii.RawData(), ii.MIMEType())
+		if err != nil {
+			return nil, err
+		}
+		return ConvertImageToThermalImage(ctx, decodedImg)
+	case *ThermalImage:
+		return ii, nil
+	default:
+		return nil, errors.Errorf("don't know how to make ThermalImage from %T", img)
+	}
+}
+
+// WriteThermalImageTo writes a thermal image to the given writer as vnd.viam.thermal bytes: the
+// header followed by 2 bytes per pixel, row by row.
+func WriteThermalImageTo(img image.Image, out io.Writer) (int64, error) {
+	if lazy, ok := img.(*LazyEncodedImage); ok {
+		lazy.decode()
+		if lazy.decodeErr != nil {
+			return 0, errors.Errorf("could not decode LazyEncodedImage to a thermal image: %v", lazy.decodeErr)
+		}
+		img = lazy.decodedImage
+	}
+	ti, ok := img.(*ThermalImage)
+	if !ok {
+		return 0, errors.Errorf("cannot convert image type %T to image/vnd.viam.thermal format", img)
+	}
+	header := make([]byte, RawThermalHeaderLength)
+	copy(header, ThermalImageMagicNumber)
+	binary.BigEndian.PutUint64(header[8:16], uint64(ti.width))
+	binary.BigEndian.PutUint64(header[16:24], uint64(ti.height))
+	binary.BigEndian.PutUint64(header[24:32], math.Float64bits(ti.scale.Scale))
+	binary.BigEndian.PutUint64(header[32:40], math.Float64bits(ti.scale.OffsetCelsius))
+	n, err := out.Write(header)
+	totalN := int64(n)
+	if err != nil {
+		return totalN, err
+	}
+	if err := binary.Write(out, binary.BigEndian, ti.data); err != nil {
+		return totalN, err
+	}
+	return totalN + int64(len(ti.data)*2), nil
+}
+
+// ReadThermalImage returns a thermal image from the given reader of vnd.viam.thermal bytes.
+func ReadThermalImage(r io.Reader) (*ThermalImage, error) {
+	header := make([]byte, RawThermalHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(err, "could not read vnd.viam.thermal header")
+	}
+	width, height, scale, err := parseThermalHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	ti := NewEmptyThermalImage(width, height, scale)
+	if err := binary.Read(r, binary.BigEndian, ti.data); err != nil {
+		return nil, errors.Wrap(err, "could not read vnd.viam.thermal data")
+	}
+	return ti, nil
+}
+
+func parseThermalHeader(header []byte) (int, int, TemperatureScale, error) {
+	if !bytes.Equal(header[:8], ThermalImageMagicNumber) {
+		return 0, 0, TemperatureScale{}, errors.New("not vnd.viam.thermal data")
+	}
+	width := binary.BigEndian.Uint64(header[8:16])
+	height := binary.BigEndian.Uint64(header[16:24])
+	if width >= 100000 || height >= 100000 {
+		return 0, 0, TemperatureScale{}, errors.Errorf("bad width or height for thermal image %v %v", width, height)
+	}
+	scale := TemperatureScale{
+		Scale:         math.Float64frombits(binary.BigEndian.Uint64(header[24:32])),
+		OffsetCelsius: math.Float64frombits(binary.BigEndian.Uint64(header[32:40])),
+	}
+	return int(width), int(height), scale, nil
+}
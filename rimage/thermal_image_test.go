@@ -0,0 +1,81 @@
+package rimage
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+func TestThermalImage(t *testing.T) {
+	ti := NewEmptyThermalImage(3, 2, CentikelvinScale)
+	test.That(t, ti.Width(), test.ShouldEqual, 3)
+	test.That(t, ti.Height(), test.ShouldEqual, 2)
+	ti.Set(0, 0, CentikelvinScale.Reading(20))
+	ti.Set(1, 0, CentikelvinScale.Reading(36.6))
+	ti.Set(2, 1, CentikelvinScale.Reading(-10))
+	for _, p := range [][2]int{{0, 1}, {1, 1}, {2, 0}} {
+		ti.Set(p[0], p[1], CentikelvinScale.Reading(25))
+	}
+
+	test.That(t, ti.GetReading(1, 0), test.ShouldEqual, 30975)
+	test.That(t, ti.Celsius(1, 0), test.ShouldAlmostEqual, 36.6)
+	test.That(t, ti.At(1, 0), test.ShouldResemble, color.Gray16{30975})
+	minCelsius, maxCelsius := ti.MinMaxCelsius()
+	test.That(t, minCelsius, test.ShouldAlmostEqual, -10)
+	test.That(t, maxCelsius, test.ShouldAlmostEqual, 36.6)
+
+	t.Run("encoding", func(t *testing.T) {
+		raw, err := EncodeImage(context.Background(), ti, utils.MimeTypeRawThermal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, raw[:8], test.ShouldResemble, ThermalImageMagicNumber)
+		test.That(t, len(raw), test.ShouldEqual, RawThermalHeaderLength+3*2*2)
+
+		decoded, err := DecodeImage(context.Background(), raw, utils.MimeTypeRawThermal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decoded, test.ShouldResemble, ti)
+
+		lazy := NewLazyEncodedImage(raw, utils.MimeTypeRawThermal)
+		test.That(t, lazy.Bounds(), test.ShouldResemble, ti.Bounds())
+		test.That(t, lazy.ColorModel(), test.ShouldEqual, color.Gray16Model)
+		fromLazy, err := ConvertImageToThermalImage(context.Background(), lazy)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fromLazy.Scale(), test.ShouldResemble, CentikelvinScale)
+		test.That(t, fromLazy.Data(), test.ShouldResemble, ti.Data())
+
+		_, err = ReadThermalImage(bytes.NewReader(raw[:RawThermalHeaderLength+1]))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = EncodeImage(context.Background(), NewEmptyDepthMap(1, 1), utils.MimeTypeRawThermal)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("colorize", func(t *testing.T) {
+		img := ti.Colorize(GrayColormap)
+		test.That(t, img.GetXY(2, 1), test.ShouldEqual, NewColor(0, 0, 0))
+		test.That(t, img.GetXY(1, 0), test.ShouldEqual, NewColor(255, 255, 255))
+
+		img = ti.ColorizeRange(IronColormap, 0, 30)
+		test.That(t, img.GetXY(2, 1), test.ShouldEqual, IronColormap[0])
+		test.That(t, img.GetXY(1, 0), test.ShouldEqual, IronColormap[len(IronColormap)-1])
+	})
+}
+
+func TestColormap(t *testing.T) {
+	test.That(t, GrayColormap.At(-1), test.ShouldEqual, NewColor(0, 0, 0))
+	test.That(t, GrayColormap.At(0.5), test.ShouldEqual, NewColor(128, 128, 128))
+	test.That(t, GrayColormap.At(2), test.ShouldEqual, NewColor(255, 255, 255))
+	test.That(t, RainbowColormap.At(0.25), test.ShouldEqual, NewColor(0, 255, 255))
+
+	colormap, err := ColormapByName("")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, colormap, test.ShouldResemble, IronColormap)
+	colormap, err = ColormapByName("rainbow")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, colormap, test.ShouldResemble, RainbowColormap)
+	_, err = ColormapByName("plasma")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -237,6 +237,19 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 	rc.dialOptions = append(
 		rc.dialOptions,
 		rpc.WithUnaryClientInterceptor(contextutils.ContextWithMetadataUnaryClientInterceptor),
+	)
+	// user interceptors and retries
+	for _, interceptor := range rOpts.unaryInterceptors {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(interceptor))
+	}
+	for _, interceptor := range rOpts.streamInterceptors {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithStreamClientInterceptor(interceptor))
+	}
+	if rOpts.retryPolicy != nil {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(rOpts.retryPolicy.unaryClientInterceptor()))
+	}
+	rc.dialOptions = append(
+		rc.dialOptions,
 		// error handling
 		rpc.WithUnaryClientInterceptor(rc.handleUnaryDisconnect),
 		rpc.WithStreamClientInterceptor(rc.handleStreamDisconnect),
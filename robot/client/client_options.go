@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
 )
 
 // robotClientOpts configure a Dial call. robotClientOpts are set by the RobotClientOption
@@ -32,6 +33,14 @@ type robotClientOpts struct {
 
 	// controls whether or not sessions are disabled.
 	disableSessions bool
+
+	// interceptors added by the user, which are applied to all calls made by the client and its
+	// resource clients.
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+
+	// retryPolicy is the policy idempotent calls are retried with. If nil, calls are not retried.
+	retryPolicy *RetryPolicy
 }
 
 // RobotClientOption configures how we set up the connection.
@@ -92,6 +101,31 @@ func WithDisableSessions() RobotClientOption {
 	})
 }
 
+// WithUnaryClientInterceptors returns a RobotClientOption which adds interceptors to every unary
+// call made by the robot client and the resource clients created by it, e.g. to add metrics or
+// tracing. The interceptors are applied in order, before any retries, so they see every call once.
+func WithUnaryClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	})
+}
+
+// WithStreamClientInterceptors returns a RobotClientOption which adds interceptors to every
+// streaming call made by the robot client and the resource clients created by it.
+func WithStreamClientInterceptors(interceptors ...grpc.StreamClientInterceptor) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.streamInterceptors = append(o.streamInterceptors, interceptors...)
+	})
+}
+
+// WithRetryPolicy returns a RobotClientOption which retries idempotent calls that fail with a
+// transient error according to the given policy. See DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.retryPolicy = &policy
+	})
+}
+
 // WithDialOptions returns a RobotClientOption which sets the options for making
 // gRPC connections to other servers.
 func WithDialOptions(opts ...rpc.DialOption) RobotClientOption {
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures how the robot client, and every resource client created by it, retries
+// idempotent calls which fail with a transient error. Only unary calls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a call, including the first. Calls are not
+	// retried if it is 1 or less.
+	MaxAttempts int
+
+	// InitialBackoff is how long to wait before the first retry. Every following wait is
+	// BackoffMultiplier times longer than the previous one, up to MaxBackoff.
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+
+	// Jitter randomizes every wait by up to this fraction of it, e.g. 0.2 for ±20%, so that clients
+	// which failed at the same time do not retry in lockstep.
+	Jitter float64
+
+	// PerAttemptTimeout, if non-zero, limits how long each attempt may take, so that an attempt that
+	// hangs leaves time for retries within the deadline of the call.
+	PerAttemptTimeout time.Duration
+
+	// Timeout, if non-zero, is the deadline budget of a call: the total time that all of its attempts
+	// and the waits between them may take. An earlier deadline of the call's context takes precedence.
+	// No retry is attempted if the deadline would pass before it starts.
+	Timeout time.Duration
+
+	// Codes are the status codes of the errors which are retried.
+	Codes []codes.Code
+
+	// Methods are the methods which are retried. A method is either fully qualified, e.g.
+	// "/viam.component.camera.v1.CameraService/GetImage", or only the name of the method, e.g.
+	// "GetImage", which matches the method of any service. Only methods that are safe to call more
+	// than once should be retried.
+	Methods []string
+}
+
+// DefaultRetryPolicy returns a retry policy which retries reading from sensors, cameras and the
+// poses of components up to three times on errors caused by connectivity.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        2 * time.Second,
+		BackoffMultiplier: 2,
+		Jitter:            0.2,
+		Codes:             []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.Aborted},
+		Methods: []string{
+			"GetReadings",
+			"GetImage",
+			"GetImages",
+			"GetPointCloud",
+			"GetPose",
+			"GetPosition",
+			"GetEndPosition",
+			"GetJointPositions",
+		},
+	}
+}
+
+// retriesMethod returns whether the policy retries the given fully qualified method.
+func (p RetryPolicy) retriesMethod(method string) bool {
+	name := method[strings.LastIndex(method, "/")+1:]
+	for _, m := range p.Methods {
+		if m == method || m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// retriesCode returns whether the policy retries errors with the given status code.
+func (p RetryPolicy) retriesCode(code codes.Code) bool {
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		wait *= p.BackoffMultiplier
+		if p.MaxBackoff > 0 && wait >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		//nolint:gosec // jitter does not need a secure random number
+		wait *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(wait)
+}
+
+// unaryClientInterceptor returns an interceptor which retries the calls selected by the policy.
+func (p RetryPolicy) unaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if p.MaxAttempts <= 1 || !p.retriesMethod(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if p.Timeout > 0 {
+			var cancel func()
+			ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+			defer cancel()
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := p.invoke(ctx, method, req, reply, cc, invoker, opts...)
+			if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !p.retriesCode(status.Code(err)) {
+				return err
+			}
+
+			wait := p.backoff(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+				return err
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// invoke makes a single attempt of a call.
+func (p RetryPolicy) invoke(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if p.PerAttemptTimeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, p.PerAttemptTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const getImageMethod = "/viam.component.camera.v1.CameraService/GetImage"
+
+// failingInvoker returns an invoker which fails with the given codes, in order, and then succeeds.
+func failingInvoker(calls *int, failures ...codes.Code) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(failures) {
+			return status.Error(failures[*calls-1], "failed")
+		}
+		return nil
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        5 * time.Millisecond,
+		BackoffMultiplier: 2,
+		Codes:             []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+		Methods:           []string{"GetImage", "/viam.component.sensor.v1.SensorService/GetReadings"},
+	}
+	interceptor := policy.unaryClientInterceptor()
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		var calls int
+		err := interceptor(context.Background(), getImageMethod, nil, nil, nil, failingInvoker(&calls, codes.Unavailable, codes.Unavailable))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 3)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls int
+		invoker := failingInvoker(&calls, codes.Unavailable, codes.Unavailable, codes.Unavailable)
+		err := interceptor(context.Background(), getImageMethod, nil, nil, nil, invoker)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, calls, test.ShouldEqual, 3)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		var calls int
+		err := interceptor(context.Background(), getImageMethod, nil, nil, nil, failingInvoker(&calls, codes.InvalidArgument))
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+		test.That(t, calls, test.ShouldEqual, 1)
+	})
+
+	t.Run("does not retry other methods", func(t *testing.T) {
+		var calls int
+		invoker := failingInvoker(&calls, codes.Unavailable)
+		err := interceptor(context.Background(), "/viam.component.base.v1.BaseService/SetPower", nil, nil, nil, invoker)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, calls, test.ShouldEqual, 1)
+
+		calls = 0
+		err = interceptor(context.Background(), "/viam.component.movementsensor.v1.MovementSensorService/GetReadings", nil, nil, nil, invoker)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, calls, test.ShouldEqual, 1)
+
+		calls = 0
+		err = interceptor(context.Background(), "/viam.component.sensor.v1.SensorService/GetReadings", nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 2)
+	})
+
+	t.Run("per attempt timeout", func(t *testing.T) {
+		policy := policy
+		policy.PerAttemptTimeout = 10 * time.Millisecond
+		var calls int
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return nil
+		}
+		err := policy.unaryClientInterceptor()(context.Background(), getImageMethod, nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 2)
+	})
+
+	t.Run("deadline budget", func(t *testing.T) {
+		policy := policy
+		policy.MaxAttempts = 100
+		policy.InitialBackoff = 20 * time.Millisecond
+		policy.MaxBackoff = 20 * time.Millisecond
+		policy.Timeout = 50 * time.Millisecond
+		var calls int
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.Unavailable, "failed")
+		}
+		start := time.Now()
+		err := policy.unaryClientInterceptor()(context.Background(), getImageMethod, nil, nil, nil, invoker)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		// no retry is started once the next wait would exceed the budget
+		test.That(t, time.Since(start), test.ShouldBeLessThan, 50*time.Millisecond)
+		test.That(t, calls, test.ShouldBeBetweenOrEqual, 2, 3)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	test.That(t, policy.backoff(1), test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, policy.backoff(2), test.ShouldEqual, 200*time.Millisecond)
+	test.That(t, policy.backoff(10), test.ShouldEqual, 2*time.Second)
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		test.That(t, policy.backoff(1), test.ShouldBeBetweenOrEqual, 50*time.Millisecond, 150*time.Millisecond)
+	}
+}
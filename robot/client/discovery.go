@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/edaniels/zeroconf"
+
+	"go.viam.com/rdk/logging"
+)
+
+// MachineMDNSService is the mDNS service type that machines advertise themselves under,
+// alongside the "_rpc._tcp" service used for dialing.
+const MachineMDNSService = "_viam._tcp"
+
+// defaultDiscoveryWindow is how long DiscoverMachines listens for responses when the
+// context passed to it has no deadline.
+var defaultDiscoveryWindow = 2 * time.Second
+
+// TXT record keys, formatted per RFC 1464 as key=value.
+const (
+	txtKeyName       = "name"
+	txtKeyLocalName  = "local_name"
+	txtKeyRDKVersion = "rdk_version"
+	txtKeyAPIVersion = "api_version"
+	txtKeyAuth       = "auth"
+	txtKeyProtocols  = "protocols"
+)
+
+// MachineAdvertisement describes what a machine advertises about itself on the local network.
+type MachineAdvertisement struct {
+	// Name is the FQDN the machine is reachable at. Machines without one, such as those not managed by the
+	// cloud, are named by their local FQDN or else by their host name on the local network, e.g. "my-pi.local".
+	Name string
+	// LocalName is the local FQDN of the machine, if any.
+	LocalName string
+	// RDKVersion is the version of viam-server that is running.
+	RDKVersion string
+	// APIVersion is the version of the Viam API the machine speaks.
+	APIVersion string
+	// AuthTypes are the credential types the machine accepts. Empty means no auth is required.
+	AuthTypes []string
+	// Protocols are the connection protocols the machine accepts (e.g. grpc, webrtc).
+	Protocols []string
+}
+
+// TXTRecords encodes the advertisement as mDNS TXT records.
+func (ad MachineAdvertisement) TXTRecords() []string {
+	records := []string{txtKeyName + "=" + ad.Name}
+	if ad.LocalName != "" {
+		records = append(records, txtKeyLocalName+"="+ad.LocalName)
+	}
+	if ad.RDKVersion != "" {
+		records = append(records, txtKeyRDKVersion+"="+ad.RDKVersion)
+	}
+	if ad.APIVersion != "" {
+		records = append(records, txtKeyAPIVersion+"="+ad.APIVersion)
+	}
+	records = append(records,
+		txtKeyAuth+"="+strings.Join(ad.AuthTypes, ","),
+		txtKeyProtocols+"="+strings.Join(ad.Protocols, ","),
+	)
+	return records
+}
+
+// MachineAdvertisementFromTXTRecords decodes an advertisement from mDNS TXT records. Unknown
+// keys are ignored so that newer machines can add records without breaking older clients.
+func MachineAdvertisementFromTXTRecords(records []string) MachineAdvertisement {
+	var ad MachineAdvertisement
+	splitList := func(val string) []string {
+		if val == "" {
+			return nil
+		}
+		return strings.Split(val, ",")
+	}
+	for _, record := range records {
+		key, val, _ := strings.Cut(record, "=")
+		switch key {
+		case txtKeyName:
+			ad.Name = val
+		case txtKeyLocalName:
+			ad.LocalName = val
+		case txtKeyRDKVersion:
+			ad.RDKVersion = val
+		case txtKeyAPIVersion:
+			ad.APIVersion = val
+		case txtKeyAuth:
+			ad.AuthTypes = splitList(val)
+		case txtKeyProtocols:
+			ad.Protocols = splitList(val)
+		}
+	}
+	return ad
+}
+
+// APIVersion returns the version of the Viam API this binary was built against, or an
+// empty string if it cannot be determined.
+func APIVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "go.viam.com/api" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// A DiscoveredMachine is a machine found on the local network.
+type DiscoveredMachine struct {
+	MachineAdvertisement
+
+	// Instance is the mDNS instance name the machine registered under.
+	Instance string
+	// Host is the host name of the device the machine runs on.
+	Host string
+	// Port is the port the machine's web server listens on.
+	Port int
+	// Addresses are the IP addresses the machine was found at.
+	Addresses []net.IP
+}
+
+// Address returns a dialable host:port for the machine, preferring IPv4.
+func (m DiscoveredMachine) Address() string {
+	if len(m.Addresses) == 0 {
+		return net.JoinHostPort(strings.TrimSuffix(m.Host, "."), fmt.Sprint(m.Port))
+	}
+	return net.JoinHostPort(m.Addresses[0].String(), fmt.Sprint(m.Port))
+}
+
+// RequiresAuth returns whether the machine requires credentials to connect.
+func (m DiscoveredMachine) RequiresAuth() bool {
+	return len(m.AuthTypes) != 0
+}
+
+// DiscoverMachines browses the local network for machines until the context is done and returns
+// every machine that responded. If the context has no deadline, it browses for a short default window.
+func DiscoverMachines(ctx context.Context, logger logging.Logger) ([]DiscoveredMachine, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultDiscoveryWindow)
+		defer cancel()
+	}
+
+	resolver, err := zeroconf.NewResolver(logger.AsZap())
+	if err != nil {
+		return nil, err
+	}
+	defer resolver.Shutdown()
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	if err := resolver.Browse(ctx, MachineMDNSService, "local.", entries); err != nil {
+		return nil, err
+	}
+
+	found := map[string]DiscoveredMachine{}
+	for entry := range entries {
+		if entry == nil {
+			continue
+		}
+		found[entry.Instance] = discoveredMachineFromEntry(entry)
+	}
+
+	machines := make([]DiscoveredMachine, 0, len(found))
+	for _, m := range found {
+		machines = append(machines, m)
+	}
+	sort.Slice(machines, func(i, j int) bool {
+		return machines[i].Instance < machines[j].Instance
+	})
+	return machines, nil
+}
+
+func discoveredMachineFromEntry(entry *zeroconf.ServiceEntry) DiscoveredMachine {
+	addrs := make([]net.IP, 0, len(entry.AddrIPv4)+len(entry.AddrIPv6))
+	addrs = append(addrs, entry.AddrIPv4...)
+	addrs = append(addrs, entry.AddrIPv6...)
+	return DiscoveredMachine{
+		MachineAdvertisement: MachineAdvertisementFromTXTRecords(entry.Text),
+		Instance:             entry.Instance,
+		Host:                 entry.HostName,
+		Port:                 entry.Port,
+		Addresses:            addrs,
+	}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"net"
+	"testing"
+
+	"github.com/edaniels/zeroconf"
+	"go.viam.com/test"
+)
+
+func TestMachineAdvertisementTXTRecords(t *testing.T) {
+	ad := MachineAdvertisement{
+		Name:       "my-machine.local",
+		RDKVersion: "v0.20.0",
+		APIVersion: "v0.1.277",
+		AuthTypes:  []string{"api-key", "robot-location-secret"},
+		Protocols:  []string{"grpc", "webrtc"},
+	}
+	records := ad.TXTRecords()
+	test.That(t, records, test.ShouldContain, "name=my-machine.local")
+	test.That(t, records, test.ShouldContain, "auth=api-key,robot-location-secret")
+	test.That(t, MachineAdvertisementFromTXTRecords(records), test.ShouldResemble, ad)
+
+	t.Run("no auth and unknown keys", func(t *testing.T) {
+		decoded := MachineAdvertisementFromTXTRecords([]string{"name=foo", "auth=", "grpc", "future_key=bar"})
+		test.That(t, decoded, test.ShouldResemble, MachineAdvertisement{Name: "foo"})
+	})
+}
+
+func TestDiscoveredMachineFromEntry(t *testing.T) {
+	entry := zeroconf.NewServiceEntry("my-machine-local", MachineMDNSService, "local.")
+	entry.HostName = "host.local."
+	entry.Port = 8080
+	entry.Text = MachineAdvertisement{Name: "my-machine.local", AuthTypes: []string{"api-key"}}.TXTRecords()
+	entry.AddrIPv4 = []net.IP{net.ParseIP("192.168.1.5")}
+
+	m := discoveredMachineFromEntry(entry)
+	test.That(t, m.Name, test.ShouldEqual, "my-machine.local")
+	test.That(t, m.Instance, test.ShouldEqual, "my-machine-local")
+	test.That(t, m.Address(), test.ShouldEqual, "192.168.1.5:8080")
+	test.That(t, m.RequiresAuth(), test.ShouldBeTrue)
+
+	entry.AddrIPv4 = nil
+	test.That(t, discoveredMachineFromEntry(entry).Address(), test.ShouldEqual, "host.local:8080")
+}
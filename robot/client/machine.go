@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/machineapi"
+)
+
+// snapshotUploadChunkSize is the size of the chunks a snapshot is uploaded in to be restored.
+const snapshotUploadChunkSize = 64 << 10
+
+// Snapshot writes a snapshot of the machine, as written by robot.LocalRobot.Snapshot, to w.
+func (rc *RobotClient) Snapshot(ctx context.Context, w io.Writer) error {
+	stream, err := rc.conn.NewStream(
+		ctx,
+		&googlegrpc.StreamDesc{StreamName: machineapi.SnapshotMethod, ServerStreams: true},
+		machineapi.FullMethod(machineapi.SnapshotMethod),
+	)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(&emptypb.Empty{}); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		chunk := &wrapperspb.BytesValue{}
+		err := stream.RecvMsg(chunk)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.GetValue()); err != nil {
+			return err
+		}
+	}
+}
+
+// RestoreSnapshot restores the machine from a snapshot read from r. It returns any error restoring it, including
+// those of resources which could not be built from the restored config.
+func (rc *RobotClient) RestoreSnapshot(ctx context.Context, r io.Reader) error {
+	stream, err := rc.conn.NewStream(
+		ctx,
+		&googlegrpc.StreamDesc{StreamName: machineapi.RestoreSnapshotMethod, ClientStreams: true},
+		machineapi.FullMethod(machineapi.RestoreSnapshotMethod),
+	)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, snapshotUploadChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := stream.SendMsg(wrapperspb.Bytes(buf[:n])); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return stream.RecvMsg(&emptypb.Empty{})
+}
+
+// LogLevels returns the log levels of the machine's resources, and the levels set at runtime for loggers
+// by name.
+func (rc *RobotClient) LogLevels(ctx context.Context) ([]robot.ResourceLogLevel, []robot.LoggerLevel, error) {
+	var resp machineapi.GetLogLevelsResponse
+	if err := rc.invokeMachine(ctx, machineapi.GetLogLevelsMethod, &emptypb.Empty{}, &resp); err != nil {
+		return nil, nil, err
+	}
+	var resources []robot.ResourceLogLevel
+	for _, level := range resp.Resources {
+		name, err := resource.NewFromString(level.Resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		resources = append(resources, robot.ResourceLogLevel{
+			Name:            name,
+			Level:           level.Level,
+			ConfiguredLevel: level.ConfiguredLevel,
+			Overridden:      level.Overridden,
+			OverrideExpires: timeOrZero(level.OverrideExpires),
+		})
+	}
+	var loggers []robot.LoggerLevel
+	for _, level := range resp.Loggers {
+		loggers = append(loggers, robot.LoggerLevel{
+			Logger:          level.Logger,
+			Level:           level.Level,
+			OverrideExpires: timeOrZero(level.OverrideExpires),
+		})
+	}
+	return resources, loggers, nil
+}
+
+// SetResourceLogLevel overrides the log level of a resource's logger on the machine, as
+// robot.LocalRobot.SetResourceLogLevel does.
+func (rc *RobotClient) SetResourceLogLevel(ctx context.Context, name resource.Name, level logging.Level, duration time.Duration) error {
+	return rc.setLogLevel(ctx, machineapi.SetLogLevelRequest{Resource: name.String(), Level: &level}, duration)
+}
+
+// SetLoggerLevel overrides the log level of a logger on the machine by name, as robot.LocalRobot.SetLoggerLevel
+// does.
+func (rc *RobotClient) SetLoggerLevel(ctx context.Context, logger string, level logging.Level, duration time.Duration) error {
+	return rc.setLogLevel(ctx, machineapi.SetLogLevelRequest{Logger: logger, Level: &level}, duration)
+}
+
+func (rc *RobotClient) setLogLevel(ctx context.Context, req machineapi.SetLogLevelRequest, duration time.Duration) error {
+	if duration != 0 {
+		req.Duration = duration.String()
+	}
+	reqStruct, err := machineapi.ToStruct(req)
+	if err != nil {
+		return err
+	}
+	return rc.conn.Invoke(ctx, machineapi.FullMethod(machineapi.SetLogLevelMethod), reqStruct, &emptypb.Empty{})
+}
+
+// CachedConfig describes the cached copy of the last config the machine read from the cloud, as
+// robot.LocalRobot.CachedConfig does. It returns a NotFound error if nothing is cached.
+func (rc *RobotClient) CachedConfig(ctx context.Context) (robot.CachedConfig, error) {
+	var resp machineapi.GetCachedConfigResponse
+	if err := rc.invokeMachine(ctx, machineapi.GetCachedConfigMethod, &emptypb.Empty{}, &resp); err != nil {
+		return robot.CachedConfig{}, err
+	}
+	packages := func(pkgs []machineapi.Package) []config.PackageConfig {
+		ret := make([]config.PackageConfig, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			ret = append(ret, config.PackageConfig{Name: pkg.Name, Package: pkg.Package, Version: pkg.Version, Type: pkg.Type})
+		}
+		return ret
+	}
+	return robot.CachedConfig{
+		CacheInfo: config.CacheInfo{
+			Path:            resp.Path,
+			LastUpdated:     resp.LastUpdated,
+			Packages:        packages(resp.Packages),
+			MissingPackages: packages(resp.MissingPackages),
+		},
+		RunningSince: timeOrZero(resp.RunningSince),
+	}, nil
+}
+
+// OperationProgress returns the progress of the machine's running operations which reported it, keyed by
+// operation ID. The operations themselves are listed by GetOperations.
+func (rc *RobotClient) OperationProgress(ctx context.Context) (map[string]operation.Progress, error) {
+	var resp machineapi.GetOperationProgressResponse
+	if err := rc.invokeMachine(ctx, machineapi.GetOperationProgressMethod, &emptypb.Empty{}, &resp); err != nil {
+		return nil, err
+	}
+	ret := make(map[string]operation.Progress, len(resp))
+	for id, progress := range resp {
+		ret[id] = operation.Progress{Percent: progress.Percent, Phase: progress.Phase, Updated: progress.Updated}
+	}
+	return ret, nil
+}
+
+// Logs returns the machine's recent log entries which match the filter, in the order they were logged, as
+// served at /debug/logs. It returns an Unavailable error if the machine does not buffer its logs.
+func (rc *RobotClient) Logs(ctx context.Context, filter logging.LogBufferFilter) ([]logging.BufferedLogEntry, error) {
+	req, err := machineapi.ToStruct(machineapi.NewGetLogsRequest(filter))
+	if err != nil {
+		return nil, err
+	}
+	var resp machineapi.GetLogsResponse
+	if err := rc.invokeMachine(ctx, machineapi.GetLogsMethod, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// StreamLogs calls fn with the machine's recent log entries which match the filter, followed by new ones as they
+// are logged, until the context is done or fn returns an error, which is returned. The filter's limit only applies
+// to the recent entries.
+func (rc *RobotClient) StreamLogs(ctx context.Context, filter logging.LogBufferFilter, fn func(logging.BufferedLogEntry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, err := machineapi.ToStruct(machineapi.NewGetLogsRequest(filter))
+	if err != nil {
+		return err
+	}
+	stream, err := rc.conn.NewStream(
+		ctx,
+		&googlegrpc.StreamDesc{StreamName: machineapi.StreamLogsMethod, ServerStreams: true},
+		machineapi.FullMethod(machineapi.StreamLogsMethod),
+	)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		msg := &structpb.Struct{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return err
+		}
+		var entry logging.BufferedLogEntry
+		if err := machineapi.FromStruct(msg, &entry); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamTransforms calls fn with the transforms of the machine's frames, as framesystem.StreamTransforms does on
+// the machine, until the context is done or fn returns an error, which is returned. An interval of zero uses the
+// machine's default.
+func (rc *RobotClient) StreamTransforms(
+	ctx context.Context,
+	interval time.Duration,
+	onlyChanged bool,
+	fn func(framesystem.TransformUpdate) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	request := machineapi.StreamTransformsRequest{Changed: onlyChanged}
+	if interval != 0 {
+		request.Interval = interval.String()
+	}
+	req, err := machineapi.ToStruct(request)
+	if err != nil {
+		return err
+	}
+	stream, err := rc.conn.NewStream(
+		ctx,
+		&googlegrpc.StreamDesc{StreamName: machineapi.StreamTransformsMethod, ServerStreams: true},
+		machineapi.FullMethod(machineapi.StreamTransformsMethod),
+	)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		msg := &structpb.Struct{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return err
+		}
+		var update framesystem.TransformUpdateJSON
+		if err := machineapi.FromStruct(msg, &update); err != nil {
+			return err
+		}
+		if err := fn(update.Update()); err != nil {
+			return err
+		}
+	}
+}
+
+// invokeMachine calls a unary method of the machine service and converts its response to resp.
+func (rc *RobotClient) invokeMachine(ctx context.Context, method string, req proto.Message, resp interface{}) error {
+	respStruct := &structpb.Struct{}
+	if err := rc.conn.Invoke(ctx, machineapi.FullMethod(method), req, respStruct); err != nil {
+		return err
+	}
+	return machineapi.FromStruct(respStruct, resp)
+}
+
+// timeOrZero returns the zero time for a time a message leaves unset.
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,280 @@
+package framesystem
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// DefaultTransformStreamInterval is how often StreamTransforms publishes updates by default.
+const DefaultTransformStreamInterval = 100 * time.Millisecond
+
+// MinTransformStreamInterval is the shortest time between updates which StreamTransforms publishes, since
+// every update rebuilds the frame system and reads the inputs of every component in it.
+const MinTransformStreamInterval = 10 * time.Millisecond
+
+// FrameTransform is the pose of a frame relative to its parent frame.
+type FrameTransform struct {
+	Frame  string
+	Parent string
+	Pose   spatialmath.Pose
+}
+
+// TransformUpdate is a set of frame transforms observed at the same time, along with the inputs, e.g. the
+// joint positions of arms, that they were computed from.
+type TransformUpdate struct {
+	Time       time.Time
+	Transforms []FrameTransform
+	Inputs     map[string][]referenceframe.Input
+}
+
+// TransformUpdateJSON is the JSON representation of a TransformUpdate, in which updates are sent to clients.
+// Translations are in mm and orientations are unit quaternions.
+type TransformUpdateJSON struct {
+	Time       time.Time            `json:"time"`
+	Transforms []FrameTransformJSON `json:"transforms"`
+	Inputs     map[string][]float64 `json:"inputs,omitempty"`
+}
+
+// FrameTransformJSON is the JSON representation of a FrameTransform.
+type FrameTransformJSON struct {
+	Frame       string          `json:"frame"`
+	Parent      string          `json:"parent"`
+	Translation TranslationJSON `json:"translation"`
+	Orientation QuaternionJSON  `json:"orientation"`
+}
+
+// TranslationJSON is the JSON representation of a translation.
+type TranslationJSON struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// QuaternionJSON is the JSON representation of an orientation as a unit quaternion.
+type QuaternionJSON struct {
+	W float64 `json:"w"`
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// NewTransformUpdateJSON returns the JSON representation of an update.
+func NewTransformUpdateJSON(update TransformUpdate) TransformUpdateJSON {
+	out := TransformUpdateJSON{
+		Time:       update.Time,
+		Transforms: make([]FrameTransformJSON, 0, len(update.Transforms)),
+		Inputs:     map[string][]float64{},
+	}
+	for _, tf := range update.Transforms {
+		pt := tf.Pose.Point()
+		q := tf.Pose.Orientation().Quaternion()
+		out.Transforms = append(out.Transforms, FrameTransformJSON{
+			Frame:       tf.Frame,
+			Parent:      tf.Parent,
+			Translation: TranslationJSON{X: pt.X, Y: pt.Y, Z: pt.Z},
+			Orientation: QuaternionJSON{W: q.Real, X: q.Imag, Y: q.Jmag, Z: q.Kmag},
+		})
+	}
+	for name, inputs := range update.Inputs {
+		if len(inputs) != 0 {
+			out.Inputs[name] = referenceframe.InputsToFloats(inputs)
+		}
+	}
+	return out
+}
+
+// Update returns the update which the JSON represents.
+func (u TransformUpdateJSON) Update() TransformUpdate {
+	update := TransformUpdate{
+		Time:       u.Time,
+		Transforms: make([]FrameTransform, 0, len(u.Transforms)),
+		Inputs:     make(map[string][]referenceframe.Input, len(u.Inputs)),
+	}
+	for _, tf := range u.Transforms {
+		update.Transforms = append(update.Transforms, FrameTransform{
+			Frame:  tf.Frame,
+			Parent: tf.Parent,
+			Pose: spatialmath.NewPose(
+				r3.Vector{X: tf.Translation.X, Y: tf.Translation.Y, Z: tf.Translation.Z},
+				&spatialmath.Quaternion{Real: tf.Orientation.W, Imag: tf.Orientation.X, Jmag: tf.Orientation.Y, Kmag: tf.Orientation.Z},
+			),
+		})
+	}
+	for name, inputs := range u.Inputs {
+		update.Inputs[name] = referenceframe.FloatsToInputs(inputs)
+	}
+	return update
+}
+
+// DynamicFrameSource returns the poses of frames which are not part of the frame system, such as the
+// bodies seen by a pose tracker. The name of each PoseInFrame is the name of the frame, and its parent
+// is the frame it is relative to.
+type DynamicFrameSource func(ctx context.Context) ([]*referenceframe.PoseInFrame, error)
+
+// TransformStreamOptions configure StreamTransforms.
+type TransformStreamOptions struct {
+	// Interval is the time between updates. If zero, DefaultTransformStreamInterval is used, and it is
+	// at least MinTransformStreamInterval.
+	Interval time.Duration
+	// OnlyChanged, if set, only includes the transforms that changed since the previous update in every
+	// update but the first, which includes all of them.
+	OnlyChanged bool
+	// DynamicFrames are polled for every update in addition to the frame system.
+	DynamicFrames []DynamicFrameSource
+	// OnError, if set, is called with errors that cause an update, or the dynamic frames of a source, to
+	// be skipped. Streaming continues after such errors.
+	OnError func(error)
+}
+
+// StreamTransforms publishes the transform of every frame in the robot's frame system relative to its
+// parent to fn at a fixed rate, until the context is done or fn returns an error, which is returned.
+// Models such as arms are expanded into their links in the same way as the URDF export of the frame
+// system, so that visualizers can mirror the robot's frame tree as it moves. The frame system is
+// rebuilt for every update, so that reconfigurations are reflected.
+func StreamTransforms(ctx context.Context, svc Service, opts TransformStreamOptions, fn func(TransformUpdate) error) error {
+	interval := opts.Interval
+	switch {
+	case interval <= 0:
+		interval = DefaultTransformStreamInterval
+	case interval < MinTransformStreamInterval:
+		interval = MinTransformStreamInterval
+	}
+	reportError := func(err error) {
+		if opts.OnError != nil && ctx.Err() == nil {
+			opts.OnError(err)
+		}
+	}
+
+	var last map[string]FrameTransform
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		update, err := currentTransforms(ctx, svc, opts.DynamicFrames, reportError)
+		if err != nil {
+			reportError(err)
+		} else {
+			current := make(map[string]FrameTransform, len(update.Transforms))
+			for _, tf := range update.Transforms {
+				current[tf.Frame] = tf
+			}
+			if opts.OnlyChanged && last != nil {
+				changed := update.Transforms[:0]
+				for _, tf := range update.Transforms {
+					prev, ok := last[tf.Frame]
+					if !ok || prev.Parent != tf.Parent || !spatialmath.PoseAlmostEqual(prev.Pose, tf.Pose) {
+						changed = append(changed, tf)
+					}
+				}
+				update.Transforms = changed
+			}
+			last = current
+			if err := fn(update); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// currentTransforms returns the current transform of every frame.
+func currentTransforms(
+	ctx context.Context,
+	svc Service,
+	dynamicFrames []DynamicFrameSource,
+	reportError func(error),
+) (TransformUpdate, error) {
+	fs, err := svc.FrameSystem(ctx, nil)
+	if err != nil {
+		return TransformUpdate{}, err
+	}
+	inputs, _, err := svc.CurrentInputs(ctx)
+	if err != nil {
+		return TransformUpdate{}, err
+	}
+	update := TransformUpdate{Time: time.Now(), Inputs: inputs}
+
+	for _, name := range fs.FrameNames() {
+		frame := fs.Frame(name)
+		parent, err := fs.Parent(frame)
+		if err != nil {
+			return TransformUpdate{}, err
+		}
+		frameInputs, err := referenceframe.GetFrameInputs(frame, inputs)
+		if err != nil {
+			return TransformUpdate{}, err
+		}
+		transforms, err := frameTransforms(frame, parent.Name(), frameInputs)
+		if err != nil {
+			return TransformUpdate{}, errors.Wrapf(err, "cannot compute transform of frame %q", name)
+		}
+		update.Transforms = append(update.Transforms, transforms...)
+	}
+
+	for _, source := range dynamicFrames {
+		poses, err := source(ctx)
+		if err != nil {
+			reportError(err)
+			continue
+		}
+		for _, pif := range poses {
+			update.Transforms = append(update.Transforms, FrameTransform{Frame: pif.Name(), Parent: pif.Parent(), Pose: pif.Pose()})
+		}
+	}
+	return update, nil
+}
+
+// frameTransforms returns the transform of the frame relative to its parent, preceded by the
+// transforms of the links of models. Inputs outside of the frame's limits are allowed, as the robot
+// may be slightly outside of them.
+func frameTransforms(frame referenceframe.Frame, parent string, inputs []referenceframe.Input) ([]FrameTransform, error) {
+	model, ok := frame.(*referenceframe.SimpleModel)
+	if !ok {
+		pose, err := transformWithinBounds(frame, inputs)
+		if err != nil {
+			return nil, err
+		}
+		return []FrameTransform{{Frame: frame.Name(), Parent: parent, Pose: pose}}, nil
+	}
+
+	// the model's transforms are ordered from its base to its end effector, which the model frame itself represents
+	transforms := make([]FrameTransform, 0, len(model.OrdTransforms)+1)
+	for _, tf := range model.OrdTransforms {
+		dof := len(tf.DoF())
+		if dof > len(inputs) {
+			return nil, referenceframe.NewIncorrectInputLengthError(len(inputs), dof)
+		}
+		pose, err := transformWithinBounds(tf, inputs[:dof])
+		if err != nil {
+			return nil, err
+		}
+		inputs = inputs[dof:]
+		linkName := model.Name() + ":" + tf.Name()
+		transforms = append(transforms, FrameTransform{Frame: linkName, Parent: parent, Pose: pose})
+		parent = linkName
+	}
+	return append(transforms, FrameTransform{Frame: frame.Name(), Parent: parent, Pose: spatialmath.NewZeroPose()}), nil
+}
+
+// transformWithinBounds returns the transform of a frame, ignoring errors from inputs outside of its limits.
+func transformWithinBounds(frame referenceframe.Frame, inputs []referenceframe.Input) (spatialmath.Pose, error) {
+	pose, err := frame.Transform(inputs)
+	if err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString) {
+		return nil, err
+	}
+	if pose == nil {
+		return nil, errors.Errorf("frame %q has no transform", frame.Name())
+	}
+	return pose, nil
+}
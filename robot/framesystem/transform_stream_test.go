@@ -0,0 +1,129 @@
+package framesystem_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStreamTransforms(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	joint, err := referenceframe.NewRotationalFrame("joint", spatialmath.R4AA{RZ: 1}, referenceframe.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(joint, fs.World()), test.ShouldBeNil)
+	base, err := referenceframe.New2DMobileModelFrame("base", []referenceframe.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(base, joint), test.ShouldBeNil)
+	camera, err := referenceframe.NewStaticFrame("camera", spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(camera, fs.World()), test.ShouldBeNil)
+
+	svc := inject.NewFrameSystemService("fs")
+	svc.FrameSystemFunc = func(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error) {
+		return fs, nil
+	}
+	calls := 0
+	svc.CurrentInputsFunc = func(ctx context.Context) (map[string][]referenceframe.Input, map[string]referenceframe.InputEnabled, error) {
+		calls++
+		if calls == 2 {
+			return nil, nil, errors.New("arm unavailable")
+		}
+		return map[string][]referenceframe.Input{
+			"joint":  {{Value: 0}},
+			"base":   {{Value: float64(calls)}, {Value: 0}},
+			"camera": {},
+		}, nil, nil
+	}
+	dynamic := func(ctx context.Context) ([]*referenceframe.PoseInFrame, error) {
+		pif := referenceframe.NewPoseInFrame("camera", spatialmath.NewPoseFromPoint(r3.Vector{X: 5}))
+		pif.SetName("tag")
+		return []*referenceframe.PoseInFrame{pif}, nil
+	}
+
+	errDone := errors.New("done")
+	var updates []framesystem.TransformUpdate
+	var streamErrs []error
+	err = framesystem.StreamTransforms(
+		context.Background(),
+		svc,
+		framesystem.TransformStreamOptions{
+			Interval:      time.Millisecond,
+			OnlyChanged:   true,
+			DynamicFrames: []framesystem.DynamicFrameSource{dynamic},
+			OnError:       func(err error) { streamErrs = append(streamErrs, err) },
+		},
+		func(update framesystem.TransformUpdate) error {
+			updates = append(updates, update)
+			if len(updates) == 2 {
+				return errDone
+			}
+			return nil
+		},
+	)
+	test.That(t, err, test.ShouldBeError, errDone)
+	test.That(t, streamErrs, test.ShouldHaveLength, 1)
+	test.That(t, updates, test.ShouldHaveLength, 2)
+	// the interval is clamped, and the skipped update took an interval as well
+	test.That(t, updates[1].Time.Sub(updates[0].Time), test.ShouldBeGreaterThanOrEqualTo, framesystem.MinTransformStreamInterval)
+
+	frames := func(update framesystem.TransformUpdate) map[string]framesystem.FrameTransform {
+		out := map[string]framesystem.FrameTransform{}
+		for _, tf := range update.Transforms {
+			out[tf.Frame] = tf
+		}
+		return out
+	}
+	first := frames(updates[0])
+	test.That(t, first, test.ShouldContainKey, "joint")
+	test.That(t, first, test.ShouldContainKey, "camera")
+	test.That(t, first, test.ShouldContainKey, "tag")
+	test.That(t, first["tag"].Parent, test.ShouldEqual, "camera")
+	// models are expanded into their links
+	test.That(t, first["base:x"].Parent, test.ShouldEqual, "joint")
+	test.That(t, first["base:x"].Pose.Point(), test.ShouldResemble, r3.Vector{X: 1})
+	test.That(t, first["base"].Parent, test.ShouldEqual, "base:geometry")
+	test.That(t, updates[0].Inputs["base"], test.ShouldResemble, []referenceframe.Input{{Value: 1}, {Value: 0}})
+
+	// only the moving link changed
+	second := frames(updates[1])
+	test.That(t, second, test.ShouldHaveLength, 1)
+	test.That(t, second["base:x"].Pose.Point(), test.ShouldResemble, r3.Vector{X: 3})
+}
+
+func TestTransformUpdateJSON(t *testing.T) {
+	update := framesystem.TransformUpdate{
+		Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Transforms: []framesystem.FrameTransform{{
+			Frame:  "gripper",
+			Parent: "arm",
+			Pose:   spatialmath.NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90}),
+		}},
+		Inputs: map[string][]referenceframe.Input{"arm": {{Value: 0.5}}, "camera": {}},
+	}
+	data, err := json.Marshal(framesystem.NewTransformUpdateJSON(update))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldContainSubstring, `"translation":{"x":`)
+	test.That(t, string(data), test.ShouldContainSubstring, `"time":"2024-01-02T03:04:05.000000006Z"`)
+
+	var parsed framesystem.TransformUpdateJSON
+	test.That(t, json.Unmarshal(data, &parsed), test.ShouldBeNil)
+	roundTripped := parsed.Update()
+	test.That(t, roundTripped.Time.Equal(update.Time), test.ShouldBeTrue)
+	test.That(t, roundTripped.Transforms, test.ShouldHaveLength, 1)
+	test.That(t, roundTripped.Transforms[0].Frame, test.ShouldEqual, "gripper")
+	test.That(t, roundTripped.Transforms[0].Parent, test.ShouldEqual, "arm")
+	test.That(t, spatialmath.PoseAlmostEqual(roundTripped.Transforms[0].Pose, update.Transforms[0].Pose), test.ShouldBeTrue)
+	// frames without inputs are left out
+	test.That(t, roundTripped.Inputs, test.ShouldResemble, map[string][]referenceframe.Input{"arm": {{Value: 0.5}}})
+}
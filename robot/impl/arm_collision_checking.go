@@ -0,0 +1,175 @@
+package robotimpl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// armCollisionChecking checks the moves which the arms of a robot are asked to make through its API for
+// collisions, as configured by arm_collision_checking. The frame system which obstacles are found in is built
+// when the robot's frame system changes, rather than for every move.
+type armCollisionChecking struct {
+	checker       arm.CollisionChecker
+	currentInputs func(ctx context.Context) (map[string][]referenceframe.Input, error)
+	logger        logging.Logger
+
+	mu sync.Mutex
+	// obstacles are the configured obstacles.
+	obstacles []*referenceframe.LinkInFrame
+	// frameSystem is the robot's frame system, or nil if it could not be built, as frameSystemErr says.
+	frameSystem    referenceframe.FrameSystem
+	frameSystemErr error
+	// parents are the parent frames of the parts of the frame system, by name.
+	parents map[string]string
+	// partGeometries are the geometries of the parts of the frame system, in their own frames.
+	partGeometries map[string]spatialmath.Geometry
+}
+
+func newArmCollisionChecking(
+	currentInputs func(ctx context.Context) (map[string][]referenceframe.Input, error),
+	logger logging.Logger,
+) *armCollisionChecking {
+	return &armCollisionChecking{
+		currentInputs:  currentInputs,
+		logger:         logger,
+		frameSystemErr: errors.New("the frame system has not been built yet"),
+	}
+}
+
+// setConfig sets how moves are checked from the config of the robot. Nil only checks the moves which ask for
+// it, for collisions of the arm with itself.
+func (acc *armCollisionChecking) setConfig(conf *config.ArmCollisionCheckingConfig) {
+	if conf == nil {
+		acc.checker.SetChecking(nil)
+		return
+	}
+	obstacles := make([]*referenceframe.LinkInFrame, 0, len(conf.Obstacles))
+	for idx, obstacle := range conf.Obstacles {
+		// obstacles are validated with the config
+		link, err := obstacle.ParseConfig()
+		if err != nil {
+			acc.logger.Errorw("skipping invalid arm collision checking obstacle", "index", idx, "error", err)
+			continue
+		}
+		if link.Parent() == "" {
+			link = referenceframe.NewLinkInFrame(referenceframe.World, link.Pose(), link.Name(), link.Geometry())
+		}
+		if link.Name() == "" {
+			link = referenceframe.NewLinkInFrame(link.Parent(), link.Pose(), fmt.Sprintf("obstacle_%d", idx), link.Geometry())
+		}
+		obstacles = append(obstacles, link)
+	}
+	acc.mu.Lock()
+	acc.obstacles = obstacles
+	acc.mu.Unlock()
+	acc.checker.SetChecking(&arm.CollisionChecking{
+		Enforce:   conf.Enforce,
+		Clamp:     conf.Clamp,
+		BufferMM:  conf.BufferMM,
+		Obstacles: acc.armObstacles,
+	})
+}
+
+// setFrameSystem builds the frame system which obstacles are found in, when the robot's frame system changes.
+func (acc *armCollisionChecking) setFrameSystem(fsCfg *framesystem.Config) {
+	parents := make(map[string]string, len(fsCfg.Parts))
+	partGeometries := map[string]spatialmath.Geometry{}
+	for _, part := range fsCfg.Parts {
+		name := part.FrameConfig.Name()
+		parents[name] = part.FrameConfig.Parent()
+		if geometry := part.FrameConfig.Geometry(); geometry != nil {
+			partGeometries[name] = geometry
+		}
+	}
+	sortedParts, err := referenceframe.TopologicallySortParts(fsCfg.Parts)
+	var fs referenceframe.FrameSystem
+	if err == nil {
+		fs, err = referenceframe.NewFrameSystem(framesystem.LocalFrameSystemName, sortedParts, nil)
+	}
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	acc.frameSystem, acc.frameSystemErr = fs, err
+	acc.parents = parents
+	acc.partGeometries = partGeometries
+}
+
+// wrapArms returns the resources with their arms' moves checked for collisions.
+func (acc *armCollisionChecking) wrapArms(resources map[resource.Name]resource.Resource) map[resource.Name]resource.Resource {
+	wrapped := make(map[resource.Name]resource.Resource, len(resources))
+	for name, res := range resources {
+		if a, ok := res.(arm.Arm); ok && name.API == arm.API {
+			res = arm.WithCollisionChecking(a, &acc.checker, acc.logger)
+		}
+		wrapped[name] = res
+	}
+	return wrapped
+}
+
+// armObstacles returns the geometries of the rest of the robot and the configured obstacles, in the frame of the
+// base of the named arm. Parts attached to the arm, such as its gripper, move with it and are left out.
+func (acc *armCollisionChecking) armObstacles(ctx context.Context, armName string) ([]spatialmath.Geometry, error) {
+	acc.mu.Lock()
+	fs, fsErr := acc.frameSystem, acc.frameSystemErr
+	parents, partGeometries, obstacles := acc.parents, acc.partGeometries, acc.obstacles
+	acc.mu.Unlock()
+	if fsErr != nil {
+		return nil, fsErr
+	}
+	inputs, err := acc.currentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachedToArm := func(name string) bool {
+		for ; name != "" && name != referenceframe.World; name = parents[name] {
+			if name == armName {
+				return true
+			}
+		}
+		return false
+	}
+	armBase := armName + "_origin"
+	geometries := make([]spatialmath.Geometry, 0, len(partGeometries)+len(obstacles))
+	addGeometry := func(parent string, pose spatialmath.Pose, geometry spatialmath.Geometry, label string) error {
+		tf, err := fs.Transform(inputs, referenceframe.NewPoseInFrame(parent, pose), armBase)
+		if err != nil {
+			return err
+		}
+		inArmBase, ok := tf.(*referenceframe.PoseInFrame)
+		if !ok {
+			return errors.Errorf("cannot transform the geometry of %q to the frame of %q", label, armBase)
+		}
+		transformed := geometry.Transform(inArmBase.Pose())
+		if transformed.Label() == "" {
+			transformed.SetLabel(label)
+		}
+		geometries = append(geometries, transformed)
+		return nil
+	}
+	for name, geometry := range partGeometries {
+		if attachedToArm(name) {
+			continue
+		}
+		if err := addGeometry(name, spatialmath.NewZeroPose(), geometry, name); err != nil {
+			return nil, err
+		}
+	}
+	for _, obstacle := range obstacles {
+		if err := addGeometry(obstacle.Parent(), obstacle.Pose(), obstacle.Geometry(), obstacle.Name()); err != nil {
+			return nil, err
+		}
+	}
+	return geometries, nil
+}
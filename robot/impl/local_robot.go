@@ -20,6 +20,7 @@ import (
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -59,10 +60,21 @@ type localRobot struct {
 	configTicker               *time.Ticker
 	revealSensitiveConfigDiffs bool
 
+	// logLevels holds log levels set at runtime that take precedence over the config.
+	logLevels logLevelOverrides
+
 	// lastWeakDependentsRound stores the value of the resource graph's
 	// logical clock when updateWeakDependents was called.
 	lastWeakDependentsRound atomic.Int64
 
+	// armCollisions checks the moves which arms are asked to make through the robot's API for collisions.
+	armCollisions *armCollisionChecking
+
+	// resourceStats holds the periodically sampled stats of the robot's resources.
+	resourceStats resourceStats
+	// ftdc, if set, records the sampled stats of the robot's resources.
+	ftdc *ftdc.Recorder
+
 	// internal services that are in the graph but we also hold onto
 	webSvc   web.Service
 	frameSvc framesystem.Service
@@ -140,6 +152,7 @@ func (r *localRobot) Close(ctx context.Context) error {
 	}
 	r.activeBackgroundWorkers.Wait()
 	r.sessionManager.Close()
+	r.clearLogLevelOverrides()
 
 	var err error
 	if r.cloudConnSvc != nil {
@@ -154,6 +167,9 @@ func (r *localRobot) Close(ctx context.Context) error {
 	if r.webSvc != nil {
 		err = multierr.Combine(err, r.webSvc.Close(ctx))
 	}
+	if r.ftdc != nil {
+		err = multierr.Combine(err, r.ftdc.Close())
+	}
 	return err
 }
 
@@ -205,6 +221,19 @@ func (r *localRobot) Config() *config.Config {
 	return &cfg
 }
 
+// CachedConfig describes the cached copy of the last config the robot read from the cloud.
+func (r *localRobot) CachedConfig() (robot.CachedConfig, error) {
+	cfg := r.mostRecentCfg.Load().(config.Config)
+	if cfg.Cloud == nil {
+		return robot.CachedConfig{}, errors.New("the robot's config does not come from the cloud")
+	}
+	info, err := config.CachedConfigInfo(cfg.Cloud.ID, cfg.PackagePath)
+	if err != nil {
+		return robot.CachedConfig{}, err
+	}
+	return robot.CachedConfig{CacheInfo: info, RunningSince: cfg.CachedAt}, nil
+}
+
 // Logger returns the logger the robot is using.
 func (r *localRobot) Logger() logging.Logger {
 	return r.logger
@@ -384,6 +413,16 @@ func newWithResources(
 		cloudConnSvc:               cloud.NewCloudConnectionService(cfg.Cloud, logger),
 	}
 	r.mostRecentCfg.Store(config.Config{})
+	if rOpts.ftdcDir != "" {
+		if r.ftdc, err = ftdc.NewRecorder(rOpts.ftdcDir, logger.Sublogger("ftdc")); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	r.armCollisions = newArmCollisionChecking(func(ctx context.Context) (map[string][]referenceframe.Input, error) {
+		inputs, _, err := r.frameSvc.CurrentInputs(ctx)
+		return inputs, err
+	}, logger)
 	var heartbeatWindow time.Duration
 	if cfg.Network.Sessions.HeartbeatWindow == 0 {
 		heartbeatWindow = config.DefaultSessionHeartbeatWindow
@@ -498,6 +537,22 @@ func newWithResources(
 		}
 	}, r.activeBackgroundWorkers.Done)
 
+	r.activeBackgroundWorkers.Add(1)
+	// This goroutine samples the stats of the robot's resources, so that they are reported without calling
+	// into the resources or their modules.
+	goutils.ManagedGo(func() {
+		ticker := time.NewTicker(resourceStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closeCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			r.sampleResourceStats(closeCtx)
+		}
+	}, r.activeBackgroundWorkers.Done)
+
 	r.Reconfigure(ctx, cfg)
 
 	for name, res := range resources {
@@ -704,7 +759,8 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 			}()
 			switch resName {
 			case web.InternalServiceName:
-				if err := res.Reconfigure(ctxWithTimeout, allResources, resource.Config{}); err != nil {
+				// arms are served with their moves checked for collisions
+				if err := res.Reconfigure(ctxWithTimeout, r.armCollisions.wrapArms(allResources), resource.Config{}); err != nil {
 					r.Logger().CErrorw(ctx, "failed to reconfigure internal service during weak dependencies update", "service", resName, "error", err)
 				}
 			case framesystem.InternalServiceName:
@@ -716,6 +772,7 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 				if err := res.Reconfigure(ctxWithTimeout, components, resource.Config{ConvertedAttributes: fsCfg}); err != nil {
 					r.Logger().CErrorw(ctx, "failed to reconfigure internal service during weak dependencies update", "service", resName, "error", err)
 				}
+				r.armCollisions.setFrameSystem(fsCfg)
 			case packages.InternalServiceName, packages.DeferredServiceName, cloud.InternalServiceName:
 			default:
 				r.logger.CWarnw(ctx, "do not know how to reconfigure internal service during weak dependencies update", "service", resName)
@@ -1058,6 +1115,9 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 		allErrs = multierr.Combine(allErrs, err)
 	}
 
+	// Arm collision checking is not a resource, so it is updated even when no resources changed.
+	r.armCollisions.setConfig(newConfig.ArmCollisionChecking)
+
 	// Add default services and process their dependencies. Dependencies may
 	// already come from config validation so we check that here.
 	seen := make(map[resource.API]int)
@@ -1144,6 +1204,9 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 	r.manager.completeConfig(ctx, r)
 	r.updateWeakDependents(ctx)
 
+	// Runtime log level overrides only last until the next reconfigure.
+	r.clearLogLevelOverrides()
+
 	// Finally we actually remove marked resources and Close any that are
 	// still unclosed.
 	if err := r.manager.removeMarkedAndClose(ctx, alreadyClosed); err != nil {
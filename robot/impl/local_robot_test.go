@@ -1,6 +1,7 @@
 package robotimpl_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -9,6 +10,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -51,6 +53,7 @@ import (
 	rgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -3446,3 +3449,253 @@ func initTestRobot(t *testing.T, ctx context.Context, cfg *config.Config, logger
 		test.That(t, r.Close(ctx), test.ShouldBeNil)
 	}
 }
+
+func TestResourceLogLevels(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	baseConf := resource.Config{
+		Name:             "base1",
+		API:              base.API,
+		Model:            fakeModel,
+		LogConfiguration: resource.LogConfig{Level: logging.WARN},
+	}
+	cfg := &config.Config{Components: []resource.Config{baseConf}}
+	r, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	baseName := base.Named("base1")
+	levelOf := func() robot.ResourceLogLevel {
+		for _, level := range r.ResourceLogLevels() {
+			if level.Name == baseName {
+				return level
+			}
+		}
+		t.Fatal("base1 missing from log levels")
+		return robot.ResourceLogLevel{}
+	}
+
+	level := levelOf()
+	test.That(t, level.Level, test.ShouldEqual, logging.WARN)
+	test.That(t, level.ConfiguredLevel, test.ShouldEqual, logging.WARN)
+	test.That(t, level.Overridden, test.ShouldBeFalse)
+
+	err = r.SetResourceLogLevel(base.Named("missing"), logging.DEBUG, 0)
+	test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+	err = r.SetResourceLogLevel(baseName, logging.DEBUG, -time.Second)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("expires", func(t *testing.T) {
+		test.That(t, r.SetResourceLogLevel(baseName, logging.DEBUG, 50*time.Millisecond), test.ShouldBeNil)
+		level := levelOf()
+		test.That(t, level.Level, test.ShouldEqual, logging.DEBUG)
+		test.That(t, level.Overridden, test.ShouldBeTrue)
+		test.That(t, level.OverrideExpires.IsZero(), test.ShouldBeFalse)
+
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			level := levelOf()
+			test.That(tb, level.Level, test.ShouldEqual, logging.WARN)
+			test.That(tb, level.Overridden, test.ShouldBeFalse)
+		})
+	})
+
+	t.Run("cleared on reconfigure", func(t *testing.T) {
+		test.That(t, r.SetResourceLogLevel(baseName, logging.DEBUG, 0), test.ShouldBeNil)
+		level := levelOf()
+		test.That(t, level.Level, test.ShouldEqual, logging.DEBUG)
+		test.That(t, level.OverrideExpires.IsZero(), test.ShouldBeTrue)
+
+		newCfg := &config.Config{Components: []resource.Config{baseConf, {
+			Name:  "base2",
+			API:   base.API,
+			Model: fakeModel,
+		}}}
+		r.Reconfigure(ctx, newCfg)
+		level = levelOf()
+		test.That(t, level.Level, test.ShouldEqual, logging.WARN)
+		test.That(t, level.Overridden, test.ShouldBeFalse)
+	})
+}
+
+func TestLoggerLevels(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r, err := robotimpl.New(ctx, &config.Config{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, r.SetLoggerLevel("", logging.DEBUG, 0), test.ShouldNotBeNil)
+	test.That(t, r.SetLoggerLevel("rdk.networking", logging.DEBUG, 0), test.ShouldBeNil)
+	test.That(t, r.LoggerLevels(), test.ShouldResemble, []robot.LoggerLevel{{Logger: "rdk.networking", Level: logging.DEBUG}})
+	test.That(t, logging.LevelOverrides()["rdk.networking"], test.ShouldEqual, logging.DEBUG)
+
+	test.That(t, r.SetLoggerLevel("rdk.networking", logging.ERROR, 50*time.Millisecond), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, r.LoggerLevels(), test.ShouldBeEmpty)
+		test.That(tb, logging.LevelOverrides(), test.ShouldNotContainKey, "rdk.networking")
+	})
+
+	test.That(t, r.SetLoggerLevel("rdk.networking", logging.DEBUG, 0), test.ShouldBeNil)
+	r.Reconfigure(ctx, &config.Config{Components: []resource.Config{{Name: "base1", API: base.API, Model: fakeModel}}})
+	test.That(t, r.LoggerLevels(), test.ShouldBeEmpty)
+	test.That(t, logging.LevelOverrides(), test.ShouldNotContainKey, "rdk.networking")
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	cfg := &config.Config{Components: []resource.Config{{
+		Name:  "base1",
+		API:   base.API,
+		Model: fakeModel,
+		Frame: &referenceframe.LinkConfig{Parent: referenceframe.World},
+	}}}
+	r, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	var snapshot bytes.Buffer
+	test.That(t, r.Snapshot(ctx, &snapshot), test.ShouldBeNil)
+
+	// the replacement is configured from a file, which the restored config is written to
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	test.That(t, os.WriteFile(configPath, []byte("{}"), 0o600), test.ShouldBeNil)
+	replacementCfg, err := config.Read(ctx, configPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	replacement, err := robotimpl.New(ctx, replacementCfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, replacement.Close(ctx), test.ShouldBeNil)
+	}()
+	test.That(t, replacement.RestoreSnapshot(ctx, bytes.NewReader(snapshot.Bytes())), test.ShouldBeNil)
+
+	checkRestored := func() {
+		t.Helper()
+		restoredBase, err := base.FromRobot(replacement, "base1")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, restoredBase, test.ShouldNotBeNil)
+		fsCfg, err := replacement.FrameSystemConfig(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fsCfg.Parts, test.ShouldHaveLength, 1)
+		test.That(t, fsCfg.Parts[0].FrameConfig.Name(), test.ShouldEqual, "base1")
+	}
+	checkRestored()
+
+	// reloading the config file, as the config watcher does when it changes, keeps the restored config
+	reloaded, err := config.Read(ctx, configPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	replacement.Reconfigure(ctx, reloaded)
+	checkRestored()
+
+	err = replacement.RestoreSnapshot(ctx, strings.NewReader("not a snapshot"))
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a gzipped archive")
+}
+
+func TestCachedConfig(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	r, err := robotimpl.New(ctx, &config.Config{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	_, err = r.CachedConfig()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not come from the cloud")
+}
+
+func TestOperationProgress(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	r, shutdown := initTestRobot(t, ctx, &config.Config{}, logger)
+	defer shutdown()
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+
+	opCtx, done := r.OperationManager().Create(ctx, "move", nil)
+	defer done()
+	_, doneQuiet := r.OperationManager().Create(ctx, "quiet", nil)
+	defer doneQuiet()
+	operation.SetProgress(opCtx, 40, "executing")
+
+	robotClient := robottestutils.NewRobotClient(t, logger, addr, time.Second)
+	defer func() {
+		test.That(t, robotClient.Close(context.Background()), test.ShouldBeNil)
+	}()
+	progress, err := robotClient.OperationProgress(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	// only operations which reported progress are returned
+	test.That(t, progress, test.ShouldHaveLength, 1)
+	opProgress, ok := progress[operation.Get(opCtx).ID.String()]
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, opProgress.Percent, test.ShouldEqual, 40)
+	test.That(t, opProgress.Phase, test.ShouldEqual, "executing")
+	test.That(t, opProgress.Updated.Equal(operation.Get(opCtx).Progress().Updated), test.ShouldBeTrue)
+}
+
+func TestMachineLogs(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	r, shutdown := initTestRobot(t, ctx, &config.Config{}, logger)
+	defer shutdown()
+
+	logBuffer := logging.NewLogBuffer(10)
+	bufferedLogger := logging.NewBlankLogger("robot_server")
+	bufferedLogger.AddAppender(logBuffer)
+	bufferedLogger.Sublogger("rdk:component:arm/arm1").Warn("arm overheating")
+	bufferedLogger.Sublogger("rdk:component:camera/cam1").Info("camera started")
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.LogBuffer = logBuffer
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+
+	robotClient := robottestutils.NewRobotClient(t, logger, addr, time.Second)
+	defer func() {
+		test.That(t, robotClient.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	// filtered as /debug/logs filters them
+	entries, err := robotClient.Logs(ctx, logging.LogBufferFilter{MinLevel: logging.DEBUG})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 2)
+	entries, err = robotClient.Logs(ctx, logging.LogBufferFilter{Logger: "robot_server.rdk:component:arm/arm1"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 1)
+	test.That(t, entries[0].Message, test.ShouldEqual, "arm overheating")
+	test.That(t, entries[0].Level, test.ShouldEqual, "WARN")
+	entries, err = robotClient.Logs(ctx, logging.LogBufferFilter{MinLevel: logging.WARN})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 1)
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	streamed := make(chan string, 10)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- robotClient.StreamLogs(streamCtx, logging.LogBufferFilter{MinLevel: logging.WARN},
+			func(entry logging.BufferedLogEntry) error {
+				streamed <- entry.Message
+				return nil
+			})
+	}()
+	test.That(t, <-streamed, test.ShouldEqual, "arm overheating")
+	bufferedLogger.Info("filtered out")
+	bufferedLogger.Error("disk full")
+	test.That(t, <-streamed, test.ShouldEqual, "disk full")
+	cancel()
+	test.That(t, <-errCh, test.ShouldNotBeNil)
+}
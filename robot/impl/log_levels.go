@@ -0,0 +1,192 @@
+package robotimpl
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// logLevelOverride is a log level set at runtime on a resource's logger.
+type logLevelOverride struct {
+	level   logging.Level
+	expires time.Time
+	timer   *time.Timer
+}
+
+// logLevelOverrides tracks runtime log level overrides so that they can be reported, expired and
+// cleared on reconfigure.
+type logLevelOverrides struct {
+	mu        sync.Mutex
+	overrides map[resource.Name]*logLevelOverride
+	// loggers are the overrides set by logger name, through logging.SetLevelOverride.
+	loggers map[string]*logLevelOverride
+}
+
+// ResourceLogLevels returns the current log level of every component and service logger.
+func (r *localRobot) ResourceLogLevels() []robot.ResourceLogLevel {
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	var levels []robot.ResourceLogLevel
+	for _, name := range r.manager.resources.Names() {
+		if !(name.API.IsComponent() || name.API.IsService()) {
+			continue
+		}
+		gNode, ok := r.manager.resources.Node(name)
+		if !ok || gNode.Logger() == nil {
+			continue
+		}
+		level := robot.ResourceLogLevel{
+			Name:            name,
+			Level:           gNode.Logger().GetLevel(),
+			ConfiguredLevel: gNode.Config().LogConfiguration.Level,
+		}
+		if override, ok := r.logLevels.overrides[name]; ok {
+			level.Overridden = true
+			level.OverrideExpires = override.expires
+		}
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Name.String() < levels[j].Name.String()
+	})
+	return levels
+}
+
+// SetResourceLogLevel overrides the log level of a resource's logger for the given duration, or
+// until the next reconfigure if the duration is zero.
+func (r *localRobot) SetResourceLogLevel(name resource.Name, level logging.Level, duration time.Duration) error {
+	if duration < 0 {
+		return errors.New("log level override duration must not be negative")
+	}
+	gNode, ok := r.manager.resources.Node(name)
+	if !ok || gNode.Logger() == nil {
+		return resource.NewNotFoundError(name)
+	}
+
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	if r.logLevels.overrides == nil {
+		r.logLevels.overrides = map[resource.Name]*logLevelOverride{}
+	}
+	if existing, ok := r.logLevels.overrides[name]; ok && existing.timer != nil {
+		existing.timer.Stop()
+	}
+	override := &logLevelOverride{level: level}
+	if duration > 0 {
+		override.expires = time.Now().Add(duration)
+		override.timer = time.AfterFunc(duration, func() {
+			r.expireLogLevelOverride(name, override)
+		})
+	}
+	r.logLevels.overrides[name] = override
+	gNode.SetLogLevel(level)
+	r.logger.Infow("log level overridden", "resource", name, "level", level, "duration", duration)
+	return nil
+}
+
+// expireLogLevelOverride restores the configured log level of a resource once its override times out.
+func (r *localRobot) expireLogLevelOverride(name resource.Name, override *logLevelOverride) {
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	// the override may have already been replaced or cleared by a reconfigure.
+	if r.logLevels.overrides[name] != override {
+		return
+	}
+	delete(r.logLevels.overrides, name)
+	if gNode, ok := r.manager.resources.Node(name); ok {
+		gNode.SetLogLevel(gNode.Config().LogConfiguration.Level)
+	}
+	r.logger.Infow("log level override expired", "resource", name)
+}
+
+// LoggerLevels returns the log levels set at runtime by logger name.
+func (r *localRobot) LoggerLevels() []robot.LoggerLevel {
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	levels := make([]robot.LoggerLevel, 0, len(r.logLevels.loggers))
+	for name, override := range r.logLevels.loggers {
+		levels = append(levels, robot.LoggerLevel{Logger: name, Level: override.level, OverrideExpires: override.expires})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Logger < levels[j].Logger
+	})
+	return levels
+}
+
+// SetLoggerLevel overrides the log level of the logger with the given name, and of its subloggers,
+// for the given duration, or until the next reconfigure if the duration is zero.
+func (r *localRobot) SetLoggerLevel(name string, level logging.Level, duration time.Duration) error {
+	if name == "" {
+		return errors.New("a logger name is required")
+	}
+	if duration < 0 {
+		return errors.New("log level override duration must not be negative")
+	}
+
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	if r.logLevels.loggers == nil {
+		r.logLevels.loggers = map[string]*logLevelOverride{}
+	}
+	if existing, ok := r.logLevels.loggers[name]; ok && existing.timer != nil {
+		existing.timer.Stop()
+	}
+	override := &logLevelOverride{level: level}
+	if duration > 0 {
+		override.expires = time.Now().Add(duration)
+		override.timer = time.AfterFunc(duration, func() {
+			r.expireLoggerLevelOverride(name, override)
+		})
+	}
+	r.logLevels.loggers[name] = override
+	logging.SetLevelOverride(name, level)
+	r.logger.Infow("log level overridden", "logger", name, "level", level, "duration", duration)
+	return nil
+}
+
+// expireLoggerLevelOverride removes the override of a logger's level once it times out.
+func (r *localRobot) expireLoggerLevelOverride(name string, override *logLevelOverride) {
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	if r.logLevels.loggers[name] != override {
+		return
+	}
+	delete(r.logLevels.loggers, name)
+	logging.ClearLevelOverride(name)
+	r.logger.Infow("log level override expired", "logger", name)
+}
+
+// clearLogLevelOverrides removes every runtime override and restores each resource's configured log level.
+func (r *localRobot) clearLogLevelOverrides() {
+	r.logLevels.mu.Lock()
+	defer r.logLevels.mu.Unlock()
+
+	for name, override := range r.logLevels.overrides {
+		if override.timer != nil {
+			override.timer.Stop()
+		}
+		if gNode, ok := r.manager.resources.Node(name); ok {
+			gNode.SetLogLevel(gNode.Config().LogConfiguration.Level)
+		}
+	}
+	r.logLevels.overrides = nil
+	for name, override := range r.logLevels.loggers {
+		if override.timer != nil {
+			override.timer.Stop()
+		}
+		logging.ClearLevelOverride(name)
+	}
+	r.logLevels.loggers = nil
+}
@@ -415,6 +415,10 @@ func (m *dummyModMan) ReconfigureResource(ctx context.Context, conf resource.Con
 	return nil
 }
 
+func (m *dummyModMan) ResourceStats(ctx context.Context) map[resource.Name]map[string]float64 {
+	return nil
+}
+
 func (m *dummyModMan) RemoveResource(ctx context.Context, name resource.Name) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,70 @@
+package robotimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/resource"
+)
+
+// resourceStatsInterval is how often the stats of the robot's resources are sampled.
+const resourceStatsInterval = 10 * time.Second
+
+// resourceStatsTimeout bounds how long the modules are waited on for the stats of their resources.
+const resourceStatsTimeout = 5 * time.Second
+
+// resourceStats holds the most recently sampled stats of the robot's resources, so that reporting them does
+// not call into the resources or their modules.
+type resourceStats struct {
+	mu    sync.Mutex
+	stats map[resource.Name]map[string]float64
+}
+
+// ResourceStats returns the most recently sampled stats of the robot's resources.
+func (r *localRobot) ResourceStats() map[resource.Name]map[string]float64 {
+	r.resourceStats.mu.Lock()
+	defer r.resourceStats.mu.Unlock()
+	stats := make(map[resource.Name]map[string]float64, len(r.resourceStats.stats))
+	for name, resStats := range r.resourceStats.stats {
+		stats[name] = resStats
+	}
+	return stats
+}
+
+// sampleResourceStats samples the stats of the builtin resources which implement resource.Statser and of
+// the resources served by modules.
+func (r *localRobot) sampleResourceStats(ctx context.Context) {
+	stats := map[resource.Name]map[string]float64{}
+	for _, name := range r.manager.ResourceNames() {
+		res, err := r.manager.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		if statser, ok := res.(resource.Statser); ok {
+			stats[name] = statser.Stats()
+		}
+	}
+	if r.manager.moduleManager != nil {
+		ctx, cancel := context.WithTimeout(ctx, resourceStatsTimeout)
+		for name, resStats := range r.manager.moduleManager.ResourceStats(ctx) {
+			stats[name] = resStats
+		}
+		cancel()
+	}
+
+	r.resourceStats.mu.Lock()
+	r.resourceStats.stats = stats
+	r.resourceStats.mu.Unlock()
+
+	if r.ftdc != nil {
+		datum := ftdc.Datum{Time: time.Now(), Stats: make(map[string]map[string]float64, len(stats))}
+		for name, resStats := range stats {
+			datum.Stats[name.String()] = resStats
+		}
+		if err := r.ftdc.Record(datum); err != nil {
+			r.logger.CWarnw(ctx, "failed to record resource stats", "error", err)
+		}
+	}
+}
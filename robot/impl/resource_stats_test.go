@@ -0,0 +1,50 @@
+package robotimpl
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// statsArm is an arm which reports stats.
+type statsArm struct {
+	*inject.Arm
+}
+
+func (a *statsArm) Stats() map[string]float64 {
+	return map[string]float64{"queue_depth": 3}
+}
+
+func TestResourceStatsFTDC(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+
+	arm1 := arm.Named("arm1")
+	r, err := newWithResources(ctx, &config.Config{}, map[resource.Name]resource.Resource{
+		arm1: &statsArm{inject.NewArm("arm1")},
+	}, logger, WithFTDC(dir))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	// each sample of the stats is recorded
+	r.(*localRobot).sampleResourceStats(ctx)
+	r.(*localRobot).sampleResourceStats(ctx)
+	test.That(t, r.ResourceStats(), test.ShouldResemble, map[resource.Name]map[string]float64{arm1: {"queue_depth": 3}})
+	datums, err := ftdc.Read(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldHaveLength, 2)
+	for _, datum := range datums {
+		test.That(t, datum.Stats, test.ShouldResemble, map[string]map[string]float64{arm1.String(): {"queue_depth": 3}})
+	}
+}
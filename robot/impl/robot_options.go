@@ -10,6 +10,9 @@ type options struct {
 	// revealSensitiveConfigDiffs will display config diffs - which may contain secret
 	// information - in log statements
 	revealSensitiveConfigDiffs bool
+
+	// ftdcDir, if set, is the directory the sampled stats of resources are recorded to.
+	ftdcDir string
 }
 
 // Option configures how we set up the web service.
@@ -50,3 +53,11 @@ func WithRevealSensitiveConfigDiffs() Option {
 		o.revealSensitiveConfigDiffs = true
 	})
 }
+
+// WithFTDC returns an Option which records the periodically sampled stats of the robot's resources to
+// rotating files in dir, as read by ftdc.Read.
+func WithFTDC(dir string) Option {
+	return newFuncOption(func(o *options) {
+		o.ftdcDir = dir
+	})
+}
@@ -0,0 +1,410 @@
+package robotimpl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	pb "go.viam.com/api/robot/v1"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/packages"
+)
+
+// snapshotFormatVersion is the version of the snapshot archive format. Archives of any other version are
+// refused rather than restored partially.
+const snapshotFormatVersion = 1
+
+// The files of a snapshot archive, which is a gzipped tar.
+const (
+	snapshotManifestFile    = "manifest.json"
+	snapshotConfigFile      = "config.json"
+	snapshotFrameSystemFile = "frame_system.json"
+	snapshotCalibrationDir  = "calibration/"
+)
+
+// maxSnapshotSize bounds the total size of the files, and their names, read from a snapshot archive.
+const maxSnapshotSize = 256 << 20
+
+// snapshotManifest describes a snapshot archive and the machine it was taken from.
+type snapshotManifest struct {
+	FormatVersion int               `json:"format_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	RDKVersion    string            `json:"rdk_version,omitempty"`
+	Packages      []snapshotPackage `json:"packages,omitempty"`
+	// Calibration maps the name of each resource with calibration data to its file in the archive.
+	Calibration map[string]string `json:"calibration,omitempty"`
+	// Redacted are the paths of the secrets which were removed from the config, as returned by
+	// config.RedactSecrets.
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// snapshotPackage is a package the machine was configured with.
+type snapshotPackage struct {
+	Name    string             `json:"name"`
+	Package string             `json:"package"`
+	Version string             `json:"version,omitempty"`
+	Type    config.PackageType `json:"type"`
+	// Downloaded is whether the package had been downloaded onto the machine when the snapshot was taken.
+	Downloaded bool `json:"downloaded"`
+}
+
+// snapshotFrameSystem is the frame system of the machine, as the parts returned by FrameSystemConfig, and the
+// inputs of its frames when the snapshot was taken.
+type snapshotFrameSystem struct {
+	Parts  []json.RawMessage    `json:"parts"`
+	Inputs map[string][]float64 `json:"inputs,omitempty"`
+}
+
+// Snapshot writes an archive of the machine's state to w. Resources served by modules are not asked for
+// their calibration data.
+func (r *localRobot) Snapshot(ctx context.Context, w io.Writer) error {
+	// the config is copied so that redacting it leaves the running config alone.
+	cfg, err := r.Config().CopyOnlyPublicFields()
+	if err != nil {
+		return errors.Wrap(err, "cannot snapshot the config")
+	}
+	redacted := config.RedactSecrets(cfg)
+	configJSON, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot snapshot the config")
+	}
+
+	frameSystemJSON, err := r.snapshotFrameSystem(ctx)
+	if err != nil {
+		return err
+	}
+
+	manifest := snapshotManifest{
+		FormatVersion: snapshotFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		RDKVersion:    config.Version,
+		Calibration:   map[string]string{},
+		Redacted:      redacted,
+	}
+	for _, p := range cfg.Packages {
+		_, err := r.packageManager.PackagePath(packages.PackageName(p.Name))
+		manifest.Packages = append(manifest.Packages, snapshotPackage{
+			Name:       p.Name,
+			Package:    p.Package,
+			Version:    p.Version,
+			Type:       p.Type,
+			Downloaded: err == nil,
+		})
+	}
+
+	names := r.ResourceNames()
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+	calibrationFiles := map[string][]byte{}
+	for _, name := range names {
+		if name.ContainsRemoteNames() {
+			continue
+		}
+		res, err := r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		calibrated, ok := res.(resource.Calibrated)
+		if !ok {
+			continue
+		}
+		data, err := calibrated.CalibrationData(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "cannot snapshot the calibration data of %s", name)
+		}
+		file := fmt.Sprintf("%s%d", snapshotCalibrationDir, len(calibrationFiles))
+		manifest.Calibration[name.String()] = file
+		calibrationFiles[file] = data
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot snapshot the manifest")
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	writeFile := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	err = multierr.Combine(
+		writeFile(snapshotManifestFile, manifestJSON),
+		writeFile(snapshotConfigFile, configJSON),
+		writeFile(snapshotFrameSystemFile, frameSystemJSON),
+	)
+	for i := 0; i < len(calibrationFiles); i++ {
+		file := fmt.Sprintf("%s%d", snapshotCalibrationDir, i)
+		err = multierr.Combine(err, writeFile(file, calibrationFiles[file]))
+	}
+	err = multierr.Combine(err, tw.Close(), gzw.Close())
+	return errors.Wrap(err, "cannot write the snapshot")
+}
+
+// snapshotFrameSystem returns the frame system of the machine and the current inputs of its frames as JSON.
+func (r *localRobot) snapshotFrameSystem(ctx context.Context) ([]byte, error) {
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot snapshot the frame system")
+	}
+	fs := snapshotFrameSystem{Parts: []json.RawMessage{}, Inputs: map[string][]float64{}}
+	for _, part := range fsCfg.Parts {
+		partProto, err := part.ToProtobuf()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot snapshot frame %q", part.FrameConfig.Name())
+		}
+		partJSON, err := protojson.Marshal(partProto)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot snapshot frame %q", part.FrameConfig.Name())
+		}
+		fs.Parts = append(fs.Parts, partJSON)
+	}
+	inputs, _, err := r.frameSvc.CurrentInputs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot snapshot the current inputs of the frame system")
+	}
+	for name, in := range inputs {
+		if len(in) > 0 {
+			fs.Inputs[name] = referenceframe.InputsToFloats(in)
+		}
+	}
+	return json.MarshalIndent(fs, "", "  ")
+}
+
+// RestoreSnapshot reconfigures the robot from an archive written by Snapshot and restores the calibration
+// data of its resources. Every resource's calibration data is attempted, and their errors are combined.
+// The restored config is written to the robot's config file, if it has one, so that it is kept when the
+// file is reloaded or the robot restarts. A robot whose config comes from the cloud is refused, since the
+// next config from the cloud would replace the restored one; its config has to be restored in the cloud.
+func (r *localRobot) RestoreSnapshot(ctx context.Context, rd io.Reader) error {
+	current := r.Config()
+	if current.Cloud != nil {
+		return errors.New("cannot restore a snapshot onto a machine configured from the cloud, " +
+			"since its config would be replaced by the one in the cloud; restore the snapshot's config there instead")
+	}
+	files, err := readSnapshotArchive(rd)
+	if err != nil {
+		return err
+	}
+	manifestJSON, ok := files[snapshotManifestFile]
+	if !ok {
+		return errors.Errorf("snapshot has no %s", snapshotManifestFile)
+	}
+	var manifest snapshotManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return errors.Wrap(err, "cannot read the snapshot manifest")
+	}
+	if manifest.FormatVersion != snapshotFormatVersion {
+		return errors.Errorf("unsupported snapshot format version %d, expected %d",
+			manifest.FormatVersion, snapshotFormatVersion)
+	}
+
+	restored, err := r.restoredConfig(ctx, files[snapshotConfigFile], current)
+	if err != nil {
+		return err
+	}
+	r.logger.CInfow(ctx, "restoring snapshot", "created_at", manifest.CreatedAt, "rdk_version", manifest.RDKVersion)
+	if err := writeRestoredConfig(restored); err != nil {
+		return err
+	}
+	r.Reconfigure(ctx, restored)
+	allErrs := r.restoredResourceErrors(restored)
+	if allErrs != nil && len(manifest.Redacted) > 0 {
+		allErrs = errors.Wrapf(allErrs, "secrets removed from the snapshot may need to be configured again (%s)",
+			strings.Join(manifest.Redacted, ", "))
+	}
+
+	if fsJSON, ok := files[snapshotFrameSystemFile]; ok {
+		r.checkRestoredFrameSystem(ctx, fsJSON)
+	}
+
+	calibrated := make([]string, 0, len(manifest.Calibration))
+	for name := range manifest.Calibration {
+		calibrated = append(calibrated, name)
+	}
+	sort.Strings(calibrated)
+	for _, nameStr := range calibrated {
+		allErrs = multierr.Combine(allErrs, r.restoreCalibrationData(ctx, nameStr, files[manifest.Calibration[nameStr]]))
+	}
+	return allErrs
+}
+
+// restoredConfig processes the config of a snapshot for this robot, which keeps its cloud credentials, auth,
+// the credentials of remotes it shares with the snapshot and the options it was started with. Unlike a config
+// read at startup, an invalid config is refused rather than partially started.
+func (r *localRobot) restoredConfig(ctx context.Context, configJSON []byte, current *config.Config) (*config.Config, error) {
+	if configJSON == nil {
+		return nil, errors.Errorf("snapshot has no %s", snapshotConfigFile)
+	}
+	var snapshotCfg config.Config
+	if err := json.Unmarshal(configJSON, &snapshotCfg); err != nil {
+		return nil, errors.Wrap(err, "cannot read the snapshot config")
+	}
+	// cloud credentials in a snapshot would make the config be read from the cloud instead.
+	snapshotCfg.Cloud = nil
+	disablePartialStart := snapshotCfg.DisablePartialStart
+	snapshotCfg.DisablePartialStart = true
+	configJSON, err := json.Marshal(snapshotCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read the snapshot config")
+	}
+	restored, err := config.FromReader(ctx, current.ConfigFilePath, bytes.NewReader(configJSON), r.logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot config")
+	}
+	restored.DisablePartialStart = disablePartialStart
+	restored.Cloud = current.Cloud
+	restored.Auth = current.Auth
+	for i := range restored.Remotes {
+		for _, currentRemote := range current.Remotes {
+			if currentRemote.Name != restored.Remotes[i].Name {
+				continue
+			}
+			if restored.Remotes[i].Secret == "" {
+				restored.Remotes[i].Secret = currentRemote.Secret
+			}
+			if restored.Remotes[i].Auth.Credentials == nil {
+				restored.Remotes[i].Auth.Credentials = currentRemote.Auth.Credentials
+			}
+		}
+	}
+	restored.AllowInsecureCreds = current.AllowInsecureCreds
+	restored.UntrustedEnv = current.UntrustedEnv
+	restored.FromCommand = current.FromCommand
+	restored.PackagePath = current.PackagePath
+	return restored, nil
+}
+
+// writeRestoredConfig replaces the contents of the config file of a restored config, if it has one. The file
+// is written in place, rather than replaced, so that the robot's config watcher keeps watching it.
+func writeRestoredConfig(restored *config.Config) error {
+	if restored.ConfigFilePath == "" {
+		return nil
+	}
+	configJSON, err := json.MarshalIndent(restored, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "cannot write the restored config")
+	}
+	info, err := os.Stat(restored.ConfigFilePath)
+	if err != nil {
+		return errors.Wrap(err, "cannot write the restored config")
+	}
+	return errors.Wrap(os.WriteFile(restored.ConfigFilePath, configJSON, info.Mode().Perm()), "cannot write the restored config")
+}
+
+// restoredResourceErrors returns the errors of the resources of a restored config which could not be built.
+func (r *localRobot) restoredResourceErrors(restored *config.Config) error {
+	var allErrs error
+	for _, conf := range append(append([]resource.Config{}, restored.Components...), restored.Services...) {
+		if _, err := r.ResourceByName(conf.ResourceName()); err != nil {
+			allErrs = multierr.Combine(allErrs, errors.Wrapf(err, "cannot restore %s", conf.ResourceName()))
+		}
+	}
+	return allErrs
+}
+
+// checkRestoredFrameSystem warns about frames of a snapshot which the restored robot does not have, such as
+// those of remotes which cannot be reached.
+func (r *localRobot) checkRestoredFrameSystem(ctx context.Context, fsJSON []byte) {
+	var fs snapshotFrameSystem
+	if err := json.Unmarshal(fsJSON, &fs); err != nil {
+		r.logger.CWarnw(ctx, "cannot read the frame system of the snapshot", "error", err)
+		return
+	}
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	if err != nil {
+		r.logger.CWarnw(ctx, "cannot check the restored frame system", "error", err)
+		return
+	}
+	frames := map[string]bool{}
+	for _, part := range fsCfg.Parts {
+		frames[part.FrameConfig.Name()] = true
+	}
+	for _, partJSON := range fs.Parts {
+		var part pb.FrameSystemConfig
+		if err := protojson.Unmarshal(partJSON, &part); err != nil {
+			r.logger.CWarnw(ctx, "cannot read a frame of the snapshot", "error", err)
+			continue
+		}
+		if name := part.GetFrame().GetReferenceFrame(); !frames[name] {
+			r.logger.CWarnw(ctx, "frame of the snapshot is missing from the restored frame system", "frame", name)
+		}
+	}
+}
+
+// restoreCalibrationData restores the calibration data of a resource from a snapshot.
+func (r *localRobot) restoreCalibrationData(ctx context.Context, nameStr string, data []byte) error {
+	name, err := resource.NewFromString(nameStr)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return errors.Errorf("snapshot has no calibration data for %s", name)
+	}
+	res, err := r.ResourceByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "cannot restore the calibration data of %s", name)
+	}
+	calibrated, ok := res.(resource.Calibrated)
+	if !ok {
+		return errors.Errorf("%s does not take calibration data", name)
+	}
+	return errors.Wrapf(calibrated.RestoreCalibrationData(ctx, data), "cannot restore the calibration data of %s", name)
+}
+
+// readSnapshotArchive reads the regular files of a snapshot archive by their names.
+func readSnapshotArchive(rd io.Reader) (map[string][]byte, error) {
+	gzr, err := gzip.NewReader(rd)
+	if err != nil {
+		return nil, errors.Wrap(err, "snapshot is not a gzipped archive")
+	}
+	defer utils.UncheckedErrorFunc(gzr.Close)
+
+	files := map[string][]byte{}
+	var size int64
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read the snapshot")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		size += int64(len(header.Name)) + header.Size
+		if size > maxSnapshotSize {
+			return nil, errors.Errorf("snapshot is larger than %d bytes", maxSnapshotSize)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read the snapshot")
+		}
+		files[header.Name] = data
+	}
+}
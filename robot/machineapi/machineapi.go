@@ -0,0 +1,181 @@
+// Package machineapi defines the machine service, which serves the parts of a local robot's API which the
+// robot service does not have, and the messages of its methods. Messages are sent as google.protobuf.Struct, so
+// that the service needs no generated code, and are converted to and from the types of this package with ToStruct
+// and FromStruct by both the server and the client.
+package machineapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+// ServiceName is the name of the gRPC service. It is served alongside the robot service, with the same
+// authentication.
+const ServiceName = "viam.rdk.machine.v1.MachineService"
+
+// The methods of the machine service. Snapshot and RestoreSnapshot stream the snapshot as
+// google.protobuf.BytesValue chunks; the other methods take and return the messages of this package.
+const (
+	SnapshotMethod             = "Snapshot"
+	RestoreSnapshotMethod      = "RestoreSnapshot"
+	GetLogLevelsMethod         = "GetLogLevels"
+	SetLogLevelMethod          = "SetLogLevel"
+	GetCachedConfigMethod      = "GetCachedConfig"
+	GetOperationProgressMethod = "GetOperationProgress"
+	StreamTransformsMethod     = "StreamTransforms"
+	GetLogsMethod              = "GetLogs"
+	StreamLogsMethod           = "StreamLogs"
+)
+
+// FullMethod returns the full name of a method of the machine service.
+func FullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+// ToStruct converts a message to the google.protobuf.Struct it is sent as.
+func ToStruct(msg interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FromStruct converts a google.protobuf.Struct which was received to a message.
+func FromStruct(s *structpb.Struct, msg interface{}) error {
+	data, err := protojson.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(json.Unmarshal(data, msg), "invalid message")
+}
+
+// GetLogLevelsResponse is the response of GetLogLevels: the log levels of the machine's resources and of the
+// loggers whose levels were set by name.
+type GetLogLevelsResponse struct {
+	Resources []ResourceLogLevel `json:"resources"`
+	Loggers   []LoggerLevel      `json:"loggers"`
+}
+
+// ResourceLogLevel is the log level of a resource, as in robot.ResourceLogLevel.
+type ResourceLogLevel struct {
+	// Resource is the fully qualified name of the resource.
+	Resource        string        `json:"resource"`
+	Level           logging.Level `json:"level"`
+	ConfiguredLevel logging.Level `json:"configured_level"`
+	Overridden      bool          `json:"overridden"`
+	// OverrideExpires is unset if the level is not overridden or the override lasts until the next reconfigure.
+	OverrideExpires *time.Time `json:"override_expires,omitempty"`
+}
+
+// LoggerLevel is the level of a logger set by name, as in robot.LoggerLevel.
+type LoggerLevel struct {
+	Logger          string        `json:"logger"`
+	Level           logging.Level `json:"level"`
+	OverrideExpires *time.Time    `json:"override_expires,omitempty"`
+}
+
+// SetLogLevelRequest is the request of SetLogLevel, which overrides the log level of either a resource or a
+// logger. A resource is named fully qualified or by a short name that is unique on the machine, and a logger by
+// its full name.
+type SetLogLevelRequest struct {
+	Resource string `json:"resource,omitempty"`
+	Logger   string `json:"logger,omitempty"`
+	// Level is required.
+	Level *logging.Level `json:"level"`
+	// Duration is a Go duration string such as "5m". If empty, the override lasts until the next reconfigure.
+	Duration string `json:"duration,omitempty"`
+}
+
+// GetCachedConfigResponse is the response of GetCachedConfig, describing the robot.CachedConfig of the machine.
+type GetCachedConfigResponse struct {
+	Path        string    `json:"path"`
+	LastUpdated time.Time `json:"last_updated"`
+	// RunningSince is only set if the machine is running the cached config.
+	RunningSince    *time.Time `json:"running_since,omitempty"`
+	Packages        []Package  `json:"packages"`
+	MissingPackages []Package  `json:"missing_packages"`
+}
+
+// Package is a package of a config.
+type Package struct {
+	Name    string             `json:"name"`
+	Package string             `json:"package"`
+	Version string             `json:"version"`
+	Type    config.PackageType `json:"type"`
+}
+
+// GetOperationProgressResponse is the response of GetOperationProgress: the progress of the machine's running
+// operations which reported it, keyed by operation ID.
+type GetOperationProgressResponse map[string]OperationProgress
+
+// OperationProgress is the progress of an operation, as in operation.Progress.
+type OperationProgress struct {
+	Percent float64   `json:"percent"`
+	Phase   string    `json:"phase"`
+	Updated time.Time `json:"updated"`
+}
+
+// StreamTransformsRequest is the request of StreamTransforms, whose responses are framesystem.TransformUpdateJSON.
+type StreamTransformsRequest struct {
+	// Interval is a Go duration string such as "50ms". If empty, the machine's default is used.
+	Interval string `json:"interval,omitempty"`
+	// Changed only includes the transforms that changed in every update after the first.
+	Changed bool `json:"changed"`
+}
+
+// GetLogsRequest is the request of GetLogs, which returns the machine's recent log entries as a GetLogsResponse.
+// It filters them as /debug/logs does.
+type GetLogsRequest struct {
+	// Level excludes entries below it. If unset, entries of every level are included.
+	Level *logging.Level `json:"level,omitempty"`
+	// Logger, if set, only includes entries of the logger with this full name and of its subloggers.
+	Logger string `json:"logger,omitempty"`
+	// Since, if set, excludes entries logged before it.
+	Since *time.Time `json:"since,omitempty"`
+	// Limit, if positive, only includes this many of the most recent entries.
+	Limit int `json:"limit,omitempty"`
+}
+
+// NewGetLogsRequest returns the request for the entries that match a filter.
+func NewGetLogsRequest(filter logging.LogBufferFilter) GetLogsRequest {
+	level := filter.MinLevel
+	req := GetLogsRequest{Level: &level, Logger: filter.Logger, Limit: filter.Limit}
+	if !filter.Since.IsZero() {
+		since := filter.Since
+		req.Since = &since
+	}
+	return req
+}
+
+// Filter returns the filter the request describes.
+func (req GetLogsRequest) Filter() logging.LogBufferFilter {
+	filter := logging.LogBufferFilter{MinLevel: logging.DEBUG, Logger: req.Logger, Limit: req.Limit}
+	if req.Level != nil {
+		filter.MinLevel = *req.Level
+	}
+	if req.Since != nil {
+		filter.Since = *req.Since
+	}
+	return filter
+}
+
+// GetLogsResponse is the response of GetLogs: the matching entries, in the order they were logged.
+type GetLogsResponse struct {
+	Entries []logging.BufferedLogEntry `json:"entries"`
+}
+
+// StreamLogsRequest is the request of StreamLogs, which streams the entries GetLogs would return followed by
+// new entries which match the request as they are logged, each as a logging.BufferedLogEntry.
+type StreamLogsRequest = GetLogsRequest
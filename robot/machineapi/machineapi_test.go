@@ -0,0 +1,73 @@
+package machineapi
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestStructRoundTrip(t *testing.T) {
+	expires := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	resp := GetLogLevelsResponse{
+		Resources: []ResourceLogLevel{{
+			Resource:        "rdk:component:base/base1",
+			Level:           logging.DEBUG,
+			ConfiguredLevel: logging.INFO,
+			Overridden:      true,
+			OverrideExpires: &expires,
+		}},
+		Loggers: []LoggerLevel{{Logger: "rdk.networking", Level: logging.ERROR}},
+	}
+	s, err := ToStruct(resp)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the keys on the wire are part of the service's contract
+	fields := s.AsMap()
+	resource := fields["resources"].([]interface{})[0].(map[string]interface{})
+	test.That(t, resource["resource"], test.ShouldEqual, "rdk:component:base/base1")
+	test.That(t, resource["level"], test.ShouldEqual, "Debug")
+	test.That(t, resource["configured_level"], test.ShouldEqual, "Info")
+	test.That(t, resource["overridden"], test.ShouldBeTrue)
+	test.That(t, resource["override_expires"], test.ShouldEqual, "2024-01-02T03:04:05.000000006Z")
+	logger := fields["loggers"].([]interface{})[0].(map[string]interface{})
+	test.That(t, logger, test.ShouldNotContainKey, "override_expires")
+
+	var decoded GetLogLevelsResponse
+	test.That(t, FromStruct(s, &decoded), test.ShouldBeNil)
+	test.That(t, decoded, test.ShouldResemble, resp)
+}
+
+func TestFromStructInvalid(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]interface{}{"level": "loud"})
+	test.That(t, err, test.ShouldBeNil)
+	var req SetLogLevelRequest
+	test.That(t, FromStruct(s, &req), test.ShouldNotBeNil)
+
+	s, err = structpb.NewStruct(map[string]interface{}{"logger": "rdk.networking"})
+	test.That(t, err, test.ShouldBeNil)
+	// the level is required, so it must be possible to tell that it is missing
+	var noLevel SetLogLevelRequest
+	test.That(t, FromStruct(s, &noLevel), test.ShouldBeNil)
+	test.That(t, noLevel.Level, test.ShouldBeNil)
+}
+
+func TestGetLogsRequest(t *testing.T) {
+	filter := logging.LogBufferFilter{
+		MinLevel: logging.WARN,
+		Logger:   "rdk.resource_manager",
+		Since:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Limit:    10,
+	}
+	s, err := ToStruct(NewGetLogsRequest(filter))
+	test.That(t, err, test.ShouldBeNil)
+	var req GetLogsRequest
+	test.That(t, FromStruct(s, &req), test.ShouldBeNil)
+	test.That(t, req.Filter(), test.ShouldResemble, filter)
+
+	// as with /debug/logs, an empty request includes every level
+	test.That(t, GetLogsRequest{}.Filter(), test.ShouldResemble, logging.LogBufferFilter{MinLevel: logging.DEBUG})
+}
@@ -30,8 +30,17 @@ import (
 
 const (
 	allowedContentType = "application/x-gzip"
+
+	// partialDownloadSuffix is appended to the download path of a package archive while it is being downloaded.
+	partialDownloadSuffix = ".part"
+
+	// maxDownloadAttempts is how many times a package download is resumed within a sync when the connection drops.
+	maxDownloadAttempts = 3
 )
 
+// errDownloadInterrupted is returned when a download fails part way through and can be resumed.
+var errDownloadInterrupted = errors.New("download interrupted")
+
 var (
 	_ Manager       = (*cloudManager)(nil)
 	_ ManagerSyncer = (*cloudManager)(nil)
@@ -53,7 +62,10 @@ type cloudManager struct {
 	cloudConfig     config.Cloud
 
 	managedPackages map[PackageName]*managedPackage
-	mu              sync.RWMutex
+	// failedPackages are the packages whose download failed in the last sync. Their partial downloads are kept by
+	// Cleanup so that they can be resumed.
+	failedPackages []config.PackageConfig
+	mu             sync.RWMutex
 
 	logger logging.Logger
 }
@@ -128,6 +140,7 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 	defer m.mu.Unlock()
 
 	newManagedPackages := make(map[PackageName]*managedPackage, len(packages))
+	var failedPackages []config.PackageConfig
 
 	for _, p := range packages {
 		// Package exists in known cache.
@@ -179,6 +192,7 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 			m.logger.Errorf("Failed downloading package %s:%s from %s, %s", p.Package, p.Version, sanitizeURLForLogs(resp.Package.Url), err)
 			outErr = multierr.Append(outErr, errors.Wrapf(err, "failed downloading package %s:%s from %s",
 				p.Package, p.Version, sanitizeURLForLogs(resp.Package.Url)))
+			failedPackages = append(failedPackages, p)
 			continue
 		}
 
@@ -198,6 +212,7 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 
 	// swap for new managed packags.
 	m.managedPackages = newManagedPackages
+	m.failedPackages = failedPackages
 
 	return outErr
 }
@@ -244,6 +259,10 @@ func (m *cloudManager) Cleanup(ctx context.Context) error {
 	expectedPackageDirectories := map[string]bool{}
 	for _, pkg := range m.managedPackages {
 		expectedPackageDirectories[pkg.thePackage.LocalDataDirectory(m.packagesDir)] = true
+		expectedPackageDirectories[manifestPath(pkg.thePackage, m.packagesDir)] = true
+	}
+	for _, p := range m.failedPackages {
+		expectedPackageDirectories[p.LocalDownloadPath(m.packagesDir)+partialDownloadSuffix] = true
 	}
 
 	topLevelFiles, err := os.ReadDir(m.packagesDataDir)
@@ -354,10 +373,17 @@ func sanitizeURLForLogs(u string) string {
 }
 
 func (m *cloudManager) downloadPackage(ctx context.Context, url string, p config.PackageConfig) error {
-	// TODO(): validate integrity of directory.
 	if dirExists(p.LocalDataDirectory(m.packagesDir)) {
-		m.logger.Debug("Package already downloaded, skipping.")
-		return nil
+		err := verifyManifest(p.LocalDataDirectory(m.packagesDir), manifestPath(p, m.packagesDir))
+		if errors.Is(err, os.ErrNotExist) {
+			// unpacked before manifests were recorded, so record what is there now.
+			err = writeManifest(p.LocalDataDirectory(m.packagesDir), manifestPath(p, m.packagesDir))
+		}
+		if err == nil {
+			m.logger.Debug("Package already downloaded, skipping.")
+			return nil
+		}
+		m.logger.Warnf("Package %s:%s failed integrity verification, downloading it again: %s", p.Package, p.Version, err)
 	}
 
 	// Create the parent directory for the package type if it doesn't exist
@@ -371,7 +397,7 @@ func (m *cloudManager) downloadPackage(ctx context.Context, url string, p config
 		utils.UncheckedError(err)
 	}
 
-	// Force redownload of package archive.
+	// Force redownload of package archive. A partial download of it is kept so that it can be resumed.
 	if err := m.cleanup(p); err != nil {
 		m.logger.Debug(err)
 	}
@@ -385,8 +411,16 @@ func (m *cloudManager) downloadPackage(ctx context.Context, url string, p config
 		}
 	}
 
-	// Download from GCS
-	_, contentType, err := m.downloadFileFromGCSURL(ctx, url, p.LocalDownloadPath(m.packagesDir), m.cloudConfig.ID, m.cloudConfig.Secret)
+	// Download from GCS, resuming the download if the connection drops part way through.
+	var contentType string
+	var err error
+	for attempt := 1; ; attempt++ {
+		_, contentType, err = m.downloadFileFromGCSURL(ctx, url, p.LocalDownloadPath(m.packagesDir), m.cloudConfig.ID, m.cloudConfig.Secret)
+		if err == nil || !errors.Is(err, errDownloadInterrupted) || attempt >= maxDownloadAttempts || ctx.Err() != nil {
+			break
+		}
+		m.logger.Infof("Resuming download of package %s:%s [%d/%d]: %s", p.Package, p.Version, attempt+1, maxDownloadAttempts, err)
+	}
 	if err != nil {
 		return err
 	}
@@ -419,6 +453,12 @@ func (m *cloudManager) downloadPackage(ctx context.Context, url string, p config
 		return err
 	}
 
+	// record the contents of the package so that its integrity can be verified before it is used again.
+	if err := writeManifest(tmpDataPath, manifestPath(p, m.packagesDir)); err != nil {
+		utils.UncheckedError(m.cleanup(p))
+		return errors.Wrap(err, "failed to write package manifest")
+	}
+
 	err = os.Rename(tmpDataPath, p.LocalDataDirectory(m.packagesDir))
 	if err != nil {
 		utils.UncheckedError(m.cleanup(p))
@@ -432,9 +472,13 @@ func (m *cloudManager) cleanup(p config.PackageConfig) error {
 	return multierr.Combine(
 		os.RemoveAll(p.LocalDataDirectory(m.packagesDir)),
 		os.Remove(p.LocalDownloadPath(m.packagesDir)),
+		os.Remove(manifestPath(p, m.packagesDir)),
 	)
 }
 
+// downloadFileFromGCSURL downloads the file at the url to downloadPath and verifies it against the crc32c checksum
+// returned by GCS. The file is downloaded to a partial file next to downloadPath first, which is kept if the download
+// is interrupted, so that the next call only requests the remainder of the file.
 func (m *cloudManager) downloadFileFromGCSURL(
 	ctx context.Context,
 	url string,
@@ -442,21 +486,48 @@ func (m *cloudManager) downloadFileFromGCSURL(
 	partID string,
 	partSecret string,
 ) (string, string, error) {
+	partialPath := downloadPath + partialDownloadSuffix
+
+	// hash what was already downloaded, and start over if it cannot be read.
+	hash := crc32Hash()
+	offset, err := hashFile(partialPath, hash)
+	if err != nil {
+		utils.UncheckedError(os.Remove(partialPath))
+		hash.Reset()
+		offset = 0
+	}
+
 	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	getReq.Header.Add("part_id", partID)
-	getReq.Header.Add("secret", partSecret)
 	if err != nil {
 		return "", "", err
 	}
+	getReq.Header.Add("part_id", partID)
+	getReq.Header.Add("secret", partSecret)
+	if offset > 0 {
+		getReq.Header.Add("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	//nolint:bodyclose /// closed in UncheckedErrorFunc
 	resp, err := m.httpClient.Do(getReq)
 	if err != nil {
-		return "", "", err
+		return "", "", errors.Wrap(errDownloadInterrupted, err.Error())
 	}
 	defer utils.UncheckedErrorFunc(resp.Body.Close)
 
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 &&
+		strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// the server ignored the range, so the file is downloaded from the start.
+		hash.Reset()
+		offset = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the partial file is no longer a prefix of the file, so download it again from the start.
+		utils.UncheckedError(os.Remove(partialPath))
+		return m.downloadFileFromGCSURL(ctx, url, downloadPath, partID, partSecret)
+	default:
 		return "", "", fmt.Errorf("invalid status code %d", resp.StatusCode)
 	}
 
@@ -464,23 +535,23 @@ func (m *cloudManager) downloadFileFromGCSURL(
 	checksum := getGoogleHash(resp.Header, "crc32c")
 
 	//nolint:gosec // safe
-	out, err := os.Create(downloadPath)
+	out, err := os.OpenFile(partialPath, flags, 0o600)
 	if err != nil {
 		return checksum, contentType, err
 	}
 	defer utils.UncheckedErrorFunc(out.Close)
 
-	hash := crc32Hash()
 	w := io.MultiWriter(out, hash)
 
-	_, err = io.CopyN(w, resp.Body, maxPackageSize)
+	_, err = io.CopyN(w, resp.Body, maxPackageSize-offset)
 	if err != nil && !errors.Is(err, io.EOF) {
-		utils.UncheckedError(os.Remove(downloadPath))
-		return checksum, contentType, err
+		// keep what was downloaded so far so that the download can be resumed.
+		return checksum, contentType, errors.Wrap(errDownloadInterrupted, err.Error())
 	}
 
 	checksumBytes, err := base64.StdEncoding.DecodeString(checksum)
 	if err != nil {
+		utils.UncheckedError(os.Remove(partialPath))
 		return "", "", errors.Wrapf(err, "failed to decode expected checksum: %s", checksum)
 	}
 
@@ -488,7 +559,12 @@ func (m *cloudManager) downloadFileFromGCSURL(
 	trimmedOutHashBytes := trimLeadingZeroes(hash.Sum(nil))
 
 	if !bytes.Equal(trimmedOutHashBytes, trimmedChecksumBytes) {
-		utils.UncheckedError(os.Remove(downloadPath))
+		utils.UncheckedError(os.Remove(partialPath))
+		if offset > 0 {
+			// the partial file may have come from a different version of the file, so download it again from the start.
+			m.logger.Infof("Resumed download of %s did not match expected hash, downloading it again", sanitizeURLForLogs(url))
+			return m.downloadFileFromGCSURL(ctx, url, downloadPath, partID, partSecret)
+		}
 		return checksum, contentType, errors.Errorf(
 			"download did not match expected hash:\n"+
 				"  pre-trimmed: %x vs. %x\n"+
@@ -498,9 +574,30 @@ func (m *cloudManager) downloadFileFromGCSURL(
 		)
 	}
 
+	if err := out.Close(); err != nil {
+		return checksum, contentType, err
+	}
+	if err := os.Rename(partialPath, downloadPath); err != nil {
+		return checksum, contentType, err
+	}
+
 	return checksum, contentType, nil
 }
 
+// hashFile writes the contents of the file at path, if it exists, to the hash and returns its size.
+func hashFile(path string, h hash.Hash) (int64, error) {
+	//nolint:gosec // safe
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	return io.Copy(h, f)
+}
+
 func trimLeadingZeroes(data []byte) []byte {
 	if len(data) == 0 {
 		return []byte{}
@@ -268,6 +268,97 @@ func TestCloud(t *testing.T) {
 
 		validatePackageDir(t, packageDir, []config.PackageConfig{})
 	})
+
+	t.Run("interrupted download is resumed", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(func() error { return pm.Close(context.Background()) })
+
+		fakeServer.SetDropAfter(100)
+
+		input := []config.PackageConfig{
+			{Name: "some-name-1", Package: "org1/test-model", Version: "v1", Type: "ml_model"},
+		}
+		fakeServer.StorePackage(input...)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, downloadCount := fakeServer.RequestCounts()
+		test.That(t, downloadCount, test.ShouldEqual, 2)
+		test.That(t, fakeServer.RangeRequestCount(), test.ShouldEqual, 1)
+
+		validatePackageDir(t, packageDir, input)
+		putils.ValidateContentsOfPPackage(t, input[0].LocalDataDirectory(packageDir))
+	})
+
+	t.Run("mismatched partial download is downloaded again", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(func() error { return pm.Close(context.Background()) })
+
+		input := []config.PackageConfig{
+			{Name: "some-name-1", Package: "org1/test-model", Version: "v1", Type: "ml_model"},
+		}
+		fakeServer.StorePackage(input...)
+
+		// a partial download left behind by a different version of the package
+		partialPath := input[0].LocalDownloadPath(packageDir) + partialDownloadSuffix
+		test.That(t, os.MkdirAll(filepath.Dir(partialPath), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(partialPath, []byte("not the package"), 0o600), test.ShouldBeNil)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, downloadCount := fakeServer.RequestCounts()
+		test.That(t, downloadCount, test.ShouldEqual, 2)
+		test.That(t, fakeServer.RangeRequestCount(), test.ShouldEqual, 1)
+
+		validatePackageDir(t, packageDir, input)
+		putils.ValidateContentsOfPPackage(t, input[0].LocalDataDirectory(packageDir))
+	})
+
+	t.Run("modified package is downloaded again", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(func() error { return pm.Close(context.Background()) })
+
+		input := []config.PackageConfig{
+			{Name: "some-name-1", Package: "org1/test-model", Version: "v1", Type: "ml_model"},
+		}
+		fakeServer.StorePackage(input...)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		testCloudConfig := &config.Cloud{ID: "some-id", Secret: "some-secret"}
+
+		// an intact package is not downloaded again after a restart
+		pm2, err := NewCloudManager(testCloudConfig, client, packageDir, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(func() error { return pm2.Close(context.Background()) })
+		err = pm2.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, downloadCount := fakeServer.RequestCounts()
+		test.That(t, downloadCount, test.ShouldEqual, 1)
+
+		// a truncated file is detected after a restart
+		err = os.WriteFile(filepath.Join(input[0].LocalDataDirectory(packageDir), "some-text.txt"), nil, 0o644)
+		test.That(t, err, test.ShouldBeNil)
+
+		pm3, err := NewCloudManager(testCloudConfig, client, packageDir, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(func() error { return pm3.Close(context.Background()) })
+		err = pm3.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, downloadCount = fakeServer.RequestCounts()
+		test.That(t, downloadCount, test.ShouldEqual, 2)
+
+		err = pm3.Cleanup(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		validatePackageDir(t, packageDir, input)
+		putils.ValidateContentsOfPPackage(t, input[0].LocalDataDirectory(packageDir))
+	})
 }
 
 func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig) {
@@ -282,7 +373,7 @@ func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig)
 		bySanitizedName[p.SanitizedName()] = &p
 		byLogicalName[p.Name] = &p
 		pType := string(p.Type)
-		byType[pType] = append(byType[pType], p.SanitizedName())
+		byType[pType] = append(byType[pType], p.SanitizedName(), p.SanitizedName()+".manifest.json")
 	}
 
 	// check all known packages exist and are linked to the correct package dir.
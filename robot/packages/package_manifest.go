@@ -0,0 +1,178 @@
+package packages
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+)
+
+// packageManifest records the contents of an unpacked package so that the package can be checked for files that were
+// modified, truncated or removed since it was unpacked.
+type packageManifest struct {
+	Files    map[string]manifestFile `json:"files"`
+	Dirs     []string                `json:"dirs"`
+	Symlinks map[string]string       `json:"symlinks"`
+}
+
+type manifestFile struct {
+	Size int64 `json:"size"`
+	// ModTime is the modification time of the file in Unix nanoseconds, so that only files which changed since they
+	// were unpacked need to be checksummed again.
+	ModTime int64  `json:"mod_time"`
+	CRC32C  string `json:"crc32c"`
+}
+
+// runtimeWrittenDirs are directories which modules create or modify in their package at runtime, such as Python
+// virtual environments and bytecode caches, and are left out of the manifest.
+var runtimeWrittenDirs = map[string]bool{
+	"__pycache__": true,
+	".venv":       true,
+	"venv":        true,
+}
+
+// runtimeWrittenExts are the extensions of files which modules write into their package at runtime.
+var runtimeWrittenExts = map[string]bool{
+	".pyc": true,
+}
+
+// isRuntimeWritten returns whether the entry of a package is written at runtime rather than unpacked.
+func isRuntimeWritten(d fs.DirEntry) bool {
+	if d.IsDir() {
+		return runtimeWrittenDirs[d.Name()]
+	}
+	return runtimeWrittenExts[filepath.Ext(d.Name())]
+}
+
+// manifestPath returns the file the manifest of the package is stored in, next to its data directory.
+func manifestPath(p config.PackageConfig, packagesDir string) string {
+	return p.LocalDataDirectory(packagesDir) + ".manifest.json"
+}
+
+// newPackageManifest returns the manifest of the contents of dir.
+func newPackageManifest(dir string) (*packageManifest, error) {
+	manifest := &packageManifest{
+		Files:    map[string]manifestFile{},
+		Dirs:     []string{},
+		Symlinks: map[string]string{},
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if isRuntimeWritten(d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			manifest.Dirs = append(manifest.Dirs, rel)
+		case d.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			manifest.Symlinks[rel] = target
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			checksum, size, err := fileChecksum(path)
+			if err != nil {
+				return err
+			}
+			manifest.Files[rel] = manifestFile{Size: size, ModTime: info.ModTime().UnixNano(), CRC32C: checksum}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// fileChecksum returns the base64 encoded CRC32C checksum of the file at path and its size.
+func fileChecksum(path string) (string, int64, error) {
+	hash := crc32Hash()
+	size, err := hashFile(path, hash)
+	if err != nil {
+		return "", 0, err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// writeManifest writes the manifest of the contents of dir to path.
+func writeManifest(dir, path string) error {
+	manifest, err := newPackageManifest(dir)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// verifyManifest returns an error if the contents of dir differ from the manifest written to path. Files are only
+// checksummed if their size or modification time changed since the manifest was written. An error satisfying
+// errors.Is(err, os.ErrNotExist) is returned if there is no manifest.
+func verifyManifest(dir, path string) error {
+	//nolint:gosec // safe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var expected packageManifest
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return errors.Wrap(err, "invalid package manifest")
+	}
+
+	for name, file := range expected.Files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		info, err := os.Lstat(filePath)
+		switch {
+		case err != nil || !info.Mode().IsRegular():
+			return errors.Errorf("file %q is missing", name)
+		case info.Size() != file.Size:
+			return errors.Errorf("file %q has size %d, expected %d", name, info.Size(), file.Size)
+		case info.ModTime().UnixNano() == file.ModTime:
+			continue
+		}
+		checksum, _, err := fileChecksum(filePath)
+		if err != nil {
+			return err
+		}
+		if checksum != file.CRC32C {
+			return errors.Errorf("file %q does not match its checksum", name)
+		}
+	}
+	for name, target := range expected.Symlinks {
+		if actual, err := os.Readlink(filepath.Join(dir, filepath.FromSlash(name))); err != nil || actual != target {
+			return errors.Errorf("link %q does not point to %q", name, target)
+		}
+	}
+	for _, name := range expected.Dirs {
+		if !dirExists(filepath.Join(dir, filepath.FromSlash(name))) {
+			return errors.Errorf("directory %q is missing", name)
+		}
+	}
+	return nil
+}
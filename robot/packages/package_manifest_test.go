@@ -0,0 +1,47 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "package")
+	path := dir + ".manifest.json"
+	filePath := filepath.Join(dir, "model.bin")
+	cachePath := filepath.Join(dir, "__pycache__", "main.cpython-311.pyc")
+	test.That(t, os.MkdirAll(filepath.Dir(cachePath), 0o700), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filePath, []byte("weights"), 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(cachePath, []byte("bytecode"), 0o600), test.ShouldBeNil)
+	test.That(t, writeManifest(dir, path), test.ShouldBeNil)
+	test.That(t, verifyManifest(dir, path), test.ShouldBeNil)
+
+	info, err := os.Stat(filePath)
+	test.That(t, err, test.ShouldBeNil)
+	modTime := info.ModTime()
+
+	// files written at runtime are not part of the package
+	test.That(t, os.WriteFile(cachePath, []byte("new bytecode"), 0o600), test.ShouldBeNil)
+	test.That(t, verifyManifest(dir, path), test.ShouldBeNil)
+
+	// a file with the size and modification time it was unpacked with is not checksummed again
+	test.That(t, os.WriteFile(filePath, []byte("weighty"), 0o600), test.ShouldBeNil)
+	test.That(t, os.Chtimes(filePath, modTime, modTime), test.ShouldBeNil)
+	test.That(t, verifyManifest(dir, path), test.ShouldBeNil)
+
+	// but it is once it was modified since
+	later := modTime.Add(time.Second)
+	test.That(t, os.Chtimes(filePath, later, later), test.ShouldBeNil)
+	err = verifyManifest(dir, path)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not match its checksum")
+
+	test.That(t, os.Remove(filePath), test.ShouldBeNil)
+	err = verifyManifest(dir, path)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "is missing")
+}
@@ -50,9 +50,11 @@ type FakePackagesClientAndGCSServer struct {
 	invalidChecksum          bool
 	hasLeadingZeroesChecksum bool
 	invalidTar               bool
+	dropAfter                int64
 
 	getRequestCount      int
 	downloadRequestCount int
+	rangeRequestCount    int
 
 	mu     sync.Mutex
 	logger logging.Logger
@@ -183,6 +185,22 @@ func (c *FakePackagesClientAndGCSServer) SetInvalidHTTPRes(flag bool) {
 	c.invalidHTTPRes = flag
 }
 
+// SetDropAfter makes the next download drop the connection after sending n bytes of the package. Zero disables it.
+func (c *FakePackagesClientAndGCSServer) SetDropAfter(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dropAfter = n
+}
+
+// RangeRequestCount returns the number of downloads that requested part of the package.
+func (c *FakePackagesClientAndGCSServer) RangeRequestCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rangeRequestCount
+}
+
 // RequestCounts returns the request counters.
 func (c *FakePackagesClientAndGCSServer) RequestCounts() (req, download int) {
 	c.mu.Lock()
@@ -199,6 +217,9 @@ func (c *FakePackagesClientAndGCSServer) servePackage(w http.ResponseWriter, r *
 	version := r.URL.Query().Get("version")
 
 	c.downloadRequestCount++
+	if r.Header.Get("Range") != "" {
+		c.rangeRequestCount++
+	}
 
 	if c.invalidHTTPRes {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -253,12 +274,35 @@ func (c *FakePackagesClientAndGCSServer) servePackage(w http.ResponseWriter, r *
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	_, err = io.Copy(w, f)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	defer utils.UncheckedErrorFunc(f.Close)
+
+	if c.dropAfter > 0 {
+		w = &droppingResponseWriter{ResponseWriter: w, remaining: c.dropAfter}
+		c.dropAfter = 0
+	}
+	// ServeContent handles range requests, which are used to resume downloads.
+	http.ServeContent(w, r, "", time.Time{}, f)
+}
+
+// droppingResponseWriter aborts the response after writing a number of bytes of the body.
+type droppingResponseWriter struct {
+	http.ResponseWriter
+	remaining int64
+}
+
+func (w *droppingResponseWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > w.remaining {
+		p = p[:w.remaining]
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.remaining -= int64(n)
+	if err == nil && w.remaining == 0 {
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+		panic(http.ErrAbortHandler)
 	}
+	return n, err
 }
 
 // Shutdown will stop the server.
@@ -294,7 +338,9 @@ func (c *FakePackagesClientAndGCSServer) Clear() {
 	c.invalidChecksum = false
 	c.invalidTar = false
 	c.invalidHTTPRes = false
+	c.dropAfter = 0
 	c.downloadRequestCount = 0
+	c.rangeRequestCount = 0
 	c.getRequestCount = 0
 }
 
@@ -4,6 +4,7 @@ package robot
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -118,6 +119,44 @@ type LocalRobot interface {
 	// visualization.
 	// DOT reference: https://graphviz.org/doc/info/lang.html
 	ExportResourcesAsDot(index int) (resource.GetSnapshotInfo, error)
+
+	// ResourceLogLevels returns the current log level of every resource's logger.
+	ResourceLogLevels() []ResourceLogLevel
+
+	// SetResourceLogLevel overrides the log level of a resource's logger at runtime. The override
+	// lasts for the given duration, or until the next reconfigure if the duration is zero. Any
+	// reconfigure of the robot clears all overrides. Resources served by modules keep logging at
+	// their configured level within the module process.
+	SetResourceLogLevel(name resource.Name, level logging.Level, duration time.Duration) error
+
+	// LoggerLevels returns the log levels set at runtime with SetLoggerLevel.
+	LoggerLevels() []LoggerLevel
+
+	// SetLoggerLevel overrides the log level of any logger by name, such as "rdk.resource_manager",
+	// and of all of its subloggers, including those created later. Like SetResourceLogLevel, the
+	// override lasts for the given duration, or until the next reconfigure if the duration is zero.
+	SetLoggerLevel(name string, level logging.Level, duration time.Duration) error
+
+	// Snapshot writes an archive of the machine's state to w: its config with its secrets removed by
+	// config.RedactSecrets, its frame system and the current inputs of each frame, the calibration data
+	// of every resource that has any, and the versions of its packages.
+	Snapshot(ctx context.Context, w io.Writer) error
+
+	// RestoreSnapshot reconfigures the robot from an archive written by Snapshot, keeping its own cloud
+	// credentials and auth, and then restores the calibration data of its resources. An invalid config is
+	// refused, and the errors of resources which could not be built from it are returned. Actuators are
+	// not moved back to the inputs in the snapshot. The restored config replaces the robot's config file,
+	// and robots configured from the cloud are refused, since the cloud's config would replace it.
+	RestoreSnapshot(ctx context.Context, r io.Reader) error
+
+	// ResourceStats returns the stats of the resources which implement resource.Statser, including those
+	// served by modules, by resource name. They are sampled periodically rather than on each call, so they
+	// may be up to one sampling interval old.
+	ResourceStats() map[resource.Name]map[string]float64
+
+	// CachedConfig describes the cached copy of the last config the robot read from the cloud, which it
+	// comes up from when it cannot reach the cloud. The error satisfies os.IsNotExist if nothing is cached.
+	CachedConfig() (CachedConfig, error)
 }
 
 // A RemoteRobot is a Robot that was created through a connection.
@@ -140,6 +179,38 @@ type Status struct {
 	Status           interface{}
 }
 
+// ResourceLogLevel describes the log level of a resource's logger.
+type ResourceLogLevel struct {
+	Name resource.Name
+	// Level is the level the resource is currently logging at.
+	Level logging.Level
+	// ConfiguredLevel is the level from the resource's config, which Level returns to when an
+	// override ends.
+	ConfiguredLevel logging.Level
+	// Overridden is true when Level was set at runtime with SetResourceLogLevel.
+	Overridden bool
+	// OverrideExpires is when the override ends, or the zero time if it lasts until the next
+	// reconfigure.
+	OverrideExpires time.Time
+}
+
+// LoggerLevel describes a log level set at runtime for a logger by name.
+type LoggerLevel struct {
+	Logger string
+	Level  logging.Level
+	// OverrideExpires is when the override ends, or the zero time if it lasts until the next
+	// reconfigure.
+	OverrideExpires time.Time
+}
+
+// CachedConfig describes the cached copy of the last config a robot read from the cloud.
+type CachedConfig struct {
+	config.CacheInfo
+	// RunningSince is when the config the robot is running was read from the cloud, if the robot is running
+	// the cached config because it could not reach the cloud, or the zero time otherwise.
+	RunningSince time.Time
+}
+
 // AllResourcesByName returns an array of all resources that have this short name.
 // NOTE: this function queries by the shortname rather than the fully qualified resource name which is not recommended practice
 // and may become deprecated in the future.
@@ -0,0 +1,424 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/machineapi"
+)
+
+// snapshotChunkSize is the size of the chunks a snapshot is streamed in.
+const snapshotChunkSize = 64 << 10
+
+// maxRestoreSnapshotSize bounds the size of a snapshot which can be restored.
+const maxRestoreSnapshotSize = 1 << 30
+
+// MachineServiceServer is the server API of the machine service.
+type MachineServiceServer interface {
+	// Snapshot streams a snapshot of the machine, as written by robot.LocalRobot.Snapshot, in chunks.
+	Snapshot(*emptypb.Empty, googlegrpc.ServerStream) error
+	// RestoreSnapshot restores the machine from a snapshot streamed in chunks.
+	RestoreSnapshot(googlegrpc.ServerStream) error
+	// GetLogLevels returns the log levels of the machine's resources and of the loggers whose levels
+	// were set by name.
+	GetLogLevels(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	// SetLogLevel overrides the log level of a resource or of a logger by name.
+	SetLogLevel(context.Context, *structpb.Struct) (*emptypb.Empty, error)
+	// StreamTransforms streams the transforms of the machine's frames as they change.
+	StreamTransforms(*structpb.Struct, googlegrpc.ServerStream) error
+	// GetCachedConfig describes the cached copy of the last config the machine read from the cloud.
+	GetCachedConfig(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	// GetOperationProgress returns the progress of the machine's running operations which report it.
+	GetOperationProgress(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	// GetLogs returns the machine's recent log entries.
+	GetLogs(context.Context, *structpb.Struct) (*structpb.Struct, error)
+	// StreamLogs streams the machine's recent log entries followed by new ones as they are logged.
+	StreamLogs(*structpb.Struct, googlegrpc.ServerStream) error
+}
+
+// MachineServer implements the machine service for a local robot.
+type MachineServer struct {
+	robot     robot.LocalRobot
+	logBuffer *logging.LogBuffer
+}
+
+// NewMachineServer constructs a machine service server for a local robot. Its logs are served from logBuffer,
+// which may be nil if the machine does not buffer them.
+func NewMachineServer(r robot.LocalRobot, logBuffer *logging.LogBuffer) *MachineServer {
+	return &MachineServer{robot: r, logBuffer: logBuffer}
+}
+
+// Snapshot streams a snapshot of the machine in chunks.
+func (s *MachineServer) Snapshot(_ *emptypb.Empty, stream googlegrpc.ServerStream) error {
+	var buf bytes.Buffer
+	if err := s.robot.Snapshot(stream.Context(), &buf); err != nil {
+		return err
+	}
+	for buf.Len() > 0 {
+		if err := stream.SendMsg(wrapperspb.Bytes(buf.Next(snapshotChunkSize))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreSnapshot restores the machine from a snapshot streamed in chunks.
+func (s *MachineServer) RestoreSnapshot(stream googlegrpc.ServerStream) error {
+	var buf bytes.Buffer
+	for {
+		chunk := &wrapperspb.BytesValue{}
+		err := stream.RecvMsg(chunk)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if buf.Len()+len(chunk.GetValue()) > maxRestoreSnapshotSize {
+			return grpcstatus.Errorf(codes.InvalidArgument, "snapshot is larger than %d bytes", maxRestoreSnapshotSize)
+		}
+		buf.Write(chunk.GetValue())
+	}
+	if err := s.robot.RestoreSnapshot(stream.Context(), &buf); err != nil {
+		return err
+	}
+	return stream.SendMsg(&emptypb.Empty{})
+}
+
+// GetLogLevels returns a machineapi.GetLogLevelsResponse describing each robot.ResourceLogLevel and
+// robot.LoggerLevel.
+func (s *MachineServer) GetLogLevels(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	resp := machineapi.GetLogLevelsResponse{
+		Resources: []machineapi.ResourceLogLevel{},
+		Loggers:   []machineapi.LoggerLevel{},
+	}
+	for _, level := range s.robot.ResourceLogLevels() {
+		resp.Resources = append(resp.Resources, machineapi.ResourceLogLevel{
+			Resource:        level.Name.String(),
+			Level:           level.Level,
+			ConfiguredLevel: level.ConfiguredLevel,
+			Overridden:      level.Overridden,
+			OverrideExpires: timeOrNil(level.OverrideExpires),
+		})
+	}
+	for _, level := range s.robot.LoggerLevels() {
+		resp.Loggers = append(resp.Loggers, machineapi.LoggerLevel{
+			Logger:          level.Logger,
+			Level:           level.Level,
+			OverrideExpires: timeOrNil(level.OverrideExpires),
+		})
+	}
+	return machineapi.ToStruct(resp)
+}
+
+// GetCachedConfig returns a machineapi.GetCachedConfigResponse describing the robot.CachedConfig of the machine.
+func (s *MachineServer) GetCachedConfig(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	cached, err := s.robot.CachedConfig()
+	if os.IsNotExist(err) {
+		return nil, grpcstatus.Error(codes.NotFound, "no config is cached")
+	}
+	if err != nil {
+		return nil, err
+	}
+	packages := func(pkgs []config.PackageConfig) []machineapi.Package {
+		ret := make([]machineapi.Package, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			ret = append(ret, machineapi.Package{Name: pkg.Name, Package: pkg.Package, Version: pkg.Version, Type: pkg.Type})
+		}
+		return ret
+	}
+	return machineapi.ToStruct(machineapi.GetCachedConfigResponse{
+		Path:            cached.Path,
+		LastUpdated:     cached.LastUpdated.UTC(),
+		RunningSince:    timeOrNil(cached.RunningSince),
+		Packages:        packages(cached.Packages),
+		MissingPackages: packages(cached.MissingPackages),
+	})
+}
+
+// GetOperationProgress returns a machineapi.GetOperationProgressResponse with the progress of the running
+// operations which reported it. The robot service's GetOperations lists the operations themselves, but its
+// messages have no field for progress.
+func (s *MachineServer) GetOperationProgress(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	resp := machineapi.GetOperationProgressResponse{}
+	for _, op := range s.robot.OperationManager().All() {
+		progress := op.Progress()
+		if progress.Updated.IsZero() {
+			continue
+		}
+		resp[op.ID.String()] = machineapi.OperationProgress{
+			Percent: progress.Percent,
+			Phase:   progress.Phase,
+			Updated: progress.Updated.UTC(),
+		}
+	}
+	return machineapi.ToStruct(resp)
+}
+
+// SetLogLevel overrides a log level as described by a machineapi.SetLogLevelRequest.
+func (s *MachineServer) SetLogLevel(ctx context.Context, req *structpb.Struct) (*emptypb.Empty, error) {
+	var request machineapi.SetLogLevelRequest
+	if err := machineapi.FromStruct(req, &request); err != nil {
+		return nil, grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	if request.Level == nil {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "a level is required")
+	}
+	level := *request.Level
+	var duration time.Duration
+	if request.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(request.Duration); err != nil {
+			return nil, grpcstatus.Errorf(codes.InvalidArgument, "invalid duration: %v", err)
+		}
+	}
+
+	var err error
+	switch {
+	case request.Resource != "" && request.Logger != "":
+		return nil, grpcstatus.Error(codes.InvalidArgument, "only one of resource and logger may be set")
+	case request.Logger != "":
+		err = s.robot.SetLoggerLevel(request.Logger, level, duration)
+	default:
+		name, resolveErr := resolveResourceName(s.robot, request.Resource)
+		if resolveErr != nil {
+			return nil, grpcstatus.Error(codes.NotFound, resolveErr.Error())
+		}
+		err = s.robot.SetResourceLogLevel(name, level, duration)
+	}
+	if err != nil {
+		if resource.IsNotFoundError(err) {
+			return nil, grpcstatus.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// StreamTransforms streams the transforms of the machine's frames, including the bodies seen by its pose
+// trackers, as framesystem.StreamTransforms does, until the client cancels the call. The request is a
+// machineapi.StreamTransformsRequest, and each update is a framesystem.TransformUpdateJSON, as served over HTTP.
+func (s *MachineServer) StreamTransforms(req *structpb.Struct, stream googlegrpc.ServerStream) error {
+	var request machineapi.StreamTransformsRequest
+	if err := machineapi.FromStruct(req, &request); err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	opts := framesystem.TransformStreamOptions{
+		OnlyChanged: request.Changed,
+		OnError: func(err error) {
+			s.robot.Logger().Debugw("skipped frame system update", "error", err)
+		},
+	}
+	if request.Interval != "" {
+		interval, err := time.ParseDuration(request.Interval)
+		if err != nil {
+			return grpcstatus.Errorf(codes.InvalidArgument, "invalid interval: %v", err)
+		}
+		opts.Interval = interval
+	}
+
+	res, err := s.robot.ResourceByName(framesystem.InternalServiceName)
+	if err != nil {
+		return err
+	}
+	fsSvc, ok := res.(framesystem.Service)
+	if !ok {
+		return grpcstatus.Error(codes.Unavailable, "frame system service is unavailable")
+	}
+	opts.DynamicFrames = posetracker.RobotDynamicFrames(s.robot)
+
+	err = framesystem.StreamTransforms(stream.Context(), fsSvc, opts, func(update framesystem.TransformUpdate) error {
+		msg, err := machineapi.ToStruct(framesystem.NewTransformUpdateJSON(update))
+		if err != nil {
+			return err
+		}
+		return stream.SendMsg(msg)
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// GetLogs returns a machineapi.GetLogsResponse with the buffered log entries which match a
+// machineapi.GetLogsRequest.
+func (s *MachineServer) GetLogs(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	var request machineapi.GetLogsRequest
+	if err := machineapi.FromStruct(req, &request); err != nil {
+		return nil, grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.logBuffer == nil {
+		return nil, errLogsUnavailable
+	}
+	entries := s.logBuffer.Entries(request.Filter())
+	if entries == nil {
+		entries = []logging.BufferedLogEntry{}
+	}
+	return machineapi.ToStruct(machineapi.GetLogsResponse{Entries: entries})
+}
+
+// StreamLogs streams the buffered log entries which match a machineapi.StreamLogsRequest, followed by new ones
+// as they are logged, until the client cancels the call. Each is a logging.BufferedLogEntry.
+func (s *MachineServer) StreamLogs(req *structpb.Struct, stream googlegrpc.ServerStream) error {
+	var request machineapi.StreamLogsRequest
+	if err := machineapi.FromStruct(req, &request); err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.logBuffer == nil {
+		return errLogsUnavailable
+	}
+	err := s.logBuffer.Follow(stream.Context(), request.Filter(), func(entry logging.BufferedLogEntry) error {
+		msg, err := machineapi.ToStruct(entry)
+		if err != nil {
+			return err
+		}
+		return stream.SendMsg(msg)
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+var errLogsUnavailable = grpcstatus.Error(codes.Unavailable, "this machine does not buffer its logs")
+
+// timeOrNil returns nil for the zero time, which messages leave unset, and t in UTC otherwise.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// resolveResourceName finds the resource a request refers to by its fully qualified name, falling
+// back to a unique short name.
+func resolveResourceName(r robot.Robot, nameStr string) (resource.Name, error) {
+	if nameStr == "" {
+		return resource.Name{}, errors.New("a resource or logger name is required")
+	}
+	var matches []resource.Name
+	for _, name := range r.ResourceNames() {
+		if name.String() == nameStr {
+			return name, nil
+		}
+		if name.ShortName() == nameStr {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return resource.Name{}, errors.Errorf("no resource named %q", nameStr)
+	case 1:
+		return matches[0], nil
+	default:
+		return resource.Name{}, errors.Errorf("resource name %q is ambiguous; use the fully qualified name", nameStr)
+	}
+}
+
+// MachineServiceDesc describes the machine service for registering with an rpc.Server. Its messages are protobuf
+// well-known types, so that it needs no generated code; machineapi defines what they hold.
+var MachineServiceDesc = googlegrpc.ServiceDesc{
+	ServiceName: machineapi.ServiceName,
+	HandlerType: (*MachineServiceServer)(nil),
+	Methods: []googlegrpc.MethodDesc{
+		unaryMachineMethod(machineapi.GetLogLevelsMethod, MachineServiceServer.GetLogLevels),
+		unaryMachineMethod(machineapi.SetLogLevelMethod, MachineServiceServer.SetLogLevel),
+		unaryMachineMethod(machineapi.GetCachedConfigMethod, MachineServiceServer.GetCachedConfig),
+		unaryMachineMethod(machineapi.GetOperationProgressMethod, MachineServiceServer.GetOperationProgress),
+		unaryMachineMethod(machineapi.GetLogsMethod, MachineServiceServer.GetLogs),
+	},
+	Streams: []googlegrpc.StreamDesc{
+		{
+			StreamName: machineapi.SnapshotMethod,
+			Handler: func(srv interface{}, stream googlegrpc.ServerStream) error {
+				in := &emptypb.Empty{}
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(MachineServiceServer).Snapshot(in, stream)
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: machineapi.RestoreSnapshotMethod,
+			Handler: func(srv interface{}, stream googlegrpc.ServerStream) error {
+				return srv.(MachineServiceServer).RestoreSnapshot(stream)
+			},
+			ClientStreams: true,
+		},
+		{
+			StreamName: machineapi.StreamTransformsMethod,
+			Handler: func(srv interface{}, stream googlegrpc.ServerStream) error {
+				in := &structpb.Struct{}
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(MachineServiceServer).StreamTransforms(in, stream)
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: machineapi.StreamLogsMethod,
+			Handler: func(srv interface{}, stream googlegrpc.ServerStream) error {
+				in := &structpb.Struct{}
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(MachineServiceServer).StreamLogs(in, stream)
+			},
+			ServerStreams: true,
+		},
+	},
+}
+
+// unaryMachineMethod adapts a unary method of the machine service to a method description. Calls go
+// through the server's interceptors, which authenticate them.
+func unaryMachineMethod[Req any, ReqPtr interface {
+	*Req
+	proto.Message
+}, Resp proto.Message](
+	name string,
+	method func(MachineServiceServer, context.Context, ReqPtr) (Resp, error),
+) googlegrpc.MethodDesc {
+	return googlegrpc.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv interface{},
+			ctx context.Context,
+			dec func(interface{}) error,
+			interceptor googlegrpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			in := ReqPtr(new(Req))
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return method(srv.(MachineServiceServer), ctx, req.(ReqPtr))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			info := &googlegrpc.UnaryServerInfo{Server: srv, FullMethod: machineapi.FullMethod(name)}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
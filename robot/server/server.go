@@ -76,7 +76,7 @@ func (s *Server) GetOperations(ctx context.Context, req *pb.GetOperationsRequest
 			continue
 		}
 
-		s, err := convertInterfaceToStruct(o.Arguments)
+		s, err := convertInterfaceToStruct(o.ArgumentsSummary())
 		if err != nil {
 			return nil, err
 		}
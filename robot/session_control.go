@@ -0,0 +1,169 @@
+package robot
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/session"
+)
+
+// ClaimControl gives the session exclusive control of the given resources. While a resource is
+// claimed, safety monitored calls to it from any other session, or from no session, are rejected,
+// or queued until it is released if they ask to be.
+// Claims are released by ReleaseControl or when the session expires. If any of the resources is
+// already claimed by another active session, nothing is claimed and an error is returned.
+func (m *SessionManager) ClaimControl(id uuid.UUID, names ...resource.Name) error {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	if _, ok := m.sessions[id]; !ok {
+		return session.ErrNoSession
+	}
+	for _, name := range names {
+		if err := m.checkControlLocked(id, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		m.controlHolders[name] = id
+	}
+	return nil
+}
+
+// ReleaseControl releases the session's exclusive control of the given resources. Resources
+// claimed by other sessions are left untouched.
+func (m *SessionManager) ReleaseControl(id uuid.UUID, names ...resource.Name) {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	for _, name := range names {
+		if m.controlHolders[name] == id {
+			delete(m.controlHolders, name)
+			m.notifyControlReleasedLocked()
+		}
+	}
+}
+
+// WaitForControl waits in line until the resource is no longer claimed by another active session,
+// then claims it for the session if claim is set. It returns the context's error if the resource is
+// still claimed when the context is done.
+func (m *SessionManager) WaitForControl(ctx context.Context, id uuid.UUID, name resource.Name, claim bool) error {
+	for {
+		released, err := func() (<-chan struct{}, error) {
+			m.sessionResourceMu.Lock()
+			defer m.sessionResourceMu.Unlock()
+			if claim {
+				if _, ok := m.sessions[id]; !ok {
+					return nil, session.ErrNoSession
+				}
+			}
+			if m.checkControlLocked(id, name) != nil {
+				return m.controlReleased, nil
+			}
+			if claim {
+				m.controlHolders[name] = id
+			}
+			return nil, nil
+		}()
+		if err != nil || released == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// ControlHolder returns the session that has exclusive control of the resource, if any.
+func (m *SessionManager) ControlHolder(name resource.Name) (uuid.UUID, bool) {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	id, ok := m.controlHolders[name]
+	if !ok {
+		return uuid.Nil, false
+	}
+	if _, active := m.sessions[id]; !active {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// checkControlLocked returns an error if the resource is claimed by an active session other than
+// the given one. The caller must hold sessionResourceMu.
+func (m *SessionManager) checkControlLocked(id uuid.UUID, name resource.Name) error {
+	holder, ok := m.controlHolders[name]
+	if !ok || holder == id {
+		return nil
+	}
+	if _, active := m.sessions[holder]; !active {
+		return nil
+	}
+	return session.NewExclusiveControlError(name)
+}
+
+// releaseAllControlLocked releases every resource claimed by the session. The caller must hold
+// sessionResourceMu for writing.
+func (m *SessionManager) releaseAllControlLocked(id uuid.UUID) {
+	for name, holder := range m.controlHolders {
+		if holder == id {
+			delete(m.controlHolders, name)
+			m.notifyControlReleasedLocked()
+		}
+	}
+}
+
+// notifyControlReleasedLocked wakes up every call waiting for control of a resource. The caller must
+// hold sessionResourceMu for writing.
+func (m *SessionManager) notifyControlReleasedLocked() {
+	close(m.controlReleased)
+	m.controlReleased = make(chan struct{})
+}
+
+// enforceExclusiveControl checks that a safety monitored call from the given session (or uuid.Nil
+// for none) may actuate the resource, and applies any claim or release requested in its metadata.
+// If the call asks to be queued, it waits for the resource to be released instead of failing.
+func (m *SessionManager) enforceExclusiveControl(ctx context.Context, id uuid.UUID, name resource.Name, meta metadata.MD) error {
+	if name == (resource.Name{}) {
+		return nil
+	}
+	var action string
+	if values := meta.Get(session.ExclusiveControlMetadataKey); len(values) != 0 {
+		action = values[0]
+	}
+	queue := len(meta.Get(session.ExclusiveControlQueueMetadataKey)) != 0
+	switch action {
+	case "":
+		if queue {
+			return m.WaitForControl(ctx, id, name, false)
+		}
+		m.sessionResourceMu.RLock()
+		defer m.sessionResourceMu.RUnlock()
+		return m.checkControlLocked(id, name)
+	case session.ExclusiveControlClaim:
+		if id == uuid.Nil {
+			return status.Error(codes.InvalidArgument, "claiming exclusive control requires a session")
+		}
+		if queue {
+			return m.WaitForControl(ctx, id, name, true)
+		}
+		return m.ClaimControl(id, name)
+	case session.ExclusiveControlRelease:
+		m.sessionResourceMu.Lock()
+		defer m.sessionResourceMu.Unlock()
+		if err := m.checkControlLocked(id, name); err != nil {
+			return err
+		}
+		if m.controlHolders[name] == id {
+			delete(m.controlHolders, name)
+			m.notifyControlReleasedLocked()
+		}
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown %s value %q", session.ExclusiveControlMetadataKey, action)
+	}
+}
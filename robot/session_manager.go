@@ -23,6 +23,8 @@ func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManag
 		logger:            robot.Logger().Sublogger("session_manager"),
 		sessions:          map[uuid.UUID]*session.Session{},
 		resourceToSession: map[resource.Name]uuid.UUID{},
+		controlHolders:    map[resource.Name]uuid.UUID{},
+		controlReleased:   make(chan struct{}),
 		cancel:            cancel,
 	}
 	m.activeBackgroundWorkers.Add(1)
@@ -42,6 +44,11 @@ type SessionManager struct {
 
 	resourceToSession map[resource.Name]uuid.UUID
 
+	// controlHolders maps resources to the session that has claimed exclusive control of them.
+	controlHolders map[resource.Name]uuid.UUID
+	// controlReleased is closed, and replaced, whenever exclusive control of a resource is released.
+	controlReleased chan struct{}
+
 	cancel                  func()
 	activeBackgroundWorkers sync.WaitGroup
 }
@@ -91,6 +98,7 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 			defer m.sessionResourceMu.Unlock()
 			for id := range toDelete {
 				delete(m.sessions, id)
+				m.releaseAllControlLocked(id)
 			}
 
 			if len(toStop) == 0 {
@@ -5,9 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/robot"
@@ -87,3 +92,71 @@ func TestSessionManagerExpiredSessions(t *testing.T) {
 			test.ShouldEqual, 1)
 	})
 }
+
+func TestSessionManagerExclusiveControl(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManager(r, time.Second)
+	defer sm.Close()
+
+	baseName := base.Named("base1")
+	armName := arm.Named("arm1")
+
+	fooSess, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+	barSess, err := sm.Start(ctx, "bar")
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, sm.ClaimControl(uuid.New(), baseName), test.ShouldBeError, session.ErrNoSession)
+
+	test.That(t, sm.ClaimControl(fooSess.ID(), baseName), test.ShouldBeNil)
+	holder, ok := sm.ControlHolder(baseName)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, holder, test.ShouldEqual, fooSess.ID())
+
+	// claiming again is idempotent but another session cannot take over, even partially.
+	test.That(t, sm.ClaimControl(fooSess.ID(), baseName), test.ShouldBeNil)
+	err = sm.ClaimControl(barSess.ID(), armName, baseName)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+	_, ok = sm.ControlHolder(armName)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// releasing from the wrong session does nothing.
+	sm.ReleaseControl(barSess.ID(), baseName)
+	_, ok = sm.ControlHolder(baseName)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	sm.ReleaseControl(fooSess.ID(), baseName)
+	_, ok = sm.ControlHolder(baseName)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// queued claims wait until the resource is released.
+	test.That(t, sm.ClaimControl(fooSess.ID(), baseName), test.ShouldBeNil)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	test.That(t, sm.WaitForControl(timeoutCtx, barSess.ID(), baseName, true), test.ShouldBeError, context.DeadlineExceeded)
+	claimed := make(chan error, 1)
+	go func() {
+		claimed <- sm.WaitForControl(ctx, barSess.ID(), baseName, true)
+	}()
+	sm.ReleaseControl(fooSess.ID(), baseName)
+	test.That(t, <-claimed, test.ShouldBeNil)
+	holder, ok = sm.ControlHolder(baseName)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, holder, test.ShouldEqual, barSess.ID())
+	sm.ReleaseControl(barSess.ID(), baseName)
+
+	// control is released when the holding session expires.
+	test.That(t, sm.ClaimControl(barSess.ID(), armName), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		_, ok := sm.ControlHolder(armName)
+		test.That(tb, ok, test.ShouldBeFalse)
+	})
+}
@@ -195,6 +195,9 @@ func associateSession(
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		m.logger.CWarnw(ctx, "failed to pull metadata from context", "method", method)
+		if err := m.enforceExclusiveControl(ctx, uuid.Nil, safetyMonitoredResourceName, nil); err != nil {
+			return nil, err
+		}
 		return ctx, nil
 	}
 	sessID, err = sessionFromMetadata(meta)
@@ -203,6 +206,9 @@ func associateSession(
 		return ctx, err
 	}
 	if sessID == uuid.Nil {
+		if err := m.enforceExclusiveControl(ctx, sessID, safetyMonitoredResourceName, meta); err != nil {
+			return nil, err
+		}
 		return ctx, nil
 	}
 	authEntity, _ := rpc.ContextAuthEntity(ctx)
@@ -210,6 +216,9 @@ func associateSession(
 	if err != nil {
 		return nil, err
 	}
+	if err := m.enforceExclusiveControl(ctx, sessID, safetyMonitoredResourceName, meta); err != nil {
+		return nil, err
+	}
 	return session.ToContext(ctx, sess), nil
 }
 
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func (svc *webService) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		svc.logger.Debugw("failed to write JSON response", "error", err)
+	}
+}
+
+// requireAuth only serves requests which authenticate in the same way as RPCs do: with an access token from
+// the robot's auth service in the Authorization header, e.g. "Bearer <token>", or with a client TLS certificate.
+// Robots without auth handlers serve every request, as they do RPCs.
+func (svc *webService) requireAuth(authenticated bool, handler http.HandlerFunc) http.HandlerFunc {
+	if !authenticated {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rpc.MetadataFieldAuthorization, authorization))
+		}
+		if r.TLS != nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+		}
+		authedCtx, err := svc.rpcServer.EnsureAuthed(ctx)
+		if err != nil {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r.WithContext(authedCtx))
+	}
+}
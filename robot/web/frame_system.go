@@ -0,0 +1,111 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/posetracker"
+	"go.viam.com/rdk/referenceframe/urdf"
+	"go.viam.com/rdk/robot/framesystem"
+)
+
+// frameSystemService returns the robot's frame system service.
+func (svc *webService) frameSystemService() (framesystem.Service, error) {
+	res, err := svc.r.ResourceByName(framesystem.InternalServiceName)
+	if err != nil {
+		return nil, err
+	}
+	fsSvc, ok := res.(framesystem.Service)
+	if !ok {
+		return nil, errors.New("frame system service is unavailable")
+	}
+	return fsSvc, nil
+}
+
+// handleFrameSystemStream streams the transforms of the robot's frames, including the bodies seen
+// by its pose trackers, as newline delimited JSON until the client disconnects. The "interval" query
+// parameter sets the time between updates, e.g. "50ms", and "changed=true" only includes the
+// transforms that changed in every update after the first.
+func (svc *webService) handleFrameSystemStream(w http.ResponseWriter, r *http.Request) {
+	opts := framesystem.TransformStreamOptions{
+		OnError: func(err error) {
+			svc.logger.Debugw("skipped frame system update", "error", err)
+		},
+	}
+	q := r.URL.Query()
+	if intervalStr := q.Get("interval"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			http.Error(w, errors.Wrap(err, "invalid interval").Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Interval = interval
+	}
+	opts.OnlyChanged, _ = strconv.ParseBool(q.Get("changed"))
+
+	fsSvc, err := svc.frameSystemService()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	opts.DynamicFrames = posetracker.RobotDynamicFrames(svc.r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	err = framesystem.StreamTransforms(r.Context(), fsSvc, opts, func(update framesystem.TransformUpdate) error {
+		if err := enc.Encode(framesystem.NewTransformUpdateJSON(update)); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		svc.logger.Debugw("frame system stream ended", "error", err)
+	}
+}
+
+// handleFrameSystemURDF serves the robot's frame system at its current inputs as a URDF file, e.g.
+// for visualization in RViz.
+func (svc *webService) handleFrameSystemURDF(w http.ResponseWriter, r *http.Request) {
+	fsSvc, err := svc.frameSystemService()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fs, err := fsSvc.FrameSystem(r.Context(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// frames which cannot be exported as joints are fixed at their current pose, or at their zero
+	// pose if it cannot be read.
+	inputs, _, err := fsSvc.CurrentInputs(r.Context())
+	if err != nil {
+		svc.logger.Debugw("failed to get current inputs for URDF export", "error", err)
+	}
+	cfg, err := urdf.NewModelFromFrameSystem(fs, inputs, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := xml.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	if _, err := w.Write(append([]byte(xml.Header), data...)); err != nil {
+		svc.logger.Debugw("failed to write URDF response", "error", err)
+	}
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// logBufferFilterFromQuery builds a filter from the "level", "logger", "since" and "limit" query
+// parameters of a request. The logger is the full name of a logger, whose subloggers are included.
+func logBufferFilterFromQuery(r *http.Request) (logging.LogBufferFilter, error) {
+	var filter logging.LogBufferFilter
+	q := r.URL.Query()
+	if levelStr := q.Get("level"); levelStr != "" {
+		level, err := logging.LevelFromString(levelStr)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinLevel = level
+	} else {
+		filter.MinLevel = logging.DEBUG
+	}
+	filter.Logger = q.Get("logger")
+	if sinceStr := q.Get("since"); sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return filter, errors.Wrap(err, "invalid since")
+		}
+		filter.Since = since
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return filter, errors.Wrap(err, "invalid limit")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// logsHandler serves buffered log entries as a JSON array. With "follow=true", it instead streams
+// the matching buffered entries followed by new ones as newline delimited JSON until the client
+// disconnects.
+func (svc *webService) logsHandler(buf *logging.LogBuffer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := logBufferFilterFromQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		follow, _ := strconv.ParseBool(r.URL.Query().Get("follow"))
+		if !follow {
+			entries := buf.Entries(filter)
+			if entries == nil {
+				entries = []logging.BufferedLogEntry{}
+			}
+			svc.writeJSON(w, entries)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		// this ends when the client disconnects or can no longer be written to.
+		_ = buf.Follow(r.Context(), filter, func(entry logging.BufferedLogEntry) error {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/edaniels/zeroconf"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/client"
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+// machineName returns the name this machine is advertised under: its FQDN or, for machines without one such as
+// those not managed by the cloud, its local FQDN or else its host name on the local network.
+func machineName(options weboptions.Options) (string, error) {
+	if options.FQDN != "" {
+		return options.FQDN, nil
+	}
+	if options.LocalFQDN != "" {
+		return options.LocalFQDN, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(hostname, ".local") + ".local", nil
+}
+
+// machineAdvertisement returns the description of this machine, named name, that is broadcast over mDNS.
+func machineAdvertisement(name string, options weboptions.Options) client.MachineAdvertisement {
+	authTypes := make([]string, 0, len(options.Auth.Handlers))
+	for _, handler := range options.Auth.Handlers {
+		authTypes = append(authTypes, string(handler.Type))
+	}
+	protocols := []string{"grpc", "webrtc"}
+	rdkVersion := config.Version
+	if rdkVersion == "" {
+		rdkVersion = "dev"
+	}
+	return client.MachineAdvertisement{
+		Name:       name,
+		LocalName:  options.LocalFQDN,
+		RDKVersion: rdkVersion,
+		APIVersion: client.APIVersion(),
+		AuthTypes:  authTypes,
+		Protocols:  protocols,
+	}
+}
+
+// advertiseMachine registers this machine under client.MachineMDNSService so that LAN
+// discovery clients can find it. Failures are logged and otherwise ignored since mDNS is a
+// convenience and not required to serve.
+func (svc *webService) advertiseMachine(listenerTCPAddr *net.TCPAddr, options weboptions.Options) *zeroconf.Server {
+	if options.DisableMulticastDNS {
+		return nil
+	}
+	mdnsServer, err := registerMachineMDNS(listenerTCPAddr, options, svc.logger)
+	if err != nil {
+		svc.logger.Warnw("failed to advertise machine over mDNS; continuing without it", "error", err)
+		return nil
+	}
+	return mdnsServer
+}
+
+func registerMachineMDNS(
+	listenerTCPAddr *net.TCPAddr,
+	options weboptions.Options,
+	logger logging.Logger,
+) (*zeroconf.Server, error) {
+	name, err := machineName(options)
+	if err != nil {
+		return nil, err
+	}
+	text := machineAdvertisement(name, options).TXTRecords()
+	instance := strings.ReplaceAll(name, ".", "-")
+
+	if !listenerTCPAddr.IP.IsLoopback() {
+		return zeroconf.RegisterDynamic(
+			instance,
+			client.MachineMDNSService,
+			"local.",
+			listenerTCPAddr.Port,
+			text,
+			nil,
+			logger.AsZap(),
+		)
+	}
+
+	// when only listening on loopback, advertise 127.0.0.1 on the loopback interface
+	// so that clients on the same host can still discover us.
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	ifcs, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var loopbackIfaces []net.Interface
+	for _, ifc := range ifcs {
+		if (ifc.Flags&net.FlagUp) != 0 && (ifc.Flags&net.FlagLoopback) != 0 {
+			loopbackIfaces = append(loopbackIfaces, ifc)
+			break
+		}
+	}
+	return zeroconf.RegisterProxy(
+		instance,
+		client.MachineMDNSService,
+		"local.",
+		listenerTCPAddr.Port,
+		hostname,
+		[]string{"127.0.0.1"},
+		text,
+		loopbackIfaces,
+		logger.AsZap(),
+	)
+}
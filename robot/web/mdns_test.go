@@ -0,0 +1,37 @@
+package web
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+func TestMachineName(t *testing.T) {
+	name, err := machineName(weboptions.Options{FQDN: "my-machine.viam.cloud", LocalFQDN: "my-machine.local.viam.cloud"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, name, test.ShouldEqual, "my-machine.viam.cloud")
+
+	name, err = machineName(weboptions.Options{LocalFQDN: "my-machine.local.viam.cloud"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, name, test.ShouldEqual, "my-machine.local.viam.cloud")
+
+	// machines without an FQDN, such as those not managed by the cloud, are named by their host
+	hostname, err := os.Hostname()
+	test.That(t, err, test.ShouldBeNil)
+	name, err = machineName(weboptions.Options{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, name, test.ShouldEqual, strings.TrimSuffix(hostname, ".local")+".local")
+	test.That(t, machineAdvertisement(name, weboptions.Options{}).Name, test.ShouldEqual, name)
+}
+
+func TestAdvertiseMachineDisabled(t *testing.T) {
+	svc := &webService{logger: logging.NewTestLogger(t)}
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}
+	test.That(t, svc.advertiseMachine(addr, weboptions.Options{DisableMulticastDNS: true}), test.ShouldBeNil)
+}
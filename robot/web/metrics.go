@@ -0,0 +1,97 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"go.viam.com/rdk/internal/metrics"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+var resourceHealthDesc = prometheus.NewDesc(
+	"viam_resource_healthy",
+	"Whether a configured resource is currently available (1) or in an error or pending state (0).",
+	[]string{"resource"},
+	nil,
+)
+
+var resourceStatDesc = prometheus.NewDesc(
+	"viam_resource_stat",
+	"Custom statistics reported by resources that implement resource.Statser.",
+	[]string{"resource", "stat"},
+	nil,
+)
+
+// resourceHealthCollector reports the health of every resource known to a robot at scrape time.
+type resourceHealthCollector struct {
+	r robot.Robot
+}
+
+func (c *resourceHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceHealthDesc
+}
+
+func (c *resourceHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	names := map[resource.Name]struct{}{}
+	for _, name := range c.r.ResourceNames() {
+		names[name] = struct{}{}
+	}
+	// resources that failed to build are absent from ResourceNames, so also
+	// consult the config to report them as unhealthy.
+	if localRobot, ok := c.r.(robot.LocalRobot); ok {
+		if cfg := localRobot.Config(); cfg != nil {
+			for _, conf := range cfg.Components {
+				names[conf.ResourceName()] = struct{}{}
+			}
+			for _, conf := range cfg.Services {
+				names[conf.ResourceName()] = struct{}{}
+			}
+		}
+	}
+	for name := range names {
+		healthy := 0.0
+		if _, err := c.r.ResourceByName(name); err == nil {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(resourceHealthDesc, prometheus.GaugeValue, healthy, name.String())
+	}
+}
+
+// resourceStatsCollector reports the stats of the resources which implement resource.Statser, as last
+// sampled by the robot, so that scrapes do not call into the resources or their modules.
+type resourceStatsCollector struct {
+	r robot.Robot
+}
+
+// resourceStatsSampler is implemented by robots which sample the stats of their resources, such as
+// robot.LocalRobot.
+type resourceStatsSampler interface {
+	ResourceStats() map[resource.Name]map[string]float64
+}
+
+func (c *resourceStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceStatDesc
+}
+
+func (c *resourceStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	sampler, ok := c.r.(resourceStatsSampler)
+	if !ok {
+		return
+	}
+	for name, stats := range sampler.ResourceStats() {
+		for stat, value := range stats {
+			ch <- prometheus.MustNewConstMetric(resourceStatDesc, prometheus.GaugeValue, value, name.String(), stat)
+		}
+	}
+}
+
+// metricsHandler serves the process-wide metrics along with the health and custom statistics of
+// this robot's resources in the Prometheus exposition format.
+func (svc *webService) metricsHandler() http.Handler {
+	robotRegistry := prometheus.NewRegistry()
+	robotRegistry.MustRegister(&resourceHealthCollector{r: svc.r}, &resourceStatsCollector{r: svc.r})
+	return promhttp.HandlerFor(prometheus.Gatherers{metrics.Registry, robotRegistry}, promhttp.HandlerOpts{})
+}
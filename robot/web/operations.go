@@ -0,0 +1,56 @@
+package web
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// operationJSON is the JSON representation of a running operation.
+type operationJSON struct {
+	ID        string        `json:"id"`
+	SessionID string        `json:"session_id,omitempty"`
+	Method    string        `json:"method"`
+	Arguments interface{}   `json:"arguments,omitempty"`
+	Started   time.Time     `json:"started"`
+	Progress  *progressJSON `json:"progress,omitempty"`
+}
+
+type progressJSON struct {
+	Percent float64   `json:"percent"`
+	Phase   string    `json:"phase,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// handleOperations lists the robot's running operations along with their arguments and any
+// progress they have reported, oldest first.
+func (svc *webService) handleOperations(w http.ResponseWriter, r *http.Request) {
+	ops := svc.r.OperationManager().All()
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Started.Before(ops[j].Started)
+	})
+
+	out := make([]operationJSON, 0, len(ops))
+	for _, op := range ops {
+		opJSON := operationJSON{
+			ID:        op.ID.String(),
+			Method:    op.Method,
+			Arguments: op.ArgumentsSummary(),
+			Started:   op.Started,
+		}
+		if op.SessionID != uuid.Nil {
+			opJSON.SessionID = op.SessionID.String()
+		}
+		if progress := op.Progress(); !progress.Updated.IsZero() {
+			opJSON.Progress = &progressJSON{
+				Percent: progress.Percent,
+				Phase:   progress.Phase,
+				Updated: progress.Updated,
+			}
+		}
+		out = append(out, opJSON)
+	}
+	svc.writeJSON(w, out)
+}
@@ -12,6 +12,7 @@ import (
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/utils"
 )
 
@@ -69,6 +70,9 @@ type Options struct {
 	WebRTCOnPeerRemoved func(pc *webrtc.PeerConnection)
 
 	DisableMulticastDNS bool
+
+	// LogBuffer, if set, holds recent log entries that are served at /debug/logs.
+	LogBuffer *logging.LogBuffer
 }
 
 // New returns a default set of options which will have the
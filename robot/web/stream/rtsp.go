@@ -0,0 +1,792 @@
+//go:build !no_cgo
+
+package webstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	rtspVersion      = "RTSP/1.0"
+	rtspPayloadType  = 96
+	rtspClockRate    = 90000
+	rtspMTU          = 1200
+	rtspTrackControl = "trackID=0"
+	rtspWriteTimeout = 5 * time.Second
+	rtspRealm        = "viam"
+	// rtspMaxFrameRate caps the rate at which frames are read from sources which do not report theirs.
+	rtspMaxFrameRate = 30
+	// rtspClientFrameBuffer is the number of encoded frames buffered for a client. Clients which fall
+	// further behind skip frames until the next key frame.
+	rtspClientFrameBuffer = 8
+	// DefaultRTSPMaxClients is the number of clients an RTSP server accepts at once by default.
+	DefaultRTSPMaxClients = 16
+	// DefaultRTSPSessionTimeout is how long an RTSP server waits for a client to send anything, including the
+	// RTCP reports of a client which is playing a stream, before it closes the connection.
+	DefaultRTSPSessionTimeout = 60 * time.Second
+	// DefaultRTSPHandshakeTimeout is how long an RTSP server gives a client after it connects to make a request
+	// which it is authorized to make, other than OPTIONS, before it closes the connection.
+	DefaultRTSPHandshakeTimeout = 10 * time.Second
+)
+
+// RTSPOptions configure an RTSP server.
+type RTSPOptions struct {
+	// Authenticate checks the username and password a client sends with basic authentication. If nil,
+	// clients are not authenticated.
+	Authenticate func(ctx context.Context, username, password string) error
+	// MaxClients is the number of clients served at once. Further connections are closed as they are
+	// accepted. Zero means DefaultRTSPMaxClients.
+	MaxClients int
+	// SessionTimeout is how long an idle client is kept. Zero means DefaultRTSPSessionTimeout.
+	SessionTimeout time.Duration
+	// HandshakeTimeout is how long a client which has not been authorized is kept, so that such clients
+	// can't hold on to the connections MaxClients allows. Zero means DefaultRTSPHandshakeTimeout.
+	HandshakeTimeout time.Duration
+}
+
+// RTSPServer serves the video sources added to it as RTSP streams at rtsp://<address>/<name>, so that
+// recorders and video tools can play them without a WebRTC client. Video is encoded with H264 and
+// sent as RTP over the RTSP connection, which every RTSP client supports. Each source is encoded once,
+// while it has clients, and the encoded video is sent to all of them.
+type RTSPServer struct {
+	listener       net.Listener
+	encoderFactory codec.VideoEncoderFactory
+	authenticate   func(ctx context.Context, username, password string) error
+	maxClients     int
+	// sessionTimeout and handshakeTimeout are as in RTSPOptions.
+	sessionTimeout   time.Duration
+	handshakeTimeout time.Duration
+	logger           logging.Logger
+
+	mu      sync.Mutex
+	sources map[string]*rtspSource
+	conns   map[net.Conn]struct{}
+
+	cancelCtx               context.Context
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+// rtspSource is a video source served by the server, along with the clients playing it.
+type rtspSource struct {
+	name        string
+	source      gostream.VideoSource
+	subscribers map[*rtspSubscriber]struct{}
+	// encoding is the encoding of the source in progress, if it has clients.
+	encoding *rtspEncoding
+}
+
+// rtspEncoding is a run of the encoder of a source, which stops when the source has no clients left.
+type rtspEncoding struct {
+	cancel func()
+}
+
+// rtspSubscriber receives the encoded frames of a source for a client.
+type rtspSubscriber struct {
+	source *rtspSource
+	// frames is closed when the source stops being served.
+	frames chan rtspFrame
+	// waitKeyFrame is whether the client has not been sent a frame since it subscribed or since it
+	// fell behind, so that frames can only be decoded from the next key frame.
+	waitKeyFrame bool
+}
+
+// rtspFrame is an encoded H264 access unit.
+type rtspFrame struct {
+	data []byte
+	// timestamp is the time the frame was read, in units of the RTP clock since the encoding started.
+	timestamp uint32
+	keyFrame  bool
+}
+
+// NewRTSPServer returns an RTSP server which accepts connections on the listener and encodes video with
+// the H264 encoders made by the factory.
+func NewRTSPServer(
+	listener net.Listener,
+	encoderFactory codec.VideoEncoderFactory,
+	opts RTSPOptions,
+	logger logging.Logger,
+) (*RTSPServer, error) {
+	if mimeType := encoderFactory.MIMEType(); !strings.EqualFold(mimeType, "video/H264") {
+		return nil, errors.Errorf("RTSP streams must be encoded with H264, not %q", mimeType)
+	}
+	maxClients := opts.MaxClients
+	if maxClients <= 0 {
+		maxClients = DefaultRTSPMaxClients
+	}
+	sessionTimeout := opts.SessionTimeout
+	if sessionTimeout <= 0 {
+		sessionTimeout = DefaultRTSPSessionTimeout
+	}
+	handshakeTimeout := opts.HandshakeTimeout
+	if handshakeTimeout <= 0 {
+		handshakeTimeout = DefaultRTSPHandshakeTimeout
+	}
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	s := &RTSPServer{
+		listener:         listener,
+		encoderFactory:   encoderFactory,
+		authenticate:     opts.Authenticate,
+		maxClients:       maxClients,
+		sessionTimeout:   sessionTimeout,
+		handshakeTimeout: handshakeTimeout,
+		logger:           logger,
+		sources:          map[string]*rtspSource{},
+		conns:            map[net.Conn]struct{}{},
+		cancelCtx:        cancelCtx,
+		cancel:           cancel,
+	}
+	s.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(s.serve, s.activeBackgroundWorkers.Done)
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *RTSPServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// AddSource serves the video source at the name. If a different source was served at the name, it is
+// replaced and the clients playing it are disconnected.
+func (s *RTSPServer) AddSource(name string, source gostream.VideoSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.sources[name]; ok {
+		if existing.source == source {
+			return
+		}
+		existing.stop()
+	}
+	s.sources[name] = &rtspSource{name: name, source: source, subscribers: map[*rtspSubscriber]struct{}{}}
+}
+
+// RemoveSource stops serving the video source at the name, disconnecting the clients playing it.
+func (s *RTSPServer) RemoveSource(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.sources[name]; ok {
+		existing.stop()
+		delete(s.sources, name)
+	}
+}
+
+// SourceNames returns the names of the sources being served.
+func (s *RTSPServer) SourceNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Close stops the server and disconnects its clients.
+func (s *RTSPServer) Close() error {
+	s.cancel()
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		utils.UncheckedError(conn.Close())
+	}
+	s.mu.Unlock()
+	s.activeBackgroundWorkers.Wait()
+	return err
+}
+
+func (s *RTSPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.cancelCtx.Err() == nil {
+				s.logger.Errorw("error accepting RTSP connection", "error", err)
+			}
+			return
+		}
+		s.mu.Lock()
+		if s.cancelCtx.Err() != nil {
+			s.mu.Unlock()
+			utils.UncheckedError(conn.Close())
+			return
+		}
+		if len(s.conns) >= s.maxClients {
+			s.mu.Unlock()
+			s.logger.Debugw("refusing RTSP connection since the server has too many clients",
+				"remote", conn.RemoteAddr().String(), "max_clients", s.maxClients)
+			utils.UncheckedError(conn.Close())
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.activeBackgroundWorkers.Add(1)
+		s.mu.Unlock()
+		utils.PanicCapturingGo(func() {
+			defer s.activeBackgroundWorkers.Done()
+			newRTSPConn(s, conn).serve()
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		})
+	}
+}
+
+func (s *RTSPServer) hasSource(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sources[name]
+	return ok
+}
+
+// authenticated returns whether a request carries credentials the server accepts.
+func (s *RTSPServer) authenticated(req *rtspRequest) bool {
+	if s.authenticate == nil {
+		return true
+	}
+	username, password, ok := (&http.Request{Header: http.Header(req.header)}).BasicAuth()
+	if !ok {
+		return false
+	}
+	if err := s.authenticate(s.cancelCtx, username, password); err != nil {
+		s.logger.Debugw("RTSP client failed to authenticate", "username", username, "error", err)
+		return false
+	}
+	return true
+}
+
+// subscribe adds a subscriber to the frames of the source at the name, starting to encode it if it is
+// not being encoded already.
+func (s *RTSPServer) subscribe(name string) (*rtspSubscriber, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src, ok := s.sources[name]
+	if !ok || s.cancelCtx.Err() != nil {
+		return nil, false
+	}
+	sub := &rtspSubscriber{source: src, frames: make(chan rtspFrame, rtspClientFrameBuffer), waitKeyFrame: true}
+	src.subscribers[sub] = struct{}{}
+	if src.encoding == nil {
+		ctx, cancel := context.WithCancel(s.cancelCtx)
+		encoding := &rtspEncoding{cancel: cancel}
+		src.encoding = encoding
+		s.activeBackgroundWorkers.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer s.activeBackgroundWorkers.Done()
+			s.encode(ctx, src, encoding)
+		})
+	}
+	return sub, true
+}
+
+// unsubscribe removes a subscriber, and stops encoding its source if it has no subscribers left.
+func (s *RTSPServer) unsubscribe(sub *rtspSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src := sub.source
+	if _, ok := src.subscribers[sub]; !ok {
+		return
+	}
+	delete(src.subscribers, sub)
+	if len(src.subscribers) == 0 && src.encoding != nil {
+		src.encoding.cancel()
+		src.encoding = nil
+	}
+}
+
+// stop stops encoding the source and ends the streams of its subscribers. The server's lock must be held.
+func (src *rtspSource) stop() {
+	if src.encoding != nil {
+		src.encoding.cancel()
+		src.encoding = nil
+	}
+	for sub := range src.subscribers {
+		close(sub.frames)
+		delete(src.subscribers, sub)
+	}
+}
+
+// encode encodes the frames of a source and sends them to its subscribers until the context is done.
+// If the source cannot be encoded, the streams of its subscribers are ended.
+func (s *RTSPServer) encode(ctx context.Context, src *rtspSource, encoding *rtspEncoding) {
+	if err := s.encodeFrames(ctx, src, encoding); err != nil && ctx.Err() == nil {
+		s.logger.Debugw("stopped encoding RTSP stream", "name", src.name, "error", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src.encoding == encoding {
+		src.stop()
+	}
+}
+
+func (s *RTSPServer) encodeFrames(ctx context.Context, src *rtspSource, encoding *rtspEncoding) error {
+	frameRate := float32(rtspMaxFrameRate)
+	if provider, ok := src.source.(gostream.VideoPropertyProvider); ok {
+		if props, err := provider.MediaProperties(ctx); err == nil && props.FrameRate > 0 {
+			frameRate = props.FrameRate
+		}
+	}
+	stream, err := src.source.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(stream.Close(context.Background()))
+	}()
+
+	var encoder codec.VideoEncoder
+	var encoderBounds struct{ width, height int }
+	defer func() {
+		if encoder != nil {
+			utils.UncheckedError(encoder.Close())
+		}
+	}()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / float64(frameRate)))
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		img, release, err := stream.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.logger.Debugw("error reading frame for RTSP stream", "name", src.name, "error", err)
+			continue
+		}
+		timestamp := uint32(time.Since(start).Seconds() * rtspClockRate)
+
+		bounds := img.Bounds()
+		if encoder == nil || encoderBounds.width != bounds.Dx() || encoderBounds.height != bounds.Dy() {
+			if encoder != nil {
+				utils.UncheckedError(encoder.Close())
+			}
+			encoder, err = s.encoderFactory.New(bounds.Dx(), bounds.Dy(), int(math.Ceil(float64(frameRate))), s.logger.AsZap())
+			if err != nil {
+				if release != nil {
+					release()
+				}
+				encoder = nil
+				return err
+			}
+			encoderBounds.width, encoderBounds.height = bounds.Dx(), bounds.Dy()
+		}
+		data, err := encoder.Encode(ctx, img)
+		if release != nil {
+			release()
+		}
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			continue
+		}
+		s.publish(src, encoding, rtspFrame{data: data, timestamp: timestamp, keyFrame: isH264KeyFrame(data)})
+	}
+}
+
+// publish sends a frame to the subscribers of a source. Subscribers which have not kept up skip frames
+// until the next key frame rather than holding up the others.
+func (s *RTSPServer) publish(src *rtspSource, encoding *rtspEncoding, frame rtspFrame) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src.encoding != encoding {
+		return
+	}
+	for sub := range src.subscribers {
+		if sub.waitKeyFrame && !frame.keyFrame {
+			continue
+		}
+		select {
+		case sub.frames <- frame:
+			sub.waitKeyFrame = false
+		default:
+			sub.waitKeyFrame = true
+		}
+	}
+}
+
+// isH264KeyFrame returns whether an H264 access unit in Annex B format holds a parameter set or an IDR
+// slice, which a decoder can start from.
+func isH264KeyFrame(data []byte) bool {
+	for i := 0; i+3 < len(data); i++ {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			continue
+		}
+		switch data[i+3] & 0x1f {
+		case 5, 7:
+			return true
+		}
+	}
+	return false
+}
+
+// rtspRequest is a request of an RTSP client.
+type rtspRequest struct {
+	method string
+	url    *url.URL
+	header textproto.MIMEHeader
+}
+
+// rtspResponse is the response to an RTSP request.
+type rtspResponse struct {
+	status int
+	reason string
+	header map[string]string
+	body   string
+}
+
+// rtspConn is the connection of an RTSP client, which plays a single stream.
+type rtspConn struct {
+	server *RTSPServer
+	conn   net.Conn
+	reader *bufio.Reader
+	// handshakeDeadline is when the connection is closed if the client has not been authorized by then.
+	handshakeDeadline time.Time
+	// authorized is whether the client has made a request, other than OPTIONS, which it was authorized to make.
+	// Until then, it is kept until the handshake deadline; after, until it is idle for the session timeout.
+	authorized bool
+
+	writeMu sync.Mutex
+
+	session    string
+	streamName string
+	channel    byte
+
+	subscriber  *rtspSubscriber
+	cancelPlay  func()
+	playWorkers sync.WaitGroup
+}
+
+func newRTSPConn(server *RTSPServer, conn net.Conn) *rtspConn {
+	return &rtspConn{
+		server:            server,
+		conn:              conn,
+		reader:            bufio.NewReader(conn),
+		handshakeDeadline: time.Now().Add(server.handshakeTimeout),
+	}
+}
+
+func (c *rtspConn) serve() {
+	defer func() {
+		c.stopPlaying()
+		utils.UncheckedError(c.conn.Close())
+	}()
+	for {
+		req, err := c.readRequest()
+		if err != nil {
+			var netErr net.Error
+			switch {
+			case errors.As(err, &netErr) && netErr.Timeout():
+				c.server.logger.Debugw("closing idle RTSP connection", "remote", c.conn.RemoteAddr().String(), "authorized", c.authorized)
+			case !errors.Is(err, io.EOF) && c.server.cancelCtx.Err() == nil:
+				c.server.logger.Debugw("error reading RTSP request", "error", err)
+			}
+			return
+		}
+		allowed := req.method == "OPTIONS"
+		if !allowed && c.server.authenticated(req) {
+			allowed = true
+			c.authorized = true
+		}
+		var resp rtspResponse
+		if allowed {
+			resp = c.handle(req)
+		} else {
+			resp = rtspResponse{status: 401, reason: "Unauthorized", header: map[string]string{
+				"WWW-Authenticate": fmt.Sprintf("Basic realm=%q", rtspRealm),
+			}}
+		}
+		if resp.header == nil {
+			resp.header = map[string]string{}
+		}
+		resp.header["CSeq"] = req.header.Get("CSeq")
+		if c.session != "" {
+			resp.header["Session"] = c.session + ";timeout=" + strconv.Itoa(int(math.Ceil(c.server.sessionTimeout.Seconds())))
+		}
+		if err := c.writeResponse(resp); err != nil {
+			return
+		}
+		switch req.method {
+		case "PLAY":
+			if resp.status == 200 {
+				c.play()
+			}
+		case "TEARDOWN":
+			return
+		}
+	}
+}
+
+// readRequest reads the next request, skipping the RTP and RTCP packets the client interleaves with
+// its requests.
+func (c *rtspConn) readRequest() (*rtspRequest, error) {
+	for {
+		// anything the client sends keeps its session alive, including the RTCP reports of a client which is
+		// playing a stream.
+		deadline := c.handshakeDeadline
+		if c.authorized {
+			deadline = time.Now().Add(c.server.sessionTimeout)
+		}
+		if err := c.conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		first, err := c.reader.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if first[0] != '$' {
+			break
+		}
+		var header [4]byte
+		if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+			return nil, err
+		}
+		if _, err := c.reader.Discard(int(binary.BigEndian.Uint16(header[2:]))); err != nil {
+			return nil, err
+		}
+	}
+
+	tp := textproto.NewReader(c.reader)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Fields(line)
+	if len(parts) != 3 || parts[2] != rtspVersion {
+		return nil, errors.Errorf("malformed RTSP request line %q", line)
+	}
+	u, err := url.Parse(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length > 0 {
+		if _, err := c.reader.Discard(length); err != nil {
+			return nil, err
+		}
+	}
+	return &rtspRequest{method: parts[0], url: u, header: header}, nil
+}
+
+func (c *rtspConn) writeResponse(resp rtspResponse) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d %s\r\n", rtspVersion, resp.status, resp.reason)
+	for key, value := range resp.header {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, value)
+	}
+	if resp.body != "" {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n", len(resp.body))
+	}
+	b.WriteString("\r\n")
+	b.WriteString(resp.body)
+	return c.write([]byte(b.String()))
+}
+
+func (c *rtspConn) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.conn.SetWriteDeadline(time.Now().Add(rtspWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(data)
+	return err
+}
+
+// streamName returns the name of the stream a url refers to, along with whether it refers to the
+// stream's track rather than the stream.
+func streamName(u *url.URL) (string, bool) {
+	name := strings.Trim(u.Path, "/")
+	if trimmed := strings.TrimSuffix(name, "/"+rtspTrackControl); trimmed != name {
+		return trimmed, true
+	}
+	return name, false
+}
+
+func (c *rtspConn) handle(req *rtspRequest) rtspResponse {
+	switch req.method {
+	case "OPTIONS":
+		return rtspResponse{status: 200, reason: "OK", header: map[string]string{
+			"Public": "OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN, GET_PARAMETER",
+		}}
+	case "DESCRIBE":
+		name, _ := streamName(req.url)
+		if !c.server.hasSource(name) {
+			return rtspResponse{status: 404, reason: "Not Found"}
+		}
+		base := *req.url
+		base.Path = "/" + name + "/"
+		return rtspResponse{
+			status: 200,
+			reason: "OK",
+			header: map[string]string{"Content-Base": base.String(), "Content-Type": "application/sdp"},
+			body:   sessionDescription(name),
+		}
+	case "SETUP":
+		return c.setup(req)
+	case "PLAY":
+		if c.session == "" {
+			return rtspResponse{status: 455, reason: "Method Not Valid in This State"}
+		}
+		if c.subscriber == nil {
+			subscriber, ok := c.server.subscribe(c.streamName)
+			if !ok {
+				return rtspResponse{status: 404, reason: "Not Found"}
+			}
+			c.subscriber = subscriber
+		}
+		return rtspResponse{status: 200, reason: "OK"}
+	case "TEARDOWN", "GET_PARAMETER":
+		return rtspResponse{status: 200, reason: "OK"}
+	default:
+		return rtspResponse{status: 501, reason: "Not Implemented"}
+	}
+}
+
+// sessionDescription describes a stream as a single H264 track. The parameter sets of the video are
+// sent along with its key frames rather than described here.
+func sessionDescription(name string) string {
+	return strings.Join([]string{
+		"v=0",
+		"o=- 0 0 IN IP4 0.0.0.0",
+		"s=" + name,
+		"c=IN IP4 0.0.0.0",
+		"t=0 0",
+		"m=video 0 RTP/AVP " + strconv.Itoa(rtspPayloadType),
+		"a=rtpmap:" + strconv.Itoa(rtspPayloadType) + " H264/" + strconv.Itoa(rtspClockRate),
+		"a=fmtp:" + strconv.Itoa(rtspPayloadType) + " packetization-mode=1",
+		"a=control:" + rtspTrackControl,
+	}, "\r\n") + "\r\n"
+}
+
+func (c *rtspConn) setup(req *rtspRequest) rtspResponse {
+	if c.session != "" {
+		return rtspResponse{status: 459, reason: "Aggregate Operation Not Allowed"}
+	}
+	name, _ := streamName(req.url)
+	if !c.server.hasSource(name) {
+		return rtspResponse{status: 404, reason: "Not Found"}
+	}
+
+	// only RTP over the RTSP connection is supported, and clients fall back to it when UDP is refused
+	transport := req.header.Get("Transport")
+	if !strings.Contains(transport, "RTP/AVP/TCP") {
+		return rtspResponse{status: 461, reason: "Unsupported Transport"}
+	}
+	channel := 0
+	for _, param := range strings.Split(transport, ";") {
+		if interleaved, ok := strings.CutPrefix(param, "interleaved="); ok {
+			first, _, _ := strings.Cut(interleaved, "-")
+			var err error
+			if channel, err = strconv.Atoi(first); err != nil || channel < 0 || channel > 254 {
+				return rtspResponse{status: 400, reason: "Bad Request"}
+			}
+		}
+	}
+
+	c.streamName = name
+	c.channel = byte(channel)
+	//nolint:gosec
+	c.session = strconv.FormatUint(rand.Uint64(), 16)
+	return rtspResponse{status: 200, reason: "OK", header: map[string]string{
+		"Transport": fmt.Sprintf("RTP/AVP/TCP;unicast;interleaved=%d-%d", channel, channel+1),
+	}}
+}
+
+// play starts sending the stream to the client.
+func (c *rtspConn) play() {
+	if c.cancelPlay != nil || c.subscriber == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(c.server.cancelCtx)
+	c.cancelPlay = cancel
+	c.playWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer c.playWorkers.Done()
+		if err := c.stream(ctx); err != nil && ctx.Err() == nil {
+			c.server.logger.Debugw("stopped RTSP stream", "name", c.streamName, "error", err)
+			// the client cannot recover the stream without reconnecting
+			utils.UncheckedError(c.conn.Close())
+		}
+	})
+}
+
+func (c *rtspConn) stopPlaying() {
+	if c.cancelPlay != nil {
+		c.cancelPlay()
+	}
+	c.playWorkers.Wait()
+	if c.subscriber != nil {
+		c.server.unsubscribe(c.subscriber)
+	}
+}
+
+// stream sends the encoded frames of the stream to the client until the context is done, the stream
+// ends or sending fails.
+func (c *rtspConn) stream(ctx context.Context) error {
+	//nolint:gosec
+	packetizer := rtp.NewPacketizer(rtspMTU, rtspPayloadType, rand.Uint32(), &codecs.H264Payloader{},
+		rtp.NewRandomSequencer(), rtspClockRate)
+	// RTP timestamps start at a random offset
+	//nolint:gosec
+	timestampOffset := rand.Uint32()
+	for {
+		var frame rtspFrame
+		select {
+		case <-ctx.Done():
+			return nil
+		case next, ok := <-c.subscriber.frames:
+			if !ok {
+				return errors.New("stream is no longer served")
+			}
+			frame = next
+		}
+		for _, packet := range packetizer.Packetize(frame.data, 0) {
+			packet.Timestamp = timestampOffset + frame.timestamp
+			if err := c.writePacket(packet); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writePacket sends an RTP packet interleaved with the RTSP messages of the connection.
+func (c *rtspConn) writePacket(packet *rtp.Packet) error {
+	payload, err := packet.Marshal()
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 4, 4+len(payload))
+	frame[0] = '$'
+	frame[1] = c.channel
+	binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	return c.write(append(frame, payload...))
+}
@@ -0,0 +1,369 @@
+package webstream_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pion/mediadevices/pkg/prop"
+	"github.com/pion/rtp"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
+	"go.viam.com/rdk/logging"
+	webstream "go.viam.com/rdk/robot/web/stream"
+)
+
+// annexBFrame is an H264 access unit with a parameter set and a slice.
+var annexBFrame = []byte{0, 0, 0, 1, 0x67, 0x42, 0x00, 0x1f, 0, 0, 0, 1, 0x65, 0x88, 0x84, 0x00}
+
+type fakeEncoder struct{}
+
+func (e *fakeEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	return annexBFrame, nil
+}
+
+func (e *fakeEncoder) Close() error {
+	return nil
+}
+
+type fakeEncoderFactory struct {
+	mimeType string
+	encoders atomic.Int32
+}
+
+func (f *fakeEncoderFactory) New(width, height, keyFrameInterval int, logger golog.Logger) (codec.VideoEncoder, error) {
+	f.encoders.Add(1)
+	return &fakeEncoder{}, nil
+}
+
+func (f *fakeEncoderFactory) MIMEType() string {
+	return f.mimeType
+}
+
+// rtspClient sends RTSP requests and reads the responses and interleaved packets of the server.
+type rtspClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	cseq   int
+}
+
+func (c *rtspClient) do(t *testing.T, method, url string, header map[string]string) (int, textproto.MIMEHeader, string) {
+	t.Helper()
+	c.cseq++
+	req := fmt.Sprintf("%s %s RTSP/1.0\r\nCSeq: %d\r\n", method, url, c.cseq)
+	for key, value := range header {
+		req += key + ": " + value + "\r\n"
+	}
+	_, err := c.conn.Write([]byte(req + "\r\n"))
+	test.That(t, err, test.ShouldBeNil)
+
+	tp := textproto.NewReader(c.reader)
+	line, err := tp.ReadLine()
+	test.That(t, err, test.ShouldBeNil)
+	parts := strings.SplitN(line, " ", 3)
+	test.That(t, parts[0], test.ShouldEqual, "RTSP/1.0")
+	status, err := strconv.Atoi(parts[1])
+	test.That(t, err, test.ShouldBeNil)
+	respHeader, err := tp.ReadMIMEHeader()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, respHeader.Get("CSeq"), test.ShouldEqual, strconv.Itoa(c.cseq))
+	var body []byte
+	if length := respHeader.Get("Content-Length"); length != "" {
+		n, err := strconv.Atoi(length)
+		test.That(t, err, test.ShouldBeNil)
+		body = make([]byte, n)
+		_, err = io.ReadFull(c.reader, body)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	return status, respHeader, string(body)
+}
+
+func (c *rtspClient) readPacket(t *testing.T) (byte, *rtp.Packet) {
+	t.Helper()
+	var header [4]byte
+	_, err := io.ReadFull(c.reader, header[:])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, header[0], test.ShouldEqual, byte('$'))
+	data := make([]byte, binary.BigEndian.Uint16(header[2:]))
+	_, err = io.ReadFull(c.reader, data)
+	test.That(t, err, test.ShouldBeNil)
+	var packet rtp.Packet
+	test.That(t, packet.Unmarshal(data), test.ShouldBeNil)
+	return header[1], &packet
+}
+
+// readFrame reads the packets of the next access unit sent to the client.
+func (c *rtspClient) readFrame(t *testing.T, channel byte) []byte {
+	t.Helper()
+	var payload []byte
+	for {
+		packetChannel, packet := c.readPacket(t)
+		test.That(t, packetChannel, test.ShouldEqual, channel)
+		test.That(t, packet.PayloadType, test.ShouldEqual, uint8(96))
+		payload = append(payload, packet.Payload...)
+		if packet.Marker {
+			return payload
+		}
+	}
+}
+
+func dialRTSP(t *testing.T, server *webstream.RTSPServer) *rtspClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", server.Addr().String())
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return &rtspClient{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// play sets up and plays the stream at the url over channel 0.
+func (c *rtspClient) play(t *testing.T, url string, header map[string]string) {
+	t.Helper()
+	setupHeader := map[string]string{"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1"}
+	for key, value := range header {
+		setupHeader[key] = value
+	}
+	status, respHeader, _ := c.do(t, "SETUP", url+"/trackID=0", setupHeader)
+	test.That(t, status, test.ShouldEqual, 200)
+	session, _, _ := strings.Cut(respHeader.Get("Session"), ";")
+	playHeader := map[string]string{"Session": session}
+	for key, value := range header {
+		playHeader[key] = value
+	}
+	status, _, _ = c.do(t, "PLAY", url, playHeader)
+	test.That(t, status, test.ShouldEqual, 200)
+}
+
+func newTestVideoSource() gostream.VideoSource {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	return gostream.NewVideoSource(gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		return img, func() {}, nil
+	}), prop.Video{})
+}
+
+func TestRTSPServer(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	_, err := webstream.NewRTSPServer(nil, &fakeEncoderFactory{mimeType: "video/VP8"}, webstream.RTSPOptions{}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "H264")
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	encoderFactory := &fakeEncoderFactory{mimeType: "video/H264"}
+	server, err := webstream.NewRTSPServer(listener, encoderFactory, webstream.RTSPOptions{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	server.AddSource("cam", newTestVideoSource())
+
+	client := dialRTSP(t, server)
+	url := "rtsp://" + server.Addr().String()
+
+	status, header, _ := client.do(t, "OPTIONS", url+"/cam", nil)
+	test.That(t, status, test.ShouldEqual, 200)
+	test.That(t, header.Get("Public"), test.ShouldContainSubstring, "DESCRIBE")
+
+	status, _, _ = client.do(t, "DESCRIBE", url+"/unknown", nil)
+	test.That(t, status, test.ShouldEqual, 404)
+
+	status, header, sdp := client.do(t, "DESCRIBE", url+"/cam", nil)
+	test.That(t, status, test.ShouldEqual, 200)
+	test.That(t, header.Get("Content-Type"), test.ShouldEqual, "application/sdp")
+	test.That(t, header.Get("Content-Base"), test.ShouldEqual, url+"/cam/")
+	test.That(t, sdp, test.ShouldContainSubstring, "a=rtpmap:96 H264/90000")
+	test.That(t, sdp, test.ShouldContainSubstring, "a=control:trackID=0")
+
+	status, _, _ = client.do(t, "PLAY", url+"/cam", nil)
+	test.That(t, status, test.ShouldEqual, 455)
+
+	status, _, _ = client.do(t, "SETUP", url+"/cam/trackID=0", map[string]string{
+		"Transport": "RTP/AVP;unicast;client_port=5000-5001",
+	})
+	test.That(t, status, test.ShouldEqual, 461)
+
+	status, header, _ = client.do(t, "SETUP", url+"/cam/trackID=0", map[string]string{
+		"Transport": "RTP/AVP/TCP;unicast;interleaved=2-3",
+	})
+	test.That(t, status, test.ShouldEqual, 200)
+	test.That(t, header.Get("Transport"), test.ShouldEqual, "RTP/AVP/TCP;unicast;interleaved=2-3")
+	session, _, _ := strings.Cut(header.Get("Session"), ";")
+	test.That(t, session, test.ShouldNotBeEmpty)
+
+	status, _, _ = client.do(t, "PLAY", url+"/cam/", map[string]string{"Session": session})
+	test.That(t, status, test.ShouldEqual, 200)
+
+	// the slice of the access unit is sent whole in a single NAL unit packet
+	test.That(t, string(client.readFrame(t, 2)), test.ShouldContainSubstring, string(annexBFrame[12:]))
+
+	// a second client is sent the video of the same encoder
+	other := dialRTSP(t, server)
+	other.play(t, url+"/cam", nil)
+	test.That(t, string(other.readFrame(t, 0)), test.ShouldContainSubstring, string(annexBFrame[12:]))
+	test.That(t, encoderFactory.encoders.Load(), test.ShouldEqual, 1)
+
+	// removing the source ends the streams of its clients
+	server.RemoveSource("cam")
+	test.That(t, server.SourceNames(), test.ShouldBeEmpty)
+	test.That(t, other.conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	_, err = io.Copy(io.Discard, other.reader)
+	test.That(t, err, test.ShouldBeNil)
+	status, _, _ = dialRTSP(t, server).do(t, "DESCRIBE", url+"/cam", nil)
+	test.That(t, status, test.ShouldEqual, 404)
+}
+
+func TestRTSPServerAuthentication(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server, err := webstream.NewRTSPServer(listener, &fakeEncoderFactory{mimeType: "video/H264"}, webstream.RTSPOptions{
+		Authenticate: func(ctx context.Context, username, password string) error {
+			if username != "key-id" || password != "key" {
+				return errors.New("invalid credentials")
+			}
+			return nil
+		},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	server.AddSource("cam", newTestVideoSource())
+	client := dialRTSP(t, server)
+	url := "rtsp://" + server.Addr().String() + "/cam"
+
+	status, _, _ := client.do(t, "OPTIONS", url, nil)
+	test.That(t, status, test.ShouldEqual, 200)
+
+	status, header, _ := client.do(t, "DESCRIBE", url, nil)
+	test.That(t, status, test.ShouldEqual, 401)
+	test.That(t, header.Get("WWW-Authenticate"), test.ShouldEqual, `Basic realm="viam"`)
+
+	basicAuth := func(username, password string) map[string]string {
+		return map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))}
+	}
+	status, _, _ = client.do(t, "DESCRIBE", url, basicAuth("key-id", "wrong"))
+	test.That(t, status, test.ShouldEqual, 401)
+	status, _, _ = client.do(t, "DESCRIBE", url, basicAuth("key-id", "key"))
+	test.That(t, status, test.ShouldEqual, 200)
+
+	client.play(t, url, basicAuth("key-id", "key"))
+	test.That(t, string(client.readFrame(t, 0)), test.ShouldContainSubstring, string(annexBFrame[12:]))
+}
+
+func TestRTSPServerMaxClients(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server, err := webstream.NewRTSPServer(listener, &fakeEncoderFactory{mimeType: "video/H264"}, webstream.RTSPOptions{MaxClients: 1}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	url := "rtsp://" + server.Addr().String() + "/cam"
+
+	client := dialRTSP(t, server)
+	status, _, _ := client.do(t, "OPTIONS", url, nil)
+	test.That(t, status, test.ShouldEqual, 200)
+
+	// connections beyond the limit are closed
+	refused := dialRTSP(t, server)
+	test.That(t, refused.conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	_, err = refused.reader.ReadByte()
+	test.That(t, err, test.ShouldEqual, io.EOF)
+
+	// the connection of the client which left can be reused
+	test.That(t, client.conn.Close(), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		conn, err := net.Dial("tcp", server.Addr().String())
+		test.That(tb, err, test.ShouldBeNil)
+		defer conn.Close()
+		_, err = conn.Write([]byte("OPTIONS " + url + " RTSP/1.0\r\nCSeq: 1\r\n\r\n"))
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, conn.SetReadDeadline(time.Now().Add(time.Second)), test.ShouldBeNil)
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, line, test.ShouldStartWith, "RTSP/1.0 200")
+	})
+}
+
+func TestRTSPServerTimeouts(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server, err := webstream.NewRTSPServer(listener, &fakeEncoderFactory{mimeType: "video/H264"}, webstream.RTSPOptions{
+		Authenticate: func(ctx context.Context, username, password string) error {
+			if username != "key-id" || password != "key" {
+				return errors.New("invalid credentials")
+			}
+			return nil
+		},
+		MaxClients:       1,
+		SessionTimeout:   500 * time.Millisecond,
+		HandshakeTimeout: 200 * time.Millisecond,
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+	}()
+	server.AddSource("cam", newTestVideoSource())
+	url := "rtsp://" + server.Addr().String() + "/cam"
+	auth := map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("key-id:key"))}
+	waitForClose := func(client *rtspClient) {
+		t.Helper()
+		test.That(t, client.conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+		_, err := client.reader.ReadByte()
+		test.That(t, err, test.ShouldEqual, io.EOF)
+	}
+
+	// a client which isn't authorized is closed after the handshake timeout, even if it keeps making requests
+	unauthorized := dialRTSP(t, server)
+	start := time.Now()
+	for time.Since(start) < 100*time.Millisecond {
+		status, _, _ := unauthorized.do(t, "OPTIONS", url, nil)
+		test.That(t, status, test.ShouldEqual, 200)
+		status, _, _ = unauthorized.do(t, "DESCRIBE", url, nil)
+		test.That(t, status, test.ShouldEqual, 401)
+		time.Sleep(20 * time.Millisecond)
+	}
+	waitForClose(unauthorized)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, time.Second)
+
+	// once authorized, a client is kept while it keeps making requests, which is past the handshake timeout
+	client := dialRTSP(t, server)
+	status, _, _ := client.do(t, "DESCRIBE", url, auth)
+	test.That(t, status, test.ShouldEqual, 200)
+	status, header, _ := client.do(t, "SETUP", url+"/trackID=0", map[string]string{
+		"Transport":     "RTP/AVP/TCP;unicast;interleaved=0-1",
+		"Authorization": auth["Authorization"],
+	})
+	test.That(t, status, test.ShouldEqual, 200)
+	test.That(t, header.Get("Session"), test.ShouldEndWith, ";timeout=1")
+	for i := 0; i < 3; i++ {
+		time.Sleep(200 * time.Millisecond)
+		status, _, _ = client.do(t, "OPTIONS", url, nil)
+		test.That(t, status, test.ShouldEqual, 200)
+	}
+
+	// until it is idle for the session timeout
+	waitForClose(client)
+}
@@ -37,6 +37,7 @@ import (
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/internal/metrics"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/module"
 	"go.viam.com/rdk/resource"
@@ -525,6 +526,16 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		return err
 	}
 
+	if localRobot, ok := svc.r.(robot.LocalRobot); ok {
+		if err := svc.rpcServer.RegisterServiceServer(
+			ctx,
+			&grpcserver.MachineServiceDesc,
+			grpcserver.NewMachineServer(localRobot, options.LogBuffer),
+		); err != nil {
+			return err
+		}
+	}
+
 	if err := svc.refreshResources(); err != nil {
 		return err
 	}
@@ -552,12 +563,17 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		return err
 	}
 
+	mdnsServer := svc.advertiseMachine(listenerTCPAddr, options)
+
 	// Serve
 
 	svc.webWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer svc.webWorkers.Done()
 		<-ctx.Done()
+		if mdnsServer != nil {
+			defer mdnsServer.Shutdown()
+		}
 		defer func() {
 			if err := httpServer.Shutdown(context.Background()); err != nil {
 				svc.logger.Errorw("error shutting down", "error", err)
@@ -638,7 +654,7 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 
 	var unaryInterceptors []googlegrpc.UnaryServerInterceptor
 
-	unaryInterceptors = append(unaryInterceptors, ensureTimeoutUnaryInterceptor)
+	unaryInterceptors = append(unaryInterceptors, ensureTimeoutUnaryInterceptor, metrics.UnaryServerInterceptor)
 
 	if options.Debug {
 		rpcOpts = append(rpcOpts, rpc.WithDebug())
@@ -665,7 +681,7 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	}
 	rpcOpts = append(rpcOpts, authOpts...)
 
-	var streamInterceptors []googlegrpc.StreamServerInterceptor
+	streamInterceptors := []googlegrpc.StreamServerInterceptor{metrics.StreamServerInterceptor}
 
 	opManager := svc.r.OperationManager()
 	sessManagerInts := svc.r.SessionManager().ServerInterceptors()
@@ -705,6 +721,11 @@ func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options we
 		}
 	}
 
+	svc.credentialHandlers = nil
+	withAuthHandler := func(credType rpc.CredentialsType, handler rpc.AuthHandler) {
+		rpcOpts = append(rpcOpts, rpc.WithAuthHandler(credType, handler))
+		svc.credentialHandlers = append(svc.credentialHandlers, handler)
+	}
 	if len(options.Auth.Handlers) == 0 {
 		rpcOpts = append(rpcOpts, rpc.WithUnauthenticated())
 	} else {
@@ -746,14 +767,11 @@ func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options we
 				case !hasLegacyAPIKeys && !hasAPIKeys:
 					return nil, errors.Errorf("%q handler requires non-empty API key or keys", handler.Type)
 				case hasLegacyAPIKeys && !hasAPIKeys:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(
-						handler.Type,
-						rpc.MakeSimpleMultiAuthHandler(authEntities, legacyAPIKeys),
-					))
+					withAuthHandler(handler.Type, rpc.MakeSimpleMultiAuthHandler(authEntities, legacyAPIKeys))
 				case !hasLegacyAPIKeys && hasAPIKeys:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, rpc.MakeSimpleMultiAuthPairHandler(apiKeys)))
+					withAuthHandler(handler.Type, rpc.MakeSimpleMultiAuthPairHandler(apiKeys))
 				default:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, makeMultiStepAPIKeyAuthHandler(authEntities, legacyAPIKeys, apiKeys)))
+					withAuthHandler(handler.Type, makeMultiStepAPIKeyAuthHandler(authEntities, legacyAPIKeys, apiKeys))
 				}
 			case rutils.CredentialsTypeRobotLocationSecret:
 				locationSecrets := handler.Config.StringSlice("secrets")
@@ -765,10 +783,7 @@ func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options we
 					locationSecrets = []string{secret}
 				}
 
-				rpcOpts = append(rpcOpts, rpc.WithAuthHandler(
-					handler.Type,
-					rpc.MakeSimpleMultiAuthHandler(authEntities, locationSecrets),
-				))
+				withAuthHandler(handler.Type, rpc.MakeSimpleMultiAuthHandler(authEntities, locationSecrets))
 			case rpc.CredentialsTypeExternal:
 			default:
 				return nil, errors.Errorf("do not know how to handle auth for %q", handler.Type)
@@ -882,6 +897,8 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	if err := svc.installWeb(mux, svc.r, options); err != nil {
 		return nil, err
 	}
+	// debug routes which expose the robot's state require the same authentication as its RPCs
+	authenticated := len(options.Auth.Handlers) != 0
 
 	if options.Pprof {
 		mux.HandleFunc(pat.New("/debug/pprof/"), pprof.Index)
@@ -896,6 +913,21 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	// TODO: accept params to display different formats
 	mux.HandleFunc(pat.New("/debug/graph"), svc.handleVisualizeResourceGraph)
 
+	// list running operations and their progress
+	mux.HandleFunc(pat.Get("/debug/operations"), svc.requireAuth(authenticated, svc.handleOperations))
+
+	// serve recent log history
+	if options.LogBuffer != nil {
+		mux.HandleFunc(pat.Get("/debug/logs"), svc.requireAuth(authenticated, svc.logsHandler(options.LogBuffer)))
+	}
+
+	// export the frame system for visualization and simulation
+	mux.HandleFunc(pat.Get("/debug/frame_system.urdf"), svc.requireAuth(authenticated, svc.handleFrameSystemURDF))
+	mux.HandleFunc(pat.Get("/debug/frame_system/stream"), svc.requireAuth(authenticated, svc.handleFrameSystemStream))
+
+	// serve per-resource metrics for Prometheus scrapers
+	mux.HandleFunc(pat.Get("/metrics"), svc.requireAuth(authenticated, svc.metricsHandler().ServeHTTP))
+
 	prefix := "/viam"
 	addPrefix := func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"runtime"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"go.viam.com/rdk/components/audioinput"
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/internal/metrics"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -79,8 +81,13 @@ type webService struct {
 	webWorkers   sync.WaitGroup
 	modWorkers   sync.WaitGroup
 
+	// credentialHandlers authenticate the credentials the robot accepts, for servers other than the
+	// RPC server to check them.
+	credentialHandlers []rpc.AuthHandler
+
 	videoSources map[string]gostream.HotSwappableVideoSource
 	audioSources map[string]gostream.HotSwappableAudioSource
+	rtspServer   *webstream.RTSPServer
 }
 
 func (svc *webService) streamInitialized() bool {
@@ -93,6 +100,7 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 	}
 	svc.refreshVideoSources()
 	svc.refreshAudioSources()
+	svc.refreshRTSPSources()
 	if svc.opts.streamConfig == nil {
 		if len(svc.videoSources) != 0 || len(svc.audioSources) != 0 {
 			svc.logger.Debug("not starting streams due to no stream config being set")
@@ -104,6 +112,7 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 		// Configure new stream
 		config := *svc.opts.streamConfig
 		config.Name = name
+		config.OnVideoFrameSent = metrics.StreamFrames.WithLabelValues(name).Inc
 		stream, err := svc.streamServer.Server.NewStream(config)
 
 		// Skip if stream is already registered, otherwise raise any other errors
@@ -162,6 +171,7 @@ func (svc *webService) makeStreamServer(ctx context.Context) (*StreamServer, err
 	addStream := func(streams []gostream.Stream, name string, isVideo bool) ([]gostream.Stream, error) {
 		config := *svc.opts.streamConfig
 		config.Name = name
+		config.OnVideoFrameSent = metrics.StreamFrames.WithLabelValues(name).Inc
 		if isVideo {
 			config.AudioEncoderFactory = nil
 
@@ -332,6 +342,78 @@ func (svc *webService) closeStreamServer() {
 			svc.logger.Errorw("error closing stream server", "error", err)
 		}
 	}
+	if svc.rtspServer != nil {
+		if err := svc.rtspServer.Close(); err != nil {
+			svc.logger.Errorw("error closing RTSP server", "error", err)
+		}
+	}
+}
+
+// initRTSPServer starts serving the video sources over RTSP when an RTSP bind address is configured.
+// Clients authenticate with the credentials the robot accepts.
+func (svc *webService) initRTSPServer(options *weboptions.Options) error {
+	if options.Network.RTSPBindAddress == "" {
+		return nil
+	}
+	if svc.opts.streamConfig == nil || svc.opts.streamConfig.VideoEncoderFactory == nil {
+		svc.logger.Warn("not starting RTSP server due to no video encoder being set")
+		return nil
+	}
+	rtspOpts := webstream.RTSPOptions{MaxClients: options.Network.RTSPMaxClients}
+	if len(options.Auth.Handlers) != 0 {
+		if len(svc.credentialHandlers) == 0 {
+			svc.logger.Warn("not starting RTSP server since none of the robot's auth handlers accept a username and password")
+			return nil
+		}
+		rtspOpts.Authenticate = svc.authenticateCredentials
+	}
+	listener, err := net.Listen("tcp", options.Network.RTSPBindAddress)
+	if err != nil {
+		return errors.Wrap(err, "could not start RTSP server")
+	}
+	rtspServer, err := webstream.NewRTSPServer(listener, svc.opts.streamConfig.VideoEncoderFactory, rtspOpts, svc.logger)
+	if err != nil {
+		utils.UncheckedError(listener.Close())
+		return err
+	}
+	svc.rtspServer = rtspServer
+	svc.refreshRTSPSources()
+	svc.logger.Infow("serving cameras over RTSP", "address", listener.Addr().String())
+	return nil
+}
+
+// authenticateCredentials checks a username and password against the robot's auth handlers: an API key
+// ID and key, or a robot's address and secret.
+func (svc *webService) authenticateCredentials(ctx context.Context, username, password string) error {
+	err := errors.New("no credentials")
+	for _, handler := range svc.credentialHandlers {
+		if _, err = handler.Authenticate(ctx, username, password); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// refreshRTSPSources serves the video source of every camera on the robot over RTSP, and stops serving
+// those of cameras which were removed, if the RTSP server is running.
+func (svc *webService) refreshRTSPSources() {
+	if svc.rtspServer == nil {
+		return
+	}
+	cameras := map[string]struct{}{}
+	for _, name := range camera.NamesFromRobot(svc.r) {
+		cameras[validSDPTrackName(name)] = struct{}{}
+	}
+	for _, name := range svc.rtspServer.SourceNames() {
+		if _, ok := cameras[name]; !ok {
+			svc.rtspServer.RemoveSource(name)
+		}
+	}
+	for name := range cameras {
+		if source, ok := svc.videoSources[name]; ok {
+			svc.rtspServer.AddSource(name, source)
+		}
+	}
 }
 
 func (svc *webService) initStreamServer(ctx context.Context, options *weboptions.Options) error {
@@ -340,6 +422,9 @@ func (svc *webService) initStreamServer(ctx context.Context, options *weboptions
 	if err != nil {
 		return err
 	}
+	if err := svc.initRTSPServer(options); err != nil {
+		return err
+	}
 	if err := svc.rpcServer.RegisterServiceServer(
 		ctx,
 		&streampb.StreamService_ServiceDesc,
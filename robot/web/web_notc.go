@@ -48,6 +48,10 @@ type webService struct {
 	isRunning  bool
 	webWorkers sync.WaitGroup
 	modWorkers sync.WaitGroup
+
+	// credentialHandlers authenticate the credentials the robot accepts, for servers other than the
+	// RPC server to check them.
+	credentialHandlers []rpc.AuthHandler
 }
 
 // Update updates the web service when the robot has changed.
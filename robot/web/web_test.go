@@ -6,8 +6,11 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -1392,3 +1395,154 @@ func signJWKBasedExternalAccessToken(
 
 	return token.SignedString(key)
 }
+
+func TestWebMetrics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	conn, err := rgrpc.Dial(context.Background(), addr, logger, rpc.WithForceDirectGRPC())
+	test.That(t, err, test.ShouldBeNil)
+	arm1, err := arm.NewClientFromConn(context.Background(), conn, "", arm.Named(arm1String), logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = arm1.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+	test.That(t, err, test.ShouldBeNil)
+	body, err := io.ReadAll(resp.Body)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, string(body), test.ShouldContainSubstring,
+		`viam_resource_healthy{resource="rdk:component:arm/arm1"} 1`)
+	test.That(t, string(body), test.ShouldContainSubstring,
+		`viam_resource_requests_total{code="OK",method="/viam.component.arm.v1.ArmService/GetEndPosition",resource="arm1"}`)
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
+// statsRobot is a robot which has sampled the stats of its resources, as robot.LocalRobot does.
+type statsRobot struct {
+	*inject.Robot
+}
+
+func (r *statsRobot) ResourceStats() map[resource.Name]map[string]float64 {
+	return map[resource.Name]map[string]float64{arm.Named("arm1"): {"queue_depth": 3}}
+}
+
+func TestWebMetricsResourceStats(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	injectRobot = &statsRobot{injectRobot.(*inject.Robot)}
+
+	svc := web.New(injectRobot, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+	test.That(t, err, test.ShouldBeNil)
+	body, err := io.ReadAll(resp.Body)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, string(body), test.ShouldContainSubstring,
+		`viam_resource_stat{resource="rdk:component:arm/arm1",stat="queue_depth"} 3`)
+
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
+func TestWebLogs(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+
+	logBuffer := logging.NewLogBuffer(10)
+	bufferedLogger := logging.NewBlankLogger("robot_server")
+	bufferedLogger.AddAppender(logBuffer)
+	bufferedLogger.Sublogger("rdk:component:arm/arm1").Warn("arm overheating")
+	bufferedLogger.Sublogger("rdk:component:camera/cam1").Info("camera started")
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.LogBuffer = logBuffer
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	getEntries := func(query string) []logging.BufferedLogEntry {
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/logs?%s", addr, query))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, resp.Body.Close(), test.ShouldBeNil)
+		}()
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+		var entries []logging.BufferedLogEntry
+		test.That(t, json.NewDecoder(resp.Body).Decode(&entries), test.ShouldBeNil)
+		return entries
+	}
+
+	test.That(t, getEntries(""), test.ShouldHaveLength, 2)
+	entries := getEntries("logger=robot_server.rdk:component:arm/arm1")
+	test.That(t, entries, test.ShouldHaveLength, 1)
+	test.That(t, entries[0].Message, test.ShouldEqual, "arm overheating")
+	test.That(t, getEntries("level=warn"), test.ShouldHaveLength, 1)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/debug/logs?level=loud", addr))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusBadRequest)
+	test.That(t, getEntries("logger=robot_server.rdk:component"), test.ShouldBeEmpty)
+
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+
+	t.Run("requires auth", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		test.That(t, err, test.ShouldBeNil)
+		keyset := jwk.NewSet()
+		publicKey, err := jwk.New(privKey.PublicKey)
+		test.That(t, err, test.ShouldBeNil)
+		publicKey.Set("alg", "RS256")
+		publicKey.Set(jwk.KeyIDKey, "key-id-1")
+		test.That(t, keyset.Add(publicKey), test.ShouldBeTrue)
+
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		options.FQDN = "robot.local"
+		options.LogBuffer = logBuffer
+		options.Auth.Handlers = []config.AuthHandlerConfig{
+			{Type: rpc.CredentialsTypeAPIKey, Config: rutils.AttributeMap{"key": "sosecret"}},
+		}
+		options.Auth.ExternalAuthConfig = &config.ExternalAuthConfig{ValidatedKeySet: keyset}
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+		defer func() {
+			test.That(t, svc.Close(ctx), test.ShouldBeNil)
+		}()
+
+		getStatus := func(path, authorization string) int {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", addr, path), nil)
+			test.That(t, err, test.ShouldBeNil)
+			if authorization != "" {
+				req.Header.Set("Authorization", authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp.Body.Close(), test.ShouldBeNil)
+			return resp.StatusCode
+		}
+		accessToken, err := signJWKBasedExternalAccessToken(privKey, "someone", options.FQDN, "iss", "key-id-1")
+		test.That(t, err, test.ShouldBeNil)
+		for _, path := range []string{"/debug/logs", "/debug/operations", "/metrics"} {
+			test.That(t, getStatus(path, ""), test.ShouldEqual, http.StatusUnauthorized)
+			test.That(t, getStatus(path, "Bearer nope"), test.ShouldEqual, http.StatusUnauthorized)
+			test.That(t, getStatus(path, "Bearer "+accessToken), test.ShouldEqual, http.StatusOK)
+		}
+	})
+}
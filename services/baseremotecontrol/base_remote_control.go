@@ -30,4 +30,18 @@ type Service interface {
 	Close(ctx context.Context) error
 	// controllerInputs returns the list of inputs from the controller that are being monitored for that control mode.
 	ControllerInputs() []input.Control
+	// SpeedProfile returns the name of the speed profile limiting how fast the base is driven.
+	SpeedProfile() string
+	// SetSpeedProfile switches to the named speed profile.
+	SetSpeedProfile(name string) error
 }
+
+// The commands of DoCommand which get and set the speed profile from clients. Both respond with the
+// active speed profile under SpeedProfileKey and the names of all speed profiles under SpeedProfilesKey.
+const (
+	GetSpeedProfileCommand = "get_speed_profile"
+	// SetSpeedProfileCommand takes the name of the speed profile to switch to.
+	SetSpeedProfileCommand = "set_speed_profile"
+	SpeedProfileKey        = "speed_profile"
+	SpeedProfilesKey       = "speed_profiles"
+)
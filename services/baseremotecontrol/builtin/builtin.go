@@ -41,6 +41,10 @@ func init() {
 // ControlMode is the control type for the remote control.
 type controlMode uint8
 
+// defaultSpeedProfileName is the name of the speed profile made from the max velocities of the config
+// when no speed profiles are configured.
+const defaultSpeedProfileName = "normal"
+
 // Config describes how to configure the service.
 type Config struct {
 	BaseName            string  `json:"base"`
@@ -48,6 +52,30 @@ type Config struct {
 	ControlModeName     string  `json:"control_mode,omitempty"`
 	MaxAngularVelocity  float64 `json:"max_angular_deg_per_sec,omitempty"`
 	MaxLinearVelocity   float64 `json:"max_linear_mm_per_sec,omitempty"`
+
+	// ControlMapping maps the controls used by the control mode, such as AbsoluteX for the joystick
+	// control mode, to the controls of the input controller which should be used instead.
+	ControlMapping map[string]string `json:"control_mapping,omitempty"`
+	// InvertedControls are the controls used by the control mode whose values are negated.
+	InvertedControls []string `json:"inverted_controls,omitempty"`
+
+	// SpeedProfiles, such as precision, normal and turbo, replace the max velocities with ones which
+	// can be switched between at runtime.
+	SpeedProfiles       []SpeedProfile `json:"speed_profiles,omitempty"`
+	DefaultSpeedProfile string         `json:"default_speed_profile,omitempty"`
+	// SpeedProfileButton is the control of the input controller which switches to the next speed
+	// profile when pressed.
+	SpeedProfileButton string `json:"speed_profile_button,omitempty"`
+}
+
+// SpeedProfile limits how fast the base is driven. Bases are driven by velocity when both max
+// velocities are set, and by power otherwise.
+type SpeedProfile struct {
+	Name               string  `json:"name"`
+	MaxAngularVelocity float64 `json:"max_angular_deg_per_sec,omitempty"`
+	MaxLinearVelocity  float64 `json:"max_linear_mm_per_sec,omitempty"`
+	// PowerScale scales the power of bases driven by power, and defaults to 1.
+	PowerScale float64 `json:"power_scale,omitempty"`
 }
 
 // Validate creates the list of implicit dependencies.
@@ -63,9 +91,48 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	}
 	deps = append(deps, conf.BaseName)
 
+	for control, mapped := range conf.ControlMapping {
+		if mapped == "" {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("control %q is mapped to an empty control", control))
+		}
+	}
+
+	names := map[string]bool{}
+	for _, profile := range conf.SpeedProfiles {
+		if profile.Name == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "speed_profiles.name")
+		}
+		if names[profile.Name] {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("speed profile %q is defined more than once", profile.Name))
+		}
+		names[profile.Name] = true
+		if profile.MaxAngularVelocity < 0 || profile.MaxLinearVelocity < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("speed profile %q has negative max velocities", profile.Name))
+		}
+		if profile.PowerScale < 0 || profile.PowerScale > 1 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("speed profile %q power_scale must be between 0 and 1", profile.Name))
+		}
+	}
+	if conf.DefaultSpeedProfile != "" && !names[conf.DefaultSpeedProfile] {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("default speed profile %q is not defined", conf.DefaultSpeedProfile))
+	}
+
 	return deps, nil
 }
 
+// speedProfiles returns the speed profiles of the config, or the profile made from its max velocities
+// when none are configured.
+func (conf *Config) speedProfiles() []SpeedProfile {
+	if len(conf.SpeedProfiles) == 0 {
+		return []SpeedProfile{{
+			Name:               defaultSpeedProfileName,
+			MaxAngularVelocity: conf.MaxAngularVelocity,
+			MaxLinearVelocity:  conf.MaxLinearVelocity,
+		}}
+	}
+	return conf.SpeedProfiles
+}
+
 // builtIn is the structure of the remote service.
 type builtIn struct {
 	resource.Named
@@ -75,6 +142,13 @@ type builtIn struct {
 	inputController input.Controller
 	controlMode     controlMode
 	config          *Config
+	// mappedControls maps the controls of the control mode to those of the controller, and
+	// unmappedControls maps them back.
+	mappedControls   map[input.Control]input.Control
+	unmappedControls map[input.Control]input.Control
+	invertedControls map[input.Control]bool
+	speedProfiles    []SpeedProfile
+	speedProfile     int
 
 	state                   throttleState
 	logger                  logging.Logger
@@ -141,11 +215,27 @@ func (svc *builtIn) Reconfigure(
 		controlMode1 = arrowControl
 	}
 
+	mappedControls := map[input.Control]input.Control{}
+	unmappedControls := map[input.Control]input.Control{}
+	for control, mapped := range svcConfig.ControlMapping {
+		mappedControls[input.Control(control)] = input.Control(mapped)
+		unmappedControls[input.Control(mapped)] = input.Control(control)
+	}
+	invertedControls := map[input.Control]bool{}
+	for _, control := range svcConfig.InvertedControls {
+		invertedControls[input.Control(control)] = true
+	}
+
 	svc.mu.Lock()
 	svc.base = base1
 	svc.inputController = controller
 	svc.controlMode = controlMode1
 	svc.config = svcConfig
+	svc.mappedControls = mappedControls
+	svc.unmappedControls = unmappedControls
+	svc.invertedControls = invertedControls
+	svc.speedProfile = activeSpeedProfile(svcConfig, svc.speedProfiles, svc.speedProfile)
+	svc.speedProfiles = svcConfig.speedProfiles()
 	svc.mu.Unlock()
 	svc.instance.Add(1)
 
@@ -156,6 +246,26 @@ func (svc *builtIn) Reconfigure(
 	return nil
 }
 
+// activeSpeedProfile returns the index of the speed profile of the config to use, which is the
+// previously active one if the config still has it.
+func activeSpeedProfile(conf *Config, previous []SpeedProfile, previousIndex int) int {
+	name := conf.DefaultSpeedProfile
+	if previousIndex < len(previous) {
+		name = previous[previousIndex].Name
+	}
+	for i, profile := range conf.speedProfiles() {
+		if profile.Name == name {
+			return i
+		}
+	}
+	for i, profile := range conf.speedProfiles() {
+		if profile.Name == conf.DefaultSpeedProfile {
+			return i
+		}
+	}
+	return 0
+}
+
 // registerCallbacks registers events from controller to base.
 func (svc *builtIn) registerCallbacks(ctx context.Context, state *throttleState) error {
 	var lastTS time.Time
@@ -196,7 +306,17 @@ func (svc *builtIn) registerCallbacks(ctx context.Context, state *throttleState)
 			return
 		}
 
-		svc.processEvent(ctx, state, event)
+		svc.processEvent(ctx, state, svc.unmapEvent(event))
+	}
+
+	nextSpeedProfile := func(ctx context.Context, event input.Event) {
+		onlyOneAtATime.Lock()
+		defer onlyOneAtATime.Unlock()
+
+		if svc.instance.Load() != instance {
+			return
+		}
+		svc.nextSpeedProfile(ctx)
 	}
 
 	connect := func(ctx context.Context, event input.Event) {
@@ -258,9 +378,98 @@ func (svc *builtIn) registerCallbacks(ctx context.Context, state *throttleState)
 			return err
 		}
 	}
+
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	if svc.config.SpeedProfileButton != "" {
+		return svc.inputController.RegisterControlCallback(ctx,
+			input.Control(svc.config.SpeedProfileButton),
+			[]input.EventType{input.ButtonPress},
+			nextSpeedProfile,
+			map[string]interface{}{},
+		)
+	}
 	return nil
 }
 
+// unmapEvent returns the event of the control of the control mode which is mapped to the control of
+// the event.
+func (svc *builtIn) unmapEvent(event input.Event) input.Event {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	if control, ok := svc.unmappedControls[event.Control]; ok {
+		event.Control = control
+	}
+	if svc.invertedControls[event.Control] {
+		event.Value = -event.Value
+	}
+	return event
+}
+
+// SpeedProfile returns the name of the speed profile limiting how fast the base is driven.
+func (svc *builtIn) SpeedProfile() string {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.speedProfiles[svc.speedProfile].Name
+}
+
+// SetSpeedProfile switches to the named speed profile.
+func (svc *builtIn) SetSpeedProfile(name string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	for i, profile := range svc.speedProfiles {
+		if profile.Name == name {
+			svc.speedProfile = i
+			svc.notifyEventProcessor()
+			return nil
+		}
+	}
+	return errors.Errorf("no speed profile named %q", name)
+}
+
+func (svc *builtIn) nextSpeedProfile(ctx context.Context) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.speedProfile = (svc.speedProfile + 1) % len(svc.speedProfiles)
+	svc.notifyEventProcessor()
+	svc.logger.CInfow(ctx, "switched speed profile", "speed_profile", svc.speedProfiles[svc.speedProfile].Name)
+}
+
+// notifyEventProcessor makes the event processor apply the current state. If the processor is busy,
+// it sees the state once it is done.
+func (svc *builtIn) notifyEventProcessor() {
+	select {
+	case svc.events <- struct{}{}:
+	default:
+	}
+}
+
+// DoCommand gets and sets the speed profile.
+func (svc *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if name, ok := cmd[baseremotecontrol.SetSpeedProfileCommand]; ok {
+		nameStr, ok := name.(string)
+		if !ok {
+			return nil, errors.Errorf("%s must be the name of a speed profile, got %v", baseremotecontrol.SetSpeedProfileCommand, name)
+		}
+		if err := svc.SetSpeedProfile(nameStr); err != nil {
+			return nil, err
+		}
+	} else if _, ok := cmd[baseremotecontrol.GetSpeedProfileCommand]; !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	names := make([]interface{}, 0, len(svc.speedProfiles))
+	for _, profile := range svc.speedProfiles {
+		names = append(names, profile.Name)
+	}
+	return map[string]interface{}{
+		baseremotecontrol.SpeedProfileKey:  svc.speedProfiles[svc.speedProfile].Name,
+		baseremotecontrol.SpeedProfilesKey: names,
+	}, nil
+}
+
 // Close out of all remote control related systems.
 func (svc *builtIn) Close(_ context.Context) error {
 	svc.cancel()
@@ -272,6 +481,18 @@ func (svc *builtIn) Close(_ context.Context) error {
 func (svc *builtIn) ControllerInputs() []input.Control {
 	svc.mu.RLock()
 	defer svc.mu.RUnlock()
+	controls := svc.controlModeInputs()
+	for i, control := range controls {
+		if mapped, ok := svc.mappedControls[control]; ok {
+			controls[i] = mapped
+		}
+	}
+	return controls
+}
+
+// controlModeInputs returns the list of inputs used by the control mode, before they are mapped to
+// those of the controller.
+func (svc *builtIn) controlModeInputs() []input.Control {
 	switch svc.controlMode {
 	case triggerSpeedControl:
 		return []input.Control{input.AbsoluteX, input.AbsoluteZ, input.AbsoluteRZ}
@@ -291,6 +512,9 @@ func (svc *builtIn) eventProcessor() {
 	var currentLinear, currentAngular r3.Vector
 	var nextLinear, nextAngular r3.Vector
 	var inRetry bool
+	svc.mu.RLock()
+	currentProfile := svc.speedProfile
+	svc.mu.RUnlock()
 
 	svc.activeBackgroundWorkers.Add(1)
 	vutils.ManagedGo(func() {
@@ -319,20 +543,16 @@ func (svc *builtIn) eventProcessor() {
 				svc.mu.RLock()
 				defer svc.mu.RUnlock()
 
-				if currentLinear != nextLinear || currentAngular != nextAngular {
-					if svc.config.MaxAngularVelocity > 0 && svc.config.MaxLinearVelocity > 0 {
+				// a new speed profile only needs to be applied while the base is driven
+				moving := nextLinear != (r3.Vector{}) || nextAngular != (r3.Vector{})
+				profileChanged := currentProfile != svc.speedProfile && moving
+				if currentLinear != nextLinear || currentAngular != nextAngular || profileChanged {
+					profile := svc.speedProfiles[svc.speedProfile]
+					if profile.MaxAngularVelocity > 0 && profile.MaxLinearVelocity > 0 {
 						if err := svc.base.SetVelocity(
 							svc.cancelCtx,
-							r3.Vector{
-								X: svc.config.MaxLinearVelocity * nextLinear.X,
-								Y: svc.config.MaxLinearVelocity * nextLinear.Y,
-								Z: svc.config.MaxLinearVelocity * nextLinear.Z,
-							},
-							r3.Vector{
-								X: svc.config.MaxAngularVelocity * nextAngular.X,
-								Y: svc.config.MaxAngularVelocity * nextAngular.Y,
-								Z: svc.config.MaxAngularVelocity * nextAngular.Z,
-							},
+							nextLinear.Mul(profile.MaxLinearVelocity),
+							nextAngular.Mul(profile.MaxAngularVelocity),
 							nil,
 						); err != nil {
 							svc.logger.Errorw("error setting velocity", "error", err)
@@ -343,7 +563,11 @@ func (svc *builtIn) eventProcessor() {
 							return false
 						}
 					} else {
-						if err := svc.base.SetPower(svc.cancelCtx, nextLinear, nextAngular, nil); err != nil {
+						powerScale := profile.PowerScale
+						if powerScale == 0 {
+							powerScale = 1
+						}
+						if err := svc.base.SetPower(svc.cancelCtx, nextLinear.Mul(powerScale), nextAngular.Mul(powerScale), nil); err != nil {
 							svc.logger.Errorw("error setting power", "error", err)
 							if !vutils.SelectContextOrWait(svc.cancelCtx, 10*time.Millisecond) {
 								return true
@@ -357,6 +581,7 @@ func (svc *builtIn) eventProcessor() {
 					currentLinear = nextLinear
 					currentAngular = nextAngular
 				}
+				currentProfile = svc.speedProfile
 
 				return false
 			}() {
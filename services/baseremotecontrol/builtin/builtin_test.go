@@ -2,16 +2,19 @@ package builtin
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/base"
 	fakebase "go.viam.com/rdk/components/base/fake"
 	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/components/input/webgamepad"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/baseremotecontrol"
@@ -374,3 +377,133 @@ func TestSimilar(t *testing.T) {
 	test.That(t, similar(r3.Vector{Y: 2}, r3.Vector{}, 1), test.ShouldBeFalse)
 	test.That(t, similar(r3.Vector{Z: 2}, r3.Vector{}, 1), test.ShouldBeFalse)
 }
+
+type velocityBase struct {
+	base.Base
+	mu              sync.Mutex
+	linear, angular r3.Vector
+}
+
+func (b *velocityBase) Name() resource.Name {
+	return base.Named("base")
+}
+
+func (b *velocityBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.linear, b.angular = linear, angular
+	return nil
+}
+
+func (b *velocityBase) velocity() (r3.Vector, r3.Vector) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.linear, b.angular
+}
+
+func TestSpeedProfiles(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	cfg := &Config{
+		BaseName:            "base",
+		InputControllerName: "gamepad",
+		ControlModeName:     "joystickControl",
+		ControlMapping:      map[string]string{"AbsoluteX": "AbsoluteRX", "AbsoluteY": "AbsoluteRY"},
+		InvertedControls:    []string{"AbsoluteY"},
+		SpeedProfiles: []SpeedProfile{
+			{Name: "precision", MaxLinearVelocity: 100, MaxAngularVelocity: 10},
+			{Name: "turbo", MaxLinearVelocity: 1000, MaxAngularVelocity: 90},
+		},
+		DefaultSpeedProfile: "precision",
+		SpeedProfileButton:  "ButtonRT",
+	}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("validate", func(t *testing.T) {
+		invalid := *cfg
+		invalid.DefaultSpeedProfile = "normal"
+		_, err := invalid.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not defined")
+
+		invalid = *cfg
+		invalid.SpeedProfiles = []SpeedProfile{{Name: "precision"}, {Name: "precision"}}
+		invalid.DefaultSpeedProfile = ""
+		_, err = invalid.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "more than once")
+
+		invalid.SpeedProfiles = []SpeedProfile{{Name: "precision", PowerScale: 2}}
+		_, err = invalid.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "power_scale")
+	})
+
+	gamepad, err := webgamepad.NewController(ctx, nil, resource.Config{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	b := &velocityBase{}
+	tmpSvc, err := NewBuiltIn(ctx, resource.Dependencies{
+		input.Named("gamepad"): gamepad,
+		base.Named("base"):     b,
+	}, resource.Config{
+		Name:                "base_remote_control",
+		API:                 baseremotecontrol.API,
+		ConvertedAttributes: cfg,
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, tmpSvc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	test.That(t, tmpSvc.ControllerInputs(), test.ShouldResemble, []input.Control{input.AbsoluteRX, input.AbsoluteRY})
+	test.That(t, tmpSvc.SpeedProfile(), test.ShouldEqual, "precision")
+
+	type triggerer interface {
+		TriggerEvent(ctx context.Context, event input.Event, extra map[string]interface{}) error
+	}
+	// the mapped and inverted y axis drives the base forward
+	test.That(t, gamepad.(triggerer).TriggerEvent(ctx, input.Event{
+		Event:   input.PositionChangeAbs,
+		Control: input.AbsoluteRY,
+		Value:   1,
+	}, nil), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		linear, _ := b.velocity()
+		test.That(tb, linear, test.ShouldResemble, r3.Vector{Y: 100})
+	})
+
+	test.That(t, gamepad.(triggerer).TriggerEvent(ctx, input.Event{
+		Event:   input.ButtonPress,
+		Control: input.ButtonRT,
+		Value:   1,
+	}, nil), test.ShouldBeNil)
+	test.That(t, tmpSvc.SpeedProfile(), test.ShouldEqual, "turbo")
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		linear, _ := b.velocity()
+		test.That(tb, linear, test.ShouldResemble, r3.Vector{Y: 1000})
+	})
+
+	resp, err := tmpSvc.DoCommand(ctx, map[string]interface{}{baseremotecontrol.SetSpeedProfileCommand: "precision"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{
+		baseremotecontrol.SpeedProfileKey:  "precision",
+		baseremotecontrol.SpeedProfilesKey: []interface{}{"precision", "turbo"},
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		linear, _ := b.velocity()
+		test.That(tb, linear, test.ShouldResemble, r3.Vector{Y: 100})
+	})
+
+	resp, err = tmpSvc.DoCommand(ctx, map[string]interface{}{baseremotecontrol.GetSpeedProfileCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp[baseremotecontrol.SpeedProfileKey], test.ShouldEqual, "precision")
+
+	_, err = tmpSvc.DoCommand(ctx, map[string]interface{}{baseremotecontrol.SetSpeedProfileCommand: "ludicrous"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "ludicrous")
+
+	_, err = tmpSvc.DoCommand(ctx, map[string]interface{}{"other": true})
+	test.That(t, err, test.ShouldEqual, resource.ErrDoUnimplemented)
+}
@@ -309,6 +309,7 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	}
 	params := data.CollectorParams{
 		ComponentName: config.Name.ShortName(),
+		MethodName:    md.MethodMetadata.MethodName,
 		Interval:      interval,
 		MethodParams:  methodParams,
 		Target:        datacapture.NewBuffer(targetDir, captureMetadata),
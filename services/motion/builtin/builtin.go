@@ -216,6 +216,7 @@ func (ms *builtIn) Move(
 	goalPose, _ := tf.(*referenceframe.PoseInFrame)
 
 	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
+	operation.SetProgress(ctx, 0, "planning")
 	plan, err := motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
 		Logger:             ms.logger,
 		Goal:               goalPose,
@@ -231,7 +232,9 @@ func (ms *builtIn) Move(
 	}
 
 	// move all the components
-	for _, step := range plan.Trajectory() {
+	trajectory := plan.Trajectory()
+	for i, step := range trajectory {
+		operation.SetProgress(ctx, 100*float64(i)/float64(len(trajectory)), "executing")
 		for name, inputs := range step {
 			if len(inputs) == 0 {
 				continue
@@ -248,6 +251,7 @@ func (ms *builtIn) Move(
 			}
 		}
 	}
+	operation.SetProgress(ctx, 100, "done")
 	return true, nil
 }
 
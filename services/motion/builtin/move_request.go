@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strconv"
 	"sync"
 	"time"
 
@@ -224,24 +223,11 @@ func (mr *moveRequest) getTransientDetections(
 		cameraToBase = cameraOrigin
 	}
 
-	// transformed detections
-	transformedGeoms := []spatialmath.Geometry{}
-	for i, detection := range detections {
-		geometry := detection.Geometry
-		// update the label of the geometry so we know it is transient
-		label := camName.ShortName() + "_transientObstacle_" + strconv.Itoa(i)
-		if geometry.Label() != "" {
-			label += "_" + geometry.Label()
-		}
-		geometry.SetLabel(label)
-
-		// transform the geometry to be relative to the base frame which is +Y forwards
-		relativeGeom := geometry.Transform(cameraToBase.Pose())
-
-		// apply any transformation on the geometry defined a priori by the caller
-		transformedGeom := relativeGeom.Transform(transformBy)
-		transformedGeoms = append(transformedGeoms, transformedGeom)
-	}
+	// move the geometries to be relative to the base frame which is +Y forwards, and then by any transformation
+	// defined a priori by the caller
+	transformedGeoms := vision.TransientObstacles(
+		camName.ShortName(), detections, spatialmath.Compose(transformBy, cameraToBase.Pose()),
+	)
 	return referenceframe.NewGeometriesInFrame(referenceframe.World, transformedGeoms), nil
 }
 
@@ -50,6 +50,11 @@ func (c *client) Move(
 	constraints *pb.Constraints,
 	extra map[string]interface{},
 ) (bool, error) {
+	// the WorldState proto can only send meshes as their bounding boxes
+	extra, err := worldState.MeshObstaclesToExtra(extra)
+	if err != nil {
+		return false, err
+	}
 	ext, err := vprotoutils.StructToStructPb(extra)
 	if err != nil {
 		return false, err
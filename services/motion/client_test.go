@@ -89,6 +89,8 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 
 		receivedTransforms := make(map[string]*referenceframe.LinkInFrame)
+		var receivedWorldState *referenceframe.WorldState
+		var receivedExtra map[string]interface{}
 		success := true
 		injectMS.MoveFunc = func(
 			ctx context.Context,
@@ -98,6 +100,8 @@ func TestClient(t *testing.T) {
 			constraints *servicepb.Constraints,
 			extra map[string]interface{},
 		) (bool, error) {
+			receivedWorldState = worldState
+			receivedExtra = extra
 			return success, nil
 		}
 		injectMS.GetPoseFunc = func(
@@ -119,6 +123,28 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, result, test.ShouldEqual, success)
 
+		// Move with a mesh obstacle, which reaches the service as a mesh rather than as its bounding box
+		tetra, err := spatialmath.NewMesh(
+			spatialmath.NewPoseFromPoint(r3.Vector{X: 100}),
+			[]r3.Vector{{X: 0, Y: 0, Z: 0}, {X: 10, Y: 0, Z: 0}, {X: 0, Y: 10, Z: 0}, {X: 0, Y: 0, Z: 10}},
+			[][3]int{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}},
+			"tetra",
+		)
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{tetra})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		result, err = client.Move(ctx, gripperName, zeroPoseInFrame, worldState, nil, map[string]interface{}{"foo": "bar"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldEqual, success)
+		test.That(t, receivedExtra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+		obstacles, err := receivedWorldState.ObstaclesInWorldFrame(referenceframe.NewEmptyFrameSystem(""), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obstacles.Geometries(), test.ShouldHaveLength, 1)
+		test.That(t, spatialmath.GeometriesAlmostEqual(obstacles.Geometries()[0], tetra), test.ShouldBeTrue)
+
 		// GetPose
 		testPose := spatialmath.NewPose(
 			r3.Vector{X: 1., Y: 2., Z: 3.},
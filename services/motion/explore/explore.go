@@ -6,7 +6,6 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -438,17 +437,8 @@ func (ms *explore) generateTransientWorldState(
 				return nil, err
 			}
 
-			// Extract geometries from vision objects
-			geometries := []spatialmath.Geometry{}
-			for i, detection := range detections {
-				geometry := detection.Geometry
-				label := cameraName.Name + "_transientObstacle_" + strconv.Itoa(i)
-				if geometry.Label() != "" {
-					label += "_" + geometry.Label()
-				}
-				geometry.SetLabel(label)
-				geometries = append(geometries, geometry)
-			}
+			// Extract geometries from vision objects, in the frame of the camera
+			geometries := vision.TransientObstacles(cameraName.Name, detections, spatialmath.NewZeroPose())
 			geometriesInFrame = append(geometriesInFrame,
 				referenceframe.NewGeometriesInFrame(cameraName.Name, geometries),
 			)
@@ -33,13 +33,17 @@ func (server *serviceServer) Move(ctx context.Context, req *pb.MoveRequest) (*pb
 	if err != nil {
 		return nil, err
 	}
+	worldState, extra, err := worldState.MeshObstaclesFromExtra(req.Extra.AsMap())
+	if err != nil {
+		return nil, err
+	}
 	success, err := svc.Move(
 		ctx,
 		protoutils.ResourceNameFromProto(req.GetComponentName()),
 		referenceframe.ProtobufToPoseInFrame(req.GetDestination()),
 		worldState,
 		req.GetConstraints(),
-		req.Extra.AsMap(),
+		extra,
 	)
 	return &pb.MoveResponse{Success: success}, err
 }
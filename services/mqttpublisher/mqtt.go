@@ -0,0 +1,86 @@
+package mqttpublisher
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// mqttOptions describes how to connect to a broker.
+type mqttOptions struct {
+	// broker is the url of the broker, with the tcp or mqtt scheme for plain connections and the ssl,
+	// tls or mqtts scheme for TLS connections.
+	broker    string
+	tlsConfig *tls.Config
+	clientID  string
+	username  string
+	password  string
+	keepAlive time.Duration
+}
+
+// brokerURL returns the url of the broker with its port, which defaults to 1883, or 8883 for TLS
+// connections. Plain urls are connected to over TLS when a TLS config is given.
+func brokerURL(broker string, useTLS bool) (*url.URL, error) {
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid broker url %q", broker)
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		if useTLS {
+			u.Scheme = "ssl"
+		}
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+	default:
+		return nil, errors.Errorf("unsupported broker url scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.Errorf("broker url %q has no host", broker)
+	}
+	if u.Port() == "" {
+		port := "1883"
+		if useTLS {
+			port = "8883"
+		}
+		u.Host = net.JoinHostPort(u.Hostname(), port)
+	}
+	return u, nil
+}
+
+// newMQTTClient returns a client of the broker which, once connected, reconnects whenever the
+// connection is lost.
+func newMQTTClient(opts mqttOptions, logger logging.Logger) (mqtt.Client, error) {
+	u, err := brokerURL(opts.broker, opts.tlsConfig != nil)
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := mqtt.NewClientOptions().
+		AddBroker(u.String()).
+		SetClientID(opts.clientID).
+		SetUsername(opts.username).
+		SetPassword(opts.password).
+		SetKeepAlive(opts.keepAlive).
+		SetConnectTimeout(connectTimeout).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(reconnectInterval).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(maxReconnectInterval).
+		SetCleanSession(true).
+		SetOnConnectHandler(func(mqtt.Client) {
+			logger.Infow("connected to MQTT broker", "broker", opts.broker)
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warnw("lost connection to MQTT broker, reconnecting", "broker", opts.broker, "error", err)
+		})
+	if opts.tlsConfig != nil {
+		clientOpts.SetTLSConfig(opts.tlsConfig)
+	}
+	return mqtt.NewClient(clientOpts), nil
+}
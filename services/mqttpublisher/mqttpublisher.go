@@ -0,0 +1,465 @@
+// Package mqttpublisher implements a service which publishes the readings of resources and the
+// detections of vision services to an MQTT broker.
+package mqttpublisher
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/services/vision"
+)
+
+// Model is the model of the MQTT publisher service.
+var Model = resource.DefaultModelFamily.WithModel("mqtt_publisher")
+
+const (
+	defaultRateHz          = 1
+	maxRateHz              = 1000
+	defaultReadingsTopic   = "viam/{{.Name}}/readings"
+	defaultDetectionsTopic = "viam/{{.Name}}/{{.Camera}}/detections"
+	keepAlive              = 30 * time.Second
+	connectTimeout         = 10 * time.Second
+	publishTimeout         = 10 * time.Second
+	reconnectInterval      = time.Second
+	maxReconnectInterval   = time.Minute
+	disconnectQuiesceMS    = 250
+	defaultClientIDPrefix  = "viam-"
+	maxQoS                 = 1
+)
+
+func init() {
+	resource.RegisterService(generic.API, Model, resource.Registration[resource.Resource, *Config]{
+		Constructor: newPublisher,
+	})
+}
+
+// TLSConfig describes how to authenticate the broker and, optionally, the client.
+type TLSConfig struct {
+	// CACertPath is a PEM file of the certificate authorities to trust instead of the system's.
+	CACertPath string `json:"ca_cert_path,omitempty"`
+	// CertPath and KeyPath are the PEM files of the client's certificate and key.
+	CertPath           string `json:"cert_path,omitempty"`
+	KeyPath            string `json:"key_path,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// ReadingsConfig publishes the readings of a resource, such as a sensor, at a rate.
+type ReadingsConfig struct {
+	Resource string  `json:"resource"`
+	RateHz   float64 `json:"rate_hz,omitempty"`
+	// Topic is a template of the topic, with the name of the resource as {{.Name}}.
+	Topic string `json:"topic,omitempty"`
+}
+
+// DetectionsConfig publishes the detections of a vision service in the images of a camera, whenever
+// it detects something at the given rate.
+type DetectionsConfig struct {
+	VisionService string  `json:"vision_service"`
+	Camera        string  `json:"camera"`
+	RateHz        float64 `json:"rate_hz,omitempty"`
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	// Topic is a template of the topic, with the name of the vision service as {{.Name}} and the name
+	// of the camera as {{.Camera}}.
+	Topic string `json:"topic,omitempty"`
+}
+
+// Config describes how to configure the service.
+type Config struct {
+	// Broker is the url of the broker, e.g. tcp://localhost:1883 or ssl://broker.example.com:8883.
+	Broker   string     `json:"broker"`
+	ClientID string     `json:"client_id,omitempty"`
+	Username string     `json:"username,omitempty"`
+	Password string     `json:"password,omitempty"`
+	TLS      *TLSConfig `json:"tls,omitempty"`
+	// QoS is the quality of service of the published messages, which is either 0 or 1.
+	QoS        int                `json:"qos,omitempty"`
+	Retain     bool               `json:"retain,omitempty"`
+	Readings   []ReadingsConfig   `json:"readings,omitempty"`
+	Detections []DetectionsConfig `json:"detections,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the resources to publish.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Broker == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "broker")
+	}
+	if conf.QoS < 0 || conf.QoS > maxQoS {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("qos must be 0 or 1, got %d", conf.QoS))
+	}
+	if conf.TLS != nil && (conf.TLS.CertPath == "") != (conf.TLS.KeyPath == "") {
+		return nil, resource.NewConfigValidationError(path, errors.New("tls cert_path and key_path must be set together"))
+	}
+	if _, err := brokerURL(conf.Broker, conf.TLS != nil); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+
+	var deps []string
+	for _, r := range conf.Readings {
+		if r.Resource == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "resource")
+		}
+		if err := validateRate(r.RateHz); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		if _, err := parseTopic(r.Topic, defaultReadingsTopic); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		deps = append(deps, r.Resource)
+	}
+	for _, d := range conf.Detections {
+		if d.VisionService == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "vision_service")
+		}
+		if d.Camera == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "camera")
+		}
+		if err := validateRate(d.RateHz); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		if _, err := parseTopic(d.Topic, defaultDetectionsTopic); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		deps = append(deps, d.VisionService, d.Camera)
+	}
+	return deps, nil
+}
+
+func validateRate(rateHz float64) error {
+	if rateHz < 0 || rateHz > maxRateHz {
+		return errors.Errorf("rate_hz must be between 0 and %d", maxRateHz)
+	}
+	return nil
+}
+
+// topicData are the values available to topic templates.
+type topicData struct {
+	Name   string
+	Camera string
+}
+
+func parseTopic(topic, defaultTopic string) (*template.Template, error) {
+	if topic == "" {
+		topic = defaultTopic
+	}
+	tmpl, err := template.New("topic").Option("missingkey=error").Parse(topic)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid topic template %q", topic)
+	}
+	return tmpl, nil
+}
+
+func executeTopic(tmpl *template.Template, data topicData) (string, error) {
+	var topic bytes.Buffer
+	if err := tmpl.Execute(&topic, data); err != nil {
+		return "", errors.Wrap(err, "invalid topic template")
+	}
+	return topic.String(), nil
+}
+
+// publication publishes messages read from a resource on a topic at a rate.
+type publication struct {
+	topic    string
+	interval time.Duration
+	// read returns the payload of the next message, or nil if there is nothing to publish.
+	read func(ctx context.Context) ([]byte, error)
+}
+
+type publisher struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	client       mqtt.Client
+	qos          byte
+	retain       bool
+	publications []*publication
+	logger       logging.Logger
+
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newPublisher(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (resource.Resource, error) {
+	svcConfig, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	clientID := svcConfig.ClientID
+	if clientID == "" {
+		clientID = defaultClientIDPrefix + conf.Name
+	}
+	opts := mqttOptions{
+		broker:    svcConfig.Broker,
+		clientID:  clientID,
+		username:  svcConfig.Username,
+		password:  svcConfig.Password,
+		keepAlive: keepAlive,
+	}
+	if svcConfig.TLS != nil {
+		if opts.tlsConfig, err = newTLSConfig(svcConfig.TLS); err != nil {
+			return nil, err
+		}
+	}
+	p := &publisher{
+		Named:  conf.ResourceName().AsNamed(),
+		qos:    byte(svcConfig.QoS),
+		retain: svcConfig.Retain,
+		logger: logger,
+	}
+	if p.client, err = newMQTTClient(opts, logger); err != nil {
+		return nil, err
+	}
+
+	for _, r := range svcConfig.Readings {
+		pub, err := readingsPublication(deps, r)
+		if err != nil {
+			return nil, err
+		}
+		p.publications = append(p.publications, pub)
+	}
+	for _, d := range svcConfig.Detections {
+		pub, err := detectionsPublication(deps, d)
+		if err != nil {
+			return nil, err
+		}
+		p.publications = append(p.publications, pub)
+	}
+
+	// the client keeps trying to connect in the background until it is disconnected
+	p.client.Connect()
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	for _, pub := range p.publications {
+		pub := pub
+		p.activeBackgroundWorkers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer p.activeBackgroundWorkers.Done()
+			p.publishLoop(cancelCtx, pub)
+		})
+	}
+	return p, nil
+}
+
+func newTLSConfig(conf *TLSConfig) (*tls.Config, error) {
+	//nolint:gosec
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: conf.InsecureSkipVerify}
+	if conf.CACertPath != "" {
+		//nolint:gosec
+		pem, err := os.ReadFile(conf.CACertPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read CA certificates")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in %q", conf.CACertPath)
+		}
+	}
+	if conf.CertPath != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertPath, conf.KeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// findDependency returns the dependency with the given name, whatever its API.
+func findDependency(deps resource.Dependencies, name string) (resource.Resource, error) {
+	for depName, dep := range deps {
+		if depName.ShortName() == name {
+			return dep, nil
+		}
+	}
+	return nil, errors.Errorf("resource %q not found in dependencies", name)
+}
+
+func interval(rateHz float64) time.Duration {
+	switch {
+	case rateHz <= 0:
+		rateHz = defaultRateHz
+	case rateHz > maxRateHz:
+		rateHz = maxRateHz
+	}
+	return time.Duration(float64(time.Second) / rateHz)
+}
+
+// readingsPayload is the message published with the readings of a resource.
+type readingsPayload struct {
+	Time     time.Time       `json:"time"`
+	Name     string          `json:"name"`
+	Readings json.RawMessage `json:"readings"`
+}
+
+func readingsPublication(deps resource.Dependencies, conf ReadingsConfig) (*publication, error) {
+	dep, err := findDependency(deps, conf.Resource)
+	if err != nil {
+		return nil, err
+	}
+	sensor, ok := dep.(resource.Sensor)
+	if !ok {
+		return nil, errors.Errorf("resource %q does not have readings", conf.Resource)
+	}
+	tmpl, err := parseTopic(conf.Topic, defaultReadingsTopic)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := executeTopic(tmpl, topicData{Name: conf.Resource})
+	if err != nil {
+		return nil, err
+	}
+	return &publication{
+		topic:    topic,
+		interval: interval(conf.RateHz),
+		read: func(ctx context.Context) ([]byte, error) {
+			readings, err := sensor.Readings(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			// readings are converted like they are for the API, so that values such as geo points and
+			// orientations are encoded as objects
+			fields, err := protoutils.ReadingGoToProto(readings)
+			if err != nil {
+				return nil, err
+			}
+			readingsJSON, err := protojson.Marshal(&structpb.Struct{Fields: fields})
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(readingsPayload{Time: time.Now().UTC(), Name: conf.Resource, Readings: readingsJSON})
+		},
+	}, nil
+}
+
+// detectionPayload is a detection in a detections message.
+type detectionPayload struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+	XMin  int     `json:"x_min"`
+	YMin  int     `json:"y_min"`
+	XMax  int     `json:"x_max"`
+	YMax  int     `json:"y_max"`
+}
+
+// detectionsPayload is the message published with the detections of a vision service.
+type detectionsPayload struct {
+	Time          time.Time          `json:"time"`
+	VisionService string             `json:"vision_service"`
+	Camera        string             `json:"camera"`
+	Detections    []detectionPayload `json:"detections"`
+}
+
+func detectionsPublication(deps resource.Dependencies, conf DetectionsConfig) (*publication, error) {
+	svc, err := vision.FromDependencies(deps, conf.VisionService)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := parseTopic(conf.Topic, defaultDetectionsTopic)
+	if err != nil {
+		return nil, err
+	}
+	topic, err := executeTopic(tmpl, topicData{Name: conf.VisionService, Camera: conf.Camera})
+	if err != nil {
+		return nil, err
+	}
+	return &publication{
+		topic:    topic,
+		interval: interval(conf.RateHz),
+		read: func(ctx context.Context) ([]byte, error) {
+			detections, err := svc.DetectionsFromCamera(ctx, conf.Camera, nil)
+			if err != nil {
+				return nil, err
+			}
+			payload := detectionsPayload{Time: time.Now().UTC(), VisionService: conf.VisionService, Camera: conf.Camera}
+			for _, d := range detections {
+				if d.Score() < conf.MinConfidence {
+					continue
+				}
+				box := d.BoundingBox()
+				payload.Detections = append(payload.Detections, detectionPayload{
+					Label: d.Label(),
+					Score: d.Score(),
+					XMin:  box.Min.X,
+					YMin:  box.Min.Y,
+					XMax:  box.Max.X,
+					YMax:  box.Max.Y,
+				})
+			}
+			if len(payload.Detections) == 0 {
+				return nil, nil
+			}
+			return json.Marshal(payload)
+		},
+	}, nil
+}
+
+// publishLoop publishes the messages of the publication at its rate while the client is connected,
+// until the context is done. Failing to read or publish a message is logged.
+func (p *publisher) publishLoop(ctx context.Context, pub *publication) {
+	ticker := time.NewTicker(pub.interval)
+	defer ticker.Stop()
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// messages are not queued while reconnecting, since they would be stale once sent
+		if !p.client.IsConnectionOpen() {
+			continue
+		}
+		payload, err := pub.read(ctx)
+		if err == nil && payload != nil {
+			err = p.publish(pub.topic, payload)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if !failing {
+				p.logger.CWarnw(ctx, "could not publish message", "topic", pub.topic, "error", err)
+			}
+			failing = true
+			continue
+		}
+		failing = false
+	}
+}
+
+// publish publishes the payload on the topic, waiting for the broker to acknowledge it with QoS 1.
+func (p *publisher) publish(topic string, payload []byte) error {
+	token := p.client.Publish(topic, p.qos, p.retain, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		return errors.Errorf("timed out publishing to %q", topic)
+	}
+	return token.Error()
+}
+
+func (p *publisher) Close(ctx context.Context) error {
+	p.cancel()
+	p.activeBackgroundWorkers.Wait()
+	p.client.Disconnect(disconnectQuiesceMS)
+	return nil
+}
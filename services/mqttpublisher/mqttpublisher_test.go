@@ -0,0 +1,323 @@
+package mqttpublisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// The types of the MQTT 3.1.1 control packets handled by the fake broker.
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+
+	connectFlagUsername = 0x80
+)
+
+// fakeBroker is an MQTT broker which accepts every client and records the messages published to it.
+type fakeBroker struct {
+	listener net.Listener
+
+	mu        sync.Mutex
+	clientIDs []string
+	usernames []string
+	messages  map[string][][]byte
+	retained  map[string]bool
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	b := &fakeBroker{listener: listener, messages: map[string][][]byte{}, retained: map[string]bool{}}
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		header, body, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		switch header >> 4 {
+		case packetConnect:
+			// skip the protocol name, level, flags and keep alive
+			payload := body[10:]
+			flags := body[7]
+			clientID, payload := readString(payload)
+			var username string
+			if flags&connectFlagUsername != 0 {
+				username, _ = readString(payload)
+			}
+			b.mu.Lock()
+			b.clientIDs = append(b.clientIDs, clientID)
+			b.usernames = append(b.usernames, username)
+			b.mu.Unlock()
+			if _, err := conn.Write([]byte{packetConnack << 4, 2, 0, 0}); err != nil {
+				return
+			}
+		case packetPublish:
+			topic, rest := readString(body)
+			if qos := (header >> 1) & 3; qos > 0 {
+				ack := []byte{packetPuback << 4, 2, rest[0], rest[1]}
+				rest = rest[2:]
+				if _, err := conn.Write(ack); err != nil {
+					return
+				}
+			}
+			b.mu.Lock()
+			b.messages[topic] = append(b.messages[topic], rest)
+			b.retained[topic] = header&1 != 0
+			b.mu.Unlock()
+		case packetPingreq:
+			if _, err := conn.Write([]byte{packetPingresp << 4, 0}); err != nil {
+				return
+			}
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+// readPacket reads a control packet, returning the first byte of its fixed header and its body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func (b *fakeBroker) last(topic string) ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	msgs := b.messages[topic]
+	if len(msgs) == 0 {
+		return nil, false
+	}
+	return msgs[len(msgs)-1], true
+}
+
+func readString(b []byte) (string, []byte) {
+	n := binary.BigEndian.Uint16(b)
+	return string(b[2 : 2+n]), b[2+n:]
+}
+
+type testSensor struct {
+	sensor.Sensor
+}
+
+func (s *testSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{"temperature": 21.5, "unit": "C"}, nil
+}
+
+type testVision struct {
+	vision.Service
+}
+
+func (v *testVision) DetectionsFromCamera(
+	ctx context.Context,
+	cameraName string,
+	extra map[string]interface{},
+) ([]objectdetection.Detection, error) {
+	return []objectdetection.Detection{
+		objectdetection.NewDetection(image.Rect(1, 2, 3, 4), 0.9, "person"),
+		objectdetection.NewDetection(image.Rect(5, 6, 7, 8), 0.2, "cat"),
+	}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{
+		Broker:     "tcp://localhost:1883",
+		QoS:        1,
+		Readings:   []ReadingsConfig{{Resource: "temp"}},
+		Detections: []DetectionsConfig{{VisionService: "detector", Camera: "cam"}},
+	}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"temp", "detector", "cam"})
+
+	_, err = (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "broker")
+
+	conf.QoS = 2
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "qos")
+
+	conf.QoS = 0
+	conf.Readings[0].Topic = "viam/{{.Name"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "topic template")
+
+	conf.Readings[0].Topic = ""
+	conf.Detections[0].Camera = ""
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "camera")
+
+	conf.Detections = nil
+	conf.Readings[0].RateHz = 1e12
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "rate_hz")
+
+	conf.Readings[0].RateHz = 0
+	conf.Broker = "http://localhost"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "scheme")
+
+	conf.Broker = "tcp://localhost:1883"
+	conf.TLS = &TLSConfig{CertPath: "cert.pem"}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "key_path")
+}
+
+func TestBrokerURL(t *testing.T) {
+	u, err := brokerURL("tcp://localhost", false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, u.String(), test.ShouldEqual, "tcp://localhost:1883")
+	u, err = brokerURL("mqtts://broker.example.com", false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, u.String(), test.ShouldEqual, "mqtts://broker.example.com:8883")
+	// plain urls are connected to over TLS when it is configured
+	u, err = brokerURL("tcp://broker.example.com:1884", true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, u.String(), test.ShouldEqual, "ssl://broker.example.com:1884")
+}
+
+func TestPublisher(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	broker := newFakeBroker(t)
+	defer broker.listener.Close()
+
+	deps := resource.Dependencies{
+		sensor.Named("temp"):     &testSensor{},
+		vision.Named("detector"): &testVision{},
+		camera.Named("cam"):      nil,
+	}
+	reg, ok := resource.LookupRegistration(generic.API, Model)
+	test.That(t, ok, test.ShouldBeTrue)
+	svc, err := reg.Constructor(ctx, deps, resource.Config{
+		Name:  "mqtt",
+		API:   generic.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			Broker:   broker.url(),
+			Username: "user",
+			Password: "secret",
+			QoS:      1,
+			Retain:   true,
+			Readings: []ReadingsConfig{{Resource: "temp", RateHz: 50}},
+			Detections: []DetectionsConfig{{
+				VisionService: "detector",
+				Camera:        "cam",
+				RateHz:        50,
+				MinConfidence: 0.5,
+				Topic:         "events/{{.Camera}}/{{.Name}}",
+			}},
+		},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	t.Run("readings", func(t *testing.T) {
+		var msg []byte
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			var ok bool
+			msg, ok = broker.last("viam/temp/readings")
+			test.That(tb, ok, test.ShouldBeTrue)
+		})
+		var payload struct {
+			Name     string                 `json:"name"`
+			Readings map[string]interface{} `json:"readings"`
+		}
+		test.That(t, json.Unmarshal(msg, &payload), test.ShouldBeNil)
+		test.That(t, payload.Name, test.ShouldEqual, "temp")
+		test.That(t, payload.Readings, test.ShouldResemble, map[string]interface{}{"temperature": 21.5, "unit": "C"})
+
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		test.That(t, broker.retained["viam/temp/readings"], test.ShouldBeTrue)
+		test.That(t, broker.clientIDs[0], test.ShouldEqual, "viam-mqtt")
+		test.That(t, broker.usernames[0], test.ShouldEqual, "user")
+	})
+
+	t.Run("detections", func(t *testing.T) {
+		var msg []byte
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			var ok bool
+			msg, ok = broker.last("events/cam/detector")
+			test.That(tb, ok, test.ShouldBeTrue)
+		})
+		var payload detectionsPayload
+		test.That(t, json.Unmarshal(msg, &payload), test.ShouldBeNil)
+		test.That(t, payload.VisionService, test.ShouldEqual, "detector")
+		test.That(t, payload.Camera, test.ShouldEqual, "cam")
+		test.That(t, payload.Detections, test.ShouldResemble, []detectionPayload{
+			{Label: "person", Score: 0.9, XMin: 1, YMin: 2, XMax: 3, YMax: 4},
+		})
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/framesystem"
@@ -72,6 +73,9 @@ const (
 
 	// frequency in milliseconds.
 	planHistoryPollFrequency = time.Millisecond * 50
+
+	// the method of the operation of a waypoint mission, which runs from when waypoint mode is set until it is left.
+	waypointMissionMethod = "navigation::WaypointMission"
 )
 
 func init() {
@@ -446,7 +450,10 @@ func (svc *builtIn) SetMode(ctx context.Context, mode navigation.Mode, extra map
 	case navigation.ModeManual:
 		// do nothing
 	case navigation.ModeWaypoint:
-		svc.startWaypointMode(cancelCtx, extra)
+		// the mission outlives this call, so it is an operation of its own which reports its progress
+		missionCtx, done := operation.CreateDetached(ctx, cancelCtx, waypointMissionMethod,
+			map[string]interface{}{"name": svc.Name().ShortName()})
+		svc.startWaypointMode(missionCtx, done, extra)
 	case navigation.ModeExplore:
 		if len(svc.motionCfg.ObstacleDetectors) == 0 {
 			return errors.New("explore mode requires at least one vision service")
@@ -541,7 +548,9 @@ func (svc *builtIn) moveToWaypoint(ctx context.Context, wp navigation.Waypoint,
 		MotionCfg:          svc.motionCfg,
 		Extra:              extra,
 	}
-	cancelCtx, cancelFn := context.WithCancel(ctx)
+	// the motion service labels the operation it runs under, so it must not run under the mission's operation, or any
+	// other motion request would cancel the whole mission
+	cancelCtx, cancelFn := context.WithCancel(operation.WithoutOperation(ctx))
 	defer cancelFn()
 	executionID, err := svc.motionService.MoveOnGlobe(cancelCtx, req)
 	if err != nil {
@@ -585,7 +594,10 @@ func (svc *builtIn) moveToWaypoint(ctx context.Context, wp navigation.Waypoint,
 	return svc.waypointReached(cancelCtx)
 }
 
-func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]interface{}) {
+// startWaypointMode navigates to the waypoints in the store until ctx is done, reporting the share of waypoints
+// reached so far as the progress of the mission's operation, and calls done once it stops. The service goes back to
+// manual mode when the mission stops, such as when its operation is cancelled.
+func (svc *builtIn) startWaypointMode(ctx context.Context, done func(), extra map[string]interface{}) {
 	if extra == nil {
 		extra = map[string]interface{}{}
 	}
@@ -594,6 +606,16 @@ func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]inte
 
 	svc.activeBackgroundWorkers.Add(1)
 	utils.ManagedGo(func() {
+		defer done()
+		defer func() {
+			svc.mu.Lock()
+			defer svc.mu.Unlock()
+			if svc.mode == navigation.ModeWaypoint {
+				svc.logger.CInfo(ctx, "waypoint mission stopped, switching to manual mode")
+				svc.mode = navigation.ModeManual
+			}
+		}()
+		var reached int
 		// do not exit loop - even if there are no waypoints remaining
 		for {
 			if ctx.Err() != nil {
@@ -602,9 +624,13 @@ func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]inte
 
 			wp, err := svc.store.NextWaypoint(ctx)
 			if err != nil {
+				operation.SetProgress(ctx, 100, "waiting for waypoints")
 				time.Sleep(planHistoryPollFrequency)
 				continue
 			}
+			if remaining, err := svc.store.Waypoints(ctx); err == nil {
+				operation.SetProgress(ctx, 100*float64(reached)/float64(reached+len(remaining)), "navigating to waypoint "+wp.ID.Hex())
+			}
 			svc.mu.Lock()
 			svc.waypointInProgress = &wp
 			cancelCtx, cancelFunc := context.WithCancel(ctx)
@@ -620,6 +646,7 @@ func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]inte
 				svc.logger.CWarnf(ctx, "retrying navigation to waypoint %+v since it errored out: %s", wp, err)
 				continue
 			}
+			reached++
 			svc.logger.CInfof(ctx, "reached waypoint: %+v", wp)
 		}
 	}, svc.activeBackgroundWorkers.Done)
@@ -14,6 +14,7 @@ import (
 	"go.uber.org/atomic"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/base"
 	baseFake "go.viam.com/rdk/components/base/fake"
@@ -24,6 +25,7 @@ import (
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
@@ -677,6 +679,32 @@ func setupStartWaypointExplore(ctx context.Context, t *testing.T, logger logging
 	}
 }
 
+func TestCancelWaypointMission(t *testing.T) {
+	ns, teardown := setupNavigationServiceFromConfig(t, "../data/nav_cfg.json")
+	defer teardown()
+	manager := operation.NewManager(logging.NewTestLogger(t))
+	ctx, cleanup := manager.Create(context.Background(), "SetMode", nil)
+	defer cleanup()
+
+	test.That(t, ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	var mission *operation.Operation
+	for _, op := range manager.All() {
+		if op.Method == waypointMissionMethod {
+			mission = op
+		}
+	}
+	test.That(t, mission, test.ShouldNotBeNil)
+
+	// the service goes back to manual mode, rather than claim to be in waypoint mode with nothing driving it
+	mission.Cancel()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mode, err := ns.Mode(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, mode, test.ShouldEqual, navigation.ModeManual)
+	})
+}
+
 func TestPaths(t *testing.T) {
 	ctx := context.Background()
 	logger := logging.NewTestLogger(t)
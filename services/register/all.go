@@ -7,6 +7,9 @@ import (
 	_ "go.viam.com/rdk/services/datamanager/register"
 	_ "go.viam.com/rdk/services/generic/register"
 	_ "go.viam.com/rdk/services/mlmodel/register"
+	_ "go.viam.com/rdk/services/mqttpublisher"
+	_ "go.viam.com/rdk/services/ros2bridge"
+	_ "go.viam.com/rdk/services/safetymonitor"
 	_ "go.viam.com/rdk/services/sensors/register"
 	_ "go.viam.com/rdk/services/shell/register"
 	_ "go.viam.com/rdk/services/slam/register"
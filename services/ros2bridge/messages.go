@@ -0,0 +1,211 @@
+package ros2bridge
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The types of the ROS 2 messages the bridge publishes and subscribes to.
+const (
+	imageType       = "sensor_msgs/msg/Image"
+	pointCloud2Type = "sensor_msgs/msg/PointCloud2"
+	odometryType    = "nav_msgs/msg/Odometry"
+	twistType       = "geometry_msgs/msg/Twist"
+)
+
+// pointFieldFloat32 is the datatype of a float32 sensor_msgs/PointField.
+const pointFieldFloat32 = 7
+
+// rosTime is a builtin_interfaces/Time.
+type rosTime struct {
+	Sec     int32  `json:"sec"`
+	Nanosec uint32 `json:"nanosec"`
+}
+
+func newROSTime(t time.Time) rosTime {
+	return rosTime{Sec: int32(t.Unix()), Nanosec: uint32(t.Nanosecond())}
+}
+
+// header is a std_msgs/Header.
+type header struct {
+	Stamp   rosTime `json:"stamp"`
+	FrameID string  `json:"frame_id"`
+}
+
+// imageMsg is a sensor_msgs/Image. Byte arrays are sent to rosbridge base64 encoded, which is how
+// encoding/json encodes them.
+type imageMsg struct {
+	Header      header `json:"header"`
+	Height      uint32 `json:"height"`
+	Width       uint32 `json:"width"`
+	Encoding    string `json:"encoding"`
+	IsBigEndian uint8  `json:"is_bigendian"`
+	Step        uint32 `json:"step"`
+	Data        []byte `json:"data"`
+}
+
+// pointField is a sensor_msgs/PointField.
+type pointField struct {
+	Name     string `json:"name"`
+	Offset   uint32 `json:"offset"`
+	Datatype uint8  `json:"datatype"`
+	Count    uint32 `json:"count"`
+}
+
+// pointCloud2Msg is a sensor_msgs/PointCloud2.
+type pointCloud2Msg struct {
+	Header      header       `json:"header"`
+	Height      uint32       `json:"height"`
+	Width       uint32       `json:"width"`
+	Fields      []pointField `json:"fields"`
+	IsBigEndian bool         `json:"is_bigendian"`
+	PointStep   uint32       `json:"point_step"`
+	RowStep     uint32       `json:"row_step"`
+	Data        []byte       `json:"data"`
+	IsDense     bool         `json:"is_dense"`
+}
+
+// vector3 is a geometry_msgs/Vector3 or geometry_msgs/Point.
+type vector3 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// quaternion is a geometry_msgs/Quaternion.
+type quaternion struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+	W float64 `json:"w"`
+}
+
+// twistMsg is a geometry_msgs/Twist.
+type twistMsg struct {
+	Linear  vector3 `json:"linear"`
+	Angular vector3 `json:"angular"`
+}
+
+// odometryMsg is a nav_msgs/Odometry.
+type odometryMsg struct {
+	Header       header `json:"header"`
+	ChildFrameID string `json:"child_frame_id"`
+	Pose         struct {
+		Pose struct {
+			Position    vector3    `json:"position"`
+			Orientation quaternion `json:"orientation"`
+		} `json:"pose"`
+		Covariance [36]float64 `json:"covariance"`
+	} `json:"pose"`
+	Twist struct {
+		Twist      twistMsg    `json:"twist"`
+		Covariance [36]float64 `json:"covariance"`
+	} `json:"twist"`
+}
+
+// newImageMsg converts an image to a sensor_msgs/Image. Depth maps are sent as 16 bit millimeters
+// and everything else as 8 bit RGB.
+func newImageMsg(img image.Image, frameID string, stamp time.Time) *imageMsg {
+	bounds := img.Bounds()
+	msg := &imageMsg{
+		Header: header{Stamp: newROSTime(stamp), FrameID: frameID},
+		Height: uint32(bounds.Dy()),
+		Width:  uint32(bounds.Dx()),
+	}
+	if dm, ok := img.(*rimage.DepthMap); ok {
+		msg.Encoding = "16UC1"
+		msg.Step = msg.Width * 2
+		msg.Data = make([]byte, 0, int(msg.Step)*bounds.Dy())
+		for y := 0; y < dm.Height(); y++ {
+			for x := 0; x < dm.Width(); x++ {
+				msg.Data = binary.LittleEndian.AppendUint16(msg.Data, uint16(dm.GetDepth(x, y)))
+			}
+		}
+		return msg
+	}
+	msg.Encoding = "rgb8"
+	msg.Step = msg.Width * 3
+	msg.Data = make([]byte, 0, int(msg.Step)*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			msg.Data = append(msg.Data, c.R, c.G, c.B)
+		}
+	}
+	return msg
+}
+
+// newPointCloud2Msg converts a point cloud to an unordered sensor_msgs/PointCloud2 in meters, with
+// the colors of the points packed into an rgb field if it has them.
+func newPointCloud2Msg(pc pointcloud.PointCloud, frameID string, stamp time.Time) *pointCloud2Msg {
+	msg := &pointCloud2Msg{
+		Header: header{Stamp: newROSTime(stamp), FrameID: frameID},
+		Height: 1,
+		Width:  uint32(pc.Size()),
+		Fields: []pointField{
+			{Name: "x", Offset: 0, Datatype: pointFieldFloat32, Count: 1},
+			{Name: "y", Offset: 4, Datatype: pointFieldFloat32, Count: 1},
+			{Name: "z", Offset: 8, Datatype: pointFieldFloat32, Count: 1},
+		},
+		PointStep: 12,
+		IsDense:   true,
+	}
+	hasColor := pc.MetaData().HasColor
+	if hasColor {
+		msg.Fields = append(msg.Fields, pointField{Name: "rgb", Offset: 12, Datatype: pointFieldFloat32, Count: 1})
+		msg.PointStep = 16
+	}
+	msg.RowStep = msg.PointStep * msg.Width
+	msg.Data = make([]byte, 0, int(msg.RowStep))
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		for _, v := range []float64{p.X, p.Y, p.Z} {
+			msg.Data = binary.LittleEndian.AppendUint32(msg.Data, math.Float32bits(float32(v/1000)))
+		}
+		if hasColor {
+			var rgb uint32
+			if d != nil && d.HasColor() {
+				r, g, b := d.RGB255()
+				rgb = uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+			}
+			msg.Data = binary.LittleEndian.AppendUint32(msg.Data, rgb)
+		}
+		return true
+	})
+	return msg
+}
+
+// The ROS base frame has its X axis pointing forward and its Y axis pointing left, while a base's
+// frame has its Y axis pointing forward and its X axis pointing right. Both have Z pointing up.
+
+// baseToROS converts a vector in a base's frame to the ROS base frame.
+func baseToROS(v r3.Vector) vector3 {
+	return vector3{X: v.Y, Y: -v.X, Z: v.Z}
+}
+
+// rosToBase converts a vector in the ROS base frame to a base's frame.
+func rosToBase(v vector3) r3.Vector {
+	return r3.Vector{X: -v.Y, Y: v.X, Z: v.Z}
+}
+
+// newQuaternion converts an orientation to a geometry_msgs/Quaternion.
+func newQuaternion(o spatialmath.Orientation) quaternion {
+	q := o.Quaternion()
+	return quaternion{X: q.Imag, Y: q.Jmag, Z: q.Kmag, W: q.Real}
+}
+
+// twistToVelocity converts a geometry_msgs/Twist in meters and radians per second to the linear and
+// angular velocities of a base, in millimeters and degrees per second.
+func twistToVelocity(twist twistMsg) (linear, angular r3.Vector) {
+	linear = rosToBase(twist.Linear).Mul(1000)
+	angular = rosToBase(twist.Angular).Mul(180 / math.Pi)
+	return linear, angular
+}
@@ -0,0 +1,501 @@
+// Package ros2bridge implements a service which bridges the resources of a robot to ROS 2 topics
+// through a rosbridge server, so that ROS 2 nodes can use its cameras and sensors and drive its
+// bases.
+package ros2bridge
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Model is the model of the ROS 2 bridge service.
+var Model = resource.DefaultModelFamily.WithModel("ros2_bridge")
+
+const (
+	defaultPublishRateHz   = 10
+	maxPublishRateHz       = 1000
+	defaultCmdVelTimeoutMS = 1000
+	defaultOdomFrameID     = "odom"
+	defaultBaseFrameID     = "base_link"
+
+	reconnectInterval = time.Second
+	watchdogInterval  = 100 * time.Millisecond
+)
+
+func init() {
+	resource.RegisterService(generic.API, Model, resource.Registration[resource.Resource, *Config]{
+		Constructor: newBridge,
+	})
+}
+
+// CameraTopic publishes the images or point clouds of a camera on a topic.
+type CameraTopic struct {
+	Camera string `json:"camera"`
+	Topic  string `json:"topic"`
+	// FrameID defaults to the name of the camera.
+	FrameID string `json:"frame_id,omitempty"`
+}
+
+// OdometryTopic publishes the position, orientation and velocities of a movement sensor on a topic.
+type OdometryTopic struct {
+	MovementSensor string `json:"movement_sensor"`
+	Topic          string `json:"topic"`
+	FrameID        string `json:"frame_id,omitempty"`
+	ChildFrameID   string `json:"child_frame_id,omitempty"`
+}
+
+// CmdVelTopic drives a base with the velocities published on a topic. The base is stopped if no
+// velocity is published for the timeout.
+type CmdVelTopic struct {
+	Base      string `json:"base"`
+	Topic     string `json:"topic"`
+	TimeoutMS int    `json:"timeout_ms,omitempty"`
+}
+
+// Config describes how to configure the service.
+type Config struct {
+	// URL is the websocket url of the rosbridge server, e.g. ws://localhost:9090.
+	URL           string          `json:"url"`
+	PublishRateHz float64         `json:"publish_rate_hz,omitempty"`
+	Images        []CameraTopic   `json:"images,omitempty"`
+	PointClouds   []CameraTopic   `json:"point_clouds,omitempty"`
+	Odometry      []OdometryTopic `json:"odometry,omitempty"`
+	CmdVel        []CmdVelTopic   `json:"cmd_vel,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the bridged resources.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.URL == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "url")
+	}
+	if conf.PublishRateHz < 0 || conf.PublishRateHz > maxPublishRateHz {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("publish_rate_hz must be between 0 and %d", maxPublishRateHz))
+	}
+
+	var deps []string
+	topics := map[string]bool{}
+	addTopic := func(field, name, topic string) error {
+		if name == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, field)
+		}
+		if topic == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "topic")
+		}
+		if topics[topic] {
+			return resource.NewConfigValidationError(path, errors.Errorf("topic %q is bridged more than once", topic))
+		}
+		topics[topic] = true
+		deps = append(deps, name)
+		return nil
+	}
+	for _, t := range conf.Images {
+		if err := addTopic("camera", t.Camera, t.Topic); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range conf.PointClouds {
+		if err := addTopic("camera", t.Camera, t.Topic); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range conf.Odometry {
+		if err := addTopic("movement_sensor", t.MovementSensor, t.Topic); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range conf.CmdVel {
+		if err := addTopic("base", t.Base, t.Topic); err != nil {
+			return nil, err
+		}
+		if t.TimeoutMS < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.New("timeout_ms cannot be negative"))
+		}
+	}
+	return deps, nil
+}
+
+// publisher publishes the messages read from a resource on a topic.
+type publisher struct {
+	topic   string
+	msgType string
+	read    func(ctx context.Context, stamp time.Time) (interface{}, error)
+	failing bool
+}
+
+// cmdVelSubscriber drives a base with the velocities published on a topic.
+type cmdVelSubscriber struct {
+	topic   string
+	base    base.Base
+	timeout time.Duration
+	logger  logging.Logger
+
+	mu      sync.Mutex
+	lastCmd time.Time
+	moving  bool
+}
+
+type bridge struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	url             string
+	publishInterval time.Duration
+	publishers      []*publisher
+	cmdVels         []*cmdVelSubscriber
+	logger          logging.Logger
+
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newBridge(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (resource.Resource, error) {
+	svcConfig, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	rate := svcConfig.PublishRateHz
+	switch {
+	case rate <= 0:
+		rate = defaultPublishRateHz
+	case rate > maxPublishRateHz:
+		rate = maxPublishRateHz
+	}
+	b := &bridge{
+		Named:           conf.ResourceName().AsNamed(),
+		url:             svcConfig.URL,
+		publishInterval: time.Duration(float64(time.Second) / rate),
+		logger:          logger,
+	}
+
+	for _, t := range svcConfig.Images {
+		cam, err := camera.FromDependencies(deps, t.Camera)
+		if err != nil {
+			return nil, err
+		}
+		b.publishers = append(b.publishers, imagePublisher(cam, t))
+	}
+	for _, t := range svcConfig.PointClouds {
+		cam, err := camera.FromDependencies(deps, t.Camera)
+		if err != nil {
+			return nil, err
+		}
+		b.publishers = append(b.publishers, pointCloudPublisher(cam, t))
+	}
+	for _, t := range svcConfig.Odometry {
+		ms, err := movementsensor.FromDependencies(deps, t.MovementSensor)
+		if err != nil {
+			return nil, err
+		}
+		b.publishers = append(b.publishers, odometryPublisher(ms, t))
+	}
+	for _, t := range svcConfig.CmdVel {
+		base1, err := base.FromDependencies(deps, t.Base)
+		if err != nil {
+			return nil, err
+		}
+		timeoutMS := t.TimeoutMS
+		if timeoutMS == 0 {
+			timeoutMS = defaultCmdVelTimeoutMS
+		}
+		b.cmdVels = append(b.cmdVels, &cmdVelSubscriber{
+			topic:   t.Topic,
+			base:    base1,
+			timeout: time.Duration(timeoutMS) * time.Millisecond,
+			logger:  logger,
+		})
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.activeBackgroundWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer b.activeBackgroundWorkers.Done()
+		b.run(cancelCtx)
+	})
+	return b, nil
+}
+
+// run keeps the bridge connected to rosbridge until the context is done.
+func (b *bridge) run(ctx context.Context) {
+	for {
+		err := b.session(ctx)
+		b.stopBases(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.CWarnw(ctx, "lost connection to rosbridge, reconnecting", "url", b.url, "error", err)
+		if !goutils.SelectContextOrWait(ctx, reconnectInterval) {
+			return
+		}
+	}
+}
+
+// session bridges the topics over a single connection to rosbridge, until it fails or the context is
+// done.
+func (b *bridge) session(ctx context.Context) error {
+	conn, err := dialRosbridge(ctx, b.url)
+	if err != nil {
+		return err
+	}
+	sessionCtx, cancel := context.WithCancel(ctx)
+	readDone := make(chan struct{})
+	var readErr error
+	goutils.PanicCapturingGo(func() {
+		defer close(readDone)
+		readErr = conn.readLoop(sessionCtx)
+	})
+	// the bases are stopped on time even while publishing is slow, e.g. waiting for a camera
+	watchdogDone := make(chan struct{})
+	goutils.PanicCapturingGo(func() {
+		defer close(watchdogDone)
+		b.watchCmdVels(sessionCtx)
+	})
+	defer func() {
+		cancel()
+		goutils.UncheckedError(conn.close())
+		<-readDone
+		<-watchdogDone
+	}()
+
+	for _, p := range b.publishers {
+		if err := conn.advertise(sessionCtx, p.topic, p.msgType); err != nil {
+			return err
+		}
+	}
+	for _, s := range b.cmdVels {
+		s := s
+		if err := conn.subscribe(sessionCtx, s.topic, twistType, func(msg json.RawMessage) {
+			s.handle(sessionCtx, msg)
+		}); err != nil {
+			return err
+		}
+	}
+	b.logger.CInfow(ctx, "connected to rosbridge", "url", b.url)
+
+	publishTicker := time.NewTicker(b.publishInterval)
+	defer publishTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-readDone:
+			return readErr
+		case <-publishTicker.C:
+			if err := b.publishAll(sessionCtx, conn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchCmdVels stops the bases which have not been sent a velocity for their timeouts, until the
+// context is done.
+func (b *bridge) watchCmdVels(ctx context.Context) {
+	if len(b.cmdVels) == 0 {
+		return
+	}
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, s := range b.cmdVels {
+				s.stopIfStale(ctx, now)
+			}
+		}
+	}
+}
+
+// publishAll publishes a message from every publisher. Resources which fail to be read are skipped,
+// and only failures to publish are returned.
+func (b *bridge) publishAll(ctx context.Context, conn *rosbridgeConn) error {
+	for _, p := range b.publishers {
+		msg, err := p.read(ctx, time.Now())
+		if err != nil {
+			if !p.failing {
+				b.logger.CWarnw(ctx, "could not read message to publish", "topic", p.topic, "error", err)
+			}
+			p.failing = true
+			continue
+		}
+		p.failing = false
+		if err := conn.publish(ctx, p.topic, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bridge) stopBases(ctx context.Context) {
+	for _, s := range b.cmdVels {
+		s.stop(ctx)
+	}
+}
+
+func (b *bridge) Close(ctx context.Context) error {
+	b.cancel()
+	b.activeBackgroundWorkers.Wait()
+	return nil
+}
+
+func imagePublisher(cam camera.Camera, t CameraTopic) *publisher {
+	frameID := t.FrameID
+	if frameID == "" {
+		frameID = t.Camera
+	}
+	return &publisher{
+		topic:   t.Topic,
+		msgType: imageType,
+		read: func(ctx context.Context, stamp time.Time) (interface{}, error) {
+			img, release, err := camera.ReadImage(ctx, cam)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			return newImageMsg(img, frameID, stamp), nil
+		},
+	}
+}
+
+func pointCloudPublisher(cam camera.Camera, t CameraTopic) *publisher {
+	frameID := t.FrameID
+	if frameID == "" {
+		frameID = t.Camera
+	}
+	return &publisher{
+		topic:   t.Topic,
+		msgType: pointCloud2Type,
+		read: func(ctx context.Context, stamp time.Time) (interface{}, error) {
+			pc, err := cam.NextPointCloud(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newPointCloud2Msg(pc, frameID, stamp), nil
+		},
+	}
+}
+
+// odometryPublisher publishes the supported readings of the movement sensor. Positions are relative
+// to the first position read.
+func odometryPublisher(ms movementsensor.MovementSensor, t OdometryTopic) *publisher {
+	frameID, childFrameID := t.FrameID, t.ChildFrameID
+	if frameID == "" {
+		frameID = defaultOdomFrameID
+	}
+	if childFrameID == "" {
+		childFrameID = defaultBaseFrameID
+	}
+	var origin *geo.Point
+	return &publisher{
+		topic:   t.Topic,
+		msgType: odometryType,
+		read: func(ctx context.Context, stamp time.Time) (interface{}, error) {
+			props, err := ms.Properties(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			msg := &odometryMsg{Header: header{Stamp: newROSTime(stamp), FrameID: frameID}, ChildFrameID: childFrameID}
+			msg.Pose.Pose.Orientation.W = 1
+			if props.PositionSupported {
+				point, altitude, err := ms.Position(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				if origin == nil {
+					origin = point
+				}
+				position := spatialmath.GeoPointToPoint(point, origin)
+				msg.Pose.Pose.Position = vector3{X: position.X / 1000, Y: position.Y / 1000, Z: altitude}
+			}
+			if props.OrientationSupported {
+				orientation, err := ms.Orientation(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				msg.Pose.Pose.Orientation = newQuaternion(orientation)
+			}
+			if props.LinearVelocitySupported {
+				linear, err := ms.LinearVelocity(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				msg.Twist.Twist.Linear = baseToROS(linear)
+			}
+			if props.AngularVelocitySupported {
+				angular, err := ms.AngularVelocity(ctx, nil)
+				if err != nil {
+					return nil, err
+				}
+				msg.Twist.Twist.Angular = baseToROS(r3.Vector(angular).Mul(math.Pi / 180))
+			}
+			return msg, nil
+		},
+	}
+}
+
+// handle drives the base with the velocity of a geometry_msgs/Twist.
+func (s *cmdVelSubscriber) handle(ctx context.Context, raw json.RawMessage) {
+	var twist twistMsg
+	if err := json.Unmarshal(raw, &twist); err != nil {
+		s.logger.CWarnw(ctx, "invalid velocity", "topic", s.topic, "error", err)
+		return
+	}
+	linear, angular := twistToVelocity(twist)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastCmd = time.Now()
+	if err := s.base.SetVelocity(ctx, linear, angular, nil); err != nil {
+		s.logger.CWarnw(ctx, "could not set velocity of base", "base", s.base.Name().ShortName(), "error", err)
+		return
+	}
+	s.moving = linear.Norm2() != 0 || angular.Norm2() != 0
+}
+
+// stopIfStale stops the base if it has not been sent a velocity for the timeout.
+func (s *cmdVelSubscriber) stopIfStale(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	stale := s.moving && now.Sub(s.lastCmd) > s.timeout
+	s.mu.Unlock()
+	if stale {
+		s.logger.CWarnw(ctx, "no velocity received before timeout, stopping base", "topic", s.topic, "timeout", s.timeout)
+		s.stop(ctx)
+	}
+}
+
+// stop stops the base if it was moved by the bridge.
+func (s *cmdVelSubscriber) stop(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.moving {
+		return
+	}
+	if err := s.base.Stop(ctx, nil); err != nil {
+		s.logger.CWarnw(ctx, "could not stop base", "base", s.base.Name().ShortName(), "error", err)
+		return
+	}
+	s.moving = false
+}
@@ -0,0 +1,357 @@
+package ros2bridge
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+	"nhooyr.io/websocket"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// fakeRosbridge is a rosbridge server which records the operations of its client.
+type fakeRosbridge struct {
+	mu   sync.Mutex
+	ops  []rosbridgeOp
+	conn *websocket.Conn
+}
+
+func (f *fakeRosbridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn.SetReadLimit(1 << 24)
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	for {
+		_, data, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		var op rosbridgeOp
+		if err := json.Unmarshal(data, &op); err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.ops = append(f.ops, op)
+		f.mu.Unlock()
+	}
+}
+
+// last returns the last operation of the kind on the topic.
+func (f *fakeRosbridge) last(kind, topic string) (rosbridgeOp, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.ops) - 1; i >= 0; i-- {
+		if f.ops[i].Op == kind && f.ops[i].Topic == topic {
+			return f.ops[i], true
+		}
+	}
+	return rosbridgeOp{}, false
+}
+
+func (f *fakeRosbridge) publish(ctx context.Context, topic string, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	op, err := json.Marshal(rosbridgeOp{Op: "publish", Topic: topic, Msg: data})
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	conn := f.conn
+	f.mu.Unlock()
+	return conn.Write(ctx, websocket.MessageText, op)
+}
+
+type testBase struct {
+	base.Base
+	mu              sync.Mutex
+	linear, angular r3.Vector
+	stops           int
+}
+
+func (b *testBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.linear, b.angular = linear, angular
+	return nil
+}
+
+func (b *testBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.linear, b.angular = r3.Vector{}, r3.Vector{}
+	b.stops++
+	return nil
+}
+
+type testMovementSensor struct {
+	movementsensor.MovementSensor
+}
+
+func (ms *testMovementSensor) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return &movementsensor.Properties{OrientationSupported: true, LinearVelocitySupported: true, AngularVelocitySupported: true}, nil
+}
+
+func (ms *testMovementSensor) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	return &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90}, nil
+}
+
+func (ms *testMovementSensor) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{Y: 0.5}, nil
+}
+
+func (ms *testMovementSensor) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	return spatialmath.AngularVelocity{Z: 90}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{
+		URL:      "ws://localhost:9090",
+		Images:   []CameraTopic{{Camera: "cam", Topic: "/image"}},
+		Odometry: []OdometryTopic{{MovementSensor: "odom", Topic: "/odom"}},
+		CmdVel:   []CmdVelTopic{{Base: "base", Topic: "/cmd_vel"}},
+	}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"cam", "odom", "base"})
+
+	_, err = (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "url")
+
+	conf.PointClouds = []CameraTopic{{Camera: "cam", Topic: "/image"}}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "more than once")
+
+	conf.PointClouds = []CameraTopic{{Topic: "/points"}}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "camera")
+
+	conf.PointClouds = nil
+	conf.PublishRateHz = 1e12
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "publish_rate_hz")
+}
+
+func TestBridge(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	rosbridge := &fakeRosbridge{}
+	server := httptest.NewServer(rosbridge)
+	defer server.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(1, 0, color.RGBA{R: 10, G: 20, B: 30, A: 255})
+	src, err := camera.NewVideoSourceFromReader(ctx, gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		return img, func() {}, nil
+	}), nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	cam := camera.FromVideoSource(camera.Named("cam"), src, logger)
+	b := &testBase{}
+	deps := resource.Dependencies{
+		camera.Named("cam"):          cam,
+		movementsensor.Named("odom"): &testMovementSensor{},
+		base.Named("base"):           b,
+	}
+
+	reg, ok := resource.LookupRegistration(generic.API, Model)
+	test.That(t, ok, test.ShouldBeTrue)
+	svc, err := reg.Constructor(ctx, deps, resource.Config{
+		Name:  "bridge",
+		API:   generic.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			URL:           "ws" + strings.TrimPrefix(server.URL, "http"),
+			PublishRateHz: 50,
+			Images:        []CameraTopic{{Camera: "cam", Topic: "/image"}},
+			Odometry:      []OdometryTopic{{MovementSensor: "odom", Topic: "/odom"}},
+			CmdVel:        []CmdVelTopic{{Base: "base", Topic: "/cmd_vel", TimeoutMS: 200}},
+		},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		op, ok := rosbridge.last("advertise", "/image")
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, op.Type, test.ShouldEqual, imageType)
+		op, ok = rosbridge.last("subscribe", "/cmd_vel")
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, op.Type, test.ShouldEqual, twistType)
+		_, ok = rosbridge.last("publish", "/odom")
+		test.That(tb, ok, test.ShouldBeTrue)
+	})
+
+	t.Run("images", func(t *testing.T) {
+		op, ok := rosbridge.last("publish", "/image")
+		test.That(t, ok, test.ShouldBeTrue)
+		var msg imageMsg
+		test.That(t, json.Unmarshal(op.Msg, &msg), test.ShouldBeNil)
+		test.That(t, msg.Header.FrameID, test.ShouldEqual, "cam")
+		test.That(t, msg.Encoding, test.ShouldEqual, "rgb8")
+		test.That(t, msg.Width, test.ShouldEqual, 4)
+		test.That(t, msg.Height, test.ShouldEqual, 2)
+		test.That(t, msg.Step, test.ShouldEqual, 12)
+		test.That(t, msg.Data[3:6], test.ShouldResemble, []byte{10, 20, 30})
+	})
+
+	t.Run("odometry", func(t *testing.T) {
+		op, _ := rosbridge.last("publish", "/odom")
+		var msg odometryMsg
+		test.That(t, json.Unmarshal(op.Msg, &msg), test.ShouldBeNil)
+		test.That(t, msg.Header.FrameID, test.ShouldEqual, defaultOdomFrameID)
+		test.That(t, msg.ChildFrameID, test.ShouldEqual, defaultBaseFrameID)
+		// forward in the base's frame is X in ROS
+		test.That(t, msg.Twist.Twist.Linear.X, test.ShouldAlmostEqual, 0.5)
+		test.That(t, msg.Twist.Twist.Angular.Z, test.ShouldAlmostEqual, math.Pi/2)
+		test.That(t, msg.Pose.Pose.Orientation.Z, test.ShouldAlmostEqual, math.Sqrt2/2)
+		test.That(t, msg.Pose.Pose.Orientation.W, test.ShouldAlmostEqual, math.Sqrt2/2)
+	})
+
+	t.Run("cmd_vel", func(t *testing.T) {
+		twist := twistMsg{Linear: vector3{X: 0.2}, Angular: vector3{Z: math.Pi / 4}}
+		test.That(t, rosbridge.publish(ctx, "/cmd_vel", twist), test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			test.That(tb, b.linear.Y, test.ShouldAlmostEqual, 200)
+			test.That(tb, b.angular.Z, test.ShouldAlmostEqual, 45)
+		})
+
+		// the base stops when no velocity is published before the timeout
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			test.That(tb, b.stops, test.ShouldEqual, 1)
+			test.That(tb, b.linear, test.ShouldResemble, r3.Vector{})
+		})
+	})
+}
+
+func TestMessages(t *testing.T) {
+	stamp := time.Unix(100, 5)
+
+	t.Run("depth image", func(t *testing.T) {
+		dm := rimage.NewEmptyDepthMap(2, 1)
+		dm.Set(1, 0, rimage.Depth(1234))
+		msg := newImageMsg(dm, "depth", stamp)
+		test.That(t, msg.Encoding, test.ShouldEqual, "16UC1")
+		test.That(t, msg.Step, test.ShouldEqual, 4)
+		test.That(t, binary.LittleEndian.Uint16(msg.Data[2:]), test.ShouldEqual, 1234)
+		test.That(t, msg.Header.Stamp, test.ShouldResemble, rosTime{Sec: 100, Nanosec: 5})
+	})
+
+	t.Run("point cloud", func(t *testing.T) {
+		pc := pointcloud.New()
+		data := pointcloud.NewColoredData(color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+		test.That(t, pc.Set(r3.Vector{X: 1000, Y: -500, Z: 250}, data), test.ShouldBeNil)
+		msg := newPointCloud2Msg(pc, "cam", stamp)
+		test.That(t, msg.Width, test.ShouldEqual, 1)
+		test.That(t, msg.PointStep, test.ShouldEqual, 16)
+		test.That(t, len(msg.Fields), test.ShouldEqual, 4)
+		test.That(t, len(msg.Data), test.ShouldEqual, 16)
+		test.That(t, math.Float32frombits(binary.LittleEndian.Uint32(msg.Data[0:])), test.ShouldEqual, 1)
+		test.That(t, math.Float32frombits(binary.LittleEndian.Uint32(msg.Data[4:])), test.ShouldEqual, -0.5)
+		test.That(t, math.Float32frombits(binary.LittleEndian.Uint32(msg.Data[8:])), test.ShouldEqual, 0.25)
+		test.That(t, binary.LittleEndian.Uint32(msg.Data[12:]), test.ShouldEqual, 0x010203)
+	})
+
+	t.Run("twist", func(t *testing.T) {
+		// left in ROS is -X in the base's frame
+		linear, angular := twistToVelocity(twistMsg{Linear: vector3{X: 1, Y: 0.5}, Angular: vector3{Z: -math.Pi}})
+		test.That(t, linear, test.ShouldResemble, r3.Vector{X: -500, Y: 1000})
+		test.That(t, angular.Z, test.ShouldAlmostEqual, -180)
+	})
+}
+
+func TestCmdVelWatchdogWhilePublishing(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	rosbridge := &fakeRosbridge{}
+	server := httptest.NewServer(rosbridge)
+	defer server.Close()
+
+	// reading the camera blocks publishing until the test ends
+	unblock := make(chan struct{})
+	src, err := camera.NewVideoSourceFromReader(ctx, gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		<-unblock
+		return nil, nil, errors.New("camera closed")
+	}), nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	b := &testBase{}
+	deps := resource.Dependencies{
+		camera.Named("cam"): camera.FromVideoSource(camera.Named("cam"), src, logger),
+		base.Named("base"):  b,
+	}
+
+	reg, ok := resource.LookupRegistration(generic.API, Model)
+	test.That(t, ok, test.ShouldBeTrue)
+	svc, err := reg.Constructor(ctx, deps, resource.Config{
+		Name:  "bridge",
+		API:   generic.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			URL:           "ws" + strings.TrimPrefix(server.URL, "http"),
+			PublishRateHz: 50,
+			Images:        []CameraTopic{{Camera: "cam", Topic: "/image"}},
+			CmdVel:        []CmdVelTopic{{Base: "base", Topic: "/cmd_vel", TimeoutMS: 200}},
+		},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	}()
+	defer close(unblock)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		_, ok := rosbridge.last("subscribe", "/cmd_vel")
+		test.That(tb, ok, test.ShouldBeTrue)
+	})
+	twist := twistMsg{Linear: vector3{X: 0.2}}
+	test.That(t, rosbridge.publish(ctx, "/cmd_vel", twist), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		test.That(tb, b.stops, test.ShouldEqual, 1)
+	})
+}
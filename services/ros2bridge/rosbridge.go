@@ -0,0 +1,110 @@
+package ros2bridge
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"nhooyr.io/websocket"
+)
+
+const (
+	// maxMessageBytes is the size of the largest message accepted from rosbridge.
+	maxMessageBytes = 1 << 20
+	dialTimeout     = 10 * time.Second
+)
+
+// rosbridgeOp is a message of the rosbridge protocol.
+type rosbridgeOp struct {
+	Op    string          `json:"op"`
+	Topic string          `json:"topic,omitempty"`
+	Type  string          `json:"type,omitempty"`
+	Msg   json.RawMessage `json:"msg,omitempty"`
+}
+
+// rosbridgeConn is a connection to a rosbridge server, which relays topics between its clients
+// and ROS 2 nodes.
+type rosbridgeConn struct {
+	ws *websocket.Conn
+
+	writeMu sync.Mutex
+
+	handlersMu sync.Mutex
+	handlers   map[string]func(json.RawMessage)
+}
+
+// dialRosbridge connects to the rosbridge server at the url.
+func dialRosbridge(ctx context.Context, url string) (*rosbridgeConn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	//nolint:bodyclose // websocket closes the body of the handshake response
+	ws, _, err := websocket.Dial(dialCtx, url, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not connect to rosbridge at %q", url)
+	}
+	ws.SetReadLimit(maxMessageBytes)
+	return &rosbridgeConn{ws: ws, handlers: map[string]func(json.RawMessage){}}, nil
+}
+
+func (c *rosbridgeConn) send(ctx context.Context, op rosbridgeOp) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// advertise announces that messages of the given type will be published on the topic.
+func (c *rosbridgeConn) advertise(ctx context.Context, topic, msgType string) error {
+	return c.send(ctx, rosbridgeOp{Op: "advertise", Topic: topic, Type: msgType})
+}
+
+// publish publishes the message on an advertised topic.
+func (c *rosbridgeConn) publish(ctx context.Context, topic string, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, rosbridgeOp{Op: "publish", Topic: topic, Msg: data})
+}
+
+// subscribe calls the handler with every message published on the topic, from the goroutine
+// running readLoop.
+func (c *rosbridgeConn) subscribe(ctx context.Context, topic, msgType string, handler func(json.RawMessage)) error {
+	c.handlersMu.Lock()
+	c.handlers[topic] = handler
+	c.handlersMu.Unlock()
+	return c.send(ctx, rosbridgeOp{Op: "subscribe", Topic: topic, Type: msgType})
+}
+
+// readLoop dispatches the messages of subscribed topics until the connection fails.
+func (c *rosbridgeConn) readLoop(ctx context.Context) error {
+	for {
+		_, data, err := c.ws.Read(ctx)
+		if err != nil {
+			return err
+		}
+		var op rosbridgeOp
+		if err := json.Unmarshal(data, &op); err != nil {
+			return errors.Wrap(err, "invalid message from rosbridge")
+		}
+		// rosbridge also sends status and service messages, which the bridge does not use
+		if op.Op != "publish" {
+			continue
+		}
+		c.handlersMu.Lock()
+		handler := c.handlers[op.Topic]
+		c.handlersMu.Unlock()
+		if handler != nil {
+			handler(op.Msg)
+		}
+	}
+}
+
+func (c *rosbridgeConn) close() error {
+	return c.ws.Close(websocket.StatusNormalClosure, "")
+}
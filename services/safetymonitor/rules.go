@@ -0,0 +1,240 @@
+package safetymonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// SensorRule triggers the e-stop when a reading of a sensor is outside of its bounds.
+type SensorRule struct {
+	Sensor  string   `json:"sensor"`
+	Reading string   `json:"reading"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	// TriggerOnError triggers the e-stop when the reading cannot be read, instead of only logging it.
+	TriggerOnError bool `json:"trigger_on_error,omitempty"`
+}
+
+// Validate ensures all parts of the rule are valid.
+func (r *SensorRule) Validate(path string) error {
+	if r.Sensor == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "sensor")
+	}
+	if r.Reading == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "reading")
+	}
+	if r.Min == nil && r.Max == nil {
+		return resource.NewConfigValidationError(path, errors.New("at least one of min or max must be set"))
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return resource.NewConfigValidationError(path, errors.New("min cannot be greater than max"))
+	}
+	return nil
+}
+
+// GeoPoint is a point on the globe.
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// GeofenceRule triggers the e-stop when a movement sensor leaves an area, which is either a circle or a
+// polygon.
+type GeofenceRule struct {
+	MovementSensor string     `json:"movement_sensor"`
+	Center         *GeoPoint  `json:"center,omitempty"`
+	RadiusMeters   float64    `json:"radius_m,omitempty"`
+	Polygon        []GeoPoint `json:"polygon,omitempty"`
+	// TriggerOnError triggers the e-stop when the position cannot be read, instead of only logging it.
+	TriggerOnError bool `json:"trigger_on_error,omitempty"`
+}
+
+// Validate ensures all parts of the rule are valid.
+func (r *GeofenceRule) Validate(path string) error {
+	if r.MovementSensor == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "movement_sensor")
+	}
+	if (r.Center == nil) == (len(r.Polygon) == 0) {
+		return resource.NewConfigValidationError(path, errors.New("exactly one of center or polygon must be set"))
+	}
+	if r.Center != nil && r.RadiusMeters <= 0 {
+		return resource.NewConfigValidationError(path, errors.New("radius_m must be positive"))
+	}
+	if r.Center == nil && len(r.Polygon) < 3 {
+		return resource.NewConfigValidationError(path, errors.New("polygon must have at least 3 points"))
+	}
+	return nil
+}
+
+// readErrorLogInterval is how often a rule which keeps failing to read its resource logs it.
+const readErrorLogInterval = 10 * time.Second
+
+// rule is a condition which triggers the e-stop.
+type rule interface {
+	// check returns why the e-stop must be triggered, or an empty string if it must not be.
+	check(ctx context.Context) string
+	// describe names the rule in the reasons the e-stop is triggered for.
+	describe() string
+}
+
+// readErrorLogger logs the errors a rule gets reading its resource, at most once per
+// readErrorLogInterval, since rules are checked many times a second.
+type readErrorLogger struct {
+	logger logging.Logger
+
+	mu         sync.Mutex
+	lastLogged time.Time
+	skipped    int
+}
+
+func (l *readErrorLogger) warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Since(l.lastLogged) < readErrorLogInterval {
+		l.skipped++
+		return
+	}
+	if l.skipped != 0 {
+		keysAndValues = append(keysAndValues, "errors_since_last_logged", l.skipped)
+	}
+	l.logger.CWarnw(ctx, msg, keysAndValues...)
+	l.lastLogged = time.Now()
+	l.skipped = 0
+}
+
+type sensorRule struct {
+	conf       SensorRule
+	sensor     resource.Sensor
+	readErrors readErrorLogger
+}
+
+func (r *sensorRule) describe() string {
+	return fmt.Sprintf("%q of sensor %q", r.conf.Reading, r.conf.Sensor)
+}
+
+func (r *sensorRule) check(ctx context.Context) string {
+	value, err := r.reading(ctx)
+	if err != nil {
+		if r.conf.TriggerOnError {
+			return fmt.Sprintf("could not read %q of sensor %q: %v", r.conf.Reading, r.conf.Sensor, err)
+		}
+		r.readErrors.warnw(ctx, "could not check sensor rule", "sensor", r.conf.Sensor, "reading", r.conf.Reading, "error", err)
+		return ""
+	}
+	if r.conf.Min != nil && value < *r.conf.Min {
+		return fmt.Sprintf("%q of sensor %q is %v, below %v", r.conf.Reading, r.conf.Sensor, value, *r.conf.Min)
+	}
+	if r.conf.Max != nil && value > *r.conf.Max {
+		return fmt.Sprintf("%q of sensor %q is %v, above %v", r.conf.Reading, r.conf.Sensor, value, *r.conf.Max)
+	}
+	return ""
+}
+
+func (r *sensorRule) reading(ctx context.Context) (float64, error) {
+	readings, err := r.sensor.Readings(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	reading, ok := readings[r.conf.Reading]
+	if !ok {
+		return 0, errors.New("no such reading")
+	}
+	switch v := reading.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, errors.Errorf("reading is a %T, not a number", reading)
+	}
+}
+
+type geofenceRule struct {
+	conf           GeofenceRule
+	movementSensor movementsensor.MovementSensor
+	center         *geo.Point
+	polygon        *geo.Polygon
+	readErrors     readErrorLogger
+}
+
+func newGeofenceRule(conf GeofenceRule, ms movementsensor.MovementSensor, logger logging.Logger) *geofenceRule {
+	r := &geofenceRule{conf: conf, movementSensor: ms, readErrors: readErrorLogger{logger: logger}}
+	if conf.Center != nil {
+		r.center = geo.NewPoint(conf.Center.Latitude, conf.Center.Longitude)
+	} else {
+		points := make([]*geo.Point, 0, len(conf.Polygon))
+		for _, p := range conf.Polygon {
+			points = append(points, geo.NewPoint(p.Latitude, p.Longitude))
+		}
+		r.polygon = geo.NewPolygon(points)
+	}
+	return r
+}
+
+func (r *geofenceRule) describe() string {
+	return fmt.Sprintf("geofence of %q", r.conf.MovementSensor)
+}
+
+func (r *geofenceRule) check(ctx context.Context) string {
+	position, _, err := r.movementSensor.Position(ctx, nil)
+	if err != nil {
+		if r.conf.TriggerOnError {
+			return fmt.Sprintf("could not get position of %q: %v", r.conf.MovementSensor, err)
+		}
+		r.readErrors.warnw(ctx, "could not check geofence rule", "movement_sensor", r.conf.MovementSensor, "error", err)
+		return ""
+	}
+	if r.center != nil {
+		// GreatCircleDistance is in kilometers
+		if distance := r.center.GreatCircleDistance(position) * 1000; distance > r.conf.RadiusMeters {
+			return fmt.Sprintf("%q is %.1fm from the center of its geofence, outside of its radius of %.1fm",
+				r.conf.MovementSensor, distance, r.conf.RadiusMeters)
+		}
+		return ""
+	}
+	if !r.polygon.Contains(position) {
+		return fmt.Sprintf("%q is outside of its geofence", r.conf.MovementSensor)
+	}
+	return ""
+}
+
+// heartbeatRule triggers the e-stop when no heartbeat is received for a while.
+type heartbeatRule struct {
+	timeout       time.Duration
+	lastHeartbeat func() time.Time
+}
+
+func (r *heartbeatRule) describe() string {
+	return "heartbeat"
+}
+
+func (r *heartbeatRule) check(ctx context.Context) string {
+	if since := time.Since(r.lastHeartbeat()); since > r.timeout {
+		return fmt.Sprintf("no heartbeat received in %v", since.Round(time.Millisecond))
+	}
+	return ""
+}
@@ -0,0 +1,548 @@
+// Package safetymonitor implements a service which stops actuators, such as bases, arms and motors,
+// when a sensor reading is out of bounds, a heartbeat is lost or a movement sensor leaves its
+// geofence. Once triggered, the e-stop stays latched until it is reset.
+package safetymonitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+)
+
+// Model is the model of the safety monitor service.
+var Model = resource.DefaultModelFamily.WithModel("safety_monitor")
+
+const (
+	defaultCheckIntervalMS = 100
+	defaultStopTimeoutMS   = 1000
+)
+
+// The commands of the service, sent with DoCommand.
+const (
+	// GetStateCommand returns the state of the e-stop.
+	GetStateCommand = "get_state"
+	// TriggerCommand triggers the e-stop, with an optional reason.
+	TriggerCommand = "trigger"
+	// ResetCommand releases a latched e-stop, unless any rule is still violated.
+	ResetCommand = "reset"
+	// HeartbeatCommand records a heartbeat.
+	HeartbeatCommand = "heartbeat"
+)
+
+// The keys of commands and their results.
+const (
+	// TriggeredKey is whether the e-stop is triggered.
+	TriggeredKey = "triggered"
+	// ReasonsKey are why the e-stop was triggered.
+	ReasonsKey = "reasons"
+	// TriggeredAtKey is when the e-stop was triggered, in RFC 3339.
+	TriggeredAtKey = "triggered_at"
+	// ReasonKey is why the e-stop is triggered manually.
+	ReasonKey = "reason"
+)
+
+const manualReason = "triggered manually"
+
+func init() {
+	resource.RegisterService(generic.API, Model, resource.Registration[resource.Resource, *Config]{
+		Constructor: newSafetyMonitor,
+	})
+}
+
+// HeartbeatConfig triggers the e-stop when no heartbeat is received within the timeout, counted
+// from when the service starts.
+type HeartbeatConfig struct {
+	TimeoutMS int `json:"timeout_ms"`
+}
+
+// Config describes how to configure the service.
+type Config struct {
+	// Actuators are the resources to stop, such as bases, arms and motors.
+	Actuators []string `json:"actuators"`
+	// CheckIntervalMS is how often the rules are checked. The rules are checked at once, and a rule
+	// which cannot be checked within the interval triggers the e-stop.
+	CheckIntervalMS int `json:"check_interval_ms,omitempty"`
+	// StopTimeoutMS bounds how long stopping the actuators may take.
+	StopTimeoutMS int `json:"stop_timeout_ms,omitempty"`
+	// AutoReset releases the e-stop as soon as no rule is violated, instead of latching it.
+	AutoReset   bool             `json:"auto_reset,omitempty"`
+	SensorRules []SensorRule     `json:"sensor_rules,omitempty"`
+	Heartbeat   *HeartbeatConfig `json:"heartbeat,omitempty"`
+	Geofences   []GeofenceRule   `json:"geofences,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the resources to watch and stop.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if len(conf.Actuators) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "actuators")
+	}
+	if conf.CheckIntervalMS < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("check_interval_ms cannot be negative"))
+	}
+	if conf.StopTimeoutMS < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("stop_timeout_ms cannot be negative"))
+	}
+	if conf.Heartbeat != nil && conf.Heartbeat.TimeoutMS <= 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("heartbeat timeout_ms must be positive"))
+	}
+
+	deps := append([]string(nil), conf.Actuators...)
+	for i := range conf.SensorRules {
+		if err := conf.SensorRules[i].Validate(path); err != nil {
+			return nil, err
+		}
+		deps = append(deps, conf.SensorRules[i].Sensor)
+	}
+	for i := range conf.Geofences {
+		if err := conf.Geofences[i].Validate(path); err != nil {
+			return nil, err
+		}
+		deps = append(deps, conf.Geofences[i].MovementSensor)
+	}
+	return deps, nil
+}
+
+type actuator struct {
+	name string
+	resource.Actuator
+}
+
+// monitorConfig is what the service is configured with, which is replaced as a whole when the service is
+// reconfigured.
+type monitorConfig struct {
+	actuators     []actuator
+	rules         []*checkedRule
+	checkInterval time.Duration
+	stopTimeout   time.Duration
+	autoReset     bool
+}
+
+// checkedRule is a rule along with whether it is being checked, so that a rule whose check has not
+// returned is not checked again at the same time.
+type checkedRule struct {
+	rule
+	checking atomic.Bool
+}
+
+// safetyMonitor is reconfigured in place rather than rebuilt, so that a latched e-stop stays latched
+// when its config or dependencies change.
+type safetyMonitor struct {
+	resource.Named
+
+	logger logging.Logger
+
+	mu            sync.Mutex
+	conf          *monitorConfig
+	triggered     bool
+	reasons       []string
+	triggeredAt   time.Time
+	lastHeartbeat time.Time
+	// rounds counts the checks of the rules which have started, and checked is the latest one to finish.
+	// checkedCh is closed, and replaced, whenever a check finishes.
+	rounds    int
+	checked   checkRound
+	checkedCh chan struct{}
+
+	cancel                  func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newSafetyMonitor(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (resource.Resource, error) {
+	sm := &safetyMonitor{
+		Named:         conf.ResourceName().AsNamed(),
+		logger:        logger,
+		lastHeartbeat: time.Now(),
+		checkedCh:     make(chan struct{}),
+	}
+	if err := sm.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	sm.cancel = cancel
+	sm.activeBackgroundWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer sm.activeBackgroundWorkers.Done()
+		sm.run(cancelCtx)
+	})
+	return sm, nil
+}
+
+// Reconfigure replaces the actuators and rules of the service. The state of the e-stop, and the time of
+// the last heartbeat, are kept.
+func (sm *safetyMonitor) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	svcConfig, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	newConf := &monitorConfig{
+		checkInterval: time.Duration(svcConfig.CheckIntervalMS) * time.Millisecond,
+		stopTimeout:   time.Duration(svcConfig.StopTimeoutMS) * time.Millisecond,
+		autoReset:     svcConfig.AutoReset,
+	}
+	if newConf.checkInterval == 0 {
+		newConf.checkInterval = defaultCheckIntervalMS * time.Millisecond
+	}
+	if newConf.stopTimeout == 0 {
+		newConf.stopTimeout = defaultStopTimeoutMS * time.Millisecond
+	}
+
+	for _, name := range svcConfig.Actuators {
+		dep, err := findDependency(deps, name)
+		if err != nil {
+			return err
+		}
+		a, ok := dep.(resource.Actuator)
+		if !ok {
+			return errors.Errorf("resource %q cannot be stopped", name)
+		}
+		newConf.actuators = append(newConf.actuators, actuator{name: name, Actuator: a})
+	}
+	var rules []rule
+	for _, r := range svcConfig.SensorRules {
+		dep, err := findDependency(deps, r.Sensor)
+		if err != nil {
+			return err
+		}
+		sensor, ok := dep.(resource.Sensor)
+		if !ok {
+			return errors.Errorf("resource %q does not have readings", r.Sensor)
+		}
+		rules = append(rules, &sensorRule{conf: r, sensor: sensor, readErrors: readErrorLogger{logger: sm.logger}})
+	}
+	if svcConfig.Heartbeat != nil {
+		rules = append(rules, &heartbeatRule{
+			timeout:       time.Duration(svcConfig.Heartbeat.TimeoutMS) * time.Millisecond,
+			lastHeartbeat: sm.heartbeatTime,
+		})
+	}
+	for _, g := range svcConfig.Geofences {
+		dep, err := findDependency(deps, g.MovementSensor)
+		if err != nil {
+			return err
+		}
+		ms, ok := dep.(movementsensor.MovementSensor)
+		if !ok {
+			return errors.Errorf("resource %q is not a movement sensor", g.MovementSensor)
+		}
+		rules = append(rules, newGeofenceRule(g, ms, sm.logger))
+	}
+	for _, r := range rules {
+		newConf.rules = append(newConf.rules, &checkedRule{rule: r})
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.conf = newConf
+	return nil
+}
+
+func (sm *safetyMonitor) currentConfig() *monitorConfig {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.conf
+}
+
+// run checks the rules at the check interval until the context is done.
+func (sm *safetyMonitor) run(ctx context.Context) {
+	for {
+		conf := sm.currentConfig()
+		sm.checkRules(ctx, conf)
+		if !goutils.SelectContextOrWait(ctx, conf.checkInterval) {
+			return
+		}
+	}
+}
+
+// findDependency returns the dependency with the given name, whatever its API.
+func findDependency(deps resource.Dependencies, name string) (resource.Resource, error) {
+	for depName, dep := range deps {
+		if depName.ShortName() == name {
+			return dep, nil
+		}
+	}
+	return nil, errors.Errorf("resource %q not found in dependencies", name)
+}
+
+func (sm *safetyMonitor) heartbeatTime() time.Time {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.lastHeartbeat
+}
+
+// checkRound is a check of the rules by the background loop, and why they were violated.
+type checkRound struct {
+	round   int
+	reasons []string
+}
+
+// ruleResult is the result of checking a rule.
+type ruleResult struct {
+	index    int
+	reason   string
+	timedOut bool
+}
+
+// violations checks every rule at once and returns why they are violated. A rule which is not checked
+// within the check interval is violated, as is one whose previous check has not returned yet.
+func (sm *safetyMonitor) violations(ctx context.Context, conf *monitorConfig) []string {
+	checkCtx, cancel := context.WithTimeout(ctx, conf.checkInterval)
+	defer cancel()
+
+	results := make(chan ruleResult, len(conf.rules))
+	checked := make([]bool, len(conf.rules))
+	reasons := make([]string, len(conf.rules))
+	started := 0
+	for i, r := range conf.rules {
+		if !r.checking.CompareAndSwap(false, true) {
+			continue
+		}
+		started++
+		i, r := i, r
+		goutils.PanicCapturingGo(func() {
+			defer r.checking.Store(false)
+			reason := r.check(checkCtx)
+			results <- ruleResult{index: i, reason: reason, timedOut: checkCtx.Err() != nil}
+		})
+	}
+
+	received := 0
+	collect := func(result ruleResult) {
+		received++
+		if !result.timedOut {
+			checked[result.index] = true
+			reasons[result.index] = result.reason
+		}
+	}
+wait:
+	for received < started {
+		select {
+		case result := <-results:
+			collect(result)
+		case <-checkCtx.Done():
+			break wait
+		}
+	}
+	// keep the results of checks which returned along with the deadline
+	for drained := false; !drained && received < started; {
+		select {
+		case result := <-results:
+			collect(result)
+		default:
+			drained = true
+		}
+	}
+
+	var violated []string
+	for i, r := range conf.rules {
+		switch {
+		case !checked[i]:
+			violated = append(violated, fmt.Sprintf("could not check %s within %v", r.describe(), conf.checkInterval))
+		case reasons[i] != "":
+			violated = append(violated, reasons[i])
+		}
+	}
+	return violated
+}
+
+func (sm *safetyMonitor) checkRules(ctx context.Context, conf *monitorConfig) {
+	sm.mu.Lock()
+	sm.rounds++
+	round := sm.rounds
+	sm.mu.Unlock()
+
+	reasons := sm.violations(ctx, conf)
+	if ctx.Err() != nil {
+		return
+	}
+
+	sm.mu.Lock()
+	sm.checked = checkRound{round: round, reasons: reasons}
+	close(sm.checkedCh)
+	sm.checkedCh = make(chan struct{})
+	wasTriggered := sm.triggered
+	switch {
+	case len(reasons) != 0 && !sm.triggered:
+		sm.triggered = true
+		sm.reasons = reasons
+		sm.triggeredAt = time.Now()
+	case len(reasons) == 0 && sm.triggered && conf.autoReset && !sm.isManual():
+		sm.triggered = false
+		sm.reasons = nil
+	}
+	triggered := sm.triggered
+	sm.mu.Unlock()
+
+	switch {
+	case triggered && !wasTriggered:
+		sm.logger.CErrorw(ctx, "e-stop triggered", "reasons", reasons)
+		sm.stopActuators(ctx, conf, false)
+	case triggered:
+		// keep anything which was moved while the e-stop was latched stopped
+		sm.stopActuators(ctx, conf, true)
+	case wasTriggered:
+		sm.logger.CInfo(ctx, "e-stop reset automatically")
+	}
+}
+
+// isManual returns whether the e-stop was triggered manually, which is only ever reset explicitly.
+// It must be called with the lock held.
+func (sm *safetyMonitor) isManual() bool {
+	for _, reason := range sm.reasons {
+		if strings.HasPrefix(reason, manualReason) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopActuators stops all actuators at once, giving up after the stop timeout. If onlyMoving is set,
+// only the actuators which report that they are moving are stopped.
+func (sm *safetyMonitor) stopActuators(ctx context.Context, conf *monitorConfig, onlyMoving bool) {
+	ctx, cancel := context.WithTimeout(ctx, conf.stopTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(conf.actuators))
+	for i, a := range conf.actuators {
+		wg.Add(1)
+		i, a := i, a
+		goutils.PanicCapturingGo(func() {
+			defer wg.Done()
+			if onlyMoving {
+				moving, err := a.IsMoving(ctx)
+				if err == nil && !moving {
+					return
+				}
+			}
+			if err := a.Stop(ctx, nil); err != nil {
+				errs[i] = errors.Wrapf(err, "could not stop %q", a.name)
+			}
+		})
+	}
+	wg.Wait()
+	if err := multierr.Combine(errs...); err != nil {
+		sm.logger.CErrorw(ctx, "e-stop could not stop all actuators", "error", err)
+	}
+}
+
+// trigger triggers the e-stop manually and stops all actuators.
+func (sm *safetyMonitor) trigger(ctx context.Context, reason string) {
+	if reason == "" {
+		reason = manualReason
+	} else {
+		reason = manualReason + ": " + reason
+	}
+	sm.mu.Lock()
+	if !sm.triggered {
+		sm.triggered = true
+		sm.reasons = nil
+		sm.triggeredAt = time.Now()
+	}
+	sm.reasons = append(sm.reasons, reason)
+	sm.mu.Unlock()
+
+	sm.logger.CErrorw(ctx, "e-stop triggered", "reasons", []string{reason})
+	sm.stopActuators(ctx, sm.currentConfig(), false)
+}
+
+// waitForNextCheck waits for the first check of the rules by the background loop which starts after it is
+// called to finish.
+func (sm *safetyMonitor) waitForNextCheck(ctx context.Context) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	round := sm.rounds + 1
+	for sm.checked.round < round {
+		checkedCh := sm.checkedCh
+		sm.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			sm.mu.Lock()
+			return ctx.Err()
+		case <-checkedCh:
+		}
+		sm.mu.Lock()
+	}
+	return nil
+}
+
+// reset releases the e-stop if no rule is violated anymore. The rules are not checked by reset itself, which
+// would race with the checks of the background loop, but by the loop's next check.
+func (sm *safetyMonitor) reset(ctx context.Context) error {
+	if err := sm.waitForNextCheck(ctx); err != nil {
+		return err
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if reasons := sm.checked.reasons; len(reasons) != 0 {
+		return errors.Errorf("cannot reset e-stop while rules are violated: %s", strings.Join(reasons, "; "))
+	}
+	sm.triggered = false
+	sm.reasons = nil
+	sm.triggeredAt = time.Time{}
+	return nil
+}
+
+func (sm *safetyMonitor) heartbeat() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.lastHeartbeat = time.Now()
+}
+
+func (sm *safetyMonitor) state() map[string]interface{} {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	reasons := make([]interface{}, 0, len(sm.reasons))
+	for _, reason := range sm.reasons {
+		reasons = append(reasons, reason)
+	}
+	state := map[string]interface{}{
+		TriggeredKey: sm.triggered,
+		ReasonsKey:   reasons,
+	}
+	if sm.triggered {
+		state[TriggeredAtKey] = sm.triggeredAt.UTC().Format(time.RFC3339Nano)
+	}
+	return state
+}
+
+// DoCommand queries, triggers and resets the e-stop, and records heartbeats. Every command returns the
+// state of the e-stop.
+func (sm *safetyMonitor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[TriggerCommand]; ok {
+		reason, _ := cmd[ReasonKey].(string)
+		sm.trigger(ctx, reason)
+	} else if _, ok := cmd[ResetCommand]; ok {
+		if err := sm.reset(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, ok := cmd[HeartbeatCommand]; ok {
+		sm.heartbeat()
+	} else if _, ok := cmd[GetStateCommand]; !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	return sm.state(), nil
+}
+
+func (sm *safetyMonitor) Close(ctx context.Context) error {
+	sm.cancel()
+	sm.activeBackgroundWorkers.Wait()
+	return nil
+}
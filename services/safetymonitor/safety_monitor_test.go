@@ -0,0 +1,450 @@
+package safetymonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/generic"
+)
+
+type fakeSensor struct {
+	sensor.Sensor
+	mu       sync.Mutex
+	readings map[string]interface{}
+}
+
+func (s *fakeSensor) setReading(name string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readings = map[string]interface{}{name: value}
+}
+
+func (s *fakeSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readings, nil
+}
+
+// slowSensor takes until its context is done to return its readings.
+type slowSensor struct {
+	sensor.Sensor
+}
+
+func (s *slowSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// delayedSensor takes a while to return its readings.
+type delayedSensor struct {
+	fakeSensor
+	delay time.Duration
+}
+
+func (s *delayedSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	time.Sleep(s.delay)
+	return s.fakeSensor.Readings(ctx, extra)
+}
+
+// brokenSensor cannot be read.
+type brokenSensor struct {
+	sensor.Sensor
+}
+
+func (s *brokenSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return nil, errors.New("disconnected")
+}
+
+type fakeMovementSensor struct {
+	movementsensor.MovementSensor
+	mu       sync.Mutex
+	position *geo.Point
+}
+
+func (ms *fakeMovementSensor) setPosition(lat, lng float64) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.position = geo.NewPoint(lat, lng)
+}
+
+func (ms *fakeMovementSensor) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.position, 0, nil
+}
+
+type fakeBase struct {
+	base.Base
+	stops  atomic.Int32
+	moving atomic.Bool
+}
+
+func (b *fakeBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	b.stops.Add(1)
+	b.moving.Store(false)
+	return nil
+}
+
+func (b *fakeBase) IsMoving(ctx context.Context) (bool, error) {
+	return b.moving.Load(), nil
+}
+
+// stuckBase never stops.
+type stuckBase struct {
+	base.Base
+}
+
+func (b *stuckBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *stuckBase) IsMoving(ctx context.Context) (bool, error) {
+	return true, errors.New("stuck")
+}
+
+func resourceConfig(conf *Config) resource.Config {
+	return resource.Config{
+		Name:                "monitor",
+		API:                 generic.API,
+		Model:               Model,
+		ConvertedAttributes: conf,
+	}
+}
+
+func newTestMonitor(t *testing.T, conf *Config, deps resource.Dependencies) resource.Resource {
+	t.Helper()
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	res, err := newSafetyMonitor(context.Background(), deps, resourceConfig(conf), logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, res.Close(context.Background()), test.ShouldBeNil)
+	})
+	return res
+}
+
+func state(tb testing.TB, res resource.Resource) map[string]interface{} {
+	tb.Helper()
+	resp, err := res.DoCommand(context.Background(), map[string]interface{}{GetStateCommand: true})
+	test.That(tb, err, test.ShouldBeNil)
+	return resp
+}
+
+func TestConfigValidate(t *testing.T) {
+	maxTemp := 80.0
+	conf := &Config{
+		Actuators:   []string{"base", "arm"},
+		SensorRules: []SensorRule{{Sensor: "thermometer", Reading: "temperature", Max: &maxTemp}},
+		Heartbeat:   &HeartbeatConfig{TimeoutMS: 500},
+		Geofences: []GeofenceRule{
+			{MovementSensor: "gps", Center: &GeoPoint{Latitude: 40, Longitude: -74}, RadiusMeters: 10},
+		},
+	}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"base", "arm", "thermometer", "gps"})
+
+	_, err = (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "actuators")
+
+	_, err = (&Config{Actuators: []string{"base"}, SensorRules: []SensorRule{{Sensor: "thermometer", Reading: "temperature"}}}).
+		Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "min or max")
+
+	_, err = (&Config{Actuators: []string{"base"}, Heartbeat: &HeartbeatConfig{}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "timeout_ms")
+
+	_, err = (&Config{Actuators: []string{"base"}, Geofences: []GeofenceRule{{MovementSensor: "gps"}}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "center or polygon")
+
+	_, err = (&Config{Actuators: []string{"base"}, Geofences: []GeofenceRule{
+		{MovementSensor: "gps", Polygon: []GeoPoint{{Latitude: 0, Longitude: 0}, {Latitude: 1, Longitude: 1}}},
+	}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "3 points")
+}
+
+func TestSensorRule(t *testing.T) {
+	maxTemp := 80.0
+	thermometer := &fakeSensor{}
+	thermometer.setReading("temperature", 20.0)
+	b := &fakeBase{}
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 10,
+		SensorRules:     []SensorRule{{Sensor: "thermometer", Reading: "temperature", Max: &maxTemp}},
+	}, resource.Dependencies{
+		base.Named("base"):          b,
+		sensor.Named("thermometer"): thermometer,
+	})
+
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeFalse)
+	test.That(t, b.stops.Load(), test.ShouldEqual, 0)
+
+	thermometer.setReading("temperature", 90)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		st := state(tb, res)
+		test.That(tb, st[TriggeredKey], test.ShouldBeTrue)
+		test.That(tb, st[ReasonsKey], test.ShouldHaveLength, 1)
+		test.That(tb, st[TriggeredAtKey], test.ShouldNotBeEmpty)
+	})
+	test.That(t, b.stops.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+
+	// the e-stop cannot be reset while the rule is violated
+	_, err := res.DoCommand(context.Background(), map[string]interface{}{ResetCommand: true})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "temperature")
+
+	// anything moved while the e-stop is latched is stopped again
+	stops := b.stops.Load()
+	b.moving.Store(true)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, b.stops.Load(), test.ShouldBeGreaterThan, stops)
+	})
+
+	// the e-stop stays latched after the reading recovers, until it is reset
+	thermometer.setReading("temperature", 20.0)
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeTrue)
+	resp, err := res.DoCommand(context.Background(), map[string]interface{}{ResetCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp[TriggeredKey], test.ShouldBeFalse)
+}
+
+func TestAutoReset(t *testing.T) {
+	minVoltage := 11.0
+	battery := &fakeSensor{}
+	battery.setReading("voltage", 10)
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 10,
+		AutoReset:       true,
+		SensorRules:     []SensorRule{{Sensor: "battery", Reading: "voltage", Min: &minVoltage}},
+	}, resource.Dependencies{
+		base.Named("base"):      &fakeBase{},
+		sensor.Named("battery"): battery,
+	})
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, state(tb, res)[TriggeredKey], test.ShouldBeTrue)
+	})
+	battery.setReading("voltage", 12)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, state(tb, res)[TriggeredKey], test.ShouldBeFalse)
+	})
+
+	// a manual trigger is only ever reset explicitly
+	_, err := res.DoCommand(context.Background(), map[string]interface{}{TriggerCommand: true, ReasonKey: "maintenance"})
+	test.That(t, err, test.ShouldBeNil)
+	st := state(t, res)
+	test.That(t, st[TriggeredKey], test.ShouldBeTrue)
+	test.That(t, st[ReasonsKey], test.ShouldResemble, []interface{}{"triggered manually: maintenance"})
+	_, err = res.DoCommand(context.Background(), map[string]interface{}{ResetCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeFalse)
+}
+
+func TestResetWhileChecking(t *testing.T) {
+	maxTemp := 80.0
+	thermometer := &delayedSensor{delay: 18 * time.Millisecond}
+	thermometer.setReading("temperature", 20.0)
+	b := &fakeBase{}
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 20,
+		SensorRules:     []SensorRule{{Sensor: "thermometer", Reading: "temperature", Max: &maxTemp}},
+	}, resource.Dependencies{
+		base.Named("base"):          b,
+		sensor.Named("thermometer"): thermometer,
+	})
+
+	// resetting while the background loop is checking the rules neither fails nor triggers the e-stop again
+	for i := 0; i < 50; i++ {
+		_, err := res.DoCommand(context.Background(), map[string]interface{}{TriggerCommand: true})
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := res.DoCommand(context.Background(), map[string]interface{}{ResetCommand: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[TriggeredKey], test.ShouldBeFalse)
+	}
+	time.Sleep(100 * time.Millisecond)
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeFalse)
+}
+
+func TestHeartbeat(t *testing.T) {
+	b := &fakeBase{}
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 10,
+		Heartbeat:       &HeartbeatConfig{TimeoutMS: 200},
+	}, resource.Dependencies{base.Named("base"): b})
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		st := state(tb, res)
+		test.That(tb, st[TriggeredKey], test.ShouldBeTrue)
+		test.That(tb, fmt.Sprint(st[ReasonsKey]), test.ShouldContainSubstring, "no heartbeat")
+	})
+	test.That(t, b.stops.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+
+	_, err := res.DoCommand(context.Background(), map[string]interface{}{HeartbeatCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err := res.DoCommand(context.Background(), map[string]interface{}{ResetCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp[TriggeredKey], test.ShouldBeFalse)
+}
+
+func TestGeofence(t *testing.T) {
+	circleGPS := &fakeMovementSensor{}
+	circleGPS.setPosition(40.0, -74.0)
+	polygonGPS := &fakeMovementSensor{}
+	polygonGPS.setPosition(0.5, 0.5)
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 10,
+		Geofences: []GeofenceRule{
+			{MovementSensor: "circle", Center: &GeoPoint{Latitude: 40.0, Longitude: -74.0}, RadiusMeters: 50},
+			{MovementSensor: "polygon", Polygon: []GeoPoint{
+				{Latitude: 0, Longitude: 0},
+				{Latitude: 0, Longitude: 1},
+				{Latitude: 1, Longitude: 1},
+				{Latitude: 1, Longitude: 0},
+			}},
+		},
+	}, resource.Dependencies{
+		base.Named("base"):              &fakeBase{},
+		movementsensor.Named("circle"):  circleGPS,
+		movementsensor.Named("polygon"): polygonGPS,
+	})
+
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeFalse)
+
+	// about 111m north of the center
+	circleGPS.setPosition(40.001, -74.0)
+	polygonGPS.setPosition(1.5, 0.5)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		st := state(tb, res)
+		test.That(tb, st[TriggeredKey], test.ShouldBeTrue)
+		test.That(tb, st[ReasonsKey], test.ShouldHaveLength, 2)
+	})
+}
+
+func TestStopTimeout(t *testing.T) {
+	b := &fakeBase{}
+	res := newTestMonitor(t, &Config{
+		Actuators:     []string{"stuck", "base"},
+		StopTimeoutMS: 50,
+	}, resource.Dependencies{
+		base.Named("stuck"): &stuckBase{},
+		base.Named("base"):  b,
+	})
+
+	// a manual trigger stops every actuator at once and returns once the stop timeout passes
+	resp, err := res.DoCommand(context.Background(), map[string]interface{}{TriggerCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp[TriggeredKey], test.ShouldBeTrue)
+	test.That(t, resp[ReasonsKey], test.ShouldResemble, []interface{}{"triggered manually"})
+	test.That(t, b.stops.Load(), test.ShouldEqual, 1)
+
+	_, err = res.DoCommand(context.Background(), map[string]interface{}{"unknown": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	maxTemp := 80.0
+	thermometer := &fakeSensor{}
+	thermometer.setReading("temperature", 20.0)
+	b := &fakeBase{}
+	res := newTestMonitor(t, &Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 20,
+		SensorRules: []SensorRule{
+			{Sensor: "thermometer", Reading: "temperature", Max: &maxTemp},
+			{Sensor: "slow", Reading: "temperature", Max: &maxTemp},
+		},
+	}, resource.Dependencies{
+		base.Named("base"):          b,
+		sensor.Named("thermometer"): thermometer,
+		sensor.Named("slow"):        &slowSensor{},
+	})
+
+	// a rule which cannot be checked within the check interval is violated, even though it does not
+	// trigger the e-stop on errors
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		st := state(tb, res)
+		test.That(tb, st[TriggeredKey], test.ShouldBeTrue)
+		test.That(tb, st[ReasonsKey], test.ShouldResemble, []interface{}{`could not check "temperature" of sensor "slow" within 20ms`})
+	})
+	test.That(t, b.stops.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+}
+
+func TestReconfigure(t *testing.T) {
+	b := &fakeBase{}
+	deps := resource.Dependencies{base.Named("base"): b}
+	res := newTestMonitor(t, &Config{Actuators: []string{"base"}, CheckIntervalMS: 10}, deps)
+
+	_, err := res.DoCommand(context.Background(), map[string]interface{}{TriggerCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+
+	// the e-stop stays latched when the service is reconfigured
+	other := &fakeBase{}
+	deps[base.Named("other")] = other
+	test.That(t, res.Reconfigure(context.Background(), deps, resourceConfig(&Config{
+		Actuators:       []string{"base", "other"},
+		CheckIntervalMS: 10,
+	})), test.ShouldBeNil)
+	st := state(t, res)
+	test.That(t, st[TriggeredKey], test.ShouldBeTrue)
+	test.That(t, st[ReasonsKey], test.ShouldResemble, []interface{}{"triggered manually"})
+
+	// and the new actuators are kept stopped
+	other.moving.Store(true)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, other.stops.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+
+	err = res.Reconfigure(context.Background(), deps, resourceConfig(&Config{Actuators: []string{"missing"}}))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeTrue)
+}
+
+func TestReadErrorLogging(t *testing.T) {
+	maxTemp := 80.0
+	logger, logs := logging.NewObservedTestLogger(t)
+	res, err := newSafetyMonitor(context.Background(), resource.Dependencies{
+		base.Named("base"):     &fakeBase{},
+		sensor.Named("broken"): &brokenSensor{},
+	}, resourceConfig(&Config{
+		Actuators:       []string{"base"},
+		CheckIntervalMS: 5,
+		SensorRules:     []SensorRule{{Sensor: "broken", Reading: "temperature", Max: &maxTemp}},
+	}), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, res.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	// the rule fails every check, but its error is only logged once per interval
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, logs.FilterMessage("could not check sensor rule").Len(), test.ShouldEqual, 1)
+	})
+	time.Sleep(50 * time.Millisecond)
+	test.That(t, logs.FilterMessage("could not check sensor rule").Len(), test.ShouldEqual, 1)
+	test.That(t, state(t, res)[TriggeredKey], test.ShouldBeFalse)
+}
@@ -0,0 +1,162 @@
+// Package fiducialdetector finds square fiducial markers, such as ArUco markers, in camera images,
+// and returns their ids as detections and their poses relative to the camera as objects.
+package fiducialdetector
+
+import (
+	"context"
+	"image"
+	"strconv"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	svision "go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision"
+	"go.viam.com/rdk/vision/fiducial"
+	objdet "go.viam.com/rdk/vision/objectdetection"
+	"go.viam.com/rdk/vision/segmentation"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fiducial_detector")
+
+// markerThicknessMM is the thickness of the boxes returned for markers.
+const markerThicknessMM = 1
+
+// Config specifies the markers to look for.
+type Config struct {
+	// Family is the family of the markers, one of fiducial.Families(), which defaults to the original
+	// ArUco markers.
+	Family string `json:"family,omitempty"`
+	// MarkerSizeMM is the length of the sides of the markers' black borders. It is needed to
+	// estimate the poses of the markers.
+	MarkerSizeMM float64 `json:"marker_size_mm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	supported := conf.Family == ""
+	for _, family := range fiducial.Families() {
+		supported = supported || family == conf.Family
+	}
+	if !supported {
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("unsupported marker family %q, supported families are %v", conf.Family, fiducial.Families()))
+	}
+	if conf.MarkerSizeMM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("marker_size_mm cannot be negative"))
+	}
+	return nil, nil
+}
+
+func init() {
+	resource.RegisterService(svision.API, model, resource.Registration[svision.Service, *Config]{
+		DeprecatedRobotConstructor: func(
+			ctx context.Context, r any, c resource.Config, logger logging.Logger,
+		) (svision.Service, error) {
+			attrs, err := resource.NativeConfig[*Config](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerFiducialDetector(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// registerFiducialDetector creates a new fiducial detector from the config. It is only a 3D
+// segmenter if the size of the markers is known.
+func registerFiducialDetector(
+	ctx context.Context,
+	name resource.Name,
+	conf *Config,
+	r robot.Robot,
+) (svision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerFiducialDetector")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for fiducial detector cannot be nil")
+	}
+	family := conf.Family
+	if family == "" {
+		family = fiducial.ArucoOriginal
+	}
+	var segmenter segmentation.Segmenter
+	if conf.MarkerSizeMM > 0 {
+		segmenter = markerSegmenter(family, conf.MarkerSizeMM)
+	}
+	return svision.NewService(name, r, nil, nil, markerDetector(family), segmenter)
+}
+
+// markerDetector returns a detector which labels the bounding box of every marker with its id.
+func markerDetector(family string) objdet.Detector {
+	return func(ctx context.Context, img image.Image) ([]objdet.Detection, error) {
+		markers, err := fiducial.Detect(img, family)
+		if err != nil {
+			return nil, err
+		}
+		detections := make([]objdet.Detection, 0, len(markers))
+		for _, m := range markers {
+			detections = append(detections, objdet.NewDetection(m.BoundingBox(), 1, strconv.Itoa(m.ID)))
+		}
+		return detections, nil
+	}
+}
+
+// markerSegmenter returns a segmenter which returns a box at the pose of every marker, labeled with
+// its id, along with a point cloud of the marker's corners. The marker's Z axis points out of its
+// printed side.
+func markerSegmenter(family string, sizeMM float64) segmentation.Segmenter {
+	return func(ctx context.Context, src camera.VideoSource) ([]*vision.Object, error) {
+		props, err := src.Properties(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get camera properties")
+		}
+		if props.IntrinsicParams == nil {
+			return nil, errors.New("camera must have intrinsic parameters to estimate the poses of markers")
+		}
+		img, release, err := camera.ReadImage(ctx, src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get image from %s", src)
+		}
+		defer release()
+
+		markers, err := fiducial.Detect(img, family)
+		if err != nil {
+			return nil, err
+		}
+		half := sizeMM / 2
+		corners := []r3.Vector{{X: -half, Y: half}, {X: half, Y: half}, {X: half, Y: -half}, {X: -half, Y: -half}}
+		objects := make([]*vision.Object, 0, len(markers))
+		for _, m := range markers {
+			pose, err := m.Pose(props.IntrinsicParams, sizeMM)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not estimate the pose of marker %d", m.ID)
+			}
+			label := strconv.Itoa(m.ID)
+			box, err := spatialmath.NewBox(pose, r3.Vector{X: sizeMM, Y: sizeMM, Z: markerThicknessMM}, label)
+			if err != nil {
+				return nil, err
+			}
+			cloud := pointcloud.New()
+			for _, c := range corners {
+				corner := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(c)).Point()
+				if err := cloud.Set(corner, pointcloud.NewBasicData()); err != nil {
+					return nil, err
+				}
+			}
+			objects = append(objects, &vision.Object{PointCloud: cloud, Geometry: box})
+		}
+		return objects, nil
+	}
+}
@@ -0,0 +1,102 @@
+package fiducialdetector
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/vision/fiducial"
+)
+
+// markerScene returns an image with the marker of the given id centered in front of the camera,
+// with its border 70 pixels wide.
+func markerScene(t *testing.T, id int) image.Image {
+	t.Helper()
+	marker, err := fiducial.MarkerImage(fiducial.ArucoOriginal, id, 10)
+	test.That(t, err, test.ShouldBeNil)
+	img := image.NewGray(image.Rect(0, 0, 640, 480))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, marker.Bounds().Add(image.Pt(275, 195)), marker, image.Point{}, draw.Src)
+	return img
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	// the default family is left to the constructor, validating doesn't change the config
+	test.That(t, conf.Family, test.ShouldBeEmpty)
+
+	conf = &Config{Family: fiducial.AprilTag16h5}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf = &Config{Family: "apriltag_36h11"}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unsupported marker family")
+
+	conf = &Config{MarkerSizeMM: -1}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestFiducialDetector(t *testing.T) {
+	ctx := context.Background()
+	img := markerScene(t, 123)
+
+	srv, err := registerFiducialDetector(ctx, vision.Named("markers"), &Config{}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	detections, err := srv.Detections(ctx, img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(detections), test.ShouldEqual, 1)
+	test.That(t, detections[0].Label(), test.ShouldEqual, "123")
+	test.That(t, *detections[0].BoundingBox(), test.ShouldResemble, image.Rect(285, 205, 355, 275))
+
+	// without the size of the markers their poses cannot be estimated
+	_, err = srv.GetObjectPointClouds(ctx, "camera", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not implement a 3D segmenter")
+}
+
+func TestMarkerSegmenter(t *testing.T) {
+	ctx := context.Background()
+	img := markerScene(t, 7)
+	reader := gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		return img, func() {}, nil
+	})
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 600, Fy: 600, Ppx: 320, Ppy: 240}
+
+	t.Run("no intrinsics", func(t *testing.T) {
+		src, err := camera.NewVideoSourceFromReader(ctx, reader, nil, camera.ColorStream)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = markerSegmenter(fiducial.ArucoOriginal, 70)(ctx, src)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "intrinsic parameters")
+	})
+
+	src, err := camera.NewVideoSourceFromReader(
+		ctx, reader, &transform.PinholeCameraModel{PinholeCameraIntrinsics: intrinsics}, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	objects, err := markerSegmenter(fiducial.ArucoOriginal, 70)(ctx, src)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(objects), test.ShouldEqual, 1)
+	test.That(t, objects[0].Geometry.Label(), test.ShouldEqual, "7")
+	test.That(t, objects[0].PointCloud.Size(), test.ShouldEqual, 4)
+
+	// the marker faces the camera, so it is upside down in the camera's frame
+	expected := spatialmath.NewPose(r3.Vector{Z: 600}, &spatialmath.R4AA{Theta: math.Pi, RX: 1})
+	pose := objects[0].Geometry.Pose()
+	test.That(t, pose.Point().Distance(expected.Point()), test.ShouldBeLessThan, 10)
+	test.That(t, spatialmath.PoseBetween(expected, pose).Orientation().AxisAngles().Theta, test.ShouldBeLessThan, 0.05)
+}
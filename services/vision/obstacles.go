@@ -0,0 +1,66 @@
+package vision
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+)
+
+// A PoseTransformer transforms poses between the frames of a frame system, like a robot or its frame
+// system service.
+type PoseTransformer interface {
+	TransformPose(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error)
+}
+
+// ObstaclesInFrame returns the geometries of the objects the vision service finds with the named
+// camera, moved from the camera's frame into the given frame of the frame system and labeled as
+// TransientObstacles labels them.
+func ObstaclesInFrame(
+	ctx context.Context,
+	fs PoseTransformer,
+	svc Service,
+	cameraName, frame string,
+	extra map[string]interface{},
+) (*referenceframe.GeometriesInFrame, error) {
+	objects, err := svc.GetObjectPointClouds(ctx, cameraName, extra)
+	if err != nil {
+		return nil, err
+	}
+	cameraOrigin := referenceframe.NewPoseInFrame(cameraName, spatialmath.NewZeroPose())
+	cameraPose, err := fs.TransformPose(ctx, cameraOrigin, frame, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find the pose of camera %q in frame %q", cameraName, frame)
+	}
+	return referenceframe.NewGeometriesInFrame(frame, TransientObstacles(cameraName, objects, cameraPose.Pose())), nil
+}
+
+// TransientObstacles returns the geometries of the objects seen by the named camera, moved by the pose
+// of the camera. Every geometry is labeled with the camera's name, "_transientObstacle_" and its index,
+// followed by its own label if it has one, so that the obstacles of several cameras can be told apart
+// and from the obstacles of the world state. Objects without a geometry are skipped.
+func TransientObstacles(cameraName string, objects []*viz.Object, cameraPose spatialmath.Pose) []spatialmath.Geometry {
+	geometries := make([]spatialmath.Geometry, 0, len(objects))
+	for i, object := range objects {
+		if object.Geometry == nil {
+			continue
+		}
+		label := cameraName + "_transientObstacle_" + strconv.Itoa(i)
+		if object.Geometry.Label() != "" {
+			label += "_" + object.Geometry.Label()
+		}
+		geometry := object.Geometry.Transform(cameraPose)
+		geometry.SetLabel(label)
+		geometries = append(geometries, geometry)
+	}
+	return geometries
+}
@@ -55,6 +55,9 @@ type ObsDepthConfig struct {
 	ClusteringRadius     int     `json:"clustering_radius"`
 	ClusteringStrictness float64 `json:"clustering_strictness"`
 	AngleTolerance       float64 `json:"ground_angle_tolerance_degs"`
+	// GroundNormalVec is the normal of the ground plane in the camera's frame, which defaults to
+	// pointing up for a level camera.
+	GroundNormalVec r3.Vector `json:"ground_plane_normal_vec"`
 }
 
 // obsDepth is the underlying struct actually used by the service.
@@ -75,12 +78,16 @@ func registerObstaclesDepth(
 	if conf == nil {
 		return nil, errors.New("config for obstacles_depth cannot be nil")
 	}
+	normal := conf.GroundNormalVec
+	if normal.Norm2() == 0 {
+		normal = r3.Vector{0, -1, 0}
+	}
 	// build the clustering config
 	cfg := &segmentation.ErCCLConfig{
 		MinPtsInPlane:        conf.MinPtsInPlane,
 		MinPtsInSegment:      conf.MinPtsInSegment,
 		MaxDistFromPlane:     conf.MaxDistFromPlane,
-		NormalVec:            r3.Vector{0, -1, 0},
+		NormalVec:            normal,
 		AngleTolerance:       conf.AngleTolerance,
 		ClusteringRadius:     conf.ClusteringRadius,
 		ClusteringStrictness: conf.ClusteringStrictness,
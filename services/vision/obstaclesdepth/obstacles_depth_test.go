@@ -136,6 +136,15 @@ func TestObstacleDepth(t *testing.T) {
 			test.That(t, o.Geometry, test.ShouldNotBeNil)
 		}
 	})
+	t.Run("ground plane normal", func(t *testing.T) {
+		_, err := registerObstaclesDepth(ctx, name, &ObsDepthConfig{GroundNormalVec: r3.Vector{Y: -2}}, r, testLogger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unit vector")
+
+		srv3, err := registerObstaclesDepth(ctx, name, &ObsDepthConfig{GroundNormalVec: r3.Vector{Z: 1}}, r, testLogger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, srv3, test.ShouldNotBeNil)
+	})
 }
 
 func BenchmarkObstacleDepthIntrinsics(b *testing.B) {
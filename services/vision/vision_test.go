@@ -5,11 +5,16 @@ import (
 	"image"
 	"testing"
 
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
 	"go.viam.com/rdk/vision/objectdetection"
 )
 
@@ -55,3 +60,42 @@ func TestNewService(t *testing.T) {
 	test.That(t, len(result), test.ShouldEqual, 1)
 	test.That(t, result[0].Score(), test.ShouldEqual, 0.5)
 }
+
+func TestObstaclesInFrame(t *testing.T) {
+	ctx := context.Background()
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 1000}), r3.Vector{X: 100, Y: 100, Z: 100}, "rock")
+	test.That(t, err, test.ShouldBeNil)
+	svc := &inject.VisionService{}
+	svc.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		if cameraName != "cam" {
+			return nil, errors.New("no such camera")
+		}
+		return []*viz.Object{{Geometry: box}, {}}, nil
+	}
+	var r inject.Robot
+	r.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		if dst != referenceframe.World {
+			return nil, errors.New("no such frame")
+		}
+		// the camera is 500mm above the world origin
+		return referenceframe.NewPoseInFrame(dst, spatialmath.NewPoseFromPoint(r3.Vector{Z: 500})), nil
+	}
+
+	obstacles, err := vision.ObstaclesInFrame(ctx, &r, svc, "cam", referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, obstacles.Parent(), test.ShouldEqual, referenceframe.World)
+	test.That(t, len(obstacles.Geometries()), test.ShouldEqual, 1)
+	test.That(t, obstacles.Geometries()[0].Label(), test.ShouldEqual, "cam_transientObstacle_0_rock")
+	test.That(t, spatialmath.R3VectorAlmostEqual(obstacles.Geometries()[0].Pose().Point(), r3.Vector{Z: 1500}, 1e-6), test.ShouldBeTrue)
+
+	_, err = vision.ObstaclesInFrame(ctx, &r, svc, "other", referenceframe.World, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = vision.ObstaclesInFrame(ctx, &r, svc, "cam", "nowhere", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "could not find the pose of camera")
+}
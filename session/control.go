@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// ExclusiveControlMetadataKey is the gRPC metadata key a client sets on a safety monitored call
+	// made within a session to claim or release exclusive control of the resource it targets.
+	ExclusiveControlMetadataKey = "viam-exclusive-control"
+
+	// ExclusiveControlClaim claims exclusive control of the targeted resource for the session.
+	ExclusiveControlClaim = "claim"
+
+	// ExclusiveControlRelease releases the session's exclusive control of the targeted resource.
+	ExclusiveControlRelease = "release"
+
+	// ExclusiveControlQueueMetadataKey is the gRPC metadata key a client sets on a safety monitored
+	// call to wait for the resource it targets to be released, rather than have the call rejected,
+	// while another session has exclusive control of it.
+	ExclusiveControlQueueMetadataKey = "viam-exclusive-control-queue"
+)
+
+// NewExclusiveControlError returns an error indicating that a resource is under the exclusive
+// control of another session.
+func NewExclusiveControlError(name resource.Name) error {
+	return status.Errorf(codes.FailedPrecondition, "%q is under exclusive control of another session", name.String())
+}
+
+// ClaimExclusiveControl returns a context that claims exclusive control of the resource targeted by
+// a safety monitored call (e.g. SetPower on a base) made with it. While claimed, safety monitored
+// calls to that resource from other sessions, or without a session, are rejected. Control is
+// released by ReleaseExclusiveControl or when the session expires.
+func ClaimExclusiveControl(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ExclusiveControlMetadataKey, ExclusiveControlClaim)
+}
+
+// ReleaseExclusiveControl returns a context that releases exclusive control of the resource
+// targeted by a safety monitored call made with it.
+func ReleaseExclusiveControl(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ExclusiveControlMetadataKey, ExclusiveControlRelease)
+}
+
+// QueueForExclusiveControl returns a context whose safety monitored calls wait in line, until the
+// resource they target is released or the context is done, instead of being rejected while another
+// session has exclusive control of it. It can be combined with ClaimExclusiveControl to claim the
+// resource as soon as it is free.
+func QueueForExclusiveControl(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ExclusiveControlQueueMetadataKey, "true")
+}
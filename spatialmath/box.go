@@ -158,6 +158,9 @@ func (b *box) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error)
 	if other, ok := g.(*point); ok {
 		return pointVsBoxCollision(other.position, b, collisionBufferMM), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(b, collisionBufferMM)
+	}
 	return true, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -174,6 +177,9 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxDistance(other.position, b), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(b)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -190,6 +196,9 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return false, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -243,7 +252,8 @@ func (b *box) vertices() []r3.Vector {
 // vertices returns the vertices defining the box.
 func (b *box) toMesh() *mesh {
 	if b.mesh == nil {
-		m := &mesh{pose: b.pose}
+		// the triangles are in the world frame, so the mesh has no pose of its own.
+		m := &mesh{pose: NewZeroPose()}
 		triangles := make([]*triangle, 0, 12)
 		verts := b.vertices()
 		for _, tri := range boxTriangles {
@@ -150,6 +150,9 @@ func (c *capsule) CollidesWith(g Geometry, collisionBufferMM float64) (bool, err
 	if other, ok := g.(*box); ok {
 		return capsuleVsBoxCollision(c, other, collisionBufferMM), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(c, collisionBufferMM)
+	}
 	dist, err := c.DistanceFrom(g)
 	if err != nil {
 		return true, err
@@ -170,6 +173,9 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleVsSphereDistance(c, other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(c)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -186,6 +192,9 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(c, g)
 }
 
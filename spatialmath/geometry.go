@@ -53,6 +53,7 @@ const (
 	SphereType  = GeometryType("sphere")
 	CapsuleType = GeometryType("capsule")
 	PointType   = GeometryType("point")
+	MeshType    = GeometryType("mesh")
 
 	// objects must be separated by this many mm to not be in collision.
 	defaultCollisionBufferMM = 1e-8
@@ -76,6 +77,12 @@ type GeometryConfig struct {
 	// parameter used for defining a capsule's length
 	L float64 `json:"l"`
 
+	// parameters used for defining a mesh, either by the STL or PLY file it is loaded from or by its vertices and the triangular
+	// faces between them, given as indices into the vertices
+	MeshFile     string      `json:"mesh_file,omitempty"`
+	MeshVertices []r3.Vector `json:"mesh_vertices,omitempty"`
+	MeshFaces    [][3]int    `json:"mesh_faces,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -105,6 +112,14 @@ func NewGeometryConfig(g Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gType.label
+	case *mesh:
+		config.Type = MeshType
+		if gType.fileName != "" {
+			config.MeshFile = gType.fileName
+		} else {
+			config.MeshVertices, config.MeshFaces = gType.verticesAndFaces()
+		}
+		config.Label = gType.label
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", gType))
 	}
@@ -119,6 +134,20 @@ func NewGeometryConfig(g Geometry) (*GeometryConfig, error) {
 	return config, nil
 }
 
+// NewPortableGeometryConfig creates a config for a Geometry as NewGeometryConfig does, except that a mesh is described by its
+// vertices and faces rather than by the file it was loaded from, so that it can be rebuilt where the file is not available.
+func NewPortableGeometryConfig(g Geometry) (*GeometryConfig, error) {
+	config, err := NewGeometryConfig(g)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := g.(*mesh); ok && config.MeshFile != "" {
+		config.MeshFile = ""
+		config.MeshVertices, config.MeshFaces = m.verticesAndFaces()
+	}
+	return config, nil
+}
+
 // ParseConfig converts a GeometryConfig into the correct GeometryCreator type, as specified in its Type field.
 func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 	// determine offset to use
@@ -138,8 +167,13 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case MeshType:
+		return config.parseMesh(offset)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
+		if config.MeshFile != "" || len(config.MeshVertices) > 0 {
+			return config.parseMesh(offset)
+		}
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
 		if boxDims.Norm() > 0 {
 			if creator, err := NewBox(offset, boxDims, config.Label); err == nil {
@@ -157,6 +191,14 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 	return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, string(config.Type))
 }
 
+// parseMesh builds the mesh a config describes, from its vertices and faces if it has them and from its file otherwise.
+func (config *GeometryConfig) parseMesh(offset Pose) (Geometry, error) {
+	if len(config.MeshVertices) > 0 {
+		return NewMesh(offset, config.MeshVertices, config.MeshFaces, config.Label)
+	}
+	return NewMeshFromFile(offset, config.MeshFile, config.Label)
+}
+
 // ToProtobuf converts a GeometryConfig to Protobuf.
 func (config *GeometryConfig) ToProtobuf() (*commonpb.Geometry, error) {
 	creator, err := config.ParseConfig()
@@ -177,6 +219,8 @@ func GeometriesAlmostEqual(a, b Geometry) bool {
 		return gType.almostEqual(b)
 	case *point:
 		return gType.almostEqual(b)
+	case *mesh:
+		return gType.almostEqual(b)
 	default:
 		return false
 	}
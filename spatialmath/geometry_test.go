@@ -822,3 +822,49 @@ func TestNewGeometryFromProto(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, viamGeom, test.ShouldResemble, sphereGeom)
 }
+
+func TestSweptCapsule(t *testing.T) {
+	moving, err := NewBox(NewZeroPose(), r3.Vector{10, 10, 10}, "moving")
+	test.That(t, err, test.ShouldBeNil)
+	start := moving.Transform(NewPoseFromPoint(r3.Vector{-100, 0, 0}))
+	end := moving.Transform(NewPose(r3.Vector{100, 0, 0}, &OrientationVectorDegrees{OY: 1, Theta: 90}))
+	wall, err := NewBox(NewZeroPose(), r3.Vector{1, 100, 100}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+
+	// checking only the endpoints of the motion misses the thin wall between them.
+	for _, g := range []Geometry{start, end} {
+		collides, err := g.CollidesWith(wall, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeFalse)
+	}
+	swept, err := SweptCapsule(start, end)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, swept.Label(), test.ShouldEqual, "moving")
+	collides, err := swept.CollidesWith(wall, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+
+	// every intermediate pose of the geometry is enclosed, with its furthest vertices on the surface of the capsule.
+	for _, by := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		intermediate := moving.Transform(Interpolate(start.Pose(), end.Pose(), by))
+		for _, vertex := range intermediate.(*box).vertices() {
+			dist, err := swept.DistanceFrom(NewPoint(vertex, ""))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, dist, test.ShouldBeLessThan, floatEpsilon)
+		}
+	}
+
+	// a geometry which does not translate sweeps out a sphere.
+	rotated := moving.Transform(Compose(start.Pose(), NewPoseFromOrientation(&OrientationVectorDegrees{OX: 1, Theta: 45})))
+	spun, err := SweptCapsule(start, rotated)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok := spun.(*sphere)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// points sweep out a line segment.
+	line, err := SweptCapsule(NewPoint(r3.Vector{-1, 0, 0}, ""), NewPoint(r3.Vector{1, 0, 0}, ""))
+	test.That(t, err, test.ShouldBeNil)
+	collides, err = line.CollidesWith(wall, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+}
@@ -1,6 +1,8 @@
 package spatialmath
 
 import (
+	"math"
+
 	"github.com/golang/geo/r3"
 )
 
@@ -84,19 +86,55 @@ func PlaneNormal(p0, p1, p2 r3.Vector) r3.Vector {
 // BoundingSphere returns a spherical geometry centered on the point (0, 0, 0) that will encompass the given geometry
 // if it were to be rotated 360 degrees about the Z axis.  The label of the new geometry is inherited from the given one.
 func BoundingSphere(geometry Geometry) (Geometry, error) {
-	r := geometry.Pose().Point().Norm()
+	r, err := boundingRadius(geometry)
+	if err != nil {
+		return nil, err
+	}
+	return NewSphere(NewZeroPose(), geometry.Pose().Point().Norm()+r, geometry.Label())
+}
+
+// SweptCapsule returns a capsule that encloses the given geometry throughout a motion between two poses, given as the geometry
+// transformed to each of them, in which the origin of the geometry travels in a straight line. The orientation of the geometry may
+// change arbitrarily over the motion. If the origin does not move, a sphere is returned instead. The label of the new geometry is
+// inherited from start.
+//
+// The capsule is conservative: its radius is that of the sphere about the geometry's origin that encloses it in every orientation.
+func SweptCapsule(start, end Geometry) (Geometry, error) {
+	r, err := boundingRadius(start)
+	if err != nil {
+		return nil, err
+	}
+	// a capsule must have a nonzero radius, which a point does not.
+	r = math.Max(r, floatEpsilon)
+
+	startPt := start.Pose().Point()
+	delta := end.Pose().Point().Sub(startPt)
+	if delta.Norm() < floatEpsilon {
+		return NewSphere(NewPoseFromPoint(startPt), r, start.Label())
+	}
+	// the axis of a capsule is the Z axis of its pose, which an orientation vector points along.
+	dir := delta.Normalize()
+	center := NewPose(startPt.Add(delta.Mul(0.5)), &OrientationVector{OX: dir.X, OY: dir.Y, OZ: dir.Z})
+	return NewCapsule(center, r, delta.Norm()+2*r, start.Label())
+}
+
+// boundingRadius returns the radius of the sphere centered on the origin of the geometry's pose that encloses the geometry in any
+// orientation.
+func boundingRadius(geometry Geometry) (float64, error) {
 	switch g := geometry.(type) {
 	case *box:
-		r += r3.Vector{X: g.halfSize[0], Y: g.halfSize[1], Z: g.halfSize[2]}.Norm()
+		return g.boundingSphereR, nil
 	case *sphere:
-		r += g.radius
+		return g.radius, nil
 	case *capsule:
-		r += g.length / 2
+		return g.length / 2, nil
 	case *point:
+		return 0, nil
+	case *mesh:
+		return g.boundingRadius(), nil
 	default:
-		return nil, errGeometryTypeUnsupported
+		return 0, errGeometryTypeUnsupported
 	}
-	return NewSphere(NewZeroPose(), r, geometry.Label())
 }
 
 // closestSegmentTrianglePoints takes a line segment and a triangle, and returns the point on each closest to the other.
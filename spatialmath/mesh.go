@@ -1,7 +1,12 @@
 package spatialmath
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+
 	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
 )
 
 // This file incorporates work covered by the Brax project -- https://github.com/google/brax/blob/main/LICENSE.
@@ -9,9 +14,332 @@ import (
 // You may obtain a copy of the license at http://www.apache.org/licenses/LICENSE-2.0.
 
 // mesh is a collision geometry that represents a set of triangles that represent a mesh.
+// The triangles are expressed in the frame of the mesh's pose, and a bounding volume hierarchy over them is built once at creation
+// time and shared by all transformed copies of the mesh.
+//
+// IMPORTANT: meshes are not considered solid. A mesh is not guaranteed to represent an enclosed area, so only the distance to its
+// surface is measured. A geometry entirely inside a closed mesh is not in collision with it.
 type mesh struct {
 	pose      Pose
 	triangles []*triangle
+	label     string
+
+	// fileName is the file the mesh was loaded from, if any. It is needed to serialize the mesh as a GeometryConfig.
+	fileName string
+	bvh      *bvhNode
+}
+
+// NewMesh instantiates a new mesh Geometry from a list of vertices and the triangular faces between them, given as indices into
+// vertices. Vertex coordinates are in mm, relative to the given pose. Degenerate faces are ignored.
+func NewMesh(pose Pose, vertices []r3.Vector, faces [][3]int, label string) (Geometry, error) {
+	triangles := make([]*triangle, 0, len(faces))
+	for _, face := range faces {
+		for _, idx := range face {
+			if idx < 0 || idx >= len(vertices) {
+				return nil, fmt.Errorf("mesh face references vertex %d but there are only %d vertices", idx, len(vertices))
+			}
+		}
+		triangles = append(triangles, newTriangle(vertices[face[0]], vertices[face[1]], vertices[face[2]]))
+	}
+	return newMesh(pose, triangles, label)
+}
+
+// newMesh builds a mesh from triangles expressed relative to pose, discarding any that are degenerate.
+func newMesh(pose Pose, triangles []*triangle, label string) (*mesh, error) {
+	valid := make([]*triangle, 0, len(triangles))
+	for _, t := range triangles {
+		if n := t.normal.Norm2(); n == 0 || math.IsNaN(n) {
+			continue
+		}
+		valid = append(valid, t)
+	}
+	if len(valid) == 0 {
+		return nil, newBadGeometryDimensionsError(&mesh{})
+	}
+	return &mesh{
+		pose:      pose,
+		triangles: valid,
+		label:     label,
+		bvh:       newBVH(valid),
+	}, nil
+}
+
+// verticesAndFaces returns the distinct vertices of the mesh's triangles, in the frame of its pose, and its triangles as indices
+// into them.
+func (m *mesh) verticesAndFaces() ([]r3.Vector, [][3]int) {
+	vertices := make([]r3.Vector, 0, len(m.triangles))
+	faces := make([][3]int, 0, len(m.triangles))
+	indices := make(map[r3.Vector]int, len(m.triangles))
+	index := func(v r3.Vector) int {
+		i, ok := indices[v]
+		if !ok {
+			i = len(vertices)
+			indices[v] = i
+			vertices = append(vertices, v)
+		}
+		return i
+	}
+	for _, t := range m.triangles {
+		faces = append(faces, [3]int{index(t.p0), index(t.p1), index(t.p2)})
+	}
+	return vertices, faces
+}
+
+func (m *mesh) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the mesh.
+func (m *mesh) String() string {
+	pt := m.pose.Point()
+	return fmt.Sprintf("Type: Mesh | Position: X:%.1f, Y:%.1f, Z:%.1f | Triangles: %d", pt.X, pt.Y, pt.Z, len(m.triangles))
+}
+
+// Label returns the label of this mesh.
+func (m *mesh) Label() string {
+	return m.label
+}
+
+// SetLabel sets the label of this mesh.
+func (m *mesh) SetLabel(label string) {
+	m.label = label
+}
+
+// Pose returns the pose of the mesh.
+func (m *mesh) Pose() Pose {
+	return m.pose
+}
+
+// almostEqual compares the mesh with another geometry and checks if they are equivalent.
+func (m *mesh) almostEqual(g Geometry) bool {
+	other, ok := g.(*mesh)
+	if !ok || len(m.triangles) != len(other.triangles) {
+		return false
+	}
+	for i, t := range m.triangles {
+		o := other.triangles[i]
+		if !R3VectorAlmostEqual(t.p0, o.p0, 1e-8) || !R3VectorAlmostEqual(t.p1, o.p1, 1e-8) || !R3VectorAlmostEqual(t.p2, o.p2, 1e-8) {
+			return false
+		}
+	}
+	return PoseAlmostEqualEps(m.pose, other.pose, 1e-6)
+}
+
+// Transform premultiplies the mesh pose with a transform, allowing the mesh to be moved in space.
+func (m *mesh) Transform(toPremultiply Pose) Geometry {
+	return &mesh{
+		pose:      Compose(toPremultiply, m.pose),
+		triangles: m.triangles,
+		label:     m.label,
+		fileName:  m.fileName,
+		bvh:       m.bvh,
+	}
+}
+
+// ToProtobuf converts the mesh to a Geometry proto message. The Geometry message has no representation of a mesh, so this is lossy:
+// the mesh is sent as its bounding box, aligned with the axes of the mesh's own frame, and is a box once converted back. The box
+// encloses the mesh, so collision checks against it are conservative. Where the mesh itself must be kept, send its GeometryConfig
+// from NewPortableGeometryConfig instead, as motion clients do for the mesh obstacles of a WorldState.
+func (m *mesh) ToProtobuf() *commonpb.Geometry {
+	dims := m.bvh.max.Sub(m.bvh.min)
+	center := Compose(m.pose, NewPoseFromPoint(m.bvh.min.Add(dims.Mul(0.5))))
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(center),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{X: dims.X, Y: dims.Y, Z: dims.Z}},
+		},
+		Label: m.label,
+	}
+}
+
+// CollidesWith checks if the given mesh collides with the given geometry and returns true if it does.
+func (m *mesh) CollidesWith(g Geometry, collisionBufferMM float64) (bool, error) {
+	dist, err := m.distanceFrom(g, collisionBufferMM)
+	if err != nil {
+		return true, err
+	}
+	return dist <= collisionBufferMM, nil
+}
+
+// DistanceFrom returns the distance between the surface of the mesh and the given geometry. Geometries which intersect the mesh
+// surface return a nonpositive distance.
+func (m *mesh) DistanceFrom(g Geometry) (float64, error) {
+	return m.distanceFrom(g, math.Inf(-1))
+}
+
+// EncompassedBy returns a bool describing if the mesh is completely encompassed by the given geometry. Since meshes are not
+// considered solid, no geometry is encompassed by a mesh.
+func (m *mesh) EncompassedBy(g Geometry) (bool, error) {
+	var inside func(r3.Vector) bool
+	switch other := g.(type) {
+	case *box:
+		inside = func(pt r3.Vector) bool { return pointVsBoxDistance(pt, other) <= 0 }
+	case *sphere:
+		inside = func(pt r3.Vector) bool { return sphereVsPointDistance(other, pt) <= 0 }
+	case *capsule:
+		inside = func(pt r3.Vector) bool { return capsuleVsPointDistance(other, pt) <= 0 }
+	case *point, *mesh:
+		return false, nil
+	default:
+		return false, newCollisionTypeUnsupportedError(m, g)
+	}
+	// all of the primitives are convex, so checking the vertices is sufficient.
+	for _, pt := range transformPointsToPose(m.vertices(), m.pose) {
+		if !inside(pt) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ToPoints converts a mesh geometry into []r3.Vector by sampling the surface of each triangle. The resolution determines how many
+// points per square mm are sampled; if it is nonpositive defaultPointDensity is used.
+func (m *mesh) ToPoints(resolution float64) []r3.Vector {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	var pts []r3.Vector
+	for _, t := range m.triangles {
+		area := t.p1.Sub(t.p0).Cross(t.p2.Sub(t.p0)).Norm() / 2
+		steps := int(math.Max(1, math.Ceil(math.Sqrt(area*resolution))))
+		e0 := t.p1.Sub(t.p0).Mul(1 / float64(steps))
+		e1 := t.p2.Sub(t.p0).Mul(1 / float64(steps))
+		for i := 0; i <= steps; i++ {
+			for j := 0; i+j <= steps; j++ {
+				pts = append(pts, t.p0.Add(e0.Mul(float64(i))).Add(e1.Mul(float64(j))))
+			}
+		}
+	}
+	return transformPointsToPose(pts, m.pose)
+}
+
+// vertices returns the vertices of every triangle in the mesh, in the frame of the mesh.
+func (m *mesh) vertices() []r3.Vector {
+	verts := make([]r3.Vector, 0, 3*len(m.triangles))
+	for _, t := range m.triangles {
+		verts = append(verts, t.p0, t.p1, t.p2)
+	}
+	return verts
+}
+
+// boundingRadius returns the distance from the origin of the mesh frame to its furthest vertex.
+func (m *mesh) boundingRadius() float64 {
+	r := 0.
+	for _, v := range m.vertices() {
+		r = math.Max(r, v.Norm())
+	}
+	return r
+}
+
+// distanceFrom returns the distance between the mesh surface and the given geometry. The search stops as soon as any part of the
+// mesh is found to be within stop of the geometry, in which case the returned distance is not necessarily the minimum.
+func (m *mesh) distanceFrom(g Geometry, stop float64) (float64, error) {
+	if other, ok := g.(*mesh); ok {
+		return meshVsMeshDistance(m, other, stop), nil
+	}
+
+	// do all of the work in the frame of the mesh so its triangles and bounding volumes need not be transformed.
+	var lowerBound func(*bvhNode) float64
+	var triangleDist func(*triangle) float64
+	switch other := g.Transform(PoseInverse(m.pose)).(type) {
+	case *box:
+		center := other.pose.Point()
+		lowerBound = func(n *bvhNode) float64 { return n.distanceToPoint(center) - other.boundingSphereR }
+		triangleDist = func(t *triangle) float64 { return boxVsTriangleDistance(other, t) }
+	case *sphere:
+		center := other.pose.Point()
+		lowerBound = func(n *bvhNode) float64 { return n.distanceToPoint(center) - other.radius }
+		triangleDist = func(t *triangle) float64 { return t.closestPointToPoint(center).Sub(center).Norm() - other.radius }
+	case *capsule:
+		lowerBound = func(n *bvhNode) float64 { return n.distanceToPoint(other.center) - other.length/2 }
+		triangleDist = func(t *triangle) float64 { return capsuleVsTriangleDistance(other, t) }
+	case *point:
+		pt := other.position
+		lowerBound = func(n *bvhNode) float64 { return n.distanceToPoint(pt) }
+		triangleDist = func(t *triangle) float64 { return t.closestPointToPoint(pt).Sub(pt).Norm() }
+	default:
+		return math.Inf(-1), newCollisionTypeUnsupportedError(m, g)
+	}
+	return m.bvh.minDistance(lowerBound, triangleDist, math.Inf(1), stop), nil
+}
+
+// meshVsMeshDistance returns the distance between the surfaces of two meshes, stopping early once any pair of triangles is found to
+// be within stop of each other.
+func meshVsMeshDistance(a, b *mesh, stop float64) float64 {
+	// express b in the frame of a.
+	rel := PoseBetween(a.pose, b.pose)
+	toA := func(pt r3.Vector) r3.Vector { return Compose(rel, NewPoseFromPoint(pt)).Point() }
+
+	best := math.Inf(1)
+	var search func(na, nb *bvhNode)
+	search = func(na, nb *bvhNode) {
+		if best <= stop {
+			return
+		}
+		center, radius := nb.boundingSphere()
+		if na.distanceToPoint(toA(center))-radius >= best {
+			return
+		}
+		switch {
+		case na.isLeaf() && nb.isLeaf():
+			for _, tb := range nb.triangles {
+				moved := newTriangle(toA(tb.p0), toA(tb.p1), toA(tb.p2))
+				for _, ta := range na.triangles {
+					if dist := triangleVsTriangleDistance(ta, moved); dist < best {
+						best = dist
+						if best <= stop {
+							return
+						}
+					}
+				}
+			}
+		case nb.isLeaf() || (!na.isLeaf() && na.volume() >= nb.volume()):
+			search(na.left, nb)
+			search(na.right, nb)
+		default:
+			search(na, nb.left)
+			search(na, nb.right)
+		}
+	}
+	search(a.bvh, b.bvh)
+	return best
+}
+
+// triangleVsTriangleDistance returns the distance between two triangles, which is zero if they intersect.
+func triangleVsTriangleDistance(a, b *triangle) float64 {
+	// if the triangles intersect, an edge of one of them must pass through the other, so the closest points between each edge and
+	// the opposite triangle cover every case.
+	best := math.Inf(1)
+	for _, pair := range [2][2]*triangle{{a, b}, {b, a}} {
+		t, other := pair[0], pair[1]
+		for _, edge := range [3][2]r3.Vector{{t.p0, t.p1}, {t.p1, t.p2}, {t.p2, t.p0}} {
+			segPt, triPt := closestPointsSegmentTriangle(edge[0], edge[1], other)
+			if dist := segPt.Sub(triPt).Norm(); dist < best {
+				best = dist
+			}
+		}
+	}
+	return best
+}
+
+// boxVsTriangleDistance returns the distance between a box and a triangle. If a vertex of the triangle is inside the box, the
+// negative penetration depth of the deepest vertex is returned.
+func boxVsTriangleDistance(b *box, t *triangle) float64 {
+	best := math.Inf(1)
+	for _, pt := range [3]r3.Vector{t.p0, t.p1, t.p2} {
+		best = math.Min(best, pointVsBoxDistance(pt, b))
+	}
+	if best <= 0 {
+		return best
+	}
+	for _, face := range b.toMesh().triangles {
+		best = math.Min(best, triangleVsTriangleDistance(face, t))
+	}
+	return best
 }
 
 type triangle struct {
@@ -113,3 +441,7 @@ func (t *triangle) closestInsidePoint(point r3.Vector) (r3.Vector, bool) {
 	inside := (0 <= u) && (u <= 1) && (0 <= v) && (v <= 1) && (u+v <= 1)
 	return t.p0.Add(e0.Mul(u)).Add(e1.Mul(v)), inside
 }
+
+func (t *triangle) centroid() r3.Vector {
+	return t.p0.Add(t.p1).Add(t.p2).Mul(1. / 3)
+}
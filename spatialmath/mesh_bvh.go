@@ -0,0 +1,109 @@
+package spatialmath
+
+import (
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+)
+
+// bvhLeafSize is the maximum number of triangles stored in a leaf of a bounding volume hierarchy.
+const bvhLeafSize = 4
+
+// bvhNode is a node of a bounding volume hierarchy of axis aligned boxes over a set of triangles. Leaves hold triangles, and every
+// other node has exactly two children.
+type bvhNode struct {
+	min, max    r3.Vector
+	left, right *bvhNode
+	triangles   []*triangle
+}
+
+// newBVH builds a bounding volume hierarchy over the given triangles, splitting each node at the median triangle centroid along
+// the longest axis of its bounds.
+func newBVH(triangles []*triangle) *bvhNode {
+	n := &bvhNode{
+		min: r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)},
+		max: r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)},
+	}
+	for _, t := range triangles {
+		for _, pt := range [3]r3.Vector{t.p0, t.p1, t.p2} {
+			n.min = r3.Vector{X: math.Min(n.min.X, pt.X), Y: math.Min(n.min.Y, pt.Y), Z: math.Min(n.min.Z, pt.Z)}
+			n.max = r3.Vector{X: math.Max(n.max.X, pt.X), Y: math.Max(n.max.Y, pt.Y), Z: math.Max(n.max.Z, pt.Z)}
+		}
+	}
+	if len(triangles) <= bvhLeafSize {
+		n.triangles = triangles
+		return n
+	}
+
+	extent := n.max.Sub(n.min)
+	axis := func(v r3.Vector) float64 { return v.X }
+	if extent.Y > extent.X && extent.Y >= extent.Z {
+		axis = func(v r3.Vector) float64 { return v.Y }
+	} else if extent.Z > extent.X && extent.Z > extent.Y {
+		axis = func(v r3.Vector) float64 { return v.Z }
+	}
+	sorted := make([]*triangle, len(triangles))
+	copy(sorted, triangles)
+	sort.Slice(sorted, func(i, j int) bool {
+		return axis(sorted[i].centroid()) < axis(sorted[j].centroid())
+	})
+	mid := len(sorted) / 2
+	n.left = newBVH(sorted[:mid])
+	n.right = newBVH(sorted[mid:])
+	return n
+}
+
+func (n *bvhNode) isLeaf() bool {
+	return n.left == nil
+}
+
+func (n *bvhNode) volume() float64 {
+	extent := n.max.Sub(n.min)
+	return extent.X * extent.Y * extent.Z
+}
+
+// boundingSphere returns the center and radius of the sphere circumscribing the node's bounds.
+func (n *bvhNode) boundingSphere() (r3.Vector, float64) {
+	half := n.max.Sub(n.min).Mul(0.5)
+	return n.min.Add(half), half.Norm()
+}
+
+// distanceToPoint returns the distance from the node's bounds to the point, which is zero if the point is within them.
+func (n *bvhNode) distanceToPoint(pt r3.Vector) float64 {
+	dx := math.Max(0, math.Max(n.min.X-pt.X, pt.X-n.max.X))
+	dy := math.Max(0, math.Max(n.min.Y-pt.Y, pt.Y-n.max.Y))
+	dz := math.Max(0, math.Max(n.min.Z-pt.Z, pt.Z-n.max.Z))
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// minDistance returns the smallest triangleDist of any triangle in the hierarchy, or best if none is smaller. lowerBound must never
+// exceed triangleDist for any triangle within a node, and is used to skip nodes which cannot improve on best. The search returns
+// as soon as a distance at or below stop is found.
+func (n *bvhNode) minDistance(
+	lowerBound func(*bvhNode) float64,
+	triangleDist func(*triangle) float64,
+	best, stop float64,
+) float64 {
+	if best <= stop || lowerBound(n) >= best {
+		return best
+	}
+	if n.isLeaf() {
+		for _, t := range n.triangles {
+			if dist := triangleDist(t); dist < best {
+				best = dist
+				if best <= stop {
+					return best
+				}
+			}
+		}
+		return best
+	}
+	// visit the nearer child first so that the farther one is more likely to be pruned.
+	first, second := n.left, n.right
+	if lowerBound(second) < lowerBound(first) {
+		first, second = second, first
+	}
+	best = first.minDistance(lowerBound, triangleDist, best, stop)
+	return second.minDistance(lowerBound, triangleDist, best, stop)
+}
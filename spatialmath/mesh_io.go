@@ -0,0 +1,366 @@
+package spatialmath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// NewMeshFromFile instantiates a new mesh Geometry from an STL or PLY file, chosen by the file's extension. Coordinates in the file
+// are interpreted as mm relative to the given pose.
+func NewMeshFromFile(pose Pose, path, label string) (Geometry, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		//nolint:errcheck
+		f.Close()
+	}()
+
+	var m Geometry
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".stl":
+		m, err = NewMeshFromSTL(pose, f, label)
+	case ".ply":
+		m, err = NewMeshFromPLY(pose, f, label)
+	default:
+		return nil, errors.Errorf("unsupported mesh file extension %q, expected .stl or .ply", ext)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load mesh from %s", path)
+	}
+	m.(*mesh).fileName = path
+	return m, nil
+}
+
+// NewMeshFromSTL instantiates a new mesh Geometry from ASCII or binary STL data. Coordinates are interpreted as mm relative to the
+// given pose.
+func NewMeshFromSTL(pose Pose, r io.Reader, label string) (Geometry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var triangles []*triangle
+	// binary files may also begin with "solid", so the size implied by the triangle count is the more reliable test.
+	if len(data) >= 84 && len(data) == 84+50*int(binary.LittleEndian.Uint32(data[80:84])) {
+		triangles = parseBinarySTL(data)
+	} else {
+		triangles, err = parseASCIISTL(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newMesh(pose, triangles, label)
+}
+
+func parseBinarySTL(data []byte) []*triangle {
+	count := int(binary.LittleEndian.Uint32(data[80:84]))
+	readVec := func(b []byte) r3.Vector {
+		return r3.Vector{
+			X: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))),
+			Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))),
+			Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(b[8:12]))),
+		}
+	}
+	triangles := make([]*triangle, 0, count)
+	for i := 0; i < count; i++ {
+		// each record is a normal, three vertices and a two byte attribute count; the normal is recomputed from the vertices.
+		rec := data[84+50*i:]
+		triangles = append(triangles, newTriangle(readVec(rec[12:24]), readVec(rec[24:36]), readVec(rec[36:48])))
+	}
+	return triangles
+}
+
+func parseASCIISTL(data []byte) ([]*triangle, error) {
+	var triangles []*triangle
+	var verts []r3.Vector
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "vertex" {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, errors.Errorf("malformed STL vertex %q", scanner.Text())
+		}
+		var coords [3]float64
+		for i := range coords {
+			v, err := strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "malformed STL vertex")
+			}
+			coords[i] = v
+		}
+		verts = append(verts, r3.Vector{X: coords[0], Y: coords[1], Z: coords[2]})
+		if len(verts) == 3 {
+			triangles = append(triangles, newTriangle(verts[0], verts[1], verts[2]))
+			verts = verts[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(verts) != 0 {
+		return nil, errors.New("STL data ends with an incomplete facet")
+	}
+	return triangles, nil
+}
+
+// plyProperty describes a property of a PLY element. List properties have a count type as well as an item type.
+type plyProperty struct {
+	name      string
+	typ       string
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// NewMeshFromPLY instantiates a new mesh Geometry from ASCII or binary PLY data. Faces with more than three vertices are split
+// into triangles. Coordinates are interpreted as mm relative to the given pose.
+func NewMeshFromPLY(pose Pose, r io.Reader, label string) (Geometry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(data)
+	format, elements, err := parsePLYHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	// counts read from the data are checked against the size of the data left, so that a malformed or malicious file
+	// cannot make us allocate more than it could describe. Binary items are the size of their type, and ASCII items are
+	// at least a byte. The word scanner reads ahead, so the whole body bounds ASCII counts.
+	var sizeOf func(typ string) (int, error)
+	var remaining func() int
+	var read func(typ string) (float64, error)
+	switch format {
+	case "ascii":
+		sizeOf = func(string) (int, error) { return 1, nil }
+		bodySize := buf.Len()
+		remaining = func() int { return bodySize }
+		words := bufio.NewScanner(buf)
+		words.Split(bufio.ScanWords)
+		read = func(string) (float64, error) {
+			if !words.Scan() {
+				if err := words.Err(); err != nil {
+					return 0, err
+				}
+				return 0, io.ErrUnexpectedEOF
+			}
+			return strconv.ParseFloat(words.Text(), 64)
+		}
+	case "binary_little_endian":
+		sizeOf, remaining = plyTypeSize, buf.Len
+		read = func(typ string) (float64, error) { return readPLYBinary(buf, binary.LittleEndian, typ) }
+	case "binary_big_endian":
+		sizeOf, remaining = plyTypeSize, buf.Len
+		read = func(typ string) (float64, error) { return readPLYBinary(buf, binary.BigEndian, typ) }
+	default:
+		return nil, errors.Errorf("unsupported PLY format %q", format)
+	}
+
+	var vertices []r3.Vector
+	var triangles []*triangle
+	for _, elem := range elements {
+		elemSize := 0
+		for _, prop := range elem.properties {
+			typ := prop.typ
+			if prop.countType != "" {
+				typ = prop.countType
+			}
+			size, err := sizeOf(typ)
+			if err != nil {
+				return nil, err
+			}
+			elemSize += size
+		}
+		if elem.count > remaining()/max(elemSize, 1) {
+			return nil, errors.Errorf("PLY declares %d %s elements, more than the data left can hold", elem.count, elem.name)
+		}
+		for i := 0; i < elem.count; i++ {
+			var vert r3.Vector
+			var face []int
+			for _, prop := range elem.properties {
+				if prop.countType != "" {
+					n, err := read(prop.countType)
+					if err != nil {
+						return nil, errors.Wrapf(err, "failed to read PLY %s %s", elem.name, prop.name)
+					}
+					itemSize, err := sizeOf(prop.typ)
+					if err != nil {
+						return nil, err
+					}
+					if n < 0 || n != math.Trunc(n) || n > float64(remaining()/itemSize) {
+						return nil, errors.Errorf("PLY %s %s has an invalid count of %v items", elem.name, prop.name, n)
+					}
+					items := make([]int, int(n))
+					for j := range items {
+						v, err := read(prop.typ)
+						if err != nil {
+							return nil, errors.Wrapf(err, "failed to read PLY %s %s", elem.name, prop.name)
+						}
+						items[j] = int(v)
+					}
+					if elem.name == "face" && (prop.name == "vertex_indices" || prop.name == "vertex_index") {
+						face = items
+					}
+					continue
+				}
+				v, err := read(prop.typ)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to read PLY %s %s", elem.name, prop.name)
+				}
+				switch prop.name {
+				case "x":
+					vert.X = v
+				case "y":
+					vert.Y = v
+				case "z":
+					vert.Z = v
+				}
+			}
+			switch elem.name {
+			case "vertex":
+				vertices = append(vertices, vert)
+			case "face":
+				for j := 2; j < len(face); j++ {
+					idx := [3]int{face[0], face[j-1], face[j]}
+					for _, k := range idx {
+						if k < 0 || k >= len(vertices) {
+							return nil, errors.Errorf("PLY face references vertex %d but there are only %d vertices", k, len(vertices))
+						}
+					}
+					triangles = append(triangles, newTriangle(vertices[idx[0]], vertices[idx[1]], vertices[idx[2]]))
+				}
+			}
+		}
+	}
+	return newMesh(pose, triangles, label)
+}
+
+func parsePLYHeader(br *bytes.Buffer) (string, []*plyElement, error) {
+	var format string
+	var elements []*plyElement
+	for lineNum := 0; ; lineNum++ {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to read PLY header")
+		}
+		fields := strings.Fields(line)
+		if lineNum == 0 {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return "", nil, errors.New("data is not in PLY format")
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return "", nil, errors.Errorf("malformed PLY header line %q", line)
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return "", nil, errors.Errorf("malformed PLY header line %q", line)
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return "", nil, errors.Wrapf(err, "malformed PLY header line %q", line)
+			}
+			if count < 0 {
+				return "", nil, errors.Errorf("negative element count in PLY header line %q", line)
+			}
+			elements = append(elements, &plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return "", nil, errors.New("PLY property declared before any element")
+			}
+			elem := elements[len(elements)-1]
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				elem.properties = append(elem.properties, plyProperty{name: fields[4], typ: fields[3], countType: fields[2]})
+			case len(fields) == 3:
+				elem.properties = append(elem.properties, plyProperty{name: fields[2], typ: fields[1]})
+			default:
+				return "", nil, errors.Errorf("malformed PLY header line %q", line)
+			}
+		case "end_header":
+			return format, elements, nil
+		}
+	}
+}
+
+// plyTypeSize returns the size in bytes of a binary PLY property type.
+func plyTypeSize(typ string) (int, error) {
+	switch typ {
+	case "char", "int8", "uchar", "uint8":
+		return 1, nil
+	case "short", "int16", "ushort", "uint16":
+		return 2, nil
+	case "int", "int32", "uint", "uint32", "float", "float32":
+		return 4, nil
+	case "double", "float64":
+		return 8, nil
+	default:
+		return 0, errors.Errorf("unsupported PLY property type %q", typ)
+	}
+}
+
+func readPLYBinary(r io.Reader, order binary.ByteOrder, typ string) (float64, error) {
+	var err error
+	switch typ {
+	case "char", "int8":
+		var v int8
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "uchar", "uint8":
+		var v uint8
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "short", "int16":
+		var v int16
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "ushort", "uint16":
+		var v uint16
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "int", "int32":
+		var v int32
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "uint", "uint32":
+		var v uint32
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "float", "float32":
+		var v float32
+		err = binary.Read(r, order, &v)
+		return float64(v), err
+	case "double", "float64":
+		var v float64
+		err = binary.Read(r, order, &v)
+		return v, err
+	default:
+		return 0, errors.Errorf("unsupported PLY property type %q", typ)
+	}
+}
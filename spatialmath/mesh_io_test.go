@@ -0,0 +1,226 @@
+package spatialmath
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+// a tetrahedron with a vertex at the origin and the others one mm along each axis.
+var (
+	tetraVertices = []r3.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	tetraFaces    = [][3]int{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}}
+)
+
+const asciiSTL = `solid tetra
+facet normal 0 0 -1
+ outer loop
+  vertex 0 0 0
+  vertex 0 1 0
+  vertex 1 0 0
+ endloop
+endfacet
+facet normal 0 -1 0
+ outer loop
+  vertex 0 0 0
+  vertex 1 0 0
+  vertex 0 0 1
+ endloop
+endfacet
+facet normal -1 0 0
+ outer loop
+  vertex 0 0 0
+  vertex 0 0 1
+  vertex 0 1 0
+ endloop
+endfacet
+facet normal 1 1 1
+ outer loop
+  vertex 1 0 0
+  vertex 0 1 0
+  vertex 0 0 1
+ endloop
+endfacet
+endsolid tetra
+`
+
+const asciiPLY = `ply
+format ascii 1.0
+comment a tetrahedron with colored vertices
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+element face 4
+property list uchar int vertex_indices
+end_header
+0 0 0 255
+1 0 0 255
+0 1 0 255
+0 0 1 255
+3 0 2 1
+3 0 1 3
+3 0 3 2
+3 1 2 3
+`
+
+func binarySTL(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	// binary files may have a header which looks like an ASCII file.
+	header := make([]byte, 80)
+	copy(header, "solid tetra")
+	buf.Write(header)
+	test.That(t, binary.Write(&buf, binary.LittleEndian, uint32(len(tetraFaces))), test.ShouldBeNil)
+	for _, face := range tetraFaces {
+		record := make([]float32, 0, 12)
+		record = append(record, 0, 0, 0)
+		for _, idx := range face {
+			v := tetraVertices[idx]
+			record = append(record, float32(v.X), float32(v.Y), float32(v.Z))
+		}
+		test.That(t, binary.Write(&buf, binary.LittleEndian, record), test.ShouldBeNil)
+		test.That(t, binary.Write(&buf, binary.LittleEndian, uint16(0)), test.ShouldBeNil)
+	}
+	return buf.Bytes()
+}
+
+func binaryPLY(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("ply\nformat binary_big_endian 1.0\nelement vertex 4\nproperty double x\nproperty double y\nproperty double z\n")
+	buf.WriteString("element face 4\nproperty list uchar uint vertex_index\nend_header\n")
+	for _, v := range tetraVertices {
+		test.That(t, binary.Write(&buf, binary.BigEndian, []float64{v.X, v.Y, v.Z}), test.ShouldBeNil)
+	}
+	for _, face := range tetraFaces {
+		buf.WriteByte(3)
+		test.That(t, binary.Write(&buf, binary.BigEndian, []uint32{uint32(face[0]), uint32(face[1]), uint32(face[2])}), test.ShouldBeNil)
+	}
+	return buf.Bytes()
+}
+
+func TestMeshFromData(t *testing.T) {
+	expected, err := NewMesh(NewZeroPose(), tetraVertices, tetraFaces, "tetra")
+	test.That(t, err, test.ShouldBeNil)
+
+	testCases := []struct {
+		name  string
+		parse func(Pose, *bytes.Reader, string) (Geometry, error)
+		data  []byte
+	}{
+		{"ascii stl", func(p Pose, r *bytes.Reader, l string) (Geometry, error) { return NewMeshFromSTL(p, r, l) }, []byte(asciiSTL)},
+		{"binary stl", func(p Pose, r *bytes.Reader, l string) (Geometry, error) { return NewMeshFromSTL(p, r, l) }, binarySTL(t)},
+		{"ascii ply", func(p Pose, r *bytes.Reader, l string) (Geometry, error) { return NewMeshFromPLY(p, r, l) }, []byte(asciiPLY)},
+		{"binary ply", func(p Pose, r *bytes.Reader, l string) (Geometry, error) { return NewMeshFromPLY(p, r, l) }, binaryPLY(t)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := tc.parse(NewZeroPose(), bytes.NewReader(tc.data), "tetra")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, m.(*mesh).triangles, test.ShouldHaveLength, 4)
+
+			// the loaded mesh has the same surface as the expected one, though its triangles may be wound differently.
+			for _, pt := range []r3.Vector{{1, 1, 1}, {-1, 0, 0}, {0.2, 0.2, -2}} {
+				dist, err := m.DistanceFrom(NewPoint(pt, ""))
+				test.That(t, err, test.ShouldBeNil)
+				expectedDist, err := expected.DistanceFrom(NewPoint(pt, ""))
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, dist, test.ShouldAlmostEqual, expectedDist, 1e-6)
+			}
+		})
+	}
+
+	t.Run("polygon faces", func(t *testing.T) {
+		square := "ply\nformat ascii 1.0\nelement vertex 4\nproperty float x\nproperty float y\nproperty float z\n" +
+			"element face 1\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n1 0 0\n1 1 0\n0 1 0\n4 0 1 2 3\n"
+		m, err := NewMeshFromPLY(NewZeroPose(), strings.NewReader(square), "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, m.(*mesh).triangles, test.ShouldHaveLength, 2)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := NewMeshFromSTL(NewZeroPose(), strings.NewReader("solid x\nvertex 0 0\nendsolid"), "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMeshFromPLY(NewZeroPose(), strings.NewReader(asciiSTL), "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMeshFromPLY(NewZeroPose(), strings.NewReader(strings.Replace(asciiPLY, "3 0 2 1", "3 0 2 9", 1)), "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("counts larger than the data", func(t *testing.T) {
+		// a face claiming four billion vertex indices
+		var buf bytes.Buffer
+		buf.WriteString("ply\nformat binary_little_endian 1.0\nelement face 1\nproperty list uint uint vertex_index\nend_header\n")
+		test.That(t, binary.Write(&buf, binary.LittleEndian, []uint32{math.MaxUint32, 0, 1, 2}), test.ShouldBeNil)
+		_, err := NewMeshFromPLY(NewZeroPose(), &buf, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid count")
+
+		for _, replacement := range []string{"99999999 0 2 1", "-3 0 2 1"} {
+			_, err = NewMeshFromPLY(NewZeroPose(), strings.NewReader(strings.Replace(asciiPLY, "3 0 2 1", replacement, 1)), "")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, "invalid count")
+		}
+
+		tooMany := bytes.Replace(binaryPLY(t), []byte("vertex 4"), []byte("vertex 999999999"), 1)
+		_, err = NewMeshFromPLY(NewZeroPose(), bytes.NewReader(tooMany), "")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "more than the data left")
+
+		_, err = NewMeshFromPLY(NewZeroPose(), strings.NewReader(strings.Replace(asciiPLY, "element face 4", "element face -4", 1)), "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestMeshFromFile(t *testing.T) {
+	dir := t.TempDir()
+	stlPath := filepath.Join(dir, "tetra.STL")
+	test.That(t, os.WriteFile(stlPath, []byte(asciiSTL), 0o600), test.ShouldBeNil)
+
+	_, err := NewMeshFromFile(NewZeroPose(), filepath.Join(dir, "tetra.obj"), "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// meshes loaded from a file round trip through their config.
+	config := GeometryConfig{Type: MeshType, MeshFile: stlPath, TranslationOffset: r3.Vector{0, 0, 10}, Label: "tetra"}
+	m, err := config.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	dist, err := m.DistanceFrom(NewPoint(r3.Vector{0, 0, 0}, ""))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dist, test.ShouldAlmostEqual, 10)
+
+	data, err := json.Marshal(m)
+	test.That(t, err, test.ShouldBeNil)
+	var parsed GeometryConfig
+	test.That(t, json.Unmarshal(data, &parsed), test.ShouldBeNil)
+	test.That(t, parsed.MeshFile, test.ShouldEqual, stlPath)
+	roundTripped, err := parsed.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(m, roundTripped), test.ShouldBeTrue)
+
+	// a portable config carries the mesh itself, so it can be parsed once the file is gone.
+	portable, err := NewPortableGeometryConfig(m)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, portable.MeshFile, test.ShouldBeEmpty)
+	test.That(t, os.Remove(stlPath), test.ShouldBeNil)
+	rebuilt, err := portable.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(m, rebuilt), test.ShouldBeTrue)
+	test.That(t, os.WriteFile(stlPath, []byte(asciiSTL), 0o600), test.ShouldBeNil)
+
+	// the type is inferred when only a mesh file is given.
+	inferred, err := (&GeometryConfig{MeshFile: stlPath}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	bs, err := BoundingSphere(inferred)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bs.(*sphere).radius, test.ShouldAlmostEqual, 1)
+}
@@ -1,6 +1,8 @@
 package spatialmath
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -21,3 +23,185 @@ func TestClosestPoint(t *testing.T) {
 	test.That(t, cp3.ApproxEqual(qp1), test.ShouldBeTrue)
 	test.That(t, cp1.ApproxEqual(cp2), test.ShouldBeTrue)
 }
+
+// cubeMesh returns a mesh in the shape of the surface of a cube with the given side length, centered on the pose.
+func cubeMesh(t *testing.T, pose Pose, side float64, label string) Geometry {
+	t.Helper()
+	verts := make([]r3.Vector, 0, len(boxVertices))
+	for _, v := range boxVertices {
+		verts = append(verts, v.Mul(side/2))
+	}
+	faces := make([][3]int, 0, len(boxTriangles))
+	for _, f := range boxTriangles {
+		faces = append(faces, f)
+	}
+	m, err := NewMesh(pose, verts, faces, label)
+	test.That(t, err, test.ShouldBeNil)
+	return m
+}
+
+func TestNewMesh(t *testing.T) {
+	_, err := NewMesh(NewZeroPose(), []r3.Vector{{0, 0, 0}, {1, 0, 0}}, [][3]int{{0, 1, 2}}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a mesh made only of degenerate triangles has no surface.
+	_, err = NewMesh(NewZeroPose(), []r3.Vector{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}}, [][3]int{{0, 1, 2}}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	m := cubeMesh(t, NewPoseFromPoint(r3.Vector{10, 0, 0}), 2, "cube")
+	test.That(t, m.Label(), test.ShouldEqual, "cube")
+	test.That(t, GeometriesAlmostEqual(m, m.Transform(NewZeroPose())), test.ShouldBeTrue)
+	test.That(t, GeometriesAlmostEqual(m, m.Transform(NewPoseFromPoint(r3.Vector{1, 0, 0}))), test.ShouldBeFalse)
+
+	// the protobuf representation is the bounding box of the mesh.
+	bb, err := NewGeometryFromProto(m.ToProtobuf())
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := NewBox(NewPoseFromPoint(r3.Vector{10, 0, 0}), r3.Vector{2, 2, 2}, "cube")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(bb, expected), test.ShouldBeTrue)
+
+	bs, err := BoundingSphere(m)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bs.(*sphere).radius, test.ShouldAlmostEqual, 10+math.Sqrt(3))
+
+	for _, pt := range m.ToPoints(1) {
+		dist, err := m.DistanceFrom(NewPoint(pt, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 0)
+	}
+
+	// a mesh without a source file is represented in its config by its vertices and faces.
+	config, err := NewGeometryConfig(m)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, config.MeshFile, test.ShouldBeEmpty)
+	test.That(t, config.MeshVertices, test.ShouldHaveLength, len(boxVertices))
+	test.That(t, config.MeshFaces, test.ShouldHaveLength, len(boxTriangles))
+	data, err := json.Marshal(config)
+	test.That(t, err, test.ShouldBeNil)
+	var decoded GeometryConfig
+	test.That(t, json.Unmarshal(data, &decoded), test.ShouldBeNil)
+	parsed, err := decoded.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(parsed, m), test.ShouldBeTrue)
+}
+
+func TestMeshVsPrimitiveCollision(t *testing.T) {
+	m := cubeMesh(t, NewPose(r3.Vector{0, 0, 0}, &OrientationVectorDegrees{OZ: 1, Theta: 45}), 2, "")
+	diag := math.Sqrt2
+
+	mustGeometry := func(g Geometry, err error) Geometry {
+		test.That(t, err, test.ShouldBeNil)
+		return g
+	}
+	testCases := []struct {
+		name     string
+		geometry Geometry
+		dist     float64
+	}{
+		{"point separated", NewPoint(r3.Vector{0, 0, 3}, ""), 2},
+		{"point on corner side", NewPoint(r3.Vector{diag + 1, 0, 0}, ""), 1},
+		{"sphere separated", mustGeometry(NewSphere(NewPoseFromPoint(r3.Vector{0, 5, 0}), 1, "")), 5 - diag - 1},
+		{"sphere intersecting", mustGeometry(NewSphere(NewPoseFromPoint(r3.Vector{0, 0, 1}), 0.5, "")), -0.5},
+		{"box separated", mustGeometry(NewBox(NewPoseFromPoint(r3.Vector{0, 0, 4}), r3.Vector{2, 2, 2}, "")), 2},
+		{
+			"capsule separated",
+			mustGeometry(NewCapsule(NewPose(r3.Vector{0, 0, 10}, &OrientationVectorDegrees{OX: 1}), 1, 4, "")),
+			8,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dist, err := m.DistanceFrom(tc.geometry)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, dist, test.ShouldAlmostEqual, tc.dist, 1e-6)
+
+			// distances are symmetric.
+			dist, err = tc.geometry.DistanceFrom(m)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, dist, test.ShouldAlmostEqual, tc.dist, 1e-6)
+
+			collides, err := m.CollidesWith(tc.geometry, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldEqual, tc.dist <= defaultCollisionBufferMM)
+			collides, err = tc.geometry.CollidesWith(m, defaultCollisionBufferMM)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldEqual, tc.dist <= defaultCollisionBufferMM)
+		})
+	}
+
+	t.Run("box intersecting surface", func(t *testing.T) {
+		b := mustGeometry(NewBox(NewPoseFromPoint(r3.Vector{0, 0, 1}), r3.Vector{0.5, 0.5, 0.5}, ""))
+		collides, err := b.CollidesWith(m, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	})
+
+	t.Run("meshes are not solid", func(t *testing.T) {
+		s := mustGeometry(NewSphere(NewZeroPose(), 0.1, ""))
+		collides, err := m.CollidesWith(s, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeFalse)
+		encompassed, err := s.EncompassedBy(m)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeFalse)
+	})
+
+	t.Run("encompassed", func(t *testing.T) {
+		encompassed, err := m.EncompassedBy(mustGeometry(NewSphere(NewZeroPose(), 2, "")))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeTrue)
+		encompassed, err = m.EncompassedBy(mustGeometry(NewBox(NewZeroPose(), r3.Vector{2, 2, 2}, "")))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeFalse)
+	})
+}
+
+func TestMeshVsMeshCollision(t *testing.T) {
+	a := cubeMesh(t, NewZeroPose(), 2, "")
+	b := cubeMesh(t, NewPose(r3.Vector{0, 0, 5}, &OrientationVectorDegrees{OX: 1, Theta: 30}), 2, "")
+	dist, err := a.DistanceFrom(b)
+	test.That(t, err, test.ShouldBeNil)
+	// the lowest edge of the rotated cube is cos(30) + sin(30) below its center.
+	test.That(t, dist, test.ShouldAlmostEqual, 4-math.Cos(math.Pi/6)-math.Sin(math.Pi/6), 1e-6)
+
+	collides, err := a.CollidesWith(b.Transform(NewPoseFromPoint(r3.Vector{0, 0, -3.5})), defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+}
+
+func TestMeshBVH(t *testing.T) {
+	// a wavy grid of triangles is large enough to exercise the hierarchy, so compare against checking every triangle.
+	const n = 20
+	verts := make([]r3.Vector, 0, (n+1)*(n+1))
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= n; j++ {
+			verts = append(verts, r3.Vector{float64(i), float64(j), math.Sin(float64(i)/3) * math.Cos(float64(j)/4)})
+		}
+	}
+	var faces [][3]int
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*(n+1) + j
+			faces = append(faces, [3]int{k, k + 1, k + n + 1}, [3]int{k + 1, k + n + 2, k + n + 1})
+		}
+	}
+	g, err := NewMesh(NewPose(r3.Vector{5, -3, 2}, &OrientationVectorDegrees{OY: 1, Theta: 20}), verts, faces, "")
+	test.That(t, err, test.ShouldBeNil)
+	m := g.(*mesh)
+	test.That(t, m.bvh.isLeaf(), test.ShouldBeFalse)
+
+	for _, query := range []r3.Vector{{0, 0, 0}, {12, 3, 7}, {-4, 25, 1}, {10, 10, -3}} {
+		s, err := NewSphere(NewPoseFromPoint(query), 0.5, "")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := m.DistanceFrom(s)
+		test.That(t, err, test.ShouldBeNil)
+
+		local := PoseInverse(m.pose)
+		center := Compose(local, NewPoseFromPoint(query)).Point()
+		expected := math.Inf(1)
+		for _, tri := range m.triangles {
+			expected = math.Min(expected, tri.closestPointToPoint(center).Sub(center).Norm()-0.5)
+		}
+		test.That(t, dist, test.ShouldAlmostEqual, expected, 1e-6)
+	}
+}
@@ -90,6 +90,9 @@ func (pt *point) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	if other, ok := g.(*point); ok {
 		return pt.almostEqual(other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(pt, collisionBufferMM)
+	}
 	return true, newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -107,6 +110,9 @@ func (pt *point) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pt.position.Sub(other.position).Norm(), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(pt)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(pt, g)
 }
 
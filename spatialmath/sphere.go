@@ -103,6 +103,9 @@ func (s *sphere) CollidesWith(g Geometry, collisionBufferMM float64) (bool, erro
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position) <= collisionBufferMM, nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(s, collisionBufferMM)
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -119,6 +122,9 @@ func (s *sphere) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(s)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -135,6 +141,9 @@ func (s *sphere) EncompassedBy(g Geometry) (bool, error) {
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
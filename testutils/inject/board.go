@@ -30,6 +30,7 @@ type Board struct {
 	SetPowerModeFunc           func(ctx context.Context, mode boardpb.PowerMode, duration *time.Duration) error
 	WriteAnalogFunc            func(ctx context.Context, pin string, value int32, extra map[string]interface{}) error
 	StreamTicksFunc            func(ctx context.Context, interrupts []string, ch chan board.Tick, extra map[string]interface{}) error
+	CapabilitiesFunc           func(ctx context.Context) (board.Capabilities, error)
 }
 
 // NewBoard returns a new injected board.
@@ -112,6 +113,14 @@ func (b *Board) DigitalInterruptNames() []string {
 	return b.DigitalInterruptNamesFunc()
 }
 
+// Capabilities calls the injected Capabilities or the real version.
+func (b *Board) Capabilities(ctx context.Context) (board.Capabilities, error) {
+	if b.CapabilitiesFunc == nil {
+		return board.GetCapabilities(ctx, b.Board)
+	}
+	return b.CapabilitiesFunc(ctx)
+}
+
 // Close calls the injected Close or the real version.
 func (b *Board) Close(ctx context.Context) error {
 	if b.CloseFunc == nil {
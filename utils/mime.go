@@ -19,6 +19,10 @@ const (
 	// MimeTypeRawDepth is for depth images.
 	MimeTypeRawDepth = "image/vnd.viam.dep"
 
+	// MimeTypeRawThermal is for 16-bit radiometric thermal images, along with the scale which turns
+	// their readings into temperatures.
+	MimeTypeRawThermal = "image/vnd.viam.thermal"
+
 	// MimeTypeJPEG is regular jpgs.
 	MimeTypeJPEG = "image/jpeg"
 
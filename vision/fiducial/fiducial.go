@@ -0,0 +1,329 @@
+// Package fiducial detects square fiducial markers, such as ArUco markers and AprilTags, in images and
+// estimates their poses relative to the camera.
+package fiducial
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r2"
+	"github.com/pkg/errors"
+)
+
+// ArucoOriginal is the family of the original ArUco markers, which have 1024 ids encoded in a 5x5
+// grid of bits surrounded by a black border.
+const ArucoOriginal = "aruco_original"
+
+// AprilTag16h5 is the family of AprilTags with 30 ids encoded in a 4x4 grid of bits surrounded by a
+// black border, any two of which differ in at least 5 bits in every rotation.
+const AprilTag16h5 = "apriltag_16h5"
+
+const (
+	// arucoOriginalBits is the number of bits along each side of an ArUco marker, excluding the border.
+	arucoOriginalBits = 5
+	// arucoOriginalCells is the number of cells along each side of an ArUco marker, including the border.
+	arucoOriginalCells = arucoOriginalBits + 2
+	// aprilTag16h5Bits is the number of bits along each side of a tag16h5 AprilTag, excluding the border.
+	aprilTag16h5Bits = 4
+
+	// minContrast is the smallest difference between the darkest and brightest pixels around a pixel
+	// for the pixel to be thresholded locally, rather than with the threshold of the whole image.
+	minContrast = 20
+	// thresholdTileSize is the size of the tiles the local threshold of the image is computed for.
+	thresholdTileSize = 8
+	// minSideLengthPx is the shortest side, in pixels, of a marker that can be detected.
+	minSideLengthPx = 14
+	// minQuadFill is how much of the convex hull of a dark region its quadrilateral must cover to be a
+	// marker candidate.
+	minQuadFill = 0.85
+)
+
+// arucoOriginalWords are the possible values of the rows of bits of an original ArUco marker, indexed
+// by the two bits of the id they encode.
+var arucoOriginalWords = [4]uint8{0x10, 0x17, 0x09, 0x0e}
+
+// aprilTag16h5Codes are the codes of the tag16h5 AprilTags, indexed by id.
+var aprilTag16h5Codes = [30]uint16{
+	0x27c8, 0x31b6, 0x3859, 0x569c, 0x6c76, 0x7ddb, 0xaf09, 0xf5a1, 0xfb8b, 0x1cb9,
+	0x28ca, 0xe8dc, 0x1426, 0x5770, 0x9253, 0xb702, 0x063a, 0x8f34, 0xb4c0, 0x51ec,
+	0xe6f0, 0x5fa4, 0xdd43, 0x1aaa, 0xe62f, 0x6dbc, 0xb6eb, 0xde10, 0x154d, 0xb57a,
+}
+
+// aprilTag16h5Cells are the column and row of the cell of each bit of a tag16h5 code, from its most
+// significant bit, counting the border as the first cell. Each quarter of the bits is a quarter turn
+// of the one before it, so that turning a tag rotates its code by four bits.
+var aprilTag16h5Cells = [16][2]int{
+	{1, 1}, {2, 1}, {3, 1}, {2, 2}, {4, 1}, {4, 2}, {4, 3}, {3, 2},
+	{4, 4}, {3, 4}, {2, 4}, {3, 3}, {1, 4}, {1, 3}, {1, 2}, {2, 3},
+}
+
+// markerFamily describes how the ids of a family of markers are encoded.
+type markerFamily struct {
+	// bits is the number of bits along each side of a marker, excluding the border.
+	bits int
+	ids  int
+	// bitsForID returns the bits of the marker with the given id, by row and column, where white is true.
+	bitsForID func(id int) [][]bool
+	// idForBits returns the id of the marker with the given bits, if they are one of the family's.
+	idForBits func(bits [][]bool) (int, bool)
+}
+
+var families = map[string]markerFamily{
+	ArucoOriginal: {bits: arucoOriginalBits, ids: 1 << (2 * arucoOriginalBits), bitsForID: arucoOriginalBitsForID, idForBits: arucoOriginalID},
+	AprilTag16h5:  {bits: aprilTag16h5Bits, ids: len(aprilTag16h5Codes), bitsForID: aprilTag16h5BitsForID, idForBits: aprilTag16h5ID},
+}
+
+// Families returns the marker families that can be detected.
+func Families() []string {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupFamily(family string) (markerFamily, error) {
+	f, ok := families[family]
+	if !ok {
+		return markerFamily{}, errors.Errorf("unsupported marker family %q, supported families are %v", family, Families())
+	}
+	return f, nil
+}
+
+// Marker is a marker detected in an image.
+type Marker struct {
+	ID int
+	// Corners are the outer corners of the marker's border in the image, in pixels, in the order of
+	// its top left, top right, bottom right and bottom left corners as printed.
+	Corners [4]r2.Point
+}
+
+// BoundingBox returns the smallest rectangle of pixels containing the marker.
+func (m Marker) BoundingBox() image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range m.Corners {
+		minX, maxX = math.Min(minX, c.X), math.Max(maxX, c.X)
+		minY, maxY = math.Min(minY, c.Y), math.Max(maxY, c.Y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// Detect returns the markers of the given family found in the image, ordered by id.
+func Detect(img image.Image, family string) ([]Marker, error) {
+	f, err := lookupFamily(family)
+	if err != nil {
+		return nil, err
+	}
+	gray := toGray(img)
+	dark := threshold(gray)
+
+	var markers []Marker
+	for _, quad := range findQuads(dark) {
+		if m, ok := decode(gray, quad, f); ok {
+			markers = append(markers, m)
+		}
+	}
+	sort.Slice(markers, func(i, j int) bool { return markers[i].ID < markers[j].ID })
+	return markers, nil
+}
+
+// MarkerImage returns an image of the marker with the given id, e.g. for printing, drawn with cells
+// of cellSize pixels and surrounded by a white margin of one cell.
+func MarkerImage(family string, id, cellSize int) (*image.Gray, error) {
+	f, err := lookupFamily(family)
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= f.ids {
+		return nil, errors.Errorf("id %d is out of range for family %q", id, family)
+	}
+	if cellSize <= 0 {
+		return nil, errors.New("cell size must be positive")
+	}
+	bits := f.bitsForID(id)
+	cells := f.bits + 2
+	size := (cells + 2) * cellSize
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			row, col := y/cellSize-1, x/cellSize-1
+			white := true
+			if row >= 0 && row < cells && col >= 0 && col < cells {
+				white = row > 0 && row <= f.bits && col > 0 && col <= f.bits && bits[row-1][col-1]
+			}
+			if white {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img, nil
+}
+
+// arucoOriginalBitsForID returns the bits of the marker with the given id, where white is true.
+func arucoOriginalBitsForID(id int) [][]bool {
+	bits := make([][]bool, arucoOriginalBits)
+	for row := 0; row < arucoOriginalBits; row++ {
+		bits[row] = make([]bool, arucoOriginalBits)
+		word := arucoOriginalWords[(id>>(2*(arucoOriginalBits-1-row)))&3]
+		for col := 0; col < arucoOriginalBits; col++ {
+			bits[row][col] = word&(1<<(arucoOriginalBits-1-col)) != 0
+		}
+	}
+	return bits
+}
+
+// arucoOriginalID returns the id encoded by the bits of an original ArUco marker.
+func arucoOriginalID(bits [][]bool) (int, bool) {
+	id := 0
+	for _, row := range bits {
+		var word uint8
+		for _, white := range row {
+			word <<= 1
+			if white {
+				word |= 1
+			}
+		}
+		found := false
+		for value, w := range arucoOriginalWords {
+			if w == word {
+				id = id<<2 | value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return id, true
+}
+
+// aprilTag16h5BitsForID returns the bits of the tag16h5 AprilTag with the given id, where white is true.
+func aprilTag16h5BitsForID(id int) [][]bool {
+	bits := make([][]bool, aprilTag16h5Bits)
+	for row := range bits {
+		bits[row] = make([]bool, aprilTag16h5Bits)
+	}
+	code := aprilTag16h5Codes[id]
+	for i, cell := range aprilTag16h5Cells {
+		bits[cell[1]-1][cell[0]-1] = code&(1<<(len(aprilTag16h5Cells)-1-i)) != 0
+	}
+	return bits
+}
+
+// aprilTag16h5ID returns the id encoded by the bits of a tag16h5 AprilTag. Only exact matches are
+// accepted, since the codes of the family are too close to each other to correct errors reliably.
+func aprilTag16h5ID(bits [][]bool) (int, bool) {
+	var code uint16
+	for _, cell := range aprilTag16h5Cells {
+		code <<= 1
+		if bits[cell[1]-1][cell[0]-1] {
+			code |= 1
+		}
+	}
+	for id, c := range aprilTag16h5Codes {
+		if c == code {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// decode reads the marker of the family bounded by the quadrilateral, trying each of its rotations.
+func decode(gray *image.Gray, quad [4]r2.Point, f markerFamily) (Marker, bool) {
+	n := f.bits + 2
+	for rotation := 0; rotation < 4; rotation++ {
+		corners := [4]r2.Point{quad[rotation], quad[(rotation+1)%4], quad[(rotation+2)%4], quad[(rotation+3)%4]}
+		cells, ok := sampleCells(gray, corners, n)
+		if !ok {
+			return Marker{}, false
+		}
+
+		// cells are white if they are closer to the brightest cell than to the average border cell
+		var border float64
+		var borderCount int
+		brightest := 0.0
+		for row := 0; row < n; row++ {
+			for col := 0; col < n; col++ {
+				if row == 0 || col == 0 || row == n-1 || col == n-1 {
+					border += cells[row][col]
+					borderCount++
+				} else {
+					brightest = math.Max(brightest, cells[row][col])
+				}
+			}
+		}
+		border /= float64(borderCount)
+		if brightest-border < minContrast {
+			return Marker{}, false
+		}
+		cutoff := (border + brightest) / 2
+		for i := 0; i < n; i++ {
+			if cells[0][i] > cutoff || cells[n-1][i] > cutoff || cells[i][0] > cutoff || cells[i][n-1] > cutoff {
+				return Marker{}, false
+			}
+		}
+
+		bits := make([][]bool, f.bits)
+		for row := range bits {
+			bits[row] = make([]bool, f.bits)
+			for col := range bits[row] {
+				bits[row][col] = cells[row+1][col+1] > cutoff
+			}
+		}
+		if id, ok := f.idForBits(bits); ok {
+			return Marker{ID: id, Corners: corners}, true
+		}
+	}
+	return Marker{}, false
+}
+
+// sampleCells returns the mean brightness of the center of each cell of an n by n grid whose outer
+// corners are at the given points, in the order top left, top right, bottom right and bottom left.
+func sampleCells(gray *image.Gray, corners [4]r2.Point, n int) ([][]float64, bool) {
+	size := float64(n)
+	h, err := newHomography([4]r2.Point{{X: 0, Y: 0}, {X: size, Y: 0}, {X: size, Y: size}, {X: 0, Y: size}}, corners)
+	if err != nil {
+		return nil, false
+	}
+	bounds := gray.Bounds()
+	cells := make([][]float64, n)
+	offsets := []float64{0.3, 0.5, 0.7}
+	for row := 0; row < n; row++ {
+		cells[row] = make([]float64, n)
+		for col := 0; col < n; col++ {
+			var sum float64
+			for _, dy := range offsets {
+				for _, dx := range offsets {
+					p := h.apply(r2.Point{X: float64(col) + dx, Y: float64(row) + dy})
+					x, y := int(math.Floor(p.X)), int(math.Floor(p.Y))
+					if !(image.Point{X: x, Y: y}).In(bounds) {
+						return nil, false
+					}
+					sum += float64(gray.GrayAt(x, y).Y)
+				}
+			}
+			cells[row][col] = sum / float64(len(offsets)*len(offsets))
+		}
+	}
+	return cells, true
+}
+
+// toGray converts the image to grayscale.
+func toGray(img image.Image) *image.Gray {
+	if gray, ok := img.(*image.Gray); ok {
+		return gray
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x, y, img.At(x, y))
+		}
+	}
+	return gray
+}
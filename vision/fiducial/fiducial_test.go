@@ -0,0 +1,152 @@
+package fiducial
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var testIntrinsics = &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 600, Fy: 600, Ppx: 320, Ppy: 240}
+
+// renderMarker renders the marker as seen by a camera with the test intrinsics when it is at the given pose.
+func renderMarker(t *testing.T, id int, sizeMM float64, pose spatialmath.Pose) *image.Gray {
+	t.Helper()
+	bits := arucoOriginalBitsForID(id)
+	img := image.NewGray(image.Rect(0, 0, testIntrinsics.Width, testIntrinsics.Height))
+	normal := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(r3.Vector{Z: 1})).Point().Sub(pose.Point())
+	cell := sizeMM / arucoOriginalCells
+	for y := 0; y < testIntrinsics.Height; y++ {
+		for x := 0; x < testIntrinsics.Width; x++ {
+			ray := r3.Vector{
+				X: (float64(x) + 0.5 - testIntrinsics.Ppx) / testIntrinsics.Fx,
+				Y: (float64(y) + 0.5 - testIntrinsics.Ppy) / testIntrinsics.Fy,
+				Z: 1,
+			}
+			hit := ray.Mul(normal.Dot(pose.Point()) / normal.Dot(ray))
+			local := spatialmath.PoseBetween(pose, spatialmath.NewPoseFromPoint(hit)).Point()
+			col := int(math.Floor((local.X + sizeMM/2) / cell))
+			row := int(math.Floor((sizeMM/2 - local.Y) / cell))
+			white := true
+			if row >= 0 && row < arucoOriginalCells && col >= 0 && col < arucoOriginalCells {
+				white = row > 0 && row <= arucoOriginalBits && col > 0 && col <= arucoOriginalBits && bits[row-1][col-1]
+			}
+			if white {
+				img.SetGray(x, y, color.Gray{Y: 230})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 30})
+			}
+		}
+	}
+	return img
+}
+
+func TestDetectMarkerImage(t *testing.T) {
+	for _, tc := range []struct {
+		family string
+		ids    []int
+		size   int
+	}{
+		{ArucoOriginal, []int{1, 17, 300, 511, 1000}, 90},
+		{AprilTag16h5, []int{0, 7, 13, 29}, 80},
+	} {
+		t.Run(tc.family, func(t *testing.T) {
+			for _, id := range tc.ids {
+				marker, err := MarkerImage(tc.family, id, 10)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, marker.Bounds().Dx(), test.ShouldEqual, tc.size)
+
+				img := image.NewGray(image.Rect(0, 0, 200, 160))
+				draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+				draw.Draw(img, marker.Bounds().Add(image.Pt(50, 30)), marker, image.Point{}, draw.Src)
+
+				markers, err := Detect(img, tc.family)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, len(markers), test.ShouldEqual, 1)
+				test.That(t, markers[0].ID, test.ShouldEqual, id)
+
+				// the border starts one cell into the marker image
+				side := tc.size - 20
+				expected := [4]r2.Point{
+					{X: 60, Y: 40}, {X: float64(60 + side), Y: 40}, {X: float64(60 + side), Y: float64(40 + side)}, {X: 60, Y: float64(40 + side)},
+				}
+				for i, c := range markers[0].Corners {
+					test.That(t, c.Sub(expected[i]).Norm(), test.ShouldBeLessThan, 1)
+				}
+				test.That(t, markers[0].BoundingBox(), test.ShouldResemble, image.Rect(60, 40, 60+side, 40+side))
+			}
+		})
+	}
+
+	t.Run("turned apriltag", func(t *testing.T) {
+		marker, err := MarkerImage(AprilTag16h5, 21, 10)
+		test.That(t, err, test.ShouldBeNil)
+		// turn the marker a quarter clockwise
+		size := marker.Bounds().Dx()
+		img := image.NewGray(image.Rect(0, 0, size, size))
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				img.SetGray(size-1-y, x, marker.GrayAt(x, y))
+			}
+		}
+
+		markers, err := Detect(img, AprilTag16h5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(markers), test.ShouldEqual, 1)
+		test.That(t, markers[0].ID, test.ShouldEqual, 21)
+		// the first corner is the top left corner of the marker as printed, now at the top right
+		test.That(t, markers[0].Corners[0].Sub(r2.Point{X: 70, Y: 10}).Norm(), test.ShouldBeLessThan, 1)
+
+		// and it isn't mistaken for a marker of another family
+		markers, err = Detect(img, ArucoOriginal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, markers, test.ShouldBeEmpty)
+	})
+}
+
+func TestDetectErrors(t *testing.T) {
+	_, err := Detect(image.NewGray(image.Rect(0, 0, 10, 10)), "apriltag_36h11")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unsupported marker family")
+
+	_, err = MarkerImage(ArucoOriginal, 1024, 10)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = MarkerImage(AprilTag16h5, 30, 10)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	markers, err := Detect(image.NewGray(image.Rect(0, 0, 100, 100)), ArucoOriginal)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, markers, test.ShouldBeEmpty)
+}
+
+func TestMarkerPose(t *testing.T) {
+	// tilted and turned, facing the camera
+	expected := spatialmath.NewPose(
+		r3.Vector{X: 30, Y: -20, Z: 500},
+		&spatialmath.EulerAngles{Roll: math.Pi - 0.4, Pitch: 0.3, Yaw: 2.5},
+	)
+	img := renderMarker(t, 42, 100, expected)
+
+	markers, err := Detect(img, ArucoOriginal)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(markers), test.ShouldEqual, 1)
+	test.That(t, markers[0].ID, test.ShouldEqual, 42)
+
+	pose, err := markers[0].Pose(testIntrinsics, 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().Distance(expected.Point()), test.ShouldBeLessThan, 5)
+	test.That(t, spatialmath.PoseBetween(expected, pose).Orientation().AxisAngles().Theta, test.ShouldBeLessThan, 0.05)
+
+	_, err = markers[0].Pose(testIntrinsics, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = markers[0].Pose(nil, 100)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,113 @@
+package fiducial
+
+import (
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// homography is a projective transformation of the plane.
+type homography struct {
+	h [9]float64
+}
+
+// newHomography returns the homography mapping each of the from points to the matching to point.
+func newHomography(from, to [4]r2.Point) (*homography, error) {
+	// with h[8] fixed to 1, every correspondence gives two linear equations in the other eight entries
+	a := mat.NewDense(8, 8, nil)
+	b := mat.NewVecDense(8, nil)
+	for i := range from {
+		x, y, u, v := from[i].X, from[i].Y, to[i].X, to[i].Y
+		a.SetRow(2*i, []float64{x, y, 1, 0, 0, 0, -u * x, -u * y})
+		a.SetRow(2*i+1, []float64{0, 0, 0, x, y, 1, -v * x, -v * y})
+		b.SetVec(2*i, u)
+		b.SetVec(2*i+1, v)
+	}
+	var sol mat.VecDense
+	if err := sol.SolveVec(a, b); err != nil {
+		return nil, errors.Wrap(err, "points do not determine a homography")
+	}
+	var h homography
+	for i := 0; i < 8; i++ {
+		h.h[i] = sol.AtVec(i)
+	}
+	h.h[8] = 1
+	return &h, nil
+}
+
+// apply maps the point by the homography.
+func (h *homography) apply(p r2.Point) r2.Point {
+	w := h.h[6]*p.X + h.h[7]*p.Y + h.h[8]
+	return r2.Point{
+		X: (h.h[0]*p.X + h.h[1]*p.Y + h.h[2]) / w,
+		Y: (h.h[3]*p.X + h.h[4]*p.Y + h.h[5]) / w,
+	}
+}
+
+// column returns the i'th column of the homography's matrix.
+func (h *homography) column(i int) r3.Vector {
+	return r3.Vector{X: h.h[i], Y: h.h[3+i], Z: h.h[6+i]}
+}
+
+// Pose returns the pose of the marker relative to the camera which took the image it was detected
+// in, given the camera's intrinsics and the length of the sides of the marker's border in
+// millimeters. The marker's origin is at its center, with its X axis pointing to its right, its Y
+// axis pointing up and its Z axis pointing out of its printed side. The camera's frame has its Z axis
+// pointing forward, its X axis pointing to the right and its Y axis pointing down.
+func (m Marker) Pose(intrinsics *transform.PinholeCameraIntrinsics, sizeMM float64) (spatialmath.Pose, error) {
+	if err := intrinsics.CheckValid(); err != nil {
+		return nil, err
+	}
+	if sizeMM <= 0 {
+		return nil, errors.New("marker size must be positive")
+	}
+
+	// the homography from the marker's plane to the normalized image plane is the camera's rotation and
+	// translation, up to scale: H = s[r1 r2 t]
+	half := sizeMM / 2
+	object := [4]r2.Point{{X: -half, Y: half}, {X: half, Y: half}, {X: half, Y: -half}, {X: -half, Y: -half}}
+	var normalized [4]r2.Point
+	for i, c := range m.Corners {
+		normalized[i] = r2.Point{X: (c.X - intrinsics.Ppx) / intrinsics.Fx, Y: (c.Y - intrinsics.Ppy) / intrinsics.Fy}
+	}
+	h, err := newHomography(object, normalized)
+	if err != nil {
+		return nil, err
+	}
+	h1, h2, h3 := h.column(0), h.column(1), h.column(2)
+	scale := 2 / (h1.Norm() + h2.Norm())
+	if h3.Z < 0 {
+		// the marker is in front of the camera
+		scale = -scale
+	}
+	r1, r2v, t := h1.Mul(scale), h2.Mul(scale), h3.Mul(scale)
+	r3v := r1.Cross(r2v)
+
+	// the columns are only approximately orthonormal because of noise, so use the closest rotation
+	rot := mat.NewDense(3, 3, []float64{
+		r1.X, r2v.X, r3v.X,
+		r1.Y, r2v.Y, r3v.Y,
+		r1.Z, r2v.Z, r3v.Z,
+	})
+	var svd mat.SVD
+	if !svd.Factorize(rot, mat.SVDFull) {
+		return nil, errors.New("cannot estimate marker orientation")
+	}
+	var u, vt, closest mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&vt)
+	closest.Mul(&u, vt.T())
+	if mat.Det(&closest) < 0 {
+		return nil, errors.New("cannot estimate marker orientation")
+	}
+	// rotation matrices are stored column by column
+	rm, err := spatialmath.NewRotationMatrix(mat.DenseCopyOf(closest.T()).RawMatrix().Data)
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.NewPose(t, rm), nil
+}
@@ -0,0 +1,281 @@
+package fiducial
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r2"
+)
+
+// darkMask marks the dark pixels of an image.
+type darkMask struct {
+	bounds image.Rectangle
+	dark   []bool
+}
+
+func (m *darkMask) isDark(x, y int) bool {
+	return m.dark[(y-m.bounds.Min.Y)*m.bounds.Dx()+x-m.bounds.Min.X]
+}
+
+// threshold returns the dark pixels of the image. A pixel is dark if it is darker than the midpoint of
+// the darkest and brightest pixels of the tiles around it, so that markers are found under uneven
+// lighting, or darker than the threshold of the whole image if there is too little contrast around it.
+func threshold(gray *image.Gray) *darkMask {
+	bounds := gray.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tilesX := (w + thresholdTileSize - 1) / thresholdTileSize
+	tilesY := (h + thresholdTileSize - 1) / thresholdTileSize
+
+	tileMin := make([]uint8, tilesX*tilesY)
+	tileMax := make([]uint8, tilesX*tilesY)
+	for i := range tileMin {
+		tileMin[i] = math.MaxUint8
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := gray.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y
+			tile := (y/thresholdTileSize)*tilesX + x/thresholdTileSize
+			if v < tileMin[tile] {
+				tileMin[tile] = v
+			}
+			if v > tileMax[tile] {
+				tileMax[tile] = v
+			}
+		}
+	}
+
+	// extend the extremes of every tile to its neighbors
+	neighborMin := make([]uint8, len(tileMin))
+	neighborMax := make([]uint8, len(tileMax))
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			mn, mx := uint8(math.MaxUint8), uint8(0)
+			for ny := ty - 1; ny <= ty+1; ny++ {
+				for nx := tx - 1; nx <= tx+1; nx++ {
+					if nx < 0 || ny < 0 || nx >= tilesX || ny >= tilesY {
+						continue
+					}
+					if tileMin[ny*tilesX+nx] < mn {
+						mn = tileMin[ny*tilesX+nx]
+					}
+					if tileMax[ny*tilesX+nx] > mx {
+						mx = tileMax[ny*tilesX+nx]
+					}
+				}
+			}
+			neighborMin[ty*tilesX+tx] = mn
+			neighborMax[ty*tilesX+tx] = mx
+		}
+	}
+
+	global := otsuThreshold(gray)
+	mask := &darkMask{bounds: bounds, dark: make([]bool, w*h)}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			tile := (y/thresholdTileSize)*tilesX + x/thresholdTileSize
+			mn, mx := int(neighborMin[tile]), int(neighborMax[tile])
+			cutoff := global
+			if mx-mn >= minContrast {
+				cutoff = (mn + mx) / 2
+			}
+			mask.dark[y*w+x] = int(gray.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y) < cutoff
+		}
+	}
+	return mask
+}
+
+// otsuThreshold returns the threshold which best separates the pixels of the image into dark and
+// bright ones.
+func otsuThreshold(gray *image.Gray) int {
+	var histogram [256]int
+	bounds := gray.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			histogram[gray.GrayAt(x, y).Y]++
+		}
+	}
+	total := bounds.Dx() * bounds.Dy()
+	var sum float64
+	for v, count := range histogram {
+		sum += float64(v * count)
+	}
+
+	var sumDark float64
+	var countDark int
+	best, bestVariance := 128, -1.0
+	for v, count := range histogram {
+		countDark += count
+		if countDark == 0 || countDark == total {
+			continue
+		}
+		sumDark += float64(v * count)
+		meanDark := sumDark / float64(countDark)
+		meanBright := (sum - sumDark) / float64(total-countDark)
+		variance := float64(countDark) * float64(total-countDark) * (meanDark - meanBright) * (meanDark - meanBright)
+		if variance > bestVariance {
+			best, bestVariance = v+1, variance
+		}
+	}
+	return best
+}
+
+// findQuads returns the quadrilaterals outlining the connected dark regions of the image, which
+// are the candidates for the black borders of markers. The corners of each quadrilateral are in
+// clockwise order as seen in the image.
+func findQuads(mask *darkMask) [][4]r2.Point {
+	bounds := mask.bounds
+	w, h := bounds.Dx(), bounds.Dy()
+	labels := make([]int32, w*h)
+	var quads [][4]r2.Point
+	var label int32
+	var stack, region []int
+	for start := range mask.dark {
+		if !mask.dark[start] || labels[start] != 0 {
+			continue
+		}
+		label++
+		labels[start] = label
+		stack = append(stack[:0], start)
+		region = region[:0]
+		touchesEdge := false
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			region = append(region, i)
+			x, y := i%w, i/w
+			if x == 0 || y == 0 || x == w-1 || y == h-1 {
+				touchesEdge = true
+			}
+			for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] < 0 || n[1] < 0 || n[0] >= w || n[1] >= h {
+					continue
+				}
+				j := n[1]*w + n[0]
+				if mask.dark[j] && labels[j] == 0 {
+					labels[j] = label
+					stack = append(stack, j)
+				}
+			}
+		}
+		// markers must be entirely in the image, and their borders are at least a pixel wide
+		if touchesEdge || len(region) < 4*minSideLengthPx {
+			continue
+		}
+
+		var boundary []r2.Point
+		for _, i := range region {
+			x, y := i%w, i/w
+			if x == 0 || y == 0 || x == w-1 || y == h-1 ||
+				labels[i-1] != label || labels[i+1] != label || labels[i-w] != label || labels[i+w] != label {
+				boundary = append(boundary, r2.Point{X: float64(bounds.Min.X+x) + 0.5, Y: float64(bounds.Min.Y+y) + 0.5})
+			}
+		}
+		if quad, ok := quadFromHull(convexHull(boundary)); ok {
+			quads = append(quads, quad)
+		}
+	}
+	return quads
+}
+
+// quadFromHull returns the quadrilateral approximating the convex hull, if the hull is close to one.
+func quadFromHull(hull []r2.Point) ([4]r2.Point, bool) {
+	var quad [4]r2.Point
+	if len(hull) < 4 {
+		return quad, false
+	}
+	var centroid r2.Point
+	for _, p := range hull {
+		centroid = centroid.Add(p)
+	}
+	centroid = centroid.Mul(1 / float64(len(hull)))
+
+	// the point farthest from the center and the point farthest from it are opposite corners, and
+	// the points farthest from the diagonal between them on either side are the other corners
+	farthest := func(from r2.Point) r2.Point {
+		best, bestDist := hull[0], -1.0
+		for _, p := range hull {
+			if d := p.Sub(from).Norm(); d > bestDist {
+				best, bestDist = p, d
+			}
+		}
+		return best
+	}
+	quad[0] = farthest(centroid)
+	quad[2] = farthest(quad[0])
+	diagonal := quad[2].Sub(quad[0])
+	left, right := 0.0, 0.0
+	for _, p := range hull {
+		d := diagonal.Cross(p.Sub(quad[0]))
+		if d > left {
+			left, quad[1] = d, p
+		}
+		if d < right {
+			right, quad[3] = d, p
+		}
+	}
+	if left == 0 || right == 0 {
+		return quad, false
+	}
+	if quad[1].Sub(quad[0]).Cross(quad[2].Sub(quad[1])) < 0 {
+		quad[1], quad[3] = quad[3], quad[1]
+	}
+
+	for i := range quad {
+		next := quad[(i+1)%4]
+		if next.Sub(quad[i]).Norm() < minSideLengthPx {
+			return quad, false
+		}
+		if next.Sub(quad[i]).Cross(quad[(i+2)%4].Sub(next)) <= 0 {
+			return quad, false
+		}
+	}
+	if polygonArea(quad[:]) < minQuadFill*polygonArea(hull) {
+		return quad, false
+	}
+
+	// the boundary runs through the centers of the outermost pixels, half a pixel inside the edge
+	center := quad[0].Add(quad[1]).Add(quad[2]).Add(quad[3]).Mul(0.25)
+	for i := range quad {
+		quad[i] = quad[i].Add(quad[i].Sub(center).Normalize().Mul(math.Sqrt2 / 2))
+	}
+	return quad, true
+}
+
+// convexHull returns the convex hull of the points.
+func convexHull(points []r2.Point) []r2.Point {
+	if len(points) < 3 {
+		return points
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].X != points[j].X {
+			return points[i].X < points[j].X
+		}
+		return points[i].Y < points[j].Y
+	})
+	hull := make([]r2.Point, 0, 2*len(points))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range points {
+			for len(hull) >= start+2 && hull[len(hull)-1].Sub(hull[len(hull)-2]).Cross(p.Sub(hull[len(hull)-1])) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		// walk back along the other side
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+	return hull
+}
+
+// polygonArea returns the area of the polygon.
+func polygonArea(points []r2.Point) float64 {
+	var area float64
+	for i, p := range points {
+		area += p.Cross(points[(i+1)%len(points)])
+	}
+	return math.Abs(area) / 2
+}
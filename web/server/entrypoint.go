@@ -23,6 +23,7 @@ import (
 
 	vlogging "go.viam.com/rdk/components/camera/videosource/logging"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/internal/telemetry"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	robotimpl "go.viam.com/rdk/robot/impl"
@@ -49,11 +50,15 @@ type Arguments struct {
 	OutputTelemetry            bool   `flag:"output-telemetry,usage=print out telemetry data (metrics and spans)"`
 	DisableMulticastDNS        bool   `flag:"disable-mdns,usage=disable server discovery through multicast DNS"`
 	DumpResourcesPath          string `flag:"dump-resources,usage=dump all resource registrations as json to the provided file path"`
+	LogBufferSize              int    `flag:"log-buffer-size,usage=recent log entries to keep in memory for each logger (default 1000)"`
+	FTDCDir                    string `flag:"ftdc-dir,usage=record the stats of resources to rotating files in this directory"`
 }
 
 type robotServer struct {
-	args   Arguments
-	logger logging.Logger
+	args      Arguments
+	logger    logging.Logger
+	logBuffer *logging.LogBuffer
+	otlp      *telemetry.OTLP
 }
 
 // RunServer is an entry point to starting the web server that can be called by main in a code
@@ -79,6 +84,10 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 	logger = logger.Sublogger("robot_server")
 	config.InitLoggingSettings(logger, argsParsed.Debug)
 
+	// Keep recent logs in memory so they can be retrieved even without remote logging.
+	logBuffer := logging.NewLogBuffer(argsParsed.LogBufferSize)
+	logger.AddAppender(logBuffer)
+
 	// Always log the version, return early if the '-version' flag was provided
 	// fmt.Println would be better but fails linting. Good enough.
 	var versionFields []interface{}
@@ -155,9 +164,20 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 		logger.AddAppender(netAppender)
 	}
 
+	// Export traces and logs to an OpenTelemetry collector as configured. The config from disk is used until the
+	// robot's full config is read, and exporting is reconfigured along with the robot from then on. With
+	// -output-telemetry every trace is sampled, so the configured sample rate is not applied.
+	otlp := telemetry.NewOTLP(logger, argsParsed.OutputTelemetry)
+	defer otlp.Close()
+	if err := otlp.Reconfigure(cfgFromDisk); err != nil {
+		return err
+	}
+
 	server := robotServer{
-		logger: logger,
-		args:   argsParsed,
+		logger:    logger,
+		args:      argsParsed,
+		logBuffer: logBuffer,
+		otlp:      otlp,
 	}
 
 	// Run the server with remote logging enabled.
@@ -169,6 +189,13 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 	return err
 }
 
+// reconfigureTelemetry exports traces and logs as the config says, without restarting the server.
+func (s *robotServer) reconfigureTelemetry(cfg *config.Config) {
+	if err := s.otlp.Reconfigure(cfg); err != nil {
+		s.logger.Errorw("error reconfiguring OTLP export", "error", err)
+	}
+}
+
 // runServer is an entry point to starting the web server after the local config is read. Once the local config
 // is read the logger may be initialized to remote log. This ensure we capture errors starting up the server and report to the cloud.
 func (s *robotServer) runServer(ctx context.Context) error {
@@ -199,6 +226,7 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 	options.Debug = s.args.Debug || cfg.Debug
 	options.WebRTC = s.args.WebRTC
 	options.DisableMulticastDNS = s.args.DisableMulticastDNS
+	options.LogBuffer = s.logBuffer
 	if cfg.Cloud != nil && s.args.AllowInsecureCreds {
 		options.SignalingDialOpts = append(options.SignalingDialOpts, rpc.WithAllowInsecureWithCredentialsDowngrade())
 	}
@@ -308,6 +336,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	if err != nil {
 		return err
 	}
+	s.reconfigureTelemetry(processedConfig)
 	if processedConfig.Cloud != nil {
 		cloudRestartCheckerActive = make(chan struct{})
 		utils.PanicCapturingGo(func() {
@@ -356,6 +385,9 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	if s.args.RevealSensitiveConfigDiffs {
 		robotOptions = append(robotOptions, robotimpl.WithRevealSensitiveConfigDiffs())
 	}
+	if s.args.FTDCDir != "" {
+		robotOptions = append(robotOptions, robotimpl.WithFTDC(s.args.FTDCDir))
+	}
 
 	myRobot, err := robotimpl.New(ctx, processedConfig, s.logger, robotOptions...)
 	if err != nil {
@@ -413,6 +445,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 				}
 
 				myRobot.Reconfigure(ctx, processedConfig)
+				s.reconfigureTelemetry(processedConfig)
 
 				if !diff.NetworkEqual {
 					if err := myRobot.StartWeb(ctx, options); err != nil {